@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/smokyabdulrahman/prayer-times/internal/cli"
@@ -13,9 +12,5 @@ import (
 var version = "dev"
 
 func main() {
-	rootCmd := cli.NewRootCmd(version)
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	os.Exit(cli.Execute(version, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
@@ -0,0 +1,99 @@
+// Package adhan plays the adhan at prayer time through an installed command-line
+// audio player (mpv, afplay or paplay).
+package adhan
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Players are the supported audio players. "auto" uses the first of them
+// that is installed.
+var Players = []string{"mpv", "afplay", "paplay"}
+
+// Silent is the Fajr sound value that plays nothing at Fajr.
+const Silent = "none"
+
+// lookPath finds a player on $PATH. Tests replace it.
+var lookPath = exec.LookPath
+
+// calls are the prayers the adhan is called for; Sunrise, Midnight and the
+// other times get none.
+var calls = map[string]bool{"Fajr": true, "Dhuhr": true, "Asr": true, "Maghrib": true, "Isha": true}
+
+// Sound returns the file to play for the named prayer: fajrSound for Fajr
+// when it is set, sound otherwise. It returns "" when nothing should play.
+func Sound(name, sound, fajrSound string) string {
+	if !calls[name] {
+		return ""
+	}
+	if name == "Fajr" && fajrSound != "" {
+		sound = fajrSound
+	}
+	if sound == Silent {
+		return ""
+	}
+	return sound
+}
+
+// ValidPlayer reports whether player is "auto", empty, or one of Players.
+func ValidPlayer(player string) bool {
+	if player == "" || player == "auto" {
+		return true
+	}
+	for _, p := range Players {
+		if p == player {
+			return true
+		}
+	}
+	return false
+}
+
+// Command returns the command that plays file with player. An empty or
+// "auto" player picks the first of Players found on $PATH.
+func Command(player, file string) (*exec.Cmd, error) {
+	if !ValidPlayer(player) {
+		return nil, fmt.Errorf("invalid adhan player %q: must be auto, %s", player, strings.Join(Players, ", "))
+	}
+	if _, err := os.Stat(file); err != nil {
+		return nil, fmt.Errorf("adhan sound: %w", err)
+	}
+
+	candidates := Players
+	if player != "" && player != "auto" {
+		candidates = []string{player}
+	}
+	for _, name := range candidates {
+		path, err := lookPath(name)
+		if err != nil {
+			continue
+		}
+		switch name {
+		case "mpv":
+			return exec.Command(path, "--no-video", "--really-quiet", file), nil
+		default:
+			return exec.Command(path, file), nil
+		}
+	}
+	if len(candidates) == 1 {
+		return nil, fmt.Errorf("adhan player %s not found on PATH", candidates[0])
+	}
+	return nil, fmt.Errorf("no audio player found: install one of %s", strings.Join(Players, ", "))
+}
+
+// Play plays file with player and waits for it to finish.
+func Play(player, file string) error {
+	cmd, err := Command(player, file)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s failed: %s", cmd.Args[0], msg)
+		}
+		return fmt.Errorf("%s failed: %w", cmd.Args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package adhan
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// withPlayers makes lookPath find only the given players.
+func withPlayers(t *testing.T, installed ...string) {
+	t.Helper()
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	lookPath = func(name string) (string, error) {
+		for _, p := range installed {
+			if p == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func tempSound(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "adhan.mp3")
+	if err := os.WriteFile(path, []byte("ID3"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSound(t *testing.T) {
+	tests := []struct {
+		name, sound, fajr, want string
+	}{
+		{"Dhuhr", "adhan.mp3", "", "adhan.mp3"},
+		{"Fajr", "adhan.mp3", "", "adhan.mp3"},
+		{"Fajr", "adhan.mp3", "fajr.mp3", "fajr.mp3"},
+		{"Asr", "adhan.mp3", "fajr.mp3", "adhan.mp3"},
+		{"Fajr", "adhan.mp3", Silent, ""},
+		{"Isha", "", "fajr.mp3", ""},
+		{"Sunrise", "adhan.mp3", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Sound(tt.name, tt.sound, tt.fajr); got != tt.want {
+			t.Errorf("Sound(%q, %q, %q) = %q, want %q", tt.name, tt.sound, tt.fajr, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_Auto(t *testing.T) {
+	file := tempSound(t)
+	withPlayers(t, "paplay", "afplay")
+
+	cmd, err := Command("auto", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Path != "/usr/bin/afplay" || cmd.Args[len(cmd.Args)-1] != file {
+		t.Errorf("auto picked %v, want afplay (the first installed in Players order)", cmd.Args)
+	}
+}
+
+func TestCommand_Mpv(t *testing.T) {
+	file := tempSound(t)
+	withPlayers(t, "mpv")
+
+	cmd, err := Command("mpv", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/usr/bin/mpv", "--no-video", "--really-quiet", file}
+	if len(cmd.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", cmd.Args, want)
+	}
+	for i := range want {
+		if cmd.Args[i] != want[i] {
+			t.Errorf("Args = %v, want %v", cmd.Args, want)
+			break
+		}
+	}
+}
+
+func TestCommand_Errors(t *testing.T) {
+	file := tempSound(t)
+	withPlayers(t)
+
+	if _, err := Command("vlc", file); err == nil {
+		t.Error("Command(vlc) should error")
+	}
+	if _, err := Command("paplay", file); err == nil {
+		t.Error("Command(paplay) should error when paplay is not installed")
+	}
+	if _, err := Command("auto", file); err == nil {
+		t.Error("Command(auto) should error when no player is installed")
+	}
+
+	withPlayers(t, "mpv")
+	_, err := Command("mpv", filepath.Join(t.TempDir(), "missing.mp3"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Command with a missing file = %v, want ErrNotExist", err)
+	}
+}
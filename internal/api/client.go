@@ -1,124 +1,308 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
 const defaultBaseURL = "https://api.aladhan.com/v1"
 
+// DefaultTimeout is how long a request may take before NewClient's clients give up.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultRetries is how many times NewClient's clients retry a failed request.
+const DefaultRetries = 2
+
+// DefaultRetryBackoff is the delay before NewClient's clients first retry.
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// maxRetryAfter caps how long a 429 response's Retry-After may hold up a retry.
+// A longer wait gives up instead.
+const maxRetryAfter = 30 * time.Second
+
 // Client communicates with the Al Adhan prayer times API.
 type Client struct {
 	httpClient *http.Client
 	// BaseURL is the API base URL. Defaults to the Al Adhan API.
 	// Exported for testing with httptest.
 	BaseURL string
+	// Timeout bounds each attempt at a request, on top of any deadline of
+	// its context. Zero leaves requests to the context alone.
+	Timeout time.Duration
+	// Retries is how many times a request is retried after a 5xx or 429
+	// response or a dropped connection. Zero disables retrying.
+	Retries int
+	// RetryBackoff is the delay before the first retry. It doubles for each
+	// further retry, and each delay is jittered to a random fraction of it.
+	RetryBackoff time.Duration
+	// MethodSettings holds custom "fajr,maghrib,isha" angles (see
+	// CustomMethodSettings). When set, every request uses the custom
+	// method (99) in place of the method argument.
+	MethodSettings string
+	// Tune holds per-prayer minute offsets in the API's tune format (see
+	// TuneParam). When set, it is sent with every request.
+	Tune string
+	// LatitudeAdjustment is the latitudeAdjustmentMethod for high latitudes:
+	// 1 middle of the night, 2 one-seventh of the night, 3 angle-based.
+	// Zero leaves it to the API.
+	LatitudeAdjustment int
+	// MidnightMode is the midnightMode value: "0" for standard (midway
+	// from sunset to sunrise) or "1" for Jafari (midway from sunset to
+	// Fajr). Empty leaves it to the calculation method.
+	MidnightMode string
+	// Shafaq is the twilight Isha is timed by under the Moonsighting
+	// Committee method: "general", "ahmer" or "abyad". It is only sent
+	// with that method; empty leaves it to the API.
+	Shafaq string
+	// Timezone is the timezonestring value: an IANA name the timings are
+	// calculated in instead of the location's own. Empty lets the API
+	// pick the location's timezone.
+	Timezone string
+	// Observe, when set, is called with how long each request took,
+	// including its retries.
+	Observe func(time.Duration)
+}
+
+// CustomMethod is the Al Adhan method ID for user-supplied angles.
+const CustomMethod = 99
+
+// MoonsightingMethod is the Al Adhan method ID for the Moonsighting
+// Committee Worldwide, the only method that takes a shafaq.
+const MoonsightingMethod = 15
+
+// CustomMethodSettings formats angles as the Al Adhan methodSettings value.
+// Zero angles are sent as "null" so the API falls back to its own default.
+// It returns "" when no angle is set.
+func CustomMethodSettings(fajr, maghrib, isha float64) string {
+	if fajr == 0 && maghrib == 0 && isha == 0 {
+		return ""
+	}
+	angle := func(v float64) string {
+		if v == 0 {
+			return "null"
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return angle(fajr) + "," + angle(maghrib) + "," + angle(isha)
+}
+
+// TunePrayers lists the prayers the API's tune parameter adjusts, in the
+// order it expects them.
+var TunePrayers = []string{"Imsak", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Sunset", "Isha", "Midnight"}
+
+// TuneParam formats per-prayer minute offsets as the Al Adhan tune value.
+// Prayers without an offset are sent as 0. It returns "" when no offset is set.
+func TuneParam(offsets map[string]int) string {
+	set := false
+	values := make([]string, len(TunePrayers))
+	for i, name := range TunePrayers {
+		values[i] = strconv.Itoa(offsets[name])
+		if offsets[name] != 0 {
+			set = true
+		}
+	}
+	if !set {
+		return ""
+	}
+	return strings.Join(values, ",")
 }
 
 // NewClient creates a new API client with sensible defaults.
 func NewClient() *Client {
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		BaseURL: defaultBaseURL,
+		httpClient:   &http.Client{},
+		BaseURL:      defaultBaseURL,
+		Timeout:      DefaultTimeout,
+		Retries:      DefaultRetries,
+		RetryBackoff: DefaultRetryBackoff,
 	}
 }
 
 // FetchByCoordinates fetches prayer times for the given date and coordinates.
-func (c *Client) FetchByCoordinates(date time.Time, lat, lon float64, method, school int) (*Response, error) {
+func (c *Client) FetchByCoordinates(ctx context.Context, date time.Time, lat, lon float64, method, school int) (*Response, error) {
 	dateStr := date.Format("02-01-2006")
 	endpoint := fmt.Sprintf("%s/timings/%s", c.BaseURL, dateStr)
 
 	params := url.Values{}
 	params.Set("latitude", fmt.Sprintf("%f", lat))
 	params.Set("longitude", fmt.Sprintf("%f", lon))
-	if method >= 0 {
-		params.Set("method", fmt.Sprintf("%d", method))
-	}
-	if school >= 0 {
-		params.Set("school", fmt.Sprintf("%d", school))
-	}
+	c.setCalculation(params, method, school)
 
-	return c.doRequest(endpoint, params)
+	return c.doRequest(ctx, endpoint, params)
 }
 
 // FetchByCity fetches prayer times for the given date, city, and country.
-func (c *Client) FetchByCity(date time.Time, city, country string, method, school int) (*Response, error) {
+func (c *Client) FetchByCity(ctx context.Context, date time.Time, city, country string, method, school int) (*Response, error) {
 	dateStr := date.Format("02-01-2006")
 	endpoint := fmt.Sprintf("%s/timingsByCity/%s", c.BaseURL, dateStr)
 
 	params := url.Values{}
 	params.Set("city", city)
 	params.Set("country", country)
-	if method >= 0 {
-		params.Set("method", fmt.Sprintf("%d", method))
-	}
-	if school >= 0 {
-		params.Set("school", fmt.Sprintf("%d", school))
-	}
+	c.setCalculation(params, method, school)
 
-	return c.doRequest(endpoint, params)
+	return c.doRequest(ctx, endpoint, params)
 }
 
 // FetchCalendarByCoordinates fetches a full month of prayer times for the given coordinates.
 // year and month specify which calendar month to fetch.
-func (c *Client) FetchCalendarByCoordinates(year int, month int, lat, lon float64, method, school int) (*CalendarResponse, error) {
+func (c *Client) FetchCalendarByCoordinates(ctx context.Context, year int, month int, lat, lon float64, method, school int) (*CalendarResponse, error) {
 	endpoint := fmt.Sprintf("%s/calendar/%d/%d", c.BaseURL, year, month)
 
 	params := url.Values{}
 	params.Set("latitude", fmt.Sprintf("%f", lat))
 	params.Set("longitude", fmt.Sprintf("%f", lon))
-	if method >= 0 {
-		params.Set("method", fmt.Sprintf("%d", method))
-	}
-	if school >= 0 {
-		params.Set("school", fmt.Sprintf("%d", school))
-	}
+	c.setCalculation(params, method, school)
 
-	return c.doCalendarRequest(endpoint, params)
+	return c.doCalendarRequest(ctx, endpoint, params)
 }
 
 // FetchCalendarByCity fetches a full month of prayer times for the given city/country.
-func (c *Client) FetchCalendarByCity(year int, month int, city, country string, method, school int) (*CalendarResponse, error) {
+func (c *Client) FetchCalendarByCity(ctx context.Context, year int, month int, city, country string, method, school int) (*CalendarResponse, error) {
 	endpoint := fmt.Sprintf("%s/calendarByCity/%d/%d", c.BaseURL, year, month)
 
 	params := url.Values{}
 	params.Set("city", city)
 	params.Set("country", country)
+	c.setCalculation(params, method, school)
+
+	return c.doCalendarRequest(ctx, endpoint, params)
+}
+
+// FetchAnnualCalendarByCoordinates fetches prayer times for every day of year,
+// in a single request.
+func (c *Client) FetchAnnualCalendarByCoordinates(ctx context.Context, year int, lat, lon float64, method, school int) (*AnnualCalendarResponse, error) {
+	endpoint := fmt.Sprintf("%s/calendar/%d", c.BaseURL, year)
+
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%f", lat))
+	params.Set("longitude", fmt.Sprintf("%f", lon))
+	c.setCalculation(params, method, school)
+
+	return c.doAnnualRequest(ctx, endpoint, params)
+}
+
+// FetchAnnualCalendarByCity fetches prayer times for every day of year for a
+// city, in a single request.
+func (c *Client) FetchAnnualCalendarByCity(ctx context.Context, year int, city, country string, method, school int) (*AnnualCalendarResponse, error) {
+	endpoint := fmt.Sprintf("%s/calendarByCity/%d", c.BaseURL, year)
+
+	params := url.Values{}
+	params.Set("city", city)
+	params.Set("country", country)
+	c.setCalculation(params, method, school)
+
+	return c.doAnnualRequest(ctx, endpoint, params)
+}
+
+// ConvertToHijri converts a Gregorian date to its Hijri equivalent.
+func (c *Client) ConvertToHijri(ctx context.Context, date time.Time) (*DateResponse, error) {
+	endpoint := fmt.Sprintf("%s/gToH/%s", c.BaseURL, date.Format("02-01-2006"))
+	return c.doDateRequest(ctx, endpoint)
+}
+
+// ConvertToGregorian converts a Hijri date to its Gregorian equivalent.
+func (c *Client) ConvertToGregorian(ctx context.Context, day, month, year int) (*DateResponse, error) {
+	endpoint := fmt.Sprintf("%s/hToG/%02d-%02d-%04d", c.BaseURL, day, month, year)
+	return c.doDateRequest(ctx, endpoint)
+}
+
+// FetchHijriMonth fetches every day of the given Hijri month with its Gregorian equivalent.
+func (c *Client) FetchHijriMonth(ctx context.Context, month, year int) (*HijriCalendarResponse, error) {
+	endpoint := fmt.Sprintf("%s/hToGCalendar/%d/%d", c.BaseURL, month, year)
+
+	var apiResp HijriCalendarResponse
+	if err := c.getJSON(ctx, endpoint, url.Values{}, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Code != 200 {
+		return nil, fmt.Errorf("API error: code=%d status=%s", apiResp.Code, apiResp.Status)
+	}
+
+	return &apiResp, nil
+}
+
+// setCalculation adds the method, school, tune, latitude adjustment,
+// midnight mode, shafaq, and timezone parameters. Unset values are omitted
+// so the API picks its defaults.
+func (c *Client) setCalculation(params url.Values, method, school int) {
+	if c.MethodSettings != "" {
+		method = CustomMethod
+		params.Set("methodSettings", c.MethodSettings)
+	}
 	if method >= 0 {
 		params.Set("method", fmt.Sprintf("%d", method))
 	}
 	if school >= 0 {
 		params.Set("school", fmt.Sprintf("%d", school))
 	}
-
-	return c.doCalendarRequest(endpoint, params)
+	if c.Tune != "" {
+		params.Set("tune", c.Tune)
+	}
+	if c.LatitudeAdjustment > 0 {
+		params.Set("latitudeAdjustmentMethod", strconv.Itoa(c.LatitudeAdjustment))
+	}
+	if c.MidnightMode != "" {
+		params.Set("midnightMode", c.MidnightMode)
+	}
+	if c.Shafaq != "" && method == MoonsightingMethod {
+		params.Set("shafaq", c.Shafaq)
+	}
+	if c.Timezone != "" {
+		params.Set("timezonestring", c.Timezone)
+	}
 }
 
-func (c *Client) doRequest(endpoint string, params url.Values) (*Response, error) {
-	reqURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
-
-	resp, err := c.httpClient.Get(reqURL)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Values) (*Response, error) {
+	var apiResp Response
+	if err := c.getJSON(ctx, endpoint, params, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Code != 200 {
+		return nil, fmt.Errorf("API error: code=%d status=%s", apiResp.Code, apiResp.Status)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	return &apiResp, nil
+}
+
+func (c *Client) doCalendarRequest(ctx context.Context, endpoint string, params url.Values) (*CalendarResponse, error) {
+	var apiResp CalendarResponse
+	if err := c.getJSON(ctx, endpoint, params, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Code != 200 {
+		return nil, fmt.Errorf("API error: code=%d status=%s", apiResp.Code, apiResp.Status)
 	}
 
-	var apiResp Response
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	return &apiResp, nil
+}
+
+func (c *Client) doAnnualRequest(ctx context.Context, endpoint string, params url.Values) (*AnnualCalendarResponse, error) {
+	var apiResp AnnualCalendarResponse
+	if err := c.getJSON(ctx, endpoint, params, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Code != 200 {
+		return nil, fmt.Errorf("API error: code=%d status=%s", apiResp.Code, apiResp.Status)
 	}
 
+	return &apiResp, nil
+}
+
+func (c *Client) doDateRequest(ctx context.Context, endpoint string) (*DateResponse, error) {
+	var apiResp DateResponse
+	if err := c.getJSON(ctx, endpoint, url.Values{}, &apiResp); err != nil {
+		return nil, err
+	}
 	if apiResp.Code != 200 {
 		return nil, fmt.Errorf("API error: code=%d status=%s", apiResp.Code, apiResp.Status)
 	}
@@ -126,28 +310,109 @@ func (c *Client) doRequest(endpoint string, params url.Values) (*Response, error
 	return &apiResp, nil
 }
 
-func (c *Client) doCalendarRequest(endpoint string, params url.Values) (*CalendarResponse, error) {
-	reqURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+// getJSON performs a GET request and decodes the JSON body into v,
+// retrying transient failures (see Retries). Each attempt is abandoned when
+// ctx is done or c.Timeout passes.
+func (c *Client) getJSON(ctx context.Context, endpoint string, params url.Values, v any) error {
+	reqURL := endpoint
+	if len(params) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", endpoint, params.Encode())
+	}
+
+	if c.Observe != nil {
+		defer func(start time.Time) { c.Observe(time.Since(start)) }(time.Now())
+	}
+
+	backoff := c.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.getJSONOnce(ctx, reqURL, v)
+		if err == nil || attempt >= c.Retries || retryAfter < 0 || ctx.Err() != nil {
+			return err
+		}
+
+		// Full jitter spreads out clients that failed together.
+		delay := time.Duration(0)
+		if backoff > 0 {
+			delay = rand.N(backoff) + 1
+		}
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
 
-	resp, err := c.httpClient.Get(reqURL)
+// getJSONOnce makes one attempt at getJSON. On failure it also returns how
+// long the server asked to wait before a retry, or -1 when the failure
+// isn't worth retrying.
+func (c *Client) getJSONOnce(ctx context.Context, reqURL string, v any) (time.Duration, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return -1, fmt.Errorf("API request failed: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+		if !droppedConnection(err) {
+			return -1, fmt.Errorf("API request failed: %w", err)
+		}
+		return 0, fmt.Errorf("API request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		err := fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			if wait > maxRetryAfter {
+				return -1, err
+			}
+			return wait, err
+		case resp.StatusCode >= 500:
+			return 0, err
+		}
+		return -1, err
 	}
 
-	var apiResp CalendarResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	if err := decodeBody(resp.Body, v); err != nil {
+		return -1, fmt.Errorf("failed to decode API response: %w", err)
 	}
 
-	if apiResp.Code != 200 {
-		return nil, fmt.Errorf("API error: code=%d status=%s", apiResp.Code, apiResp.Status)
-	}
+	return 0, nil
+}
 
-	return &apiResp, nil
+// droppedConnection reports whether err means the server reset or closed
+// the connection. Timeouts and unreachable hosts are not retried: they
+// would only make an offline command slower to fail.
+func droppedConnection(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Unreachable reports whether err means the API couldn't be reached: the
+// host couldn't be resolved or connected to, or didn't answer in time. An
+// error status from the API, a bad response or a canceled request is not.
+func Unreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// retryAfter parses a Retry-After header given in seconds. HTTP dates and
+// missing or malformed values yield zero.
+func retryAfter(header string) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
 }
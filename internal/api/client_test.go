@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -52,6 +54,12 @@ func TestNewClient(t *testing.T) {
 	if c.BaseURL != defaultBaseURL {
 		t.Errorf("BaseURL = %q, want %q", c.BaseURL, defaultBaseURL)
 	}
+	if c.Timeout != DefaultTimeout {
+		t.Errorf("Timeout = %v, want %v", c.Timeout, DefaultTimeout)
+	}
+	if c.Retries != DefaultRetries || c.RetryBackoff != DefaultRetryBackoff {
+		t.Errorf("Retries, RetryBackoff = %d, %v, want %d, %v", c.Retries, c.RetryBackoff, DefaultRetries, DefaultRetryBackoff)
+	}
 }
 
 func TestFetchByCoordinates_Success(t *testing.T) {
@@ -85,7 +93,7 @@ func TestFetchByCoordinates_Success(t *testing.T) {
 	c.BaseURL = server.URL
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	got, err := c.FetchByCoordinates(date, 51.5074, -0.1278, 2, 1)
+	got, err := c.FetchByCoordinates(context.Background(), date, 51.5074, -0.1278, 2, 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -117,7 +125,7 @@ func TestFetchByCoordinates_NoMethodOrSchool(t *testing.T) {
 	c.BaseURL = server.URL
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	_, err := c.FetchByCoordinates(date, 51.5074, -0.1278, -1, -1)
+	_, err := c.FetchByCoordinates(context.Background(), date, 51.5074, -0.1278, -1, -1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -145,7 +153,7 @@ func TestFetchByCity_Success(t *testing.T) {
 	c.BaseURL = server.URL
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	got, err := c.FetchByCity(date, "London", "UK", -1, -1)
+	got, err := c.FetchByCity(context.Background(), date, "London", "UK", -1, -1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -154,6 +162,170 @@ func TestFetchByCity_Success(t *testing.T) {
 	}
 }
 
+func TestFetchByCoordinates_CustomMethod(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("method") != "99" {
+			t.Errorf("method = %q, want %q", q.Get("method"), "99")
+		}
+		if q.Get("methodSettings") != "18.5,null,17" {
+			t.Errorf("methodSettings = %q, want %q", q.Get("methodSettings"), "18.5,null,17")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.MethodSettings = CustomMethodSettings(18.5, 0, 17)
+
+	// The custom method replaces any method passed in.
+	if _, err := c.FetchByCoordinates(context.Background(), time.Now(), 51.5074, -0.1278, 2, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCustomMethodSettings(t *testing.T) {
+	tests := []struct {
+		fajr, maghrib, isha float64
+		want                string
+	}{
+		{0, 0, 0, ""},
+		{18, 0, 17, "18,null,17"},
+		{19.5, 4, 0, "19.5,4,null"},
+	}
+	for _, tt := range tests {
+		got := CustomMethodSettings(tt.fajr, tt.maghrib, tt.isha)
+		if got != tt.want {
+			t.Errorf("CustomMethodSettings(%v, %v, %v) = %q, want %q", tt.fajr, tt.maghrib, tt.isha, got, tt.want)
+		}
+	}
+}
+
+func TestFetchByCoordinates_Tune(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("tune"); got != "0,3,0,0,-2,0,0,0,0" {
+			t.Errorf("tune = %q, want %q", got, "0,3,0,0,-2,0,0,0,0")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.Tune = TuneParam(map[string]int{"Fajr": 3, "Asr": -2})
+
+	if _, err := c.FetchByCoordinates(context.Background(), time.Now(), 51.5074, -0.1278, 2, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchByCoordinates_LatitudeAdjustment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("latitudeAdjustmentMethod"); got != "3" {
+			t.Errorf("latitudeAdjustmentMethod = %q, want 3", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.LatitudeAdjustment = 3
+
+	if _, err := c.FetchByCoordinates(context.Background(), time.Now(), 59.9139, 10.7522, 3, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchByCoordinates_MidnightMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("midnightMode"); got != "0" {
+			t.Errorf("midnightMode = %q, want 0", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleResponse())
+	}))
+	defer server.Close()
+
+	// Standard is sent explicitly: Jafari-method requests default to Jafari midnight.
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.MidnightMode = "0"
+
+	if _, err := c.FetchByCoordinates(context.Background(), time.Now(), 35.6892, 51.3890, 0, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchByCoordinates_Shafaq(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query().Get("shafaq"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.Shafaq = "ahmer"
+
+	for _, method := range []int{MoonsightingMethod, 2} {
+		if _, err := c.FetchByCoordinates(context.Background(), time.Now(), 51.5074, -0.1278, method, -1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Only the Moonsighting Committee method takes a shafaq.
+	if len(got) != 2 || got[0] != "ahmer" || got[1] != "" {
+		t.Errorf("shafaq params = %q, want [\"ahmer\" \"\"]", got)
+	}
+}
+
+func TestFetchByCity_Timezone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("timezonestring"); got != "America/New_York" {
+			t.Errorf("timezonestring = %q, want America/New_York", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.Timezone = "America/New_York"
+
+	if _, err := c.FetchByCity(context.Background(), time.Now(), "London", "UK", 2, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTuneParam(t *testing.T) {
+	tests := []struct {
+		offsets map[string]int
+		want    string
+	}{
+		{nil, ""},
+		{map[string]int{"Fajr": 0}, ""},
+		{map[string]int{"Imsak": -5, "Midnight": 10}, "-5,0,0,0,0,0,0,0,10"},
+	}
+	for _, tt := range tests {
+		if got := TuneParam(tt.offsets); got != tt.want {
+			t.Errorf("TuneParam(%v) = %q, want %q", tt.offsets, got, tt.want)
+		}
+	}
+}
+
 func TestFetchByCoordinates_HTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
@@ -164,7 +336,7 @@ func TestFetchByCoordinates_HTTPError(t *testing.T) {
 	c.BaseURL = server.URL
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	_, err := c.FetchByCoordinates(date, 51.5, -0.1, -1, -1)
+	_, err := c.FetchByCoordinates(context.Background(), date, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for HTTP 503, got nil")
 	}
@@ -184,7 +356,7 @@ func TestFetchByCoordinates_InvalidJSON(t *testing.T) {
 	c.BaseURL = server.URL
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	_, err := c.FetchByCoordinates(date, 51.5, -0.1, -1, -1)
+	_, err := c.FetchByCoordinates(context.Background(), date, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for invalid JSON, got nil")
 	}
@@ -205,7 +377,7 @@ func TestFetchByCoordinates_APIErrorCode(t *testing.T) {
 	c.BaseURL = server.URL
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	_, err := c.FetchByCoordinates(date, 51.5, -0.1, -1, -1)
+	_, err := c.FetchByCoordinates(context.Background(), date, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for API code 400, got nil")
 	}
@@ -219,7 +391,7 @@ func TestFetchByCoordinates_ConnectionRefused(t *testing.T) {
 	c.BaseURL = "http://127.0.0.1:1" // nothing listening
 
 	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-	_, err := c.FetchByCoordinates(date, 51.5, -0.1, -1, -1)
+	_, err := c.FetchByCoordinates(context.Background(), date, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for connection refused, got nil")
 	}
@@ -301,7 +473,7 @@ func TestFetchCalendarByCoordinates_Success(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = server.URL
 
-	got, err := c.FetchCalendarByCoordinates(2026, 2, 51.5074, -0.1278, 2, 1)
+	got, err := c.FetchCalendarByCoordinates(context.Background(), 2026, 2, 51.5074, -0.1278, 2, 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -331,7 +503,7 @@ func TestFetchCalendarByCoordinates_NoMethodOrSchool(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = server.URL
 
-	_, err := c.FetchCalendarByCoordinates(2026, 2, 51.5074, -0.1278, -1, -1)
+	_, err := c.FetchCalendarByCoordinates(context.Background(), 2026, 2, 51.5074, -0.1278, -1, -1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -358,7 +530,7 @@ func TestFetchCalendarByCity_Success(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = server.URL
 
-	got, err := c.FetchCalendarByCity(2026, 3, "London", "UK", -1, -1)
+	got, err := c.FetchCalendarByCity(context.Background(), 2026, 3, "London", "UK", -1, -1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -367,6 +539,96 @@ func TestFetchCalendarByCity_Success(t *testing.T) {
 	}
 }
 
+// sampleAnnualResponse returns a year of calendar data keyed by month number.
+func sampleAnnualResponse() AnnualCalendarResponse {
+	data := make(map[string][]Data)
+	for m := 1; m <= 12; m++ {
+		days := time.Date(2026, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		data[fmt.Sprintf("%d", m)] = sampleCalendarResponse(days).Data
+	}
+	return AnnualCalendarResponse{Code: 200, Status: "OK", Data: data}
+}
+
+func TestFetchAnnualCalendarByCoordinates_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendar/2026" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("latitude") == "" || q.Get("longitude") == "" {
+			t.Error("missing coordinate params")
+		}
+		if q.Get("method") != "4" {
+			t.Errorf("method = %q, want %q", q.Get("method"), "4")
+		}
+		if q.Get("school") != "" {
+			t.Errorf("school should not be set, got %q", q.Get("school"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleAnnualResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	got, err := c.FetchAnnualCalendarByCoordinates(context.Background(), 2026, 21.4225, 39.8262, 4, -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Month(2)) != 28 {
+		t.Errorf("February has %d days, want 28", len(got.Month(2)))
+	}
+	if len(got.Month(12)) != 31 {
+		t.Errorf("December has %d days, want 31", len(got.Month(12)))
+	}
+	if got.Month(13) != nil {
+		t.Error("Month(13) should be nil")
+	}
+}
+
+func TestFetchAnnualCalendarByCity_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendarByCity/2026" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("city") != "London" || q.Get("country") != "UK" {
+			t.Errorf("city/country = %q/%q, want London/UK", q.Get("city"), q.Get("country"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sampleAnnualResponse())
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	got, err := c.FetchAnnualCalendarByCity(context.Background(), 2026, "London", "UK", -1, -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Data) != 12 {
+		t.Errorf("got %d months, want 12", len(got.Data))
+	}
+}
+
+func TestFetchAnnualCalendar_APIErrorCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"code": 400, "status": "Bad Request", "data": map[string]any{}})
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	if _, err := c.FetchAnnualCalendarByCoordinates(context.Background(), 2026, 51.5, -0.1, -1, -1); err == nil {
+		t.Error("expected error for API error code")
+	}
+}
+
 func TestFetchCalendarByCoordinates_HTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
@@ -376,7 +638,7 @@ func TestFetchCalendarByCoordinates_HTTPError(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = server.URL
 
-	_, err := c.FetchCalendarByCoordinates(2026, 2, 51.5, -0.1, -1, -1)
+	_, err := c.FetchCalendarByCoordinates(context.Background(), 2026, 2, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for HTTP 503, got nil")
 	}
@@ -395,7 +657,7 @@ func TestFetchCalendarByCoordinates_InvalidJSON(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = server.URL
 
-	_, err := c.FetchCalendarByCoordinates(2026, 2, 51.5, -0.1, -1, -1)
+	_, err := c.FetchCalendarByCoordinates(context.Background(), 2026, 2, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for invalid JSON, got nil")
 	}
@@ -415,7 +677,7 @@ func TestFetchCalendarByCoordinates_APIErrorCode(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = server.URL
 
-	_, err := c.FetchCalendarByCoordinates(2026, 2, 51.5, -0.1, -1, -1)
+	_, err := c.FetchCalendarByCoordinates(context.Background(), 2026, 2, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for API code 400, got nil")
 	}
@@ -428,7 +690,7 @@ func TestFetchCalendarByCoordinates_ConnectionRefused(t *testing.T) {
 	c := NewClient()
 	c.BaseURL = "http://127.0.0.1:1" // nothing listening
 
-	_, err := c.FetchCalendarByCoordinates(2026, 2, 51.5, -0.1, -1, -1)
+	_, err := c.FetchCalendarByCoordinates(context.Background(), 2026, 2, 51.5, -0.1, -1, -1)
 	if err == nil {
 		t.Fatal("expected error for connection refused, got nil")
 	}
@@ -448,7 +710,7 @@ func TestFetchByCoordinates_DateFormat(t *testing.T) {
 
 	// Test that the date is formatted as DD-MM-YYYY.
 	date := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
-	_, err := c.FetchByCoordinates(date, 0, 0, -1, -1)
+	_, err := c.FetchByCoordinates(context.Background(), date, 0, 0, -1, -1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -456,3 +718,214 @@ func TestFetchByCoordinates_DateFormat(t *testing.T) {
 		t.Errorf("date format wrong in path: %s (expected DD-MM-YYYY)", capturedPath)
 	}
 }
+
+func sampleDateInfo(hDay, hMonth int, gDate string) DateInfo {
+	return DateInfo{
+		Hijri: HijriDate{
+			Date:  fmt.Sprintf("%02d-%02d-1447", hDay, hMonth),
+			Day:   fmt.Sprintf("%d", hDay),
+			Month: HijriMonth{Number: hMonth, En: "Ramaḍān"},
+			Year:  "1447",
+		},
+		Gregorian: GregorianDate{Date: gDate},
+	}
+}
+
+func TestConvertToHijri_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gToH/01-03-2026" {
+			t.Errorf("path = %s, want /gToH/01-03-2026", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DateResponse{Code: 200, Status: "OK", Data: sampleDateInfo(12, 9, "01-03-2026")})
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	result, err := c.ConvertToHijri(context.Background(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data.Hijri.Date != "12-09-1447" {
+		t.Errorf("Hijri.Date = %q, want 12-09-1447", result.Data.Hijri.Date)
+	}
+}
+
+func TestConvertToGregorian_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hToG/01-09-1447" {
+			t.Errorf("path = %s, want /hToG/01-09-1447", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DateResponse{Code: 200, Status: "OK", Data: sampleDateInfo(1, 9, "18-02-2026")})
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	result, err := c.ConvertToGregorian(context.Background(), 1, 9, 1447)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data.Gregorian.Date != "18-02-2026" {
+		t.Errorf("Gregorian.Date = %q, want 18-02-2026", result.Data.Gregorian.Date)
+	}
+}
+
+func TestFetchHijriMonth_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hToGCalendar/9/1447" {
+			t.Errorf("path = %s, want /hToGCalendar/9/1447", r.URL.Path)
+		}
+		resp := HijriCalendarResponse{Code: 200, Status: "OK"}
+		for d := 1; d <= 29; d++ {
+			resp.Data = append(resp.Data, sampleDateInfo(d, 9, "18-02-2026"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	result, err := c.FetchHijriMonth(context.Background(), 9, 1447)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Data) != 29 {
+		t.Errorf("got %d days, want 29", len(result.Data))
+	}
+}
+
+func TestConvertToHijri_APIErrorCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DateResponse{Code: 400, Status: "Invalid date"})
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	if _, err := c.ConvertToHijri(context.Background(), time.Now()); err == nil {
+		t.Fatal("expected error for non-200 API code, got nil")
+	}
+}
+
+// TestUnreachable checks that only failing to reach the API counts as
+// unreachable, not an error status or a canceled request.
+func TestUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	if _, err := c.ConvertToHijri(context.Background(), time.Now()); err == nil || Unreachable(err) {
+		t.Errorf("HTTP 400: Unreachable(%v) = true, want false", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ConvertToHijri(ctx, time.Now()); err == nil || Unreachable(err) {
+		t.Errorf("canceled: Unreachable(%v) = true, want false", err)
+	}
+
+	c.BaseURL = "http://127.0.0.1:1"
+	if _, err := c.ConvertToHijri(context.Background(), time.Now()); !Unreachable(err) {
+		t.Errorf("connection refused: Unreachable(%v) = false, want true", err)
+	}
+}
+
+func TestFetch_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.Timeout = 50 * time.Millisecond
+
+	_, err := c.FetchByCoordinates(context.Background(), time.Now(), 51.5, -0.1, -1, -1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want a deadline exceeded", err)
+	}
+}
+
+func TestFetch_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	c.Timeout = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := c.FetchCalendarByCity(ctx, 2026, 2, "London", "UK", -1, -1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want canceled", err)
+	}
+}
+
+func TestFetch_Retries(t *testing.T) {
+	tests := []struct {
+		name     string
+		fail     func(w http.ResponseWriter)
+		failures int
+		wantErr  bool
+		wantHits int
+	}{
+		{"5xx then success", func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) }, 2, false, 3},
+		{"429 then success", func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) }, 1, false, 2},
+		{"gives up after retries", func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }, 5, true, 3},
+		{"no retry on 4xx", func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadRequest) }, 1, true, 1},
+		{"no retry on long Retry-After", func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, 1, true, 1},
+		{"connection reset", func(w http.ResponseWriter) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}, 1, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				if hits <= tt.failures {
+					tt.fail(w)
+					return
+				}
+				json.NewEncoder(w).Encode(sampleResponse())
+			}))
+			defer server.Close()
+
+			c := NewClient()
+			c.BaseURL = server.URL
+			c.RetryBackoff = time.Millisecond
+
+			_, err := c.FetchByCoordinates(context.Background(), time.Now(), 51.5, -0.1, -1, -1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hits != tt.wantHits {
+				t.Errorf("server hit %d times, want %d", hits, tt.wantHits)
+			}
+		})
+	}
+}
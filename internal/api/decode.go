@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// streamDecoder is implemented by responses that decode themselves from a
+// stream of tokens rather than as one value. Decoding a calendar's days one
+// at a time lets the decoder reuse a read buffer the size of a day for the
+// whole response, where decoding it as one value grows the buffer to hold
+// all of it: a year's calendar is over a megabyte of JSON.
+type streamDecoder interface {
+	decodeStream(dec *json.Decoder) error
+}
+
+// decodeBody decodes the JSON body r into v, streaming it when v is a
+// streamDecoder.
+func decodeBody(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	if s, ok := v.(streamDecoder); ok {
+		return s.decodeStream(dec)
+	}
+	return dec.Decode(v)
+}
+
+// decodeObject reads a JSON object from dec, calling field with each key
+// to decode that key's value.
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected an object, got %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := field(tok.(string)); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // the closing brace
+	return err
+}
+
+// decodeArray reads a JSON array, or null, from dec, calling elem to decode
+// each element.
+func decodeArray(dec *json.Decoder, elem func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected an array, got %v", tok)
+	}
+	for dec.More() {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // the closing bracket
+	return err
+}
+
+// skipValue discards the next value from dec.
+func skipValue(dec *json.Decoder) error {
+	var skip json.RawMessage
+	return dec.Decode(&skip)
+}
+
+// daysInMonth is the most days a calendar month holds, used to size slices.
+const daysInMonth = 31
+
+// decodeDays decodes an array of days, interning their repeated strings.
+func decodeDays(dec *json.Decoder, in interner) ([]Data, error) {
+	var days []Data
+	err := decodeArray(dec, func() error {
+		if days == nil {
+			days = make([]Data, 0, daysInMonth)
+		}
+		days = append(days, Data{})
+		d := &days[len(days)-1]
+		if err := dec.Decode(d); err != nil {
+			return err
+		}
+		in.data(d)
+		return nil
+	})
+	return days, err
+}
+
+func (r *CalendarResponse) decodeStream(dec *json.Decoder) error {
+	in := interner{}
+	return decodeObject(dec, func(key string) error {
+		switch key {
+		case "code":
+			return dec.Decode(&r.Code)
+		case "status":
+			return dec.Decode(&r.Status)
+		case "data":
+			days, err := decodeDays(dec, in)
+			r.Data = days
+			return err
+		}
+		return skipValue(dec)
+	})
+}
+
+func (r *AnnualCalendarResponse) decodeStream(dec *json.Decoder) error {
+	in := interner{}
+	return decodeObject(dec, func(key string) error {
+		switch key {
+		case "code":
+			return dec.Decode(&r.Code)
+		case "status":
+			return dec.Decode(&r.Status)
+		case "data":
+			r.Data = make(map[string][]Data, 12)
+			return decodeObject(dec, func(month string) error {
+				days, err := decodeDays(dec, in)
+				r.Data[month] = days
+				return err
+			})
+		}
+		return skipValue(dec)
+	})
+}
+
+func (r *HijriCalendarResponse) decodeStream(dec *json.Decoder) error {
+	in := interner{}
+	return decodeObject(dec, func(key string) error {
+		switch key {
+		case "code":
+			return dec.Decode(&r.Code)
+		case "status":
+			return dec.Decode(&r.Status)
+		case "data":
+			return decodeArray(dec, func() error {
+				if r.Data == nil {
+					r.Data = make([]DateInfo, 0, daysInMonth)
+				}
+				r.Data = append(r.Data, DateInfo{})
+				d := &r.Data[len(r.Data)-1]
+				if err := dec.Decode(d); err != nil {
+					return err
+				}
+				in.date(d)
+				return nil
+			})
+		}
+		return skipValue(dec)
+	})
+}
+
+// interner makes equal strings share one copy. Every day of a calendar
+// repeats its timezone, method, month names and so on; interning them keeps
+// a year of days held in memory to one copy of each.
+type interner map[string]string
+
+func (in interner) intern(s *string) {
+	if v, ok := in[*s]; ok {
+		*s = v
+		return
+	}
+	in[*s] = *s
+}
+
+func (in interner) data(d *Data) {
+	in.date(&d.Date)
+	in.intern(&d.Meta.Timezone)
+	in.intern(&d.Meta.Method.Name)
+	in.intern(&d.Meta.School)
+}
+
+func (in interner) date(d *DateInfo) {
+	h, g := &d.Hijri, &d.Gregorian
+	for _, s := range []*string{
+		&h.Month.En, &h.Month.Ar, &h.Year, &h.Designation.Abbreviated, &h.Designation.Expanded,
+		&g.Weekday.En, &g.Month.En, &g.Year,
+	} {
+		in.intern(s)
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// calendarDays returns n days as the calendar endpoint sends them, with
+// their Hijri and Gregorian dates filled in.
+func calendarDays(n int) []Data {
+	days := make([]Data, n)
+	for i := range days {
+		days[i] = Data{
+			Timings: Timings{
+				Fajr: fmt.Sprintf("05:%02d (+03)", 30-i%30), Sunrise: "06:50 (+03)", Dhuhr: "12:30 (+03)",
+				Asr: "15:45 (+03)", Sunset: "18:10 (+03)", Maghrib: "18:10 (+03)", Isha: "19:40 (+03)",
+				Imsak: "05:20 (+03)", Midnight: "00:30 (+03)", Firstthird: "22:20 (+03)", Lastthird: "02:40 (+03)",
+			},
+			Date: DateInfo{
+				Readable:  fmt.Sprintf("%02d Mar 2026", i+1),
+				Timestamp: fmt.Sprint(1772323200 + i*86400),
+				Hijri: HijriDate{
+					Date:        fmt.Sprintf("%02d-09-1447", i+1),
+					Day:         fmt.Sprint(i + 1),
+					Month:       HijriMonth{Number: 9, En: "Ramaḍān", Ar: "رَمَضان"},
+					Year:        "1447",
+					Designation: HijriDesignation{Abbreviated: "AH", Expanded: "Anno Hegirae"},
+				},
+				Gregorian: GregorianDate{
+					Date:    fmt.Sprintf("%02d-03-2026", i+1),
+					Day:     fmt.Sprintf("%02d", i+1),
+					Weekday: GregorianDay{En: "Sunday"},
+					Month:   GregorianMonth{Number: 3, En: "March"},
+					Year:    "2026",
+				},
+			},
+			Meta: Meta{
+				Latitude: 21.4225, Longitude: 39.8262, Timezone: "Asia/Riyadh",
+				Method: MethodInfo{ID: 4, Name: "Umm Al-Qura University, Makkah"}, School: "STANDARD",
+			},
+		}
+	}
+	return days
+}
+
+func mustJSON(t testing.TB, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecodeBody_Calendar(t *testing.T) {
+	want := CalendarResponse{Code: 200, Status: "OK", Data: calendarDays(31)}
+	// Fields the types don't know are skipped, wherever they are.
+	body := strings.Replace(string(mustJSON(t, want)), `{"code"`, `{"extra":{"a":[1,{"b":null}]},"code"`, 1)
+	body = strings.Replace(body, `"meta":{`, `"meta":{"offset":{"Fajr":0},`, 1)
+
+	var got CalendarResponse
+	if err := decodeBody(strings.NewReader(body), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeBody() = %+v, want %+v", got, want)
+	}
+
+	// Repeated strings share one copy.
+	if unsafe.StringData(got.Data[0].Meta.Timezone) != unsafe.StringData(got.Data[30].Meta.Timezone) {
+		t.Error("the days' timezones weren't interned")
+	}
+}
+
+func TestDecodeBody_Annual(t *testing.T) {
+	want := AnnualCalendarResponse{Code: 200, Status: "OK", Data: map[string][]Data{}}
+	for m := 1; m <= 12; m++ {
+		want.Data[fmt.Sprint(m)] = calendarDays(28 + m%4)
+	}
+	var got AnnualCalendarResponse
+	if err := decodeBody(bytes.NewReader(mustJSON(t, want)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("decodeBody() of an annual calendar doesn't match json.Unmarshal")
+	}
+}
+
+func TestDecodeBody_HijriCalendar(t *testing.T) {
+	want := HijriCalendarResponse{Code: 200, Status: "OK"}
+	for _, d := range calendarDays(29) {
+		want.Data = append(want.Data, d.Date)
+	}
+	var got HijriCalendarResponse
+	if err := decodeBody(bytes.NewReader(mustJSON(t, want)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("decodeBody() of a Hijri calendar doesn't match json.Unmarshal")
+	}
+}
+
+func TestDecodeBody_Errors(t *testing.T) {
+	var got CalendarResponse
+	if err := decodeBody(strings.NewReader(`{"code":200,"status":"OK","data":null}`), &got); err != nil || got.Data != nil {
+		t.Errorf("decodeBody() with null data = %v, %v; want no days", got.Data, err)
+	}
+	for _, body := range []string{
+		`{"code":400,"status":"BAD_REQUEST","data":"Invalid date"}`,
+		`{"code":200,"status":"OK","data":[{"timings":`,
+		`[]`,
+		``,
+	} {
+		var got CalendarResponse
+		if err := decodeBody(strings.NewReader(body), &got); err == nil {
+			t.Errorf("decodeBody(%q) expected error", body)
+		}
+	}
+}
+
+func benchmarkDecode(b *testing.B, body []byte, v func() any, decode func([]byte, any) error) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	for range b.N {
+		if err := decode(body, v()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func streamDecode(body []byte, v any) error {
+	return decodeBody(bytes.NewReader(body), v)
+}
+
+func plainDecode(body []byte, v any) error {
+	return json.NewDecoder(bytes.NewReader(body)).Decode(v)
+}
+
+// The _Decode benchmarks decode the same bodies as one value, as before
+// the streaming decoder, for comparison.
+
+func BenchmarkDecodeCalendar(b *testing.B) {
+	body := mustJSON(b, CalendarResponse{Code: 200, Status: "OK", Data: calendarDays(31)})
+	benchmarkDecode(b, body, func() any { return new(CalendarResponse) }, streamDecode)
+}
+
+func BenchmarkDecodeCalendar_Decode(b *testing.B) {
+	body := mustJSON(b, CalendarResponse{Code: 200, Status: "OK", Data: calendarDays(31)})
+	benchmarkDecode(b, body, func() any { return new(CalendarResponse) }, plainDecode)
+}
+
+func annualBody(b *testing.B) []byte {
+	resp := AnnualCalendarResponse{Code: 200, Status: "OK", Data: map[string][]Data{}}
+	for m := 1; m <= 12; m++ {
+		resp.Data[fmt.Sprint(m)] = calendarDays(30)
+	}
+	return mustJSON(b, resp)
+}
+
+func BenchmarkDecodeAnnual(b *testing.B) {
+	benchmarkDecode(b, annualBody(b), func() any { return new(AnnualCalendarResponse) }, streamDecode)
+}
+
+func BenchmarkDecodeAnnual_Decode(b *testing.B) {
+	benchmarkDecode(b, annualBody(b), func() any { return new(AnnualCalendarResponse) }, plainDecode)
+}
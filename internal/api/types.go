@@ -1,5 +1,7 @@
 package api
 
+import "strconv"
+
 // Response represents the top-level Al Adhan API response.
 type Response struct {
 	Code   int    `json:"code"`
@@ -45,6 +47,7 @@ type HijriDate struct {
 	Month       HijriMonth       `json:"month"`
 	Year        string           `json:"year"`
 	Designation HijriDesignation `json:"designation"`
+	Holidays    []string         `json:"holidays,omitempty"`
 }
 
 // HijriMonth represents the month in the Hijri calendar.
@@ -114,3 +117,31 @@ type CalendarResponse struct {
 	Status string `json:"status"`
 	Data   []Data `json:"data"`
 }
+
+// AnnualCalendarResponse represents the Al Adhan calendar response for a whole
+// year. Data is keyed by month number ("1" through "12").
+type AnnualCalendarResponse struct {
+	Code   int               `json:"code"`
+	Status string            `json:"status"`
+	Data   map[string][]Data `json:"data"`
+}
+
+// Month returns the daily data for month (1-12), or nil if it is missing.
+func (r *AnnualCalendarResponse) Month(month int) []Data {
+	return r.Data[strconv.Itoa(month)]
+}
+
+// DateResponse represents the Al Adhan date conversion (gToH/hToG) response.
+type DateResponse struct {
+	Code   int      `json:"code"`
+	Status string   `json:"status"`
+	Data   DateInfo `json:"data"`
+}
+
+// HijriCalendarResponse represents the Al Adhan hToGCalendar response:
+// one entry per day of a Hijri month.
+type HijriCalendarResponse struct {
+	Code   int        `json:"code"`
+	Status string     `json:"status"`
+	Data   []DateInfo `json:"data"`
+}
@@ -0,0 +1,288 @@
+// Package archive keeps a permanent, append-only history of fetched prayer times.
+//
+// Unlike the working cache, archived records are never overwritten: a new record
+// is appended only when the API returns different timings for the same day,
+// location, method, and school. This makes it possible to audit whether upstream
+// calculations changed over time.
+//
+// Records are stored as JSON lines, one file per day, under
+// ~/.local/share/prayer-times/archive/ (respects $XDG_DATA_HOME).
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+const dateLayout = "2006-01-02"
+
+// Archive is a directory of per-day record files.
+type Archive struct {
+	dir string
+}
+
+// Record is a single archived fetch of one day's timings.
+type Record struct {
+	Date      string      `json:"date"` // YYYY-MM-DD
+	FetchedAt time.Time   `json:"fetched_at"`
+	Latitude  float64     `json:"latitude,omitempty"`
+	Longitude float64     `json:"longitude,omitempty"`
+	City      string      `json:"city,omitempty"`
+	Country   string      `json:"country,omitempty"`
+	Method    int         `json:"method"`
+	School    int         `json:"school"`
+	Settings  string      `json:"settings,omitempty"`            // custom method angles, if any
+	Tune      string      `json:"tune,omitempty"`                // per-prayer minute offsets, if any
+	LatAdjust int         `json:"latitude_adjustment,omitempty"` // high-latitude rule, if any
+	Midnight  string      `json:"midnight_mode,omitempty"`       // midnight mode, if set
+	Shafaq    string      `json:"shafaq,omitempty"`              // shafaq, if set
+	TZ        string      `json:"timezone_override,omitempty"`   // requested timezone, if overridden
+	Timezone  string      `json:"timezone,omitempty"`
+	Timings   api.Timings `json:"timings"`
+}
+
+// Key identifies the location and calculation parameters of a record.
+// Records with the same date and key are versions of the same schedule.
+func (r Record) Key() string {
+	key := fmt.Sprintf("%.6f|%.6f|%s|%s|%d|%d", r.Latitude, r.Longitude, r.City, r.Country, r.Method, r.School)
+	if r.Settings != "" {
+		key += "|" + r.Settings
+	}
+	if r.Tune != "" {
+		key += "|tune=" + r.Tune
+	}
+	if r.LatAdjust > 0 {
+		key += fmt.Sprintf("|latadj=%d", r.LatAdjust)
+	}
+	if r.Midnight != "" {
+		key += "|midnight=" + r.Midnight
+	}
+	if r.Shafaq != "" {
+		key += "|shafaq=" + r.Shafaq
+	}
+	if r.TZ != "" {
+		key += "|tz=" + r.TZ
+	}
+	return key
+}
+
+// Label returns a human-readable description of the record's location and method.
+func (r Record) Label() string {
+	loc := fmt.Sprintf("%.4f, %.4f", r.Latitude, r.Longitude)
+	if r.City != "" {
+		loc = r.City + ", " + r.Country
+	}
+	calc := fmt.Sprintf("method %d", r.Method)
+	if r.Settings != "" {
+		calc = "custom angles " + r.Settings
+	}
+	if r.Tune != "" {
+		calc += ", tune " + r.Tune
+	}
+	if r.LatAdjust > 0 {
+		calc += fmt.Sprintf(", latitude adjustment %d", r.LatAdjust)
+	}
+	if r.Midnight != "" {
+		calc += ", midnight mode " + r.Midnight
+	}
+	if r.Shafaq != "" {
+		calc += ", shafaq " + r.Shafaq
+	}
+	if r.TZ != "" {
+		calc += ", timezone " + r.TZ
+	}
+	return fmt.Sprintf("%s (%s, school %d)", loc, calc, r.School)
+}
+
+// DefaultDir returns the default archive directory.
+// It respects $XDG_DATA_HOME if set, otherwise uses ~/.local/share/.
+func DefaultDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot determine home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "prayer-times", "archive"), nil
+}
+
+// New creates an Archive rooted at dir, creating it if needed.
+// If dir is empty, DefaultDir is used.
+func New(dir string) (*Archive, error) {
+	if dir == "" {
+		d, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = d
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create archive directory %s: %w", dir, err)
+	}
+
+	return &Archive{dir: dir}, nil
+}
+
+// Dir returns the archive's root directory.
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+func (a *Archive) path(date string) string {
+	return filepath.Join(a.dir, date+".jsonl")
+}
+
+// Add appends rec to its day's file unless the most recent record with the same key
+// already has identical timings. It reports whether a record was written.
+func (a *Archive) Add(rec Record) (bool, error) {
+	if _, err := time.Parse(dateLayout, rec.Date); err != nil {
+		return false, fmt.Errorf("invalid archive date %q: %w", rec.Date, err)
+	}
+
+	existing, err := a.Load(rec.Date)
+	if err != nil {
+		return false, err
+	}
+	for i := len(existing) - 1; i >= 0; i-- {
+		if existing[i].Key() == rec.Key() {
+			if existing[i].Timings == rec.Timings {
+				return false, nil
+			}
+			break
+		}
+	}
+
+	if rec.FetchedAt.IsZero() {
+		rec.FetchedAt = time.Now()
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal archive record: %w", err)
+	}
+
+	f, err := os.OpenFile(a.path(rec.Date), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return false, fmt.Errorf("failed to write archive record: %w", err)
+	}
+
+	return true, nil
+}
+
+// Load returns all records for the given date (YYYY-MM-DD) in the order they were added.
+// A missing file yields no records and no error.
+func (a *Archive) Load(date string) ([]Record, error) {
+	f, err := os.Open(a.path(date))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("corrupt archive record in %s: %w", a.path(date), err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	return records, nil
+}
+
+// Dates returns all archived dates in ascending order.
+func (a *Archive) Dates() ([]string, error) {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive: %w", err)
+	}
+
+	var dates []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		date := strings.TrimSuffix(name, ".jsonl")
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// Change describes a single prayer whose time differs between two records.
+type Change struct {
+	Prayer  string
+	Old     string
+	New     string
+	Minutes int // New minus Old
+}
+
+// Diff compares two sets of timings and returns the prayers whose times differ,
+// in prayer.AllPrayerNames order.
+func Diff(old, new api.Timings) []Change {
+	oldMap := prayer.TimingsMap(old)
+	newMap := prayer.TimingsMap(new)
+
+	var changes []Change
+	for _, name := range prayer.AllPrayerNames {
+		o, n := oldMap[name], newMap[name]
+		if o == n {
+			continue
+		}
+		changes = append(changes, Change{
+			Prayer:  name,
+			Old:     o,
+			New:     n,
+			Minutes: minutesBetween(name, old, new),
+		})
+	}
+	return changes
+}
+
+// minutesBetween returns the difference in minutes for one prayer, or 0 if
+// either time cannot be parsed.
+func minutesBetween(name string, old, new api.Timings) int {
+	day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	o, err := prayer.ParseTimings(old, day, time.UTC, []string{name})
+	if err != nil || len(o) == 0 {
+		return 0
+	}
+	n, err := prayer.ParseTimings(new, day, time.UTC, []string{name})
+	if err != nil || len(n) == 0 {
+		return 0
+	}
+	return int(n[0].Time.Sub(o[0].Time).Minutes())
+}
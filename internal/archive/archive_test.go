@@ -0,0 +1,199 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+)
+
+func sampleTimings() api.Timings {
+	return api.Timings{
+		Fajr: "05:17", Sunrise: "06:48", Dhuhr: "12:13", Asr: "15:02",
+		Sunset: "17:39", Maghrib: "17:39", Isha: "19:10",
+		Imsak: "05:07", Midnight: "00:14", Firstthird: "22:02", Lastthird: "02:25",
+	}
+}
+
+func sampleRecord() Record {
+	return Record{
+		Date:      "2026-02-28",
+		FetchedAt: time.Date(2026, 2, 27, 20, 0, 0, 0, time.UTC),
+		Latitude:  51.5074,
+		Longitude: -0.1278,
+		Method:    2,
+		School:    0,
+		Timings:   sampleTimings(),
+	}
+}
+
+func TestNew_CreatesDir(t *testing.T) {
+	dir := t.TempDir() + "/nested/archive"
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if a.Dir() != dir {
+		t.Errorf("Dir() = %q, want %q", a.Dir(), dir)
+	}
+}
+
+func TestDefaultDir_XDG(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg-data")
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != "/tmp/xdg-data/prayer-times/archive" {
+		t.Errorf("DefaultDir() = %q", dir)
+	}
+}
+
+func TestAdd_SkipsUnchanged(t *testing.T) {
+	a, _ := New(t.TempDir())
+
+	wrote, err := a.Add(sampleRecord())
+	if err != nil || !wrote {
+		t.Fatalf("first Add() = %v, %v; want true, nil", wrote, err)
+	}
+
+	wrote, err = a.Add(sampleRecord())
+	if err != nil || wrote {
+		t.Fatalf("duplicate Add() = %v, %v; want false, nil", wrote, err)
+	}
+
+	records, err := a.Load("2026-02-28")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Errorf("got %d records, want 1", len(records))
+	}
+}
+
+func TestAdd_AppendsChangedVersion(t *testing.T) {
+	a, _ := New(t.TempDir())
+	a.Add(sampleRecord())
+
+	changed := sampleRecord()
+	changed.Timings.Fajr = "05:19"
+	if wrote, err := a.Add(changed); err != nil || !wrote {
+		t.Fatalf("Add(changed) = %v, %v; want true, nil", wrote, err)
+	}
+
+	records, _ := a.Load("2026-02-28")
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[1].Timings.Fajr != "05:19" {
+		t.Errorf("latest Fajr = %q, want 05:19", records[1].Timings.Fajr)
+	}
+}
+
+func TestAdd_SeparateKeys(t *testing.T) {
+	a, _ := New(t.TempDir())
+	a.Add(sampleRecord())
+
+	other := sampleRecord()
+	other.Method = 3
+	if wrote, _ := a.Add(other); !wrote {
+		t.Error("record with a different method should be archived")
+	}
+
+	custom := sampleRecord()
+	custom.Settings = "18,null,17"
+	if wrote, _ := a.Add(custom); !wrote {
+		t.Error("record with custom angles should be archived separately")
+	}
+
+	tuned := sampleRecord()
+	tuned.Tune = "0,3,0,0,0,0,0,0,0"
+	if wrote, _ := a.Add(tuned); !wrote {
+		t.Error("record with tune offsets should be archived separately")
+	}
+
+	adjusted := sampleRecord()
+	adjusted.LatAdjust = 3
+	if wrote, _ := a.Add(adjusted); !wrote {
+		t.Error("record with a latitude adjustment should be archived separately")
+	}
+
+	withShafaq := sampleRecord()
+	withShafaq.Shafaq = "abyad"
+	if wrote, _ := a.Add(withShafaq); !wrote {
+		t.Error("record with a shafaq should be archived separately")
+	}
+
+	elsewhere := sampleRecord()
+	elsewhere.TZ = "Asia/Tokyo"
+	if wrote, _ := a.Add(elsewhere); !wrote {
+		t.Error("record with a timezone override should be archived separately")
+	}
+}
+
+func TestAdd_InvalidDate(t *testing.T) {
+	a, _ := New(t.TempDir())
+	rec := sampleRecord()
+	rec.Date = "28-02-2026"
+	if _, err := a.Add(rec); err == nil {
+		t.Error("Add() with invalid date should fail")
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	a, _ := New(t.TempDir())
+	records, err := a.Load("2026-01-01")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if records != nil {
+		t.Errorf("Load() = %v, want nil", records)
+	}
+}
+
+func TestDates_Sorted(t *testing.T) {
+	a, _ := New(t.TempDir())
+	for _, d := range []string{"2026-03-01", "2026-02-28", "2026-02-01"} {
+		rec := sampleRecord()
+		rec.Date = d
+		a.Add(rec)
+	}
+
+	dates, err := a.Dates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-02-01", "2026-02-28", "2026-03-01"}
+	if len(dates) != len(want) {
+		t.Fatalf("Dates() = %v, want %v", dates, want)
+	}
+	for i := range want {
+		if dates[i] != want[i] {
+			t.Errorf("Dates()[%d] = %q, want %q", i, dates[i], want[i])
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := sampleTimings()
+	new := sampleTimings()
+	new.Fajr = "05:20"
+	new.Isha = "19:05 (GMT)"
+
+	changes := Diff(old, new)
+	if len(changes) != 2 {
+		t.Fatalf("Diff() returned %d changes, want 2: %+v", len(changes), changes)
+	}
+	if changes[0].Prayer != "Fajr" || changes[0].Minutes != 3 {
+		t.Errorf("changes[0] = %+v, want Fajr +3", changes[0])
+	}
+	if changes[1].Prayer != "Isha" || changes[1].Minutes != -5 {
+		t.Errorf("changes[1] = %+v, want Isha -5", changes[1])
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	if changes := Diff(sampleTimings(), sampleTimings()); len(changes) != 0 {
+		t.Errorf("Diff() of identical timings = %+v, want none", changes)
+	}
+}
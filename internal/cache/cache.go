@@ -3,6 +3,7 @@ package cache
 import (
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 const (
 	prayerCacheFile   = "timings_%s.json"  // keyed by hash
 	calendarCacheFile = "calendar_%s.json" // keyed by hash
+	annualCacheFile   = "annual_%s.json"   // keyed by hash
 	geoCacheFile      = "geolocation.json"
 	geoTTL            = 24 * time.Hour
 )
@@ -22,6 +24,15 @@ const (
 // Cache provides file-based caching for prayer times and geolocation data.
 type Cache struct {
 	dir string
+	// Settings identifies non-default calculation settings, such as custom
+	// angles. It is folded into every timings key so results computed with
+	// different settings never collide. Empty leaves keys unchanged.
+	Settings string
+	// Limit caps the entries kept on disk; after each write the least
+	// recently used are evicted beyond it (see Evict). Zero keeps them all.
+	Limit Limit
+	// mem is the in-memory layer set up by KeepInMemory, or nil.
+	mem *memory
 }
 
 // PrayerCacheEntry stores a day's prayer times along with metadata for validation.
@@ -32,6 +43,7 @@ type PrayerCacheEntry struct {
 	Timings  api.Timings  `json:"timings"`
 	Meta     api.Meta     `json:"meta"`
 	DateInfo api.DateInfo `json:"date_info"`
+	CachedAt time.Time    `json:"cached_at"` // zero in entries written before it was recorded
 }
 
 // GeoCacheEntry stores a cached geolocation result with a timestamp.
@@ -49,15 +61,32 @@ type CalendarCacheEntry struct {
 	Days   []api.Data `json:"days"`
 }
 
+// AnnualCacheEntry stores a full year of prayer times, keyed by month (1-12).
+type AnnualCacheEntry struct {
+	Year   int                `json:"year"`
+	Method int                `json:"method"`
+	School int                `json:"school"`
+	Months map[int][]api.Data `json:"months"`
+}
+
+// DefaultDir returns the default cache directory, ~/.cache/prayer-times/.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", "prayer-times"), nil
+}
+
 // New creates a Cache rooted at the given directory.
-// If dir is empty, it defaults to ~/.cache/prayer-times/.
+// If dir is empty, DefaultDir is used.
 func New(dir string) (*Cache, error) {
 	if dir == "" {
-		home, err := os.UserHomeDir()
+		d, err := DefaultDir()
 		if err != nil {
-			return nil, fmt.Errorf("cannot determine home directory: %w", err)
+			return nil, err
 		}
-		dir = filepath.Join(home, ".cache", "prayer-times")
+		dir = d
 	}
 
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -67,6 +96,172 @@ func New(dir string) (*Cache, error) {
 	return &Cache{dir: dir}, nil
 }
 
+// Dir returns the cache's root directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// GeoPath returns the file holding the cached geolocation result.
+func (c *Cache) GeoPath() string {
+	return filepath.Join(c.dir, geoCacheFile)
+}
+
+// Files returns the paths of every cache file. Other files in the directory
+// are not included, since it may be shared (see --cache-dir).
+func (c *Cache) Files() ([]string, error) {
+	return c.glob("")
+}
+
+// glob returns the paths of every cache file with suffix appended to its
+// name, such as ".lock" for the lock files.
+func (c *Cache) glob(suffix string) ([]string, error) {
+	var files []string
+	globs := []string{
+		fmt.Sprintf(prayerCacheFile, "*"),
+		fmt.Sprintf(calendarCacheFile, "*"),
+		fmt.Sprintf(annualCacheFile, "*"),
+		geoCacheFile,
+	}
+	for _, glob := range globs {
+		matches, err := filepath.Glob(filepath.Join(c.dir, glob+suffix))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// Locks returns the paths of the lock files in the cache directory: those
+// held by a fetch or an eviction in progress, and leftovers that Clear,
+// Prune and Evict remove.
+func (c *Cache) Locks() ([]string, error) {
+	locks, err := c.glob(".lock")
+	if err != nil {
+		return nil, err
+	}
+	stats := filepath.Join(c.dir, evictionStatsFile+".lock")
+	if _, err := os.Stat(stats); err == nil {
+		locks = append(locks, stats)
+	}
+	return locks, nil
+}
+
+// removeLocks removes the lock files no process holds: ones left by a
+// process that exited while holding them, or kept by earlier versions,
+// which didn't remove them on release.
+func (c *Cache) removeLocks() {
+	locks, _ := c.Locks()
+	for _, path := range locks {
+		removeLock(path)
+	}
+}
+
+// Clear deletes every cache file (see Files) and returns their paths. Lock
+// files no process holds are deleted too.
+func (c *Cache) Clear() ([]string, error) {
+	c.removeLocks()
+	files, err := c.Files()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, path := range files {
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// Prune deletes the cache files last written or read before cutoff and
+// returns their paths. Lock files no process holds are deleted too.
+func (c *Cache) Prune(cutoff time.Time) ([]string, error) {
+	c.removeLocks()
+	files, err := c.Files()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// writeFile writes data to path through a temporary file in the same
+// directory and renames it into place, so concurrent readers see either the
+// old file or the new one, never a partial write.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// lockWait bounds how long the Lock methods wait for another process's lock,
+// so a hung process can't stall the rest. An API request with its retries
+// finishes well within it.
+var lockWait = time.Minute
+
+// ErrLockTimeout is returned by the Lock methods when another process held
+// the lock for longer than lockWait.
+var ErrLockTimeout = errors.New("timed out waiting for another process to fetch")
+
+// LockTimings takes an exclusive lock on the timings entry for the given
+// parameters, shared with other processes using the same cache directory,
+// and waits until it is held. Callers hold it across a cache miss so that
+// only one process fetches the entry; the others wait and then find it
+// cached. The returned func releases the lock.
+func (c *Cache) LockTimings(date time.Time, lat, lon float64, city, country string, method, school int) (func(), error) {
+	key := cacheKey(date.Format("2006-01-02"), lat, lon, city, country, method, school)
+	return lockFile(c.path(prayerCacheFile, key)+".lock", lockWait)
+}
+
+// LockCalendar is LockTimings for a monthly calendar entry.
+func (c *Cache) LockCalendar(year, month int, lat, lon float64, city, country string, method, school int) (func(), error) {
+	key := calendarKey(year, month, lat, lon, city, country, method, school)
+	return lockFile(c.path(calendarCacheFile, key)+".lock", lockWait)
+}
+
+// LockAnnual is LockTimings for an annual calendar entry.
+func (c *Cache) LockAnnual(year int, lat, lon float64, city, country string, method, school int) (func(), error) {
+	key := annualKey(year, lat, lon, city, country, method, school)
+	return lockFile(c.path(annualCacheFile, key)+".lock", lockWait)
+}
+
+// LockGeo is LockTimings for the cached geolocation result.
+func (c *Cache) LockGeo() (func(), error) {
+	return lockFile(c.GeoPath()+".lock", lockWait)
+}
+
 // cacheKey builds a deterministic hash from the parameters that affect prayer times.
 // This ensures different locations/methods/schools get separate cache files.
 func cacheKey(date string, lat, lon float64, city, country string, method, school int) string {
@@ -75,12 +270,24 @@ func cacheKey(date string, lat, lon float64, city, country string, method, schoo
 	return fmt.Sprintf("%x", h[:8]) // 16 hex chars is plenty for uniqueness
 }
 
+// path returns the cache file for a timings key, folding in Settings when set.
+func (c *Cache) path(pattern, key string) string {
+	if c.Settings != "" {
+		h := sha256.Sum256([]byte(key + "|" + c.Settings))
+		key = fmt.Sprintf("%x", h[:8])
+	}
+	return filepath.Join(c.dir, fmt.Sprintf(pattern, key))
+}
+
 // LoadTimings attempts to read cached prayer times for the given parameters.
 // Returns nil if the cache is missing or stale (wrong date).
 func (c *Cache) LoadTimings(date time.Time, lat, lon float64, city, country string, method, school int) *PrayerCacheEntry {
 	dateStr := date.Format("2006-01-02")
 	key := cacheKey(dateStr, lat, lon, city, country, method, school)
-	path := filepath.Join(c.dir, fmt.Sprintf(prayerCacheFile, key))
+	path := c.path(prayerCacheFile, key)
+	if v, ok := c.mem.get(path); ok {
+		return v.(*PrayerCacheEntry)
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -97,6 +304,8 @@ func (c *Cache) LoadTimings(date time.Time, lat, lon float64, city, country stri
 		return nil
 	}
 
+	touch(path)
+	c.mem.put(path, &entry)
 	return &entry
 }
 
@@ -104,7 +313,7 @@ func (c *Cache) LoadTimings(date time.Time, lat, lon float64, city, country stri
 func (c *Cache) SaveTimings(date time.Time, lat, lon float64, city, country string, method, school int, resp *api.Response) error {
 	dateStr := date.Format("2006-01-02")
 	key := cacheKey(dateStr, lat, lon, city, country, method, school)
-	path := filepath.Join(c.dir, fmt.Sprintf(prayerCacheFile, key))
+	path := c.path(prayerCacheFile, key)
 
 	entry := PrayerCacheEntry{
 		Date:     dateStr,
@@ -113,6 +322,7 @@ func (c *Cache) SaveTimings(date time.Time, lat, lon float64, city, country stri
 		Timings:  resp.Data.Timings,
 		Meta:     resp.Data.Meta,
 		DateInfo: resp.Data.Date,
+		CachedAt: time.Now(),
 	}
 
 	data, err := json.Marshal(entry)
@@ -120,9 +330,13 @@ func (c *Cache) SaveTimings(date time.Time, lat, lon float64, city, country stri
 		return fmt.Errorf("failed to marshal cache entry: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	if err := writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
+	c.mem.put(path, &entry)
+	// Eviction is best effort: on failure the cache stays over its limit
+	// until the next write.
+	c.Evict()
 
 	return nil
 }
@@ -138,7 +352,10 @@ func calendarKey(year, month int, lat, lon float64, city, country string, method
 // Returns nil if the cache is missing or for a different month.
 func (c *Cache) LoadCalendar(year, month int, lat, lon float64, city, country string, method, school int) *CalendarCacheEntry {
 	key := calendarKey(year, month, lat, lon, city, country, method, school)
-	path := filepath.Join(c.dir, fmt.Sprintf(calendarCacheFile, key))
+	path := c.path(calendarCacheFile, key)
+	if v, ok := c.mem.get(path); ok {
+		return v.(*CalendarCacheEntry)
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -155,13 +372,15 @@ func (c *Cache) LoadCalendar(year, month int, lat, lon float64, city, country st
 		return nil
 	}
 
+	touch(path)
+	c.mem.put(path, &entry)
 	return &entry
 }
 
 // SaveCalendar writes a full month of calendar data to the cache.
 func (c *Cache) SaveCalendar(year, month int, lat, lon float64, city, country string, method, school int, resp *api.CalendarResponse) error {
 	key := calendarKey(year, month, lat, lon, city, country, method, school)
-	path := filepath.Join(c.dir, fmt.Sprintf(calendarCacheFile, key))
+	path := c.path(calendarCacheFile, key)
 
 	entry := CalendarCacheEntry{
 		Year:   year,
@@ -176,9 +395,77 @@ func (c *Cache) SaveCalendar(year, month int, lat, lon float64, city, country st
 		return fmt.Errorf("failed to marshal calendar cache entry: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	if err := writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write calendar cache file: %w", err)
 	}
+	c.mem.put(path, &entry)
+	c.Evict()
+
+	return nil
+}
+
+// annualKey builds a deterministic hash for a year of calendar data.
+func annualKey(year int, lat, lon float64, city, country string, method, school int) string {
+	raw := fmt.Sprintf("year|%d|%.6f|%.6f|%s|%s|%d|%d", year, lat, lon, city, country, method, school)
+	h := sha256.Sum256([]byte(raw))
+	return fmt.Sprintf("%x", h[:8])
+}
+
+// LoadAnnual attempts to read a cached annual calendar for the given parameters.
+// Returns nil if the cache is missing or for a different year.
+func (c *Cache) LoadAnnual(year int, lat, lon float64, city, country string, method, school int) *AnnualCacheEntry {
+	key := annualKey(year, lat, lon, city, country, method, school)
+	path := c.path(annualCacheFile, key)
+	if v, ok := c.mem.get(path); ok {
+		return v.(*AnnualCacheEntry)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var entry AnnualCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+
+	if entry.Year != year {
+		return nil
+	}
+
+	touch(path)
+	c.mem.put(path, &entry)
+	return &entry
+}
+
+// SaveAnnual writes a full year of calendar data to the cache.
+func (c *Cache) SaveAnnual(year int, lat, lon float64, city, country string, method, school int, resp *api.AnnualCalendarResponse) error {
+	key := annualKey(year, lat, lon, city, country, method, school)
+	path := c.path(annualCacheFile, key)
+
+	entry := AnnualCacheEntry{
+		Year:   year,
+		Method: method,
+		School: school,
+		Months: make(map[int][]api.Data, 12),
+	}
+	for m := 1; m <= 12; m++ {
+		if days := resp.Month(m); days != nil {
+			entry.Months[m] = days
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal annual cache entry: %w", err)
+	}
+
+	if err := writeFile(path, data); err != nil {
+		return fmt.Errorf("failed to write annual cache file: %w", err)
+	}
+	c.mem.put(path, &entry)
+	c.Evict()
 
 	return nil
 }
@@ -186,7 +473,7 @@ func (c *Cache) SaveCalendar(year, month int, lat, lon float64, city, country st
 // LoadGeo attempts to read a cached geolocation result.
 // Returns nil if the cache is missing or older than the TTL (24 hours).
 func (c *Cache) LoadGeo() *geo.Location {
-	path := filepath.Join(c.dir, geoCacheFile)
+	path := c.GeoPath()
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -207,7 +494,7 @@ func (c *Cache) LoadGeo() *geo.Location {
 
 // SaveGeo writes a geolocation result to the cache.
 func (c *Cache) SaveGeo(loc *geo.Location) error {
-	path := filepath.Join(c.dir, geoCacheFile)
+	path := c.GeoPath()
 
 	entry := GeoCacheEntry{
 		Location: *loc,
@@ -219,7 +506,7 @@ func (c *Cache) SaveGeo(loc *geo.Location) error {
 		return fmt.Errorf("failed to marshal geo cache: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	if err := writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write geo cache: %w", err)
 	}
 
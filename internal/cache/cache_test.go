@@ -99,6 +99,9 @@ func TestTimings_RoundTrip(t *testing.T) {
 	if entry.Meta.Timezone != "Europe/London" {
 		t.Errorf("Timezone = %q, want %q", entry.Meta.Timezone, "Europe/London")
 	}
+	if time.Since(entry.CachedAt) > time.Minute {
+		t.Errorf("CachedAt = %v, want the time of saving", entry.CachedAt)
+	}
 }
 
 func TestTimings_CacheMiss(t *testing.T) {
@@ -445,6 +448,81 @@ func TestCalendar_CorruptedFile(t *testing.T) {
 // calendarKey
 // ---------------------------------------------------------------------------
 
+func sampleAnnualResponse() *api.AnnualCalendarResponse {
+	data := make(map[string][]api.Data)
+	for m := 1; m <= 12; m++ {
+		data[fmt.Sprintf("%d", m)] = sampleCalendarResponse(28).Data
+	}
+	return &api.AnnualCalendarResponse{Code: 200, Status: "OK", Data: data}
+}
+
+func TestAnnual_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+
+	if err := c.SaveAnnual(2026, 51.5074, -0.1278, "", "", 2, 0, sampleAnnualResponse()); err != nil {
+		t.Fatalf("SaveAnnual error: %v", err)
+	}
+
+	entry := c.LoadAnnual(2026, 51.5074, -0.1278, "", "", 2, 0)
+	if entry == nil {
+		t.Fatal("LoadAnnual returned nil after save")
+	}
+	if entry.Year != 2026 {
+		t.Errorf("Year = %d, want %d", entry.Year, 2026)
+	}
+	if len(entry.Months) != 12 {
+		t.Errorf("Months count = %d, want 12", len(entry.Months))
+	}
+	if entry.Months[3][0].Timings.Fajr != "05:17" {
+		t.Errorf("Months[3][0].Fajr = %q, want %q", entry.Months[3][0].Timings.Fajr, "05:17")
+	}
+}
+
+func TestAnnual_Miss(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+
+	if err := c.SaveAnnual(2026, 51.5, -0.1, "", "", 2, 0, sampleAnnualResponse()); err != nil {
+		t.Fatalf("SaveAnnual error: %v", err)
+	}
+
+	if c.LoadAnnual(2027, 51.5, -0.1, "", "", 2, 0) != nil {
+		t.Error("expected nil for a different year")
+	}
+	if c.LoadAnnual(2026, 51.5, -0.1, "", "", 3, 0) != nil {
+		t.Error("expected nil for a different method")
+	}
+	if c.LoadCalendar(2026, 1, 51.5, -0.1, "", "", 2, 0) != nil {
+		t.Error("annual and monthly entries must not share keys")
+	}
+}
+
+func TestSettings_SeparateEntries(t *testing.T) {
+	dir := t.TempDir()
+	plain, _ := New(dir)
+	custom, _ := New(dir)
+	custom.Settings = "18,null,17"
+
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	if err := plain.SaveTimings(date, 51.5, -0.1, "", "", 2, 0, sampleAPIResponse()); err != nil {
+		t.Fatalf("SaveTimings error: %v", err)
+	}
+	if custom.LoadTimings(date, 51.5, -0.1, "", "", 2, 0) != nil {
+		t.Error("custom settings must not read entries saved without them")
+	}
+
+	if err := custom.SaveCalendar(2026, 2, 51.5, -0.1, "", "", 2, 0, sampleCalendarResponse(28)); err != nil {
+		t.Fatalf("SaveCalendar error: %v", err)
+	}
+	if plain.LoadCalendar(2026, 2, 51.5, -0.1, "", "", 2, 0) != nil {
+		t.Error("entries saved with custom settings must not be read without them")
+	}
+	if custom.LoadCalendar(2026, 2, 51.5, -0.1, "", "", 2, 0) == nil {
+		t.Error("LoadCalendar returned nil for matching settings")
+	}
+}
+
 func TestCalendarKey_Deterministic(t *testing.T) {
 	k1 := calendarKey(2026, 2, 51.5, -0.1, "", "", 2, 0)
 	k2 := calendarKey(2026, 2, 51.5, -0.1, "", "", 2, 0)
@@ -505,3 +583,221 @@ func TestCacheKey_Length(t *testing.T) {
 		t.Errorf("cacheKey length = %d, want 16", len(k))
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Clear
+// ---------------------------------------------------------------------------
+
+func TestClear(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	if err := c.SaveTimings(date, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse()); err != nil {
+		t.Fatalf("SaveTimings error: %v", err)
+	}
+	if err := c.SaveGeo(&geo.Location{City: "London"}); err != nil {
+		t.Fatalf("SaveGeo error: %v", err)
+	}
+	other := filepath.Join(dir, "notes.txt")
+	os.WriteFile(other, []byte("not ours"), 0o644)
+
+	removed, err := c.Clear()
+	if err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Clear removed %v, want the timings and geo files", removed)
+	}
+	if c.LoadTimings(date, 51.5074, -0.1278, "", "", 2, 0) != nil || c.LoadGeo() != nil {
+		t.Error("cache entries survived Clear")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Clear removed a file it doesn't own: %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+
+	old := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	recent := old.AddDate(0, 0, 1)
+	c.SaveTimings(old, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse())
+	c.SaveTimings(recent, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse())
+	files, _ := c.Files()
+	for _, f := range files {
+		os.Chtimes(f, old, old)
+	}
+	c.SaveGeo(&geo.Location{City: "London"})
+
+	removed, err := c.Prune(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Prune error: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Prune removed %v, want both timings files", removed)
+	}
+	if c.LoadGeo() == nil {
+		t.Error("Prune removed the freshly written geo cache")
+	}
+}
+
+func TestSave_LeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := c.SaveTimings(date, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse()); err != nil {
+			t.Fatalf("SaveTimings error: %v", err)
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("cache dir holds %v, want only the timings file", names)
+	}
+	info, err := os.Stat(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestLockTimings_Excludes(t *testing.T) {
+	c, _ := New(t.TempDir())
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	unlock, err := c.LockTimings(date, 51.5074, -0.1278, "", "", 2, 0)
+	if err != nil {
+		t.Fatalf("LockTimings error: %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		second, err := c.LockTimings(date, 51.5074, -0.1278, "", "", 2, 0)
+		if err != nil {
+			t.Errorf("second LockTimings error: %v", err)
+			second = func() {}
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while the first was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case second := <-acquired:
+		second()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not acquired after release")
+	}
+
+	if entries, _ := os.ReadDir(c.Dir()); len(entries) != 0 {
+		t.Errorf("cache dir holds %d files after the locks were released, want none", len(entries))
+	}
+}
+
+func TestClear_RemovesLeftoverLocks(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	c.SaveTimings(date, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse())
+	files, _ := c.Files()
+
+	// A lock file left by a process that exited while holding it, and one
+	// another process holds.
+	os.WriteFile(files[0]+".lock", nil, 0o644)
+	unlock, err := c.LockCalendar(2026, 2, 51.5074, -0.1278, "", "", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	if _, err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(files[0] + ".lock"); !os.IsNotExist(err) {
+		t.Error("Clear left a lock file no process holds")
+	}
+	if locks, _ := c.glob(".lock"); len(locks) != 1 {
+		t.Errorf("lock files after Clear = %v, want the held one", locks)
+	}
+}
+
+func TestLockTimings_TimesOut(t *testing.T) {
+	c, _ := New(t.TempDir())
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	prev := lockWait
+	lockWait = 100 * time.Millisecond
+	t.Cleanup(func() { lockWait = prev })
+
+	unlock, err := c.LockTimings(date, 51.5074, -0.1278, "", "", 2, 0)
+	if err != nil {
+		t.Fatalf("LockTimings error: %v", err)
+	}
+	defer unlock()
+
+	if _, err := c.LockTimings(date, 51.5074, -0.1278, "", "", 2, 0); err != ErrLockTimeout {
+		t.Errorf("LockTimings on a held lock = %v, want ErrLockTimeout", err)
+	}
+	// Other entries aren't affected.
+	if other, err := c.LockCalendar(2026, 2, 51.5074, -0.1278, "", "", 2, 0); err != nil {
+		t.Errorf("LockCalendar error: %v", err)
+	} else {
+		other()
+	}
+}
+
+func TestKeepInMemory(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+	c.KeepInMemory(time.Hour)
+
+	date := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	if err := c.SaveTimings(date, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse()); err != nil {
+		t.Fatalf("SaveTimings error: %v", err)
+	}
+	if err := c.SaveCalendar(2026, 2, 51.5074, -0.1278, "", "", 2, 0, &api.CalendarResponse{Data: []api.Data{{}}}); err != nil {
+		t.Fatalf("SaveCalendar error: %v", err)
+	}
+
+	// With the files gone, entries are still answered from memory.
+	if _, err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if c.LoadTimings(date, 51.5074, -0.1278, "", "", 2, 0) == nil {
+		t.Error("LoadTimings missed the in-memory entry")
+	}
+	if c.LoadCalendar(2026, 2, 51.5074, -0.1278, "", "", 2, 0) == nil {
+		t.Error("LoadCalendar missed the in-memory entry")
+	}
+
+	// Expired entries fall through to the files.
+	c.KeepInMemory(-time.Second)
+	c.SaveTimings(date, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse())
+	c.Clear()
+	if c.LoadTimings(date, 51.5074, -0.1278, "", "", 2, 0) != nil {
+		t.Error("LoadTimings returned an expired in-memory entry")
+	}
+}
+
+func TestMemory_Rollover(t *testing.T) {
+	m := &memory{ttl: time.Hour, entries: make(map[string]memoryEntry)}
+	m.put("a", 1)
+	m.day = "2000-01-01" // as if the entry had been held since then
+	if _, ok := m.get("a"); ok {
+		t.Error("entry survived the date changing")
+	}
+}
@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// evictionStatsFile records the evictions made to keep a cache within its
+// Limit. Files leaves it out, so clearing the cache keeps the counts.
+const evictionStatsFile = "evictions.json"
+
+// Limit caps the size of a cache by its number of entries, its total size in
+// bytes, or both. A zero field is no cap.
+type Limit struct {
+	Entries int
+	Bytes   int64
+}
+
+// byteUnits are the size suffixes ParseLimit accepts, in binary multiples.
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+}
+
+// ParseLimit parses a cache size limit: a number of entries such as "500",
+// a size such as "20MB" (B, KB, MB or GB), or one of each separated by a
+// comma. An empty string is no limit.
+func ParseLimit(s string) (Limit, error) {
+	var l Limit
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToUpper(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(part); err == nil {
+			if n <= 0 || l.Entries != 0 {
+				return Limit{}, fmt.Errorf("invalid cache size %q: want a positive number of entries and at most one", s)
+			}
+			l.Entries = n
+			continue
+		}
+		parsed := false
+		for _, u := range byteUnits {
+			num, ok := strings.CutSuffix(part, u.suffix)
+			if !ok {
+				continue
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil || f <= 0 || l.Bytes != 0 {
+				break
+			}
+			l.Bytes = int64(f * float64(u.size))
+			parsed = true
+			break
+		}
+		if !parsed {
+			return Limit{}, fmt.Errorf("invalid cache size %q: want entries (e.g. 500), a size (e.g. 20MB), or both (e.g. 500,20MB)", s)
+		}
+	}
+	return l, nil
+}
+
+// IsZero reports whether l caps nothing.
+func (l Limit) IsZero() bool {
+	return l.Entries == 0 && l.Bytes == 0
+}
+
+// String describes l, e.g. "500 entries, 20.0 MB", or "none".
+func (l Limit) String() string {
+	var parts []string
+	switch {
+	case l.Entries == 1:
+		parts = append(parts, "1 entry")
+	case l.Entries > 1:
+		parts = append(parts, fmt.Sprintf("%d entries", l.Entries))
+	}
+	if l.Bytes > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f MB", float64(l.Bytes)/(1<<20)))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// EvictionStats counts the entries evicted from a cache to keep it within
+// its Limit.
+type EvictionStats struct {
+	Evictions int       `json:"evictions"`
+	Bytes     int64     `json:"bytes"`
+	Last      time.Time `json:"last"`
+}
+
+// EvictionStats returns the evictions recorded for the cache directory, by
+// this process and others sharing it.
+func (c *Cache) EvictionStats() EvictionStats {
+	var stats EvictionStats
+	if data, err := os.ReadFile(filepath.Join(c.dir, evictionStatsFile)); err == nil {
+		json.Unmarshal(data, &stats)
+	}
+	return stats
+}
+
+// Evict deletes the least recently used entries until the cache is within
+// c.Limit, and returns their paths. An entry is used when it is written or
+// read from disk. The most recently used entry and the detected location are
+// never evicted, so a limit smaller than one entry still caches the last.
+// Lock files no process holds are deleted too, as they would otherwise
+// collect beside the entries.
+func (c *Cache) Evict() ([]string, error) {
+	if c.Limit.IsZero() {
+		return nil, nil
+	}
+	unlock, err := lockFile(filepath.Join(c.dir, evictionStatsFile)+".lock", lockWait)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	c.removeLocks()
+
+	files, err := c.Files()
+	if err != nil {
+		return nil, err
+	}
+	type entry struct {
+		path string
+		size int64
+		used time.Time
+	}
+	var entries []entry
+	var total int64
+	for _, path := range files {
+		if path == c.GeoPath() {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{path, info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].used.Before(entries[j].used) })
+
+	over := func(n int) bool {
+		return (c.Limit.Entries > 0 && n > c.Limit.Entries) || (c.Limit.Bytes > 0 && total > c.Limit.Bytes)
+	}
+	var removed []string
+	var freed int64
+	for i := 0; i < len(entries)-1 && over(len(entries)-i); i++ {
+		e := entries[i]
+		if err := os.Remove(e.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		c.mem.delete(e.path)
+		removed = append(removed, e.path)
+		total -= e.size
+		freed += e.size
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	stats := c.EvictionStats()
+	stats.Evictions += len(removed)
+	stats.Bytes += freed
+	stats.Last = time.Now()
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return removed, fmt.Errorf("failed to marshal eviction stats: %w", err)
+	}
+	if err := writeFile(filepath.Join(c.dir, evictionStatsFile), data); err != nil {
+		return removed, fmt.Errorf("failed to write eviction stats: %w", err)
+	}
+	return removed, nil
+}
+
+// touch marks the entry at path used, so Evict keeps it over entries that
+// were used less recently.
+func touch(path string) {
+	now := time.Now()
+	os.Chtimes(path, now, now)
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/geo"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Limit
+	}{
+		{"", Limit{}},
+		{"500", Limit{Entries: 500}},
+		{"20MB", Limit{Bytes: 20 << 20}},
+		{"512kb", Limit{Bytes: 512 << 10}},
+		{"1.5GB", Limit{Bytes: 3 << 29}},
+		{"4096B", Limit{Bytes: 4096}},
+		{"500, 20MB", Limit{Entries: 500, Bytes: 20 << 20}},
+		{"20MB,500", Limit{Entries: 500, Bytes: 20 << 20}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"0", "-1", "big", "20TB", "MB", "0MB", "1,2", "1MB,2MB"} {
+		if _, err := ParseLimit(in); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestLimitString(t *testing.T) {
+	if got := (Limit{}).String(); got != "none" {
+		t.Errorf("Limit{}.String() = %q, want none", got)
+	}
+	if got := (Limit{Entries: 500, Bytes: 20 << 20}).String(); got != "500 entries, 20.0 MB" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Limit{Entries: 1}).String(); got != "1 entry" {
+		t.Errorf("String() = %q, want 1 entry", got)
+	}
+}
+
+// saveDays caches the timings of n consecutive days, each last used a minute
+// after the one before, and returns the days.
+func saveDays(t *testing.T, c *Cache, n int) []time.Time {
+	t.Helper()
+	first := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	used := time.Now().Add(-time.Hour)
+	var days []time.Time
+	for i := range n {
+		day := first.AddDate(0, 0, i)
+		if err := c.SaveTimings(day, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse()); err != nil {
+			t.Fatal(err)
+		}
+		path := c.path(prayerCacheFile, cacheKey(day.Format("2006-01-02"), 51.5074, -0.1278, "", "", 2, 0))
+		at := used.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(path, at, at)
+		days = append(days, day)
+	}
+	return days
+}
+
+func TestEvict_LeastRecentlyUsed(t *testing.T) {
+	c, _ := New(t.TempDir())
+	days := saveDays(t, c, 3)
+	c.SaveGeo(&geo.Location{City: "London"})
+
+	// Reading the oldest day makes the second the least recently used.
+	if c.LoadTimings(days[0], 51.5074, -0.1278, "", "", 2, 0) == nil {
+		t.Fatal("LoadTimings missed a cached day")
+	}
+
+	c.Limit = Limit{Entries: 3}
+	fourth := days[2].AddDate(0, 0, 1)
+	if err := c.SaveTimings(fourth, 51.5074, -0.1278, "", "", 2, 0, sampleAPIResponse()); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, day := range append(days, fourth) {
+		cached := c.LoadTimings(day, 51.5074, -0.1278, "", "", 2, 0) != nil
+		if want := i != 1; cached != want {
+			t.Errorf("day %d cached = %v, want %v", i, cached, want)
+		}
+	}
+	if c.LoadGeo() == nil {
+		t.Error("eviction removed the detected location")
+	}
+
+	stats := c.EvictionStats()
+	if stats.Evictions != 1 || stats.Bytes == 0 || time.Since(stats.Last) > time.Minute {
+		t.Errorf("EvictionStats() = %+v, want one eviction just now", stats)
+	}
+}
+
+func TestEvict_Bytes(t *testing.T) {
+	c, _ := New(t.TempDir())
+	saveDays(t, c, 5)
+	files, _ := c.Files()
+	info, _ := os.Stat(files[0])
+
+	c.Limit = Limit{Bytes: 2*info.Size() + 1}
+	removed, err := c.Evict()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("Evict removed %d entries, want 3 to fit two in the limit", len(removed))
+	}
+	if files, _ := c.Files(); len(files) != 2 {
+		t.Errorf("%d entries left, want 2", len(files))
+	}
+
+	// Stats add up across evictions.
+	c.Limit = Limit{Entries: 1}
+	c.Evict()
+	if stats := c.EvictionStats(); stats.Evictions != 4 {
+		t.Errorf("EvictionStats().Evictions = %d, want 4", stats.Evictions)
+	}
+}
+
+func TestEvict_RemovesLeftoverLocks(t *testing.T) {
+	c, _ := New(t.TempDir())
+	saveDays(t, c, 3)
+	files, _ := c.Files()
+	for _, f := range files {
+		os.WriteFile(f+".lock", nil, 0o644)
+	}
+	if locks, _ := c.Locks(); len(locks) != 3 {
+		t.Fatalf("Locks() = %v, want the 3 left over", locks)
+	}
+
+	c.Limit = Limit{Entries: 2}
+	if _, err := c.Evict(); err != nil {
+		t.Fatal(err)
+	}
+	if locks, _ := c.Locks(); len(locks) != 0 {
+		t.Errorf("Locks() after Evict = %v, want none", locks)
+	}
+	if entries, _ := os.ReadDir(c.Dir()); len(entries) != 3 {
+		t.Errorf("cache dir holds %d files, want 2 entries and the eviction stats", len(entries))
+	}
+}
+
+func TestEvict_KeepsNewest(t *testing.T) {
+	c, _ := New(t.TempDir())
+	c.Limit = Limit{Bytes: 1}
+	days := saveDays(t, c, 2)
+
+	if files, _ := c.Files(); len(files) != 1 {
+		t.Errorf("%d entries left, want the newest under a limit smaller than it", len(files))
+	}
+	if c.LoadTimings(days[1], 51.5074, -0.1278, "", "", 2, 0) == nil {
+		t.Error("the newest entry was evicted")
+	}
+}
+
+func TestEvict_NoLimit(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := New(dir)
+	saveDays(t, c, 3)
+
+	if removed, err := c.Evict(); err != nil || removed != nil {
+		t.Errorf("Evict() without a limit = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, evictionStatsFile)); !os.IsNotExist(err) {
+		t.Error("Evict wrote stats without a limit")
+	}
+	if stats := c.EvictionStats(); stats.Evictions != 0 {
+		t.Errorf("EvictionStats() = %+v, want none", stats)
+	}
+}
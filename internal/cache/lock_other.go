@@ -0,0 +1,20 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package cache
+
+import (
+	"os"
+	"time"
+)
+
+// lockFile is a no-op where flock isn't available; concurrent processes may
+// then fetch the same entry twice, which atomic writes keep harmless.
+func lockFile(path string, wait time.Duration) (func(), error) {
+	return func() {}, nil
+}
+
+// removeLock removes the lock file at path; without flock, lockFile never
+// creates one.
+func removeLock(path string) {
+	os.Remove(path)
+}
@@ -0,0 +1,79 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockPoll is how often lockFile retries a lock held by another process.
+const lockPoll = 50 * time.Millisecond
+
+// lockFile takes an exclusive flock on path, creating it if needed, waiting
+// up to wait for another holder to release it. The lock file is removed on
+// release, so the cache doesn't collect one per entry. A process that opened
+// the file before it was removed finds, once it holds the lock, that path no
+// longer names the file it locked, and starts over with a new one.
+func lockFile(path string, wait time.Duration) (func(), error) {
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open lock file: %w", err)
+		}
+		for {
+			err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+				f.Close()
+				return nil, fmt.Errorf("cannot lock %s: %w", path, err)
+			}
+			if time.Now().After(deadline) {
+				f.Close()
+				return nil, ErrLockTimeout
+			}
+			time.Sleep(lockPoll)
+		}
+		if lockedAt(f, path) {
+			return func() {
+				os.Remove(path)
+				syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+				f.Close()
+			}, nil
+		}
+		f.Close()
+	}
+}
+
+// removeLock removes the lock file at path left behind by a process that
+// exited without releasing it, unless the lock is held.
+func removeLock(path string) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) != nil {
+		return
+	}
+	if lockedAt(f, path) {
+		os.Remove(path)
+	}
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+// lockedAt reports whether path still names the open file f.
+func lockedAt(f *os.File, path string) bool {
+	held, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	return err == nil && os.SameFile(held, current)
+}
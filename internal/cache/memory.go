@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// memory keeps recently read and written entries in memory, keyed by their
+// cache file, so a long-running process answers repeated lookups without
+// touching the disk. Entries live for ttl, and all are dropped when the local
+// date changes, since most lookups are for today.
+type memory struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	day     string
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   any
+	expires time.Time
+}
+
+// KeepInMemory adds an in-memory layer in front of the cache files for
+// timings and calendars, holding each entry for ttl. It is meant for
+// long-running processes such as serve; entries returned from it are shared
+// and must not be modified. Changes made to the files by other processes are
+// seen once the entry expires.
+func (c *Cache) KeepInMemory(ttl time.Duration) {
+	c.mem = &memory{ttl: ttl, entries: make(map[string]memoryEntry)}
+}
+
+// get returns the entry for path, if it is held and current.
+func (m *memory) get(path string) (any, bool) {
+	if m == nil {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.rollover(now)
+	e, ok := m.entries[path]
+	if !ok || now.After(e.expires) {
+		delete(m.entries, path)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// put holds value as the entry for path.
+func (m *memory) put(path string, value any) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.rollover(now)
+	m.entries[path] = memoryEntry{value: value, expires: now.Add(m.ttl)}
+}
+
+// delete drops the entry for path.
+func (m *memory) delete(path string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, path)
+}
+
+// rollover drops every entry once the date has changed since the last
+// lookup. The caller holds mu.
+func (m *memory) rollover(now time.Time) {
+	day := now.Format("2006-01-02")
+	if day != m.day {
+		m.day = day
+		clear(m.entries)
+	}
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/adhan"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAdhanFajr  bool
+	flagAdhanChime bool
+)
+
+func newAdhanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adhan",
+		Short: "Adhan playback at prayer time",
+		Long: `While 'prayer-times watch' runs, the adhan_sound file is played at each
+prayer with adhan_player (auto, mpv, afplay or paplay). adhan_fajr_sound plays
+at Fajr instead; set it to "none" for a silent Fajr.
+
+chime_sound is a short sound played chime_before (10m by default) ahead of
+each prayer in chime_prayers (all five by default), as a reminder.`,
+		Example: `  prayer-times config set adhan_sound ~/Music/adhan.mp3
+  prayer-times config set adhan_fajr_sound ~/Music/adhan-fajr.mp3
+  prayer-times config set chime_sound ~/Music/chime.wav
+  prayer-times config set chime_prayers Fajr,Maghrib
+  prayer-times adhan test`,
+		Args: cobra.NoArgs,
+	}
+
+	test := &cobra.Command{
+		Use:   "test",
+		Short: "Play the configured adhan now",
+		Args:  cobra.NoArgs,
+		RunE:  runAdhanTest,
+	}
+	test.Flags().BoolVar(&flagAdhanFajr, "fajr", false, "Play the Fajr adhan")
+	test.Flags().BoolVar(&flagAdhanChime, "chime", false, "Play the pre-prayer chime")
+	cmd.AddCommand(test)
+
+	return cmd
+}
+
+func runAdhanTest(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	cfg := effectiveConfig(cmd)
+
+	if flagAdhanChime {
+		if cfg.ChimeSound == "" {
+			return fmt.Errorf("no chime sound set; use 'prayer-times config set chime_sound /path/to/chime.wav'")
+		}
+		fmt.Fprintf(w, "Playing %s...\n", cfg.ChimeSound)
+		return adhan.Play(cfg.AdhanPlayer, cfg.ChimeSound)
+	}
+
+	if cfg.AdhanSound == "" && cfg.AdhanFajrSound == "" {
+		return fmt.Errorf("no adhan sound set; use 'prayer-times config set adhan_sound /path/to/adhan.mp3'")
+	}
+
+	name := "Dhuhr"
+	if flagAdhanFajr {
+		name = "Fajr"
+	}
+	file := adhan.Sound(name, cfg.AdhanSound, cfg.AdhanFajrSound)
+	if file == "" {
+		fmt.Fprintf(w, "No adhan plays at %s with the current config.\n", name)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Playing %s...\n", file)
+	return adhan.Play(cfg.AdhanPlayer, file)
+}
+
+// adhanAlert plays the adhan when the dashboard sees a prayer begin.
+type adhanAlert struct {
+	sound, fajrSound, player string
+	// pending is the next prayer of the last frame; the adhan plays when a
+	// later frame's current prayer is it.
+	pending *prayer.Prayer
+}
+
+// newAdhanAlert returns an alert for cfg, or nil when no sound is set.
+func newAdhanAlert(cfg *config.Config) *adhanAlert {
+	if cfg.AdhanSound == "" && cfg.AdhanFajrSound == "" {
+		return nil
+	}
+	return &adhanAlert{sound: cfg.AdhanSound, fajrSound: cfg.AdhanFajrSound, player: cfg.AdhanPlayer}
+}
+
+// due returns the sound to play for frame f, or "" when no prayer has begun
+// since the previous frame. A prayer already in progress when the dashboard
+// starts does not trigger the adhan.
+func (a *adhanAlert) due(f watchFrame) string {
+	prev := a.pending
+	a.pending = f.Next
+	if prev == nil || f.Prev == nil || f.Prev.Name != prev.Name || !f.Prev.Time.Equal(prev.Time) {
+		return ""
+	}
+	return adhan.Sound(prev.Name, a.sound, a.fajrSound)
+}
+
+// play starts playback of file without waiting for it to finish.
+func (a *adhanAlert) play(file string) error {
+	return startSound(a.player, file)
+}
+
+// chimeAlert plays a short chime when the next prayer comes within before.
+type chimeAlert struct {
+	sound, player string
+	before        time.Duration
+	prayers       map[string]bool
+	// armed is the next prayer while it is still further away than before;
+	// the chime plays when a later frame finds it within before.
+	armed *prayer.Prayer
+}
+
+// defaultChimePrayers are the prayers chimed for when chime_prayers isn't
+// set.
+var defaultChimePrayers = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// newChimeAlert returns an alert for cfg, or nil when no chime sound is set.
+func newChimeAlert(cfg *config.Config) (*chimeAlert, error) {
+	if cfg.ChimeSound == "" {
+		return nil, nil
+	}
+	before, err := config.ParseChimeBefore(cfg.ChimeBefore)
+	if err != nil {
+		return nil, err
+	}
+	names, err := normalizePrayerNames(strings.Split(cfg.ChimePrayers, ","), defaultChimePrayers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chime_prayers: %w", err)
+	}
+	a := &chimeAlert{sound: cfg.ChimeSound, player: cfg.AdhanPlayer, before: before, prayers: make(map[string]bool)}
+	for _, name := range names {
+		a.prayers[name] = true
+	}
+	return a, nil
+}
+
+// due reports whether the chime should play for frame f: its next prayer is
+// one to chime for and has come within before since an earlier frame. A
+// prayer already that close when the dashboard starts gets no chime.
+func (a *chimeAlert) due(f watchFrame) bool {
+	next := f.Next
+	if next == nil || !a.prayers[next.Name] {
+		a.armed = nil
+		return false
+	}
+	if next.Time.Sub(f.Now) > a.before {
+		a.armed = next
+		return false
+	}
+	armed := a.armed
+	a.armed = nil
+	return armed != nil && armed.Name == next.Name && armed.Time.Equal(next.Time)
+}
+
+// play starts the chime without waiting for it to finish.
+func (a *chimeAlert) play() error {
+	return startSound(a.player, a.sound)
+}
+
+// startSound starts playing file with player and returns without waiting
+// for it to finish.
+func startSound(player, file string) error {
+	c, err := adhan.Command(player, file)
+	if err != nil {
+		return err
+	}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", c.Args[0], err)
+	}
+	go c.Wait()
+	return nil
+}
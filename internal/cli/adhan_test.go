@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdhanTestCmd(t *testing.T) {
+	isolateConfig(t)
+
+	_, stderr, code := runCLI(t, "adhan", "test")
+	if code == 0 || !strings.Contains(stderr, "adhan_sound") {
+		t.Errorf("adhan test without a sound = %d %q, want an error naming adhan_sound", code, stderr)
+	}
+
+	if _, stderr, code := runCLI(t, "config", "set", "adhan_sound", "/tmp/adhan.mp3"); code != 0 {
+		t.Fatal(stderr)
+	}
+	if _, stderr, code := runCLI(t, "config", "set", "adhan_fajr_sound", "none"); code != 0 {
+		t.Fatal(stderr)
+	}
+	out, stderr, code := runCLI(t, "adhan", "test", "--fajr")
+	if code != 0 {
+		t.Fatalf("adhan test --fajr exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "No adhan plays at Fajr") {
+		t.Errorf("adhan test --fajr = %q, want a silent Fajr", out)
+	}
+
+	_, stderr, code = runCLI(t, "adhan", "test", "--chime")
+	if code == 0 || !strings.Contains(stderr, "chime_sound") {
+		t.Errorf("adhan test --chime without a sound = %d %q, want an error naming chime_sound", code, stderr)
+	}
+}
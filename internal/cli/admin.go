@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// serveDaemon is serve's HTTP handler. It forwards the public endpoints to
+// the current server and, when admin_token is set, serves the /admin API
+// that lets a central script manage the instance: change its config, refetch
+// its times, push an announcement to its display and report its version.
+type serveDaemon struct {
+	ctx     context.Context
+	cmd     *cobra.Command
+	version string
+	started time.Time
+
+	// mu guards everything below. Public requests hold it for reading, so a
+	// config change waits for them and swaps the server in between.
+	mu           sync.RWMutex
+	token        string
+	srv          *server
+	routes       http.Handler
+	notifier     *eventNotifier
+	stopNotifier func()
+	announcement *announcementJSON
+
+	heartbeatURL   string
+	heartbeatEvery time.Duration
+}
+
+// announcementJSON is a message pushed to the display through the admin API.
+type announcementJSON struct {
+	Text    string     `json:"text"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// adminVersionJSON is the JSON output of GET /admin/version.
+type adminVersionJSON struct {
+	Version string    `json:"version"`
+	Started time.Time `json:"started"`
+}
+
+// newServeDaemon starts serving s with cfg's notifications.
+func newServeDaemon(ctx context.Context, cmd *cobra.Command, cfg *config.Config, s *server) (*serveDaemon, error) {
+	d := &serveDaemon{ctx: ctx, cmd: cmd, version: cmd.Root().Version, started: time.Now()}
+	if err := d.install(cfg, s); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// install makes s the current server, restarting the notifier with cfg's
+// webhooks and MQTT broker and taking its heartbeat settings. The caller holds mu for writing, or d isn't
+// serving yet.
+func (d *serveDaemon) install(cfg *config.Config, s *server) error {
+	n, err := newEventNotifier(cfg, s)
+	if err != nil {
+		return err
+	}
+	every, err := config.ParseHeartbeatInterval(cfg.HeartbeatInterval)
+	if err != nil {
+		return err
+	}
+	d.stopNotifying()
+	d.startNotifying(n)
+	d.token = cfg.AdminToken
+	d.heartbeatURL, d.heartbeatEvery = cfg.HeartbeatURL, every
+	d.srv = s
+	d.routes = s.routes()
+	return nil
+}
+
+// startNotifying runs n, if any, until stopNotifying is called.
+func (d *serveDaemon) startNotifying(n *eventNotifier) {
+	d.notifier = n
+	if n == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(d.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		n.run(ctx)
+	}()
+	d.stopNotifier = func() {
+		cancel()
+		<-done
+	}
+}
+
+// stopNotifying stops the running notifier and waits for it to return.
+func (d *serveDaemon) stopNotifying() {
+	if d.stopNotifier != nil {
+		d.stopNotifier()
+		d.stopNotifier = nil
+	}
+}
+
+// reload applies the config file on disk, swapping in a server built from
+// it. Flags given to serve still take precedence. If the config is invalid,
+// the current settings are kept and the error returned. The caller holds mu
+// for writing.
+func (d *serveDaemon) reload() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	merged := mergeConfig(d.cmd, cfg)
+	next, err := parseSettings(merged)
+	if err != nil {
+		return err
+	}
+
+	// The notifier and background fetches read the settings being replaced,
+	// so they stop first; public requests wait on mu.
+	notifier := d.notifier
+	d.stopNotifying()
+	waitForPrefetches()
+	previous := currentSettings()
+	next.apply()
+	s, err := newServer(d.ctx, merged)
+	if err == nil {
+		err = d.install(merged, s)
+	}
+	if err != nil {
+		previous.apply()
+		d.startNotifying(notifier)
+		return err
+	}
+	loadedConfig = cfg
+	return nil
+}
+
+// handler registers the admin and announcement endpoints in front of the
+// current server's routes.
+func (d *serveDaemon) handler() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /announcement", d.handleAnnouncement)
+	mux.HandleFunc("GET /admin/version", d.authorized(d.handleAdminVersion))
+	mux.HandleFunc("PATCH /admin/config", d.authorized(d.handleAdminConfig))
+	mux.HandleFunc("POST /admin/refresh", d.authorized(d.handleAdminRefresh))
+	mux.HandleFunc("PUT /admin/announcement", d.authorized(d.handleAdminAnnounce))
+	mux.HandleFunc("DELETE /admin/announcement", d.authorized(d.handleAdminAnnounce))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+		d.routes.ServeHTTP(w, r)
+	})
+	return mux
+}
+
+// authorized wraps an admin handler, requiring the admin_token as a bearer
+// token. Without admin_token the admin API doesn't exist.
+func (d *serveDaemon) authorized(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d.mu.RLock()
+		token := d.token
+		d.mu.RUnlock()
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="prayer-times"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid admin token"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (d *serveDaemon) handleAnnouncement(w http.ResponseWriter, r *http.Request) {
+	d.mu.RLock()
+	a := d.announcement
+	d.mu.RUnlock()
+	if a == nil || (a.Expires != nil && time.Now().After(*a.Expires)) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func (d *serveDaemon) handleAdminVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, adminVersionJSON{Version: d.version, Started: d.started})
+}
+
+// handleAdminConfig sets the config keys in the request body, a JSON object
+// of key to value as for 'config set', saves the config and applies it.
+func (d *serveDaemon) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
+	var values map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(values) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no config keys given"))
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cfg, err := config.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := cfg.Set(key, values[key]); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	// Check the settings before saving, so a bad value can't leave a config
+	// behind that stops the next start.
+	if err := validateSettings(d.cmd, cfg); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := cfg.Save(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := d.reload(); err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("config saved but not applied: %w", err))
+		return
+	}
+	notices.Infof("Config updated through the admin API: %s", strings.Join(keys, ", "))
+	writeJSON(w, http.StatusOK, map[string][]string{"updated": keys})
+}
+
+// validateSettings checks the settings cfg would give d's command, leaving
+// the ones in use untouched.
+func validateSettings(cmd *cobra.Command, cfg *config.Config) error {
+	_, err := parseSettings(mergeConfig(cmd, cfg))
+	return err
+}
+
+// handleAdminRefresh re-reads the config file, for changes made outside the
+// admin API, and refetches today's times from the API, replacing the cached
+// ones.
+func (d *serveDaemon) handleAdminRefresh(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.reload(); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s := d.srv
+	today := s.loc.localTime(time.Now())
+	if _, err := fetchDay(r.Context(), today, s.loc, s.method, s.school, s.cache); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	notices.Infof("Refreshed through the admin API")
+	writeJSON(w, http.StatusOK, map[string]string{"refreshed": today.Format("2006-01-02")})
+}
+
+// handleAdminAnnounce sets the announcement from the request body (PUT) or
+// removes it (DELETE).
+func (d *serveDaemon) handleAdminAnnounce(w http.ResponseWriter, r *http.Request) {
+	var a *announcementJSON
+	if r.Method == http.MethodPut {
+		a = &announcementJSON{}
+		if err := json.NewDecoder(r.Body).Decode(a); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+		if strings.TrimSpace(a.Text) == "" {
+			writeError(w, http.StatusBadRequest, errors.New("announcement text is empty"))
+			return
+		}
+	}
+
+	d.mu.Lock()
+	d.announcement = a
+	d.mu.Unlock()
+
+	if a == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+)
+
+// TestServeAdmin verifies the admin API needs the token, reports the version,
+// publishes announcements and applies config changes to the running server.
+func TestServeAdmin(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	cfg := &config.Config{}
+	for key, value := range map[string]string{
+		"latitude": "21.4225", "longitude": "39.8262",
+		"cache_dir": t.TempDir(), "admin_token": "s3cret",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	previous := loadedConfig
+	loadedConfig = cfg
+	t.Cleanup(func() { loadedConfig = previous })
+
+	root := NewRootCmd("1.2.3")
+	cmd, _, err := root.Find([]string{"serve"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newServer(context.Background(), effectiveConfig(cmd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := newServeDaemon(context.Background(), cmd, effectiveConfig(cmd), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(d.handler())
+	defer ts.Close()
+
+	do := func(method, path, token, body string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	if code, _ := do("GET", "/admin/version", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("version without token = %d, want 401", code)
+	}
+	if code, _ := do("GET", "/admin/version", "wrong", ""); code != http.StatusUnauthorized {
+		t.Errorf("version with a wrong token = %d, want 401", code)
+	}
+	code, body := do("GET", "/admin/version", "s3cret", "")
+	var version adminVersionJSON
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &version) != nil || version.Version != "1.2.3" {
+		t.Errorf("version = %d %s, want 1.2.3", code, body)
+	}
+
+	if code, _ := do("GET", "/announcement", "", ""); code != http.StatusNoContent {
+		t.Errorf("announcement before one is set = %d, want 204", code)
+	}
+	if code, body := do("PUT", "/admin/announcement", "s3cret", `{"text":"Eid prayer at 7:00"}`); code != http.StatusOK {
+		t.Fatalf("PUT announcement = %d %s", code, body)
+	}
+	if code, body := do("GET", "/announcement", "", ""); code != http.StatusOK || !strings.Contains(body, "Eid prayer at 7:00") {
+		t.Errorf("announcement = %d %s, want the pushed text", code, body)
+	}
+	if code, _ := do("PUT", "/admin/announcement", "s3cret", `{"text":"old","expires":"2020-01-01T00:00:00Z"}`); code != http.StatusOK {
+		t.Fatalf("PUT expired announcement = %d", code)
+	}
+	if code, _ := do("GET", "/announcement", "", ""); code != http.StatusNoContent {
+		t.Errorf("expired announcement = %d, want 204", code)
+	}
+
+	if code, body := do("PATCH", "/admin/config", "s3cret", `{"method":"4"}`); code != http.StatusOK {
+		t.Fatalf("PATCH config = %d %s", code, body)
+	}
+	if d.srv.method != 4 {
+		t.Errorf("server method = %d after config change, want 4", d.srv.method)
+	}
+	saved, err := config.Load()
+	if err != nil || saved.Method == nil || *saved.Method != 4 {
+		t.Errorf("config file not updated: %+v, %v", saved, err)
+	}
+	if code, _ := do("PATCH", "/admin/config", "s3cret", `{"tune":"Fajr:x"}`); code != http.StatusBadRequest {
+		t.Errorf("invalid config value = %d, want 400", code)
+	}
+	if saved, _ := config.Load(); saved.Tune != "" {
+		t.Errorf("invalid tune %q was saved", saved.Tune)
+	}
+
+	if code, body := do("POST", "/admin/refresh", "s3cret", ""); code != http.StatusOK {
+		t.Errorf("refresh = %d %s", code, body)
+	}
+	if code, _ := do("GET", "/today", "", ""); code != http.StatusOK {
+		t.Errorf("today after admin changes = %d, want 200", code)
+	}
+
+	// Clearing the token disables the admin API.
+	if code, body := do("PATCH", "/admin/config", "s3cret", `{"admin_token":""}`); code != http.StatusOK {
+		t.Fatalf("PATCH admin_token = %d %s", code, body)
+	}
+	if code, _ := do("GET", "/admin/version", "s3cret", ""); code != http.StatusNotFound {
+		t.Errorf("version without admin_token = %d, want 404", code)
+	}
+}
+
+// TestServeAdminKeepsFlags verifies a config change through the admin API
+// saves only the changed keys, not serve's flags or the built-in defaults,
+// while the notifier keeps running.
+func TestServeAdminKeepsFlags(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer hook.Close()
+
+	cfg := &config.Config{}
+	for key, value := range map[string]string{
+		"cache_dir": t.TempDir(), "admin_token": "s3cret", "webhooks": hook.URL,
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	previous := loadedConfig
+	loadedConfig = cfg
+	t.Cleanup(func() { loadedConfig = previous })
+
+	root := NewRootCmd("1.2.3")
+	cmd, _, err := root.Find([]string{"serve"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags([]string{"--latitude", "21.4225", "--longitude", "39.8262", "--time-format", "12h", "--tune", "Fajr:+2"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newServer(context.Background(), effectiveConfig(cmd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := newServeDaemon(context.Background(), cmd, effectiveConfig(cmd), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.stopNotifying()
+	ts := httptest.NewServer(d.handler())
+	defer ts.Close()
+
+	req, _ := http.NewRequest("PATCH", ts.URL+"/admin/config", strings.NewReader(`{"school":"1"}`))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PATCH config = %d", resp.StatusCode)
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.School == nil || *saved.School != 1 {
+		t.Errorf("school not saved: %+v", saved)
+	}
+	if saved.Latitude != nil || saved.TimeFormat != "" || saved.Tune != "" || saved.Method != nil {
+		t.Errorf("serve's flags or defaults were saved: %+v", saved)
+	}
+	if tuneSettings == "" || d.srv.school != 1 {
+		t.Errorf("config change lost serve's flags: tune %q, school %d", tuneSettings, d.srv.school)
+	}
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+// The --alert-threshold and --alert-style flags shared by next and status.
+var (
+	flagAlertThreshold time.Duration
+	flagAlertStyle     string
+)
+
+// addAlertFlags registers --alert-threshold and --alert-style on cmd.
+func addAlertFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&flagAlertThreshold, "alert-threshold", 0, "Style the output with --alert-style when the next prayer is less than this away, e.g. 15m (0 to disable)")
+	cmd.Flags().StringVar(&flagAlertStyle, "alert-style", "red,bold", "Terminal style of an imminent prayer: a comma-separated list of bold, dim, red, green, yellow, blue, magenta, cyan and gray")
+}
+
+// applyAlert wraps text in --alert-style when next is within
+// --alert-threshold of now. Like the rest of the colour output, the style is
+// left out when stdout isn't a terminal or NO_COLOR is set; tmux and bar
+// have their own --urgent styles for status lines.
+func applyAlert(text string, next prayer.Prayer, now time.Time) (string, error) {
+	code, err := display.ParseStyle(flagAlertStyle)
+	if err != nil {
+		return "", fmt.Errorf("invalid --alert-style: %w", err)
+	}
+	if !prayer.Imminent(next, now, flagAlertThreshold) {
+		return text, nil
+	}
+	return display.Styled(code, text), nil
+}
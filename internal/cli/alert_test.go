@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+func TestApplyAlert(t *testing.T) {
+	prev := display.Enabled()
+	display.SetEnabled(true)
+	defer display.SetEnabled(prev)
+	defer func(threshold time.Duration, style string) {
+		flagAlertThreshold, flagAlertStyle = threshold, style
+	}(flagAlertThreshold, flagAlertStyle)
+
+	now := time.Date(2026, 2, 28, 14, 50, 0, 0, time.UTC)
+	asr := prayer.Prayer{Name: "Asr", Time: time.Date(2026, 2, 28, 15, 2, 0, 0, time.UTC)}
+
+	flagAlertThreshold, flagAlertStyle = 15*time.Minute, "yellow"
+	if got, err := applyAlert("Asr 12m", asr, now); err != nil || got != "\033[33mAsr 12m\033[0m" {
+		t.Errorf("applyAlert() within the threshold = %q, %v; want it styled", got, err)
+	}
+	flagAlertThreshold = 10 * time.Minute
+	if got, err := applyAlert("Asr 12m", asr, now); err != nil || got != "Asr 12m" {
+		t.Errorf("applyAlert() outside the threshold = %q, %v; want it plain", got, err)
+	}
+	flagAlertStyle = "blink"
+	if _, err := applyAlert("Asr 12m", asr, now); err == nil || !strings.Contains(err.Error(), "--alert-style") {
+		t.Errorf("applyAlert() with a bad style error = %v, want one naming the flag", err)
+	}
+}
+
+func TestNextAlertStyleInvalid(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	_, stderr, code := runCLI(t, append([]string{"next", "--alert-threshold", "15m", "--alert-style", "sparkly"}, meccaArgs(t)...)...)
+	if code == 0 || !strings.Contains(stderr, "--alert-style") {
+		t.Errorf("next with an invalid --alert-style = %d, %q; want an error naming it", code, stderr)
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// aliasAnnotation marks alias commands and holds their command line.
+const aliasAnnotation = "alias"
+
+// addAliases registers the config file's aliases as subcommands of root.
+// Aliases named like a command are skipped, as git does; a config file that
+// fails to load is reported once the command runs.
+func addAliases(root *cobra.Command) {
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if builtinCommand(root, name) != nil {
+			continue
+		}
+		root.AddCommand(newAliasCmd(name, cfg.Aliases[name]))
+	}
+}
+
+func newAliasCmd(name, line string) *cobra.Command {
+	return &cobra.Command{
+		Use:         name,
+		Short:       fmt.Sprintf("Alias for '%s'", line),
+		Annotations: map[string]string{aliasAnnotation: line},
+		// Everything after the alias goes to the command it expands to.
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAlias(cmd.Root(), name, line, args)
+		},
+	}
+}
+
+// runAlias runs root again with the alias expanded in front of args.
+func runAlias(root *cobra.Command, name, line string, args []string) error {
+	expanded, err := expandAlias(root, line)
+	if err != nil {
+		return fmt.Errorf("alias %s: %w", name, err)
+	}
+	root.SetArgs(append(expanded, args...))
+	return root.Execute()
+}
+
+// expandAlias splits an alias's command line and checks that it starts with
+// a command or a flag (for the default action). Aliases can't refer to other
+// aliases, so expansion can't loop.
+func expandAlias(root *cobra.Command, line string) ([]string, error) {
+	args, err := config.SplitAlias(line)
+	if err != nil {
+		return nil, err
+	}
+	if first := args[0]; !strings.HasPrefix(first, "-") && builtinCommand(root, first) == nil {
+		if c, _, err := root.Find([]string{first}); err == nil && c.Annotations[aliasAnnotation] != "" {
+			return nil, fmt.Errorf("%q is another alias; aliases must start with a command", first)
+		}
+		return nil, fmt.Errorf("unknown command %q", first)
+	}
+	return args, nil
+}
+
+// builtinCommand returns root's subcommand named name (or with name as a
+// cobra alias), or nil when there is none. Alias commands don't count.
+func builtinCommand(root *cobra.Command, name string) *cobra.Command {
+	if name == "help" {
+		return root
+	}
+	for _, c := range root.Commands() {
+		if c.Annotations[aliasAnnotation] != "" {
+			continue
+		}
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// checkAliasKey verifies that an aliases.<name> key doesn't shadow a command
+// and that its value starts with a command.
+func checkAliasKey(root *cobra.Command, key, value string) error {
+	name, err := config.ParseAliasKey(key)
+	if err != nil {
+		return err
+	}
+	if builtinCommand(root, name) != nil {
+		return fmt.Errorf("alias %q would shadow the %s command", name, name)
+	}
+	if value == "" {
+		return nil
+	}
+	if _, err := expandAlias(root, value); err != nil {
+		return fmt.Errorf("alias %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	if _, stderr, code := runCLI(t, "config", "set", "aliases.iftar", "query Maghrib --json"); code != 0 {
+		t.Fatalf("config set exited with %d: %s", code, stderr)
+	}
+
+	want, _, _ := runCLI(t, append([]string{"query", "Maghrib", "--json"}, meccaArgs(t)...)...)
+	out, stderr, code := runCLI(t, append(meccaArgs(t), "iftar")...)
+	if code != 0 {
+		t.Fatalf("iftar exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, `"18:10"`) || out != want {
+		t.Errorf("iftar = %q, want the output of query Maghrib --json %q", out, want)
+	}
+
+	help, _, _ := runCLI(t, "--help")
+	if !strings.Contains(help, "Alias for 'query Maghrib --json'") {
+		t.Errorf("help should list the alias:\n%s", help)
+	}
+}
+
+func TestAlias_Invalid(t *testing.T) {
+	isolateConfig(t)
+
+	for _, args := range [][]string{
+		{"aliases.next", "query Fajr"},  // shadows a command
+		{"aliases.fajr", "fetch Fajr"},  // unknown command
+		{"aliases.fajr", "query 'Fajr"}, // unterminated quote
+	} {
+		if _, _, code := runCLI(t, append([]string{"config", "set"}, args...)...); code == 0 {
+			t.Errorf("config set %v should fail", args)
+		}
+	}
+
+	if _, stderr, code := runCLI(t, "config", "set", "aliases.fajr", "query Fajr"); code != 0 {
+		t.Fatal(stderr)
+	}
+	if _, _, code := runCLI(t, "config", "set", "aliases.dawn", "fajr"); code == 0 {
+		t.Error("an alias of an alias should be rejected")
+	}
+}
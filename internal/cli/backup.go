@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBackupCache bool
+	flagBackupYes   bool
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up or restore the config, state and cache",
+		Long: `Bundle the config directory, the data directory (history archive, khatmah
+plan) and the state directory (--timing stats) into one .tar.gz, optionally with the cache, and restore it on another
+machine. 'data export' archives can be restored too.`,
+		Example: `  prayer-times backup create
+  prayer-times backup create kiosk.tar.gz --cache
+  prayer-times backup restore kiosk.tar.gz`,
+	}
+	cmd.PersistentFlags().BoolVarP(&flagBackupYes, "yes", "y", false, "Don't ask before overwriting")
+
+	create := &cobra.Command{
+		Use:   "create [file]",
+		Short: "Write a backup (default: prayer-times-backup-YYYY-MM-DD.tar.gz; - for stdout)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBackupCreate,
+	}
+	create.Flags().BoolVar(&flagBackupCache, "cache", false, "Include the cache, so the machine works offline straight away")
+	cmd.AddCommand(create)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restore <file>",
+		Short: "Restore a backup over the current files (- for stdin)",
+		Long:  "Restore a backup over the current files. Files the backup doesn't hold are\nleft alone. Cache files are restored into --cache-dir or the cache_dir key.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runBackupRestore,
+	})
+
+	return cmd
+}
+
+// runBackupCreate writes the config, data and state directories, and the cache
+// files with --cache, to a gzipped tar archive.
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	path := "prayer-times-backup-" + time.Now().Format("2006-01-02") + ".tar.gz"
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	confDir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	dDir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	sDir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	sources := []dataSource{{"config", confDir}, {"data", dDir}, {"state", sDir}}
+	if flagBackupCache {
+		cDir, err := cacheDir(cmd)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(cDir); err == nil {
+			c, err := cache.New(cDir)
+			if err != nil {
+				return err
+			}
+			files, err := c.Files()
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				sources = append(sources, dataSource{"cache/" + filepath.Base(f), f})
+			}
+		}
+	}
+
+	n, err := createDataArchive(cmd, path, flagBackupYes, sources)
+	if err != nil {
+		return err
+	}
+	if path != "-" {
+		fmt.Fprintf(w, "Backed up %d files to %s.\n", n, path)
+	}
+	return nil
+}
+
+// runBackupRestore extracts a backup into the config, data, state and cache
+// directories.
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	confDir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	dDir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	sDir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	cDir, err := cacheDir(cmd)
+	if err != nil {
+		return err
+	}
+	targets := map[string]string{"config": confDir, "data": dDir, "state": sDir, "cache": cDir}
+
+	if _, err := os.Stat(filepath.Join(confDir, "config.json")); err == nil && !flagBackupYes &&
+		!confirm(cmd, "Restoring replaces the current config. Continue?") {
+		return errors.New("restore cancelled; use --yes to skip the prompt")
+	}
+
+	var in io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("cannot open backup: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	n, err := restoreDataArchive(in, targets)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Restored %d files.\n", n)
+	return nil
+}
+
+// restoreDataArchive extracts the regular files of a gzipped tar written by
+// writeDataArchive. Each name's first element picks its directory from
+// targets; names outside targets, or escaping their directory, are rejected
+// before anything is written. It returns how many files were restored.
+func restoreDataArchive(r io.Reader, targets map[string]string) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	type entry struct {
+		dest string
+		mode os.FileMode
+		data []byte
+	}
+	var entries []entry
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("not a backup archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(hdr.Name)
+		top, rel, _ := strings.Cut(name, "/")
+		dir, ok := targets[top]
+		if !ok || rel == "" || !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return 0, fmt.Errorf("unexpected file %q in backup", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read backup: %w", err)
+		}
+		entries = append(entries, entry{filepath.Join(dir, filepath.FromSlash(rel)), hdr.FileInfo().Mode().Perm(), data})
+	}
+	if len(entries) == 0 {
+		return 0, errors.New("the backup holds no files")
+	}
+
+	for _, e := range entries {
+		if err := os.MkdirAll(filepath.Dir(e.dest), 0o755); err != nil {
+			return 0, fmt.Errorf("cannot create directory for %s: %w", e.dest, err)
+		}
+		if err := os.WriteFile(e.dest, e.data, e.mode); err != nil {
+			return 0, fmt.Errorf("failed to restore %s: %w", e.dest, err)
+		}
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/geo"
+)
+
+// TestBackup verifies a backup made on one machine restores on another.
+func TestBackup(t *testing.T) {
+	isolateConfig(t)
+	cacheDir := t.TempDir()
+
+	if _, stderr, code := runCLI(t, "config", "set", "city", "Riyadh"); code != 0 {
+		t.Fatal(stderr)
+	}
+	c, _ := cache.New(cacheDir)
+	c.SaveGeo(&geo.Location{City: "Riyadh"})
+	sDir, err := stateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(sDir, 0o755)
+	os.WriteFile(filepath.Join(sDir, "timing.json"), []byte(`{"runs":[]}`), 0o644)
+
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	out, stderr, code := runCLI(t, "backup", "create", path, "--cache", "--cache-dir", cacheDir)
+	if code != 0 {
+		t.Fatalf("backup create exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "Backed up 3 files") {
+		t.Errorf("backup create = %q, want the config, the timing stats and the cached location", out)
+	}
+
+	// A fresh machine.
+	isolateConfig(t)
+	newCache := t.TempDir()
+	if _, stderr, code := runCLI(t, "backup", "restore", path, "--cache-dir", newCache); code != 0 {
+		t.Fatalf("backup restore exited with %d: %s", code, stderr)
+	}
+	if out, _, _ := runCLI(t, "config"); !strings.Contains(out, "Riyadh") {
+		t.Errorf("config after restore should contain Riyadh:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(newCache, "geolocation.json")); err != nil {
+		t.Errorf("cached location wasn't restored: %v", err)
+	}
+	sDir, _ = stateDir()
+	if _, err := os.Stat(filepath.Join(sDir, "timing.json")); err != nil {
+		t.Errorf("timing stats weren't restored: %v", err)
+	}
+
+	// With a config in place, restoring needs confirmation.
+	if _, _, code := runCLI(t, "backup", "restore", path, "--cache-dir", newCache); code == 0 {
+		t.Error("restoring over a config should need confirmation")
+	}
+}
+
+func TestRestoreDataArchive_RejectsEscapes(t *testing.T) {
+	for _, name := range []string{"config/../../evil", "other/file", "/etc/passwd"} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 1, Typeflag: tar.TypeReg})
+		tw.Write([]byte("x"))
+		tw.Close()
+		gz.Close()
+
+		dir := t.TempDir()
+		if _, err := restoreDataArchive(&buf, map[string]string{"config": filepath.Join(dir, "config")}); err == nil {
+			t.Errorf("restoring %q should fail", name)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBarOutput      string
+	flagBarFormat      string
+	flagBarUrgent      time.Duration
+	flagBarUrgentColor string
+)
+
+func newBarCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bar",
+		Short: "Print the next prayer for a status bar (Waybar, Polybar, i3blocks)",
+		Long: `Print the next prayer in the format a status bar module expects:
+
+  waybar    JSON with text, tooltip (today's schedule), class and alt
+  polybar   one line, colored with --urgent-color when urgent
+  i3blocks  full_text, short_text and, when urgent, color lines
+
+A prayer is urgent when it is less than --urgent away; Waybar gets the
+"urgent" class to style in its CSS, otherwise "normal".`,
+		Example: `  prayer-times bar
+  prayer-times bar --output polybar --format name-and-remaining
+  prayer-times bar --output i3blocks --urgent 10m`,
+		Args: cobra.NoArgs,
+		RunE: runBar,
+	}
+
+	cmd.Flags().StringVar(&flagBarOutput, "output", "waybar", "Status bar: waybar, polybar or i3blocks")
+	cmd.Flags().StringVar(&flagBarFormat, "format", prayer.FormatNameAndRemaining, "Display format for the text (see 'next --format')")
+	cmd.Flags().DurationVar(&flagBarUrgent, "urgent", 15*time.Minute, "Mark the next prayer urgent when it is less than this away (0 to disable)")
+	cmd.Flags().StringVar(&flagBarUrgentColor, "urgent-color", "#e06c75", "Color of urgent text for polybar and i3blocks")
+
+	return cmd
+}
+
+// barState is what a status bar module shows.
+type barState struct {
+	Text    string
+	Short   string
+	Tooltip string
+	Alt     string // lowercase prayer name, for Waybar format-icons
+	Urgent  bool
+}
+
+// waybarJSON is a Waybar custom module's return-type json line.
+type waybarJSON struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+	Alt     string `json:"alt,omitempty"`
+}
+
+// renderBar writes s in the format of the given status bar.
+func renderBar(w io.Writer, output string, s barState, urgentColor string) error {
+	switch output {
+	case "waybar":
+		out := waybarJSON{Text: s.Text, Tooltip: s.Tooltip, Class: "normal", Alt: s.Alt}
+		if s.Urgent {
+			out.Class = "urgent"
+		}
+		// Waybar reads one JSON object per line.
+		data, err := json.Marshal(out)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+	case "polybar":
+		if s.Urgent {
+			fmt.Fprintf(w, "%%{F%s}%s%%{F-}\n", urgentColor, s.Text)
+		} else {
+			fmt.Fprintln(w, s.Text)
+		}
+	case "i3blocks":
+		fmt.Fprintln(w, s.Text)
+		fmt.Fprintln(w, s.Short)
+		if s.Urgent {
+			fmt.Fprintln(w, urgentColor)
+		}
+	default:
+		return fmt.Errorf("invalid --output %q: must be waybar, polybar or i3blocks", output)
+	}
+	return nil
+}
+
+// barTooltip lists the day's prayers, marking the next one.
+func barTooltip(prayers []prayer.Prayer, next *prayer.Prayer, goTimeFmt string) string {
+	lines := make([]string, 0, len(prayers))
+	for _, p := range prayers {
+		line := fmt.Sprintf("%-8s %s", p.Name, p.Time.Format(goTimeFmt))
+		if next != nil && p.Name == next.Name && p.Time.Equal(next.Time) {
+			line += "  ←"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statusSnapshot is the day's schedule and next prayer, for status bar output.
+type statusSnapshot struct {
+	Prayers   []prayer.Prayer
+	Next      *prayer.Prayer // nil when tomorrow's times can't be fetched
+	Start     time.Time      // when the window ending at Next began; zero if unknown
+	Now       time.Time
+	GoTimeFmt string
+}
+
+// loadStatus resolves the location and finds the next prayer, as next does.
+// Failing to fetch tomorrow's times is not an error: status bars show the
+// day's last prayer instead of going blank.
+func loadStatus(cmd *cobra.Command) (statusSnapshot, error) {
+	ctx := cmd.Context()
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return statusSnapshot{}, err
+	}
+	now := loc.localTime(currentTime())
+
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
+	if err != nil {
+		return statusSnapshot{}, err
+	}
+
+	tz := loc.timezoneFor(result.Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return statusSnapshot{}, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	now = now.In(tzLoc)
+
+	prayers, err := prayer.ParseTimings(result.Timings, now, tzLoc, selectedPrayers)
+	if err != nil {
+		return statusSnapshot{}, err
+	}
+
+	next, err := nextPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	if err != nil {
+		next = nil
+	}
+	start := windowStart(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	return statusSnapshot{Prayers: prayers, Next: next, Start: start, Now: now, GoTimeFmt: goTimeFormat(cfg)}, nil
+}
+
+// staleText is the status text when the next prayer is unknown: the day's
+// last prayer with no time.
+func (s statusSnapshot) staleText() string {
+	if len(s.Prayers) == 0 {
+		return "--:--"
+	}
+	return s.Prayers[len(s.Prayers)-1].Name + " --:--"
+}
+
+func runBar(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	if flagBarOutput != "waybar" && flagBarOutput != "polybar" && flagBarOutput != "i3blocks" {
+		return fmt.Errorf("invalid --output %q: must be waybar, polybar or i3blocks", flagBarOutput)
+	}
+
+	st, err := loadStatus(cmd)
+	if err != nil {
+		return err
+	}
+
+	if st.Next == nil {
+		state := barState{Text: st.staleText(), Short: "--:--", Tooltip: barTooltip(st.Prayers, nil, st.GoTimeFmt)}
+		return renderBar(w, flagBarOutput, state, flagBarUrgentColor)
+	}
+
+	next := *st.Next
+	state := barState{
+		Text:    prayer.FormatOutputSince(next, st.Start, st.Now, flagBarFormat, st.GoTimeFmt),
+		Short:   prayer.FormatOutput(next, st.Now, prayer.FormatShortNameAndRemain, st.GoTimeFmt),
+		Tooltip: barTooltip(st.Prayers, st.Next, st.GoTimeFmt),
+		Alt:     strings.ToLower(next.Name),
+		Urgent:  prayer.Imminent(next, st.Now, flagBarUrgent),
+	}
+	return renderBar(w, flagBarOutput, state, flagBarUrgentColor)
+}
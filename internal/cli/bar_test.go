@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+func TestRenderBar(t *testing.T) {
+	s := barState{Text: "Asr in 10m", Short: "A 10m", Tooltip: "Fajr 05:30\nAsr 15:45", Alt: "asr", Urgent: true}
+
+	tests := []struct {
+		output string
+		urgent bool
+		want   string
+	}{
+		{"waybar", true, `{"text":"Asr in 10m","tooltip":"Fajr 05:30\nAsr 15:45","class":"urgent","alt":"asr"}` + "\n"},
+		{"waybar", false, `{"text":"Asr in 10m","tooltip":"Fajr 05:30\nAsr 15:45","class":"normal","alt":"asr"}` + "\n"},
+		{"polybar", true, "%{F#ff0000}Asr in 10m%{F-}\n"},
+		{"polybar", false, "Asr in 10m\n"},
+		{"i3blocks", true, "Asr in 10m\nA 10m\n#ff0000\n"},
+		{"i3blocks", false, "Asr in 10m\nA 10m\n"},
+	}
+	for _, tt := range tests {
+		s.Urgent = tt.urgent
+		var buf bytes.Buffer
+		if err := renderBar(&buf, tt.output, s, "#ff0000"); err != nil {
+			t.Fatalf("renderBar(%s) error: %v", tt.output, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("renderBar(%s, urgent=%v) = %q, want %q", tt.output, tt.urgent, buf.String(), tt.want)
+		}
+	}
+
+	if err := renderBar(&bytes.Buffer{}, "xmobar", s, ""); err == nil {
+		t.Error("renderBar(xmobar) expected error")
+	}
+}
+
+func TestBarTooltip(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	prayers := []prayer.Prayer{
+		{Name: "Fajr", Time: day.Add(5*time.Hour + 30*time.Minute)},
+		{Name: "Dhuhr", Time: day.Add(12*time.Hour + 30*time.Minute)},
+	}
+	got := barTooltip(prayers, &prayers[1], "15:04")
+	if want := "Fajr     05:30\nDhuhr    12:30  ←"; got != want {
+		t.Errorf("barTooltip() = %q, want %q", got, want)
+	}
+}
+
+func TestBarWaybar(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"bar"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("bar exited with %d: %s", code, stderr)
+	}
+	if strings.Count(out, "\n") != 1 {
+		t.Errorf("bar output should be a single line, got %q", out)
+	}
+	var got waybarJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Text == "" || got.Alt == "" || (got.Class != "normal" && got.Class != "urgent") {
+		t.Errorf("bar = %+v, want text, alt and a class", got)
+	}
+	if !strings.Contains(got.Tooltip, "Maghrib  18:10") {
+		t.Errorf("tooltip = %q, want the day's schedule", got.Tooltip)
+	}
+
+	if _, _, code := runCLI(t, append([]string{"bar", "--output", "xmobar"}, meccaArgs(t)...)...); code == 0 {
+		t.Error("bar --output xmobar: expected non-zero exit")
+	}
+}
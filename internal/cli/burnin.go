@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+)
+
+// shiftEvery is how long watch --shift keeps the dashboard in one place.
+const shiftEvery = 5 * time.Minute
+
+// shiftPositions are the offsets, in columns and rows, that watch --shift
+// moves the dashboard through. They are small enough to go unnoticed but
+// keep the same pixels from lighting the same text all day.
+var shiftPositions = [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 1}, {0, 1}}
+
+// blankScreen is what watch shows during the --blank-from/--blank-until
+// period: an empty screen, painted black when colours are enabled.
+func blankScreen() string {
+	if !display.Enabled() {
+		return clearScreen
+	}
+	return "\033[0;40m" + clearScreen
+}
+
+// shiftOffset returns the --shift offset for now.
+func shiftOffset(now time.Time) (dx, dy int) {
+	p := shiftPositions[int(now.Unix()/int64(shiftEvery/time.Second))%len(shiftPositions)]
+	return p[0], p[1]
+}
+
+// shiftFrame moves a rendered frame dx columns right and dy rows down.
+func shiftFrame(frame string, dx, dy int) string {
+	if dx == 0 && dy == 0 {
+		return frame
+	}
+	lines := strings.Split(frame, "\n")
+	pad := strings.Repeat(" ", dx)
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Repeat("\n", dy) + strings.Join(lines, "\n")
+}
+
+// blankPoint is one end of the blank period: a prayer plus an offset, such
+// as Isha+2h, or a clock time, such as 23:30.
+type blankPoint struct {
+	Prayer string        // empty for a clock time
+	Offset time.Duration // from the prayer, or from midnight for a clock time
+}
+
+// parseBlankPoint parses a --blank-from or --blank-until value.
+func parseBlankPoint(flag, value string) (blankPoint, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse("15:04", value); err == nil {
+		return blankPoint{Offset: time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute}, nil
+	}
+
+	name, offset := value, ""
+	if i := strings.IndexAny(value, "+-"); i >= 0 {
+		name, offset = value[:i], value[i:]
+	}
+	names, err := normalizePrayerNames([]string{name}, nil)
+	if err != nil {
+		return blankPoint{}, fmt.Errorf("invalid %s %q: %w", flag, value, err)
+	}
+	if len(names) == 0 {
+		return blankPoint{}, fmt.Errorf("invalid %s %q: must be a prayer with an optional offset, e.g. Isha+2h, or a time such as 23:30", flag, value)
+	}
+	p := blankPoint{Prayer: names[0]}
+	if offset != "" {
+		p.Offset, err = time.ParseDuration(strings.ReplaceAll(offset, " ", ""))
+		if err != nil {
+			return blankPoint{}, fmt.Errorf("invalid %s %q: offset must be a duration such as +2h or -30m", flag, value)
+		}
+	}
+	return p, nil
+}
+
+// timeOfDay returns the point's time as a duration since midnight, taking
+// a prayer's time from times.
+func (p blankPoint) timeOfDay(times map[string]time.Time) time.Duration {
+	d := p.Offset
+	if p.Prayer != "" {
+		t := times[p.Prayer]
+		d += time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	}
+	d %= 24 * time.Hour
+	if d < 0 {
+		d += 24 * time.Hour
+	}
+	return d
+}
+
+// inBlankPeriod reports whether now falls between from and until, given the
+// day's prayer times. A period whose end comes before its start, such as
+// Isha+2h to Fajr-1h, runs through midnight.
+func inBlankPeriod(from, until blankPoint, times map[string]time.Time, now time.Time) bool {
+	start, end := from.timeOfDay(times), until.timeOfDay(times)
+	clock := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+	if start <= end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
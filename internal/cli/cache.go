@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var flagCachePruneDays int
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect, clear, prune or warm the cache",
+		Long: `Manage the cache of fetched prayer times and the detected location, kept in
+~/.cache/prayer-times/ or --cache-dir (the cache_dir config key).`,
+		Example: `  prayer-times cache info
+  prayer-times cache prune --days 30
+  prayer-times cache warm`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "info",
+		Short: "Show the cache directory, its entries and their size",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheInfo,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Delete every cache entry",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheClear,
+	})
+	prune := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete entries not used in the last --days days",
+		Args:  cobra.NoArgs,
+		RunE:  runCachePrune,
+	}
+	prune.Flags().IntVar(&flagCachePruneDays, "days", 30, "Delete entries older than this many days")
+	cmd.AddCommand(prune)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "warm",
+		Short: "Prefetch this month and next into the cache",
+		Long:  "Fetch this month's and next month's prayer times for the current location\nand settings into the cache, so they are shown without network access.",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheWarm,
+	})
+
+	return cmd
+}
+
+// openCacheDir opens the cache directory in use, for commands that work on
+// its files rather than on timings for particular settings.
+func openCacheDir(cmd *cobra.Command) (*cache.Cache, error) {
+	dir, err := cacheDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cache.New(dir)
+	if err != nil {
+		return nil, err
+	}
+	c.Limit = cacheLimit(effectiveConfig(cmd))
+	return c, nil
+}
+
+// cacheInfoJSON is the JSON output structure for cache info.
+type cacheInfoJSON struct {
+	Dir       string              `json:"dir"`
+	Entries   int                 `json:"entries"`
+	Bytes     int64               `json:"bytes"`
+	Locks     int                 `json:"locks,omitempty"` // lock files, held or left over
+	Limit     *cacheLimitJSON     `json:"limit,omitempty"`
+	Evictions cache.EvictionStats `json:"evictions"`
+}
+
+// cacheLimitJSON is the cache_max_size setting in cache info's JSON; a zero
+// field is no cap.
+type cacheLimitJSON struct {
+	Entries int   `json:"entries,omitempty"`
+	Bytes   int64 `json:"bytes,omitempty"`
+}
+
+// runCacheInfo prints the cache directory, entry count and total size.
+func runCacheInfo(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	c, err := openCacheDir(cmd)
+	if err != nil {
+		return err
+	}
+	files, err := c.Files()
+	if err != nil {
+		return err
+	}
+	locks, err := c.Locks()
+	if err != nil {
+		return err
+	}
+	info := cacheInfoJSON{Dir: c.Dir(), Entries: len(files), Locks: len(locks), Evictions: c.EvictionStats()}
+	if !c.Limit.IsZero() {
+		info.Limit = &cacheLimitJSON{Entries: c.Limit.Entries, Bytes: c.Limit.Bytes}
+	}
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil {
+			info.Bytes += fi.Size()
+		}
+	}
+
+	if FlagJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(w, "  %-10s %s\n", "Directory", info.Dir)
+	fmt.Fprintf(w, "  %-10s %d\n", "Entries", info.Entries)
+	fmt.Fprintf(w, "  %-10s %s\n", "Size", formatBytes(info.Bytes))
+	if info.Locks > 0 {
+		fmt.Fprintf(w, "  %-10s %d\n", "Locks", info.Locks)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "Limit", c.Limit)
+	if ev := info.Evictions; ev.Evictions > 0 {
+		fmt.Fprintf(w, "  %-10s %d (%s), last %s\n", "Evicted", ev.Evictions, formatBytes(ev.Bytes), ev.Last.Local().Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// formatBytes formats n as B, KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
+
+// runCacheClear deletes every cache entry.
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	c, err := openCacheDir(cmd)
+	if err != nil {
+		return err
+	}
+	removed, err := c.Clear()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d cache entries from %s.\n", len(removed), c.Dir())
+	return nil
+}
+
+// runCachePrune deletes the entries not used in the last --days days.
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	if flagCachePruneDays < 0 {
+		return fmt.Errorf("invalid --days %d: must not be negative", flagCachePruneDays)
+	}
+	c, err := openCacheDir(cmd)
+	if err != nil {
+		return err
+	}
+	removed, err := c.Prune(time.Now().AddDate(0, 0, -flagCachePruneDays))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d cache entries unused for %d days.\n", len(removed), flagCachePruneDays)
+	return nil
+}
+
+// runCacheWarm fetches this month and next into the cache.
+func runCacheWarm(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg := effectiveConfig(cmd)
+	c := openCache(cfg)
+	if c == nil {
+		return fmt.Errorf("the cache is unavailable")
+	}
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	now := loc.localTime(currentTime())
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	next := start.AddDate(0, 1, 0)
+	days := start.AddDate(0, 1, -1).Day() + next.AddDate(0, 1, -1).Day()
+
+	if _, err := fetchCalendarDays(ctx, start, days, loc, cfg.MethodOrDefault(-1), cfg.SchoolOrDefault(-1), c); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Cached %s and %s (%d days).\n", start.Format("January 2006"), next.Format("January 2006"), days)
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestCacheCommand verifies cache warm fills the cache so commands work
+// offline, and that info, prune and clear see its entries.
+func TestCacheCommand(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	args := meccaArgs(t)
+
+	out, stderr, code := runCLI(t, append([]string{"cache", "warm"}, args...)...)
+	if code != 0 {
+		t.Fatalf("cache warm exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "Cached") {
+		t.Errorf("cache warm = %q", out)
+	}
+
+	out, _, _ = runCLI(t, append([]string{"cache", "info", "--json"}, args...)...)
+	var info cacheInfoJSON
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if info.Entries != 2 || info.Bytes == 0 {
+		t.Errorf("cache info = %+v, want the two warmed months", info)
+	}
+
+	// The warmed months answer without the API.
+	apiBaseURL = "http://127.0.0.1:1"
+	if _, stderr, code := runCLI(t, append([]string{"next"}, args...)...); code != 0 {
+		t.Errorf("next after cache warm needed the API: %s", stderr)
+	}
+
+	if out, _, _ := runCLI(t, append([]string{"cache", "prune", "--days", "1"}, args...)...); !strings.Contains(out, "Deleted 0") {
+		t.Errorf("cache prune --days 1 = %q, want nothing that recent pruned", out)
+	}
+	if out, _, _ := runCLI(t, append([]string{"cache", "clear"}, args...)...); !strings.Contains(out, "Deleted 2") {
+		t.Errorf("cache clear = %q, want both months deleted", out)
+	}
+}
+
+// TestCacheMaxSize verifies cache_max_size evicts the least recently used
+// entries and that cache info reports the limit and evictions.
+func TestCacheMaxSize(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	args := meccaArgs(t)
+
+	if _, stderr, code := runCLI(t, "config", "set", "cache_max_size", "1"); code != 0 {
+		t.Fatalf("config set cache_max_size exited with %d: %s", code, stderr)
+	}
+	if _, stderr, code := runCLI(t, append([]string{"cache", "warm"}, args...)...); code != 0 {
+		t.Fatalf("cache warm exited with %d: %s", code, stderr)
+	}
+
+	out, _, _ := runCLI(t, append([]string{"cache", "info", "--json"}, args...)...)
+	var info cacheInfoJSON
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if info.Entries != 1 || info.Limit == nil || info.Limit.Entries != 1 || info.Evictions.Evictions != 1 {
+		t.Errorf("cache info = %+v, want one month kept and one evicted", info)
+	}
+	if info.Locks != 0 {
+		t.Errorf("cache info reports %d lock files after warm, want none left", info.Locks)
+	}
+
+	out, _, _ = runCLI(t, append([]string{"cache", "info"}, args...)...)
+	for _, want := range []string{"Limit      1 entry", "Evicted    1 ("} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cache info missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, stderr, code := runCLI(t, "config", "set", "cache_max_size", "lots"); code == 0 || !strings.Contains(stderr, "invalid cache size") {
+		t.Errorf("config set cache_max_size lots = %d, %q; want an error", code, stderr)
+	}
+}
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"strings"
 
 	"github.com/smokyabdulrahman/prayer-times/internal/config"
@@ -10,15 +13,26 @@ import (
 )
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list [days]",
 		Short: "Show prayer times for multiple days",
-		Long:  "Display a grid of prayer times for N days (default: 7).",
-		Args:  cobra.MaximumNArgs(1),
+		Long:  "Display a grid of prayer times for N days (default: 7, or --days).\n\nUse --from/--to for a date range, or --from alone to start N days from another date.",
+		Example: `  prayer-times list
+  prayer-times list 30 --prayers Fajr,Maghrib
+  prayer-times list --from 2026-03-01 --to 2026-03-31 --json
+  prayer-times list 7 --output markdown >> newsletter.md`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runList(cmd, args, 7)
 		},
 	}
+
+	cmd.Flags().IntVar(&flagListDays, "days", 0, "Number of days when none is given as an argument (default 7)")
+	cmd.Flags().StringVar(&flagListFrom, "from", "", "Start date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&flagListTo, "to", "", "End date, inclusive (YYYY-MM-DD); needs --from")
+	addListOutputFlag(cmd)
+
+	return cmd
 }
 
 func newWeekCmd() *cobra.Command {
@@ -32,17 +46,6 @@ func newWeekCmd() *cobra.Command {
 	}
 }
 
-func newMonthCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "month",
-		Short: "Show prayer times for the next 30 days",
-		Long:  "Alias for 'list 30'. Display a grid of prayer times for 30 days.",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(cmd, nil, 30)
-		},
-	}
-}
-
 func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
@@ -54,8 +57,13 @@ func newConfigCmd() *cobra.Command {
 	cmd.AddCommand(&cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a config value",
-		Long: fmt.Sprintf("Set a configuration value. Valid keys: %s\n\nExamples:\n  prayer-times config set city Riyadh\n  prayer-times config set country \"Saudi Arabia\"\n  prayer-times config set method 4\n  prayer-times config set time_format 12h\n  prayer-times config set prayers Fajr,Dhuhr,Asr,Maghrib,Isha",
+		Long: fmt.Sprintf("Set a configuration value. Valid keys: %s\n\nKeys of the form commands.<command>.<flag> save a default for one command's\nflag, e.g. commands.next.format name-and-time; an empty value removes it.\n\nKeys of the form aliases.<name> add a command that runs another, e.g.\naliases.iftar \"query Maghrib --format time-remaining\".",
 			strings.Join(config.ValidKeys, ", ")),
+		Example: `  prayer-times config set city Riyadh
+  prayer-times config set country "Saudi Arabia"
+  prayer-times config set method 4
+  prayer-times config set time_format 12h
+  prayer-times config set prayers Fajr,Dhuhr,Asr,Maghrib,Isha`,
 		Args: cobra.ExactArgs(2),
 		RunE: runConfigSet,
 	})
@@ -63,10 +71,21 @@ func newConfigCmd() *cobra.Command {
 	cmd.AddCommand(&cobra.Command{
 		Use:   "reset",
 		Short: "Reset config to defaults",
-		Long:  "Delete the config file and restore all settings to defaults.",
+		Long:  "Delete the config file and restore all settings to defaults.\nThe old file is backed up; 'config undo' brings it back.",
 		RunE:  runConfigReset,
 	})
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "undo",
+		Short: "Restore the config from before the last change",
+		Long:  "Restore the config file from its most recent backup. 'config set' and\n'config reset' back up the previous file; run undo again to step further back.",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigUndo,
+	})
+
+	cmd.AddCommand(newConfigProfileCmd())
+	cmd.AddCommand(newConfigSyncCmd())
+
 	cmd.AddCommand(&cobra.Command{
 		Use:   "path",
 		Short: "Print config file path",
@@ -76,8 +95,69 @@ func newConfigCmd() *cobra.Command {
 	return cmd
 }
 
+// secretKeys are the config keys whose values runConfigShow hides.
+var secretKeys = map[string]bool{
+	"mqtt_password":  true,
+	"admin_token":    true,
+	"geo_api_key":    true,
+	"matrix_token":   true,
+	"ntfy_token":     true,
+	"gotify_token":   true,
+	"pushover_token": true,
+}
+
+// urlKeys are the config keys holding URLs, which can carry a password. The
+// true ones are addresses whose path or query is itself a token, as in Slack
+// and Discord webhooks or a healthchecks.io ping URL.
+var urlKeys = map[string]bool{
+	"sync_url":          false,
+	"mqtt_broker":       false,
+	"matrix_homeserver": false,
+	"ntfy_url":          false,
+	"gotify_url":        false,
+	"webhooks":          true,
+	"heartbeat_url":     true,
+}
+
+// shownValue returns the value of key as runConfigShow shows it, with
+// secrets and the credentials in URLs hidden.
+func shownValue(key, val string) string {
+	if val == "" {
+		return ""
+	}
+	if secretKeys[key] {
+		return "(set)"
+	}
+	hidePath, ok := urlKeys[key]
+	if !ok {
+		return val
+	}
+	parts := strings.Split(val, ",")
+	for i, part := range parts {
+		parts[i] = maskURL(strings.TrimSpace(part), hidePath)
+	}
+	return strings.Join(parts, ",")
+}
+
+// maskURL hides the password in raw and, with hidePath, everything after its
+// host. Values that don't parse as URLs, such as scp-style Git remotes, can't
+// hold a password and are returned as they are.
+func maskURL(raw string, hidePath bool) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	if hidePath && (strings.Trim(u.Path, "/") != "" || u.RawQuery != "") {
+		u.Path, u.RawPath, u.RawQuery, u.Fragment = "", "", "", ""
+		return u.Redacted() + "/…"
+	}
+	return u.Redacted()
+}
+
 // runConfigShow displays the current configuration.
 func runConfigShow(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
 	path, err := config.Path()
 	if err != nil {
 		return err
@@ -89,14 +169,14 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	}
 
 	if FlagJSON {
-		return printConfigJSON(cfg, path)
+		return printConfigJSON(w, cfg, path)
 	}
 
-	fmt.Printf("  Configuration (%s)\n\n", path)
+	fmt.Fprintf(w, "  Configuration (%s)\n\n", path)
 
-	for _, key := range config.ValidKeys {
+	for _, key := range cfg.Keys() {
 		val, _ := cfg.Get(key)
-		display := val
+		display := shownValue(key, val)
 		if display == "" {
 			display = "(not set)"
 		}
@@ -107,7 +187,7 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		if key == "school" && val != "" {
 			display = formatSchoolValue(val)
 		}
-		fmt.Printf("  %-14s %s\n", key, display)
+		fmt.Fprintf(w, "  %-14s %s\n", key, display)
 	}
 	return nil
 }
@@ -119,12 +199,12 @@ type configJSON struct {
 }
 
 // printConfigJSON outputs the current configuration as JSON.
-func printConfigJSON(cfg *config.Config, path string) error {
+func printConfigJSON(w io.Writer, cfg *config.Config, path string) error {
 	values := make(map[string]string)
-	for _, key := range config.ValidKeys {
+	for _, key := range cfg.Keys() {
 		val, _ := cfg.Get(key)
 		if val != "" {
-			values[key] = val
+			values[key] = shownValue(key, val)
 		}
 	}
 
@@ -137,14 +217,27 @@ func printConfigJSON(cfg *config.Config, path string) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
-	fmt.Println(string(data))
+	fmt.Fprintln(w, string(data))
 	return nil
 }
 
 // runConfigSet sets a config key to the given value.
 func runConfigSet(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
 	key, value := args[0], args[1]
 
+	if strings.HasPrefix(key, config.CommandKeyPrefix) {
+		if err := checkCommandKey(cmd.Root(), key); err != nil {
+			return err
+		}
+	}
+	if strings.HasPrefix(key, config.AliasKeyPrefix) {
+		if err := checkAliasKey(cmd.Root(), key, value); err != nil {
+			return err
+		}
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return err
@@ -158,26 +251,66 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Set %s = %s\n", key, value)
+	fmt.Fprintf(w, "Set %s = %s\n", key, value)
+	return nil
+}
+
+// checkCommandKey verifies that a commands.<command>.<flag> key names a
+// command and one of its flags.
+func checkCommandKey(root *cobra.Command, key string) error {
+	command, flag, err := config.ParseCommandKey(key)
+	if err != nil {
+		return err
+	}
+	target := root
+	if command != "today" {
+		found, rest, err := root.Find(strings.Fields(command))
+		if err != nil || len(rest) > 0 || found == root {
+			return fmt.Errorf("unknown command %q in config key %q", command, key)
+		}
+		target = found
+	}
+	if target.LocalFlags().Lookup(flag) == nil && target.InheritedFlags().Lookup(flag) == nil {
+		return fmt.Errorf("%s has no --%s flag", command, flag)
+	}
 	return nil
 }
 
 // runConfigReset deletes the config file.
 func runConfigReset(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
 	if err := config.Reset(); err != nil {
 		return err
 	}
-	fmt.Println("Configuration reset to defaults.")
+	fmt.Fprintln(w, "Configuration reset to defaults.")
+	return nil
+}
+
+// runConfigUndo restores the most recent config backup.
+func runConfigUndo(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	taken, err := config.Undo()
+	if errors.Is(err, config.ErrNoBackup) {
+		return fmt.Errorf("nothing to undo: no config backup found")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Configuration restored from %s.\n", taken.Format("2006-01-02 15:04:05"))
 	return nil
 }
 
 // runConfigPath prints the config file path.
 func runConfigPath(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
 	path, err := config.Path()
 	if err != nil {
 		return err
 	}
-	fmt.Println(path)
+	fmt.Fprintln(w, path)
 	return nil
 }
 
@@ -234,28 +367,32 @@ var CalculationMethods = []struct {
 }
 
 func newMethodsCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "methods",
 		Short: "List all calculation methods",
 		Long:  "Print the table of all supported Al Adhan API calculation methods.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
 			if FlagJSON {
-				return printMethodsJSON()
+				return printMethodsJSON(w)
 			}
 
-			fmt.Println("Supported calculation methods:")
-			fmt.Println()
-			fmt.Printf("  %-4s %s\n", "ID", "Name")
-			fmt.Printf("  %-4s %s\n", "──", "────")
+			fmt.Fprintln(w, "Supported calculation methods:")
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "  %-4s %s\n", "ID", "Name")
+			fmt.Fprintf(w, "  %-4s %s\n", "──", "────")
 			for _, m := range CalculationMethods {
-				fmt.Printf("  %-4d %s\n", m.ID, m.Name)
+				fmt.Fprintf(w, "  %-4d %s\n", m.ID, m.Name)
 			}
-			fmt.Println()
-			fmt.Println("Use --method <ID> to select a calculation method.")
-			fmt.Println("If omitted, the API picks a default based on your location.")
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Use --method <ID> to select a calculation method.")
+			fmt.Fprintln(w, "If omitted, the API picks a default based on your location.")
+			fmt.Fprintln(w, "Run 'prayer-times methods pick' to compare today's times under each.")
 			return nil
 		},
 	}
+	cmd.AddCommand(newMethodsPickCmd())
+	return cmd
 }
 
 // methodJSON is the JSON structure for a single calculation method.
@@ -265,7 +402,7 @@ type methodJSON struct {
 }
 
 // printMethodsJSON outputs the calculation methods as a JSON array.
-func printMethodsJSON() error {
+func printMethodsJSON(w io.Writer) error {
 	methods := make([]methodJSON, len(CalculationMethods))
 	for i, m := range CalculationMethods {
 		methods[i] = methodJSON{ID: m.ID, Name: m.Name}
@@ -275,6 +412,6 @@ func printMethodsJSON() error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
-	fmt.Println(string(data))
+	fmt.Fprintln(w, string(data))
 	return nil
 }
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+func newCompareCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compare <location> <location>...",
+		Short: "Show today's prayer times for several locations side by side",
+		Long: `Show today's prayer times for several locations in one table, each in its
+own local time, e.g. to find a time for an iftar call with family abroad.
+Locations are fetched concurrently and cached.
+
+Each location is a City:Country pair, a latitude,longitude pair or the name
+of a saved profile (see 'config profile'). Your method, school and other
+settings apply to all of them; a profile's own method and school win.`,
+		Example: `  prayer-times compare London:UK Cairo:EG "Kuala Lumpur:MY"
+  prayer-times compare 51.5074,-0.1278 21.4225,39.8262
+  prayer-times compare home makkah --json`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: runCompare,
+	}
+}
+
+// compareLocation returns a copy of base located by a compare argument, and
+// the argument's label.
+func compareLocation(base *config.Config, arg string) (*config.Config, string, error) {
+	cfg := *base
+	if _, ok := base.Profiles[arg]; ok {
+		if err := cfg.ApplyProfile(arg); err != nil {
+			return nil, "", err
+		}
+		return &cfg, arg, nil
+	}
+
+	if city, country, ok := strings.Cut(arg, ":"); ok {
+		city, country = strings.TrimSpace(city), strings.TrimSpace(country)
+		if city == "" || country == "" {
+			return nil, "", fmt.Errorf("invalid location %q: want City:Country, e.g. London:UK", arg)
+		}
+		cfg.Latitude, cfg.Longitude = nil, nil
+		cfg.City, cfg.Country = city, country
+		return &cfg, city + ", " + country, nil
+	}
+
+	lat, lon, ok := strings.Cut(arg, ",")
+	if ok {
+		lat, lon = strings.TrimSpace(lat), strings.TrimSpace(lon)
+		_, latErr := strconv.ParseFloat(lat, 64)
+		_, lonErr := strconv.ParseFloat(lon, 64)
+		ok = latErr == nil && lonErr == nil
+	}
+	if !ok {
+		return nil, "", fmt.Errorf("invalid location %q: want City:Country, latitude,longitude or a profile name", arg)
+	}
+	cfg.City, cfg.Country = "", ""
+	if err := cfg.Set("latitude", lat); err != nil {
+		return nil, "", err
+	}
+	if err := cfg.Set("longitude", lon); err != nil {
+		return nil, "", err
+	}
+	return &cfg, lat + ", " + lon, nil
+}
+
+// compareTarget is one location to show in a side-by-side comparison.
+type compareTarget struct {
+	Label          string
+	Loc            resolvedLocation
+	Method, School int
+}
+
+// newCompareTarget resolves cfg's location and calculation settings.
+func newCompareTarget(ctx context.Context, label string, cfg *config.Config, c *cache.Cache) (compareTarget, error) {
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return compareTarget{}, err
+	}
+	return compareTarget{
+		Label:  label,
+		Loc:    loc,
+		Method: cfg.MethodOrDefault(-1),
+		School: cfg.SchoolOrDefault(-1),
+	}, nil
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	c := openCache(cfg)
+
+	targets := make([]compareTarget, 0, len(args))
+	for _, arg := range args {
+		lc, label, err := compareLocation(cfg, arg)
+		if err != nil {
+			return err
+		}
+		t, err := newCompareTarget(ctx, label, lc, c)
+		if err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		targets = append(targets, t)
+	}
+
+	cols := fetchCompareColumns(ctx, targets, currentTime(), selectedPrayers, c)
+	return printCompare(cmd.OutOrStdout(), "Prayer Times Compared", cols, selectedPrayers, goTimeFormat(cfg))
+}
+
+// compareColumn is one location's prayer times for a comparison.
+type compareColumn struct {
+	Label    string
+	Location string
+	Timezone string
+	Date     time.Time
+	Prayers  []prayer.Prayer
+	Err      error
+}
+
+// fetchCompareColumns fetches today's timings for every target concurrently.
+// Each location uses its own date, so columns can be a day apart.
+func fetchCompareColumns(ctx context.Context, targets []compareTarget, now time.Time, selected []string, c *cache.Cache) []compareColumn {
+	cols := make([]compareColumn, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cols[i] = fetchCompareColumn(ctx, t, now, selected, c)
+		}()
+	}
+	wg.Wait()
+	return cols
+}
+
+// fetchCompareColumn fetches one target's prayer times for its local today.
+func fetchCompareColumn(ctx context.Context, t compareTarget, now time.Time, selected []string, c *cache.Cache) compareColumn {
+	col := compareColumn{Label: t.Label}
+
+	local := t.Loc.localTime(now)
+	result, err := fetchTimings(ctx, local, t.Loc, t.Method, t.School, c)
+	if err != nil {
+		col.Err = err
+		return col
+	}
+
+	tz := t.Loc.timezoneFor(result.Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		col.Err = fmt.Errorf("invalid timezone %q: %w", tz, err)
+		return col
+	}
+
+	// The timezone may only be known from the response; refetch if the
+	// location's date turns out to differ from the one asked for.
+	if day := now.In(tzLoc); day.Format("2006-01-02") != local.Format("2006-01-02") {
+		local = day
+		if result, err = fetchTimings(ctx, local, t.Loc, t.Method, t.School, c); err != nil {
+			col.Err = err
+			return col
+		}
+	}
+
+	col.Date = now.In(tzLoc)
+	col.Prayers, col.Err = prayer.ParseTimings(result.Timings, col.Date, tzLoc, selected)
+	col.Location = buildLocationStr(t.Loc, result)
+	col.Timezone = tz
+	return col
+}
+
+// compareJSONColumn is one location of a comparison's JSON output.
+type compareJSONColumn struct {
+	Label    string            `json:"label"`
+	Location string            `json:"location,omitempty"`
+	Timezone string            `json:"timezone,omitempty"`
+	Date     string            `json:"date,omitempty"`
+	Timings  map[string]string `json:"timings,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// printCompare renders columns side by side: one row per prayer, one column
+// per location, each in its own local time. It fails only when every column did.
+func printCompare(w io.Writer, title string, cols []compareColumn, selected []string, goTimeFmt string) error {
+	failed := 0
+	for _, col := range cols {
+		if col.Err != nil {
+			failed++
+			notices.Warnf("%s: %v", col.Label, col.Err)
+		}
+	}
+	if failed == len(cols) {
+		return fmt.Errorf("failed to fetch prayer times for any location")
+	}
+
+	if FlagJSON {
+		out := make([]compareJSONColumn, 0, len(cols))
+		for _, col := range cols {
+			jc := compareJSONColumn{Label: col.Label}
+			if col.Err != nil {
+				jc.Error = col.Err.Error()
+			} else {
+				jc.Location = col.Location
+				jc.Timezone = col.Timezone
+				jc.Date = col.Date.Format("2006-01-02")
+				jc.Timings = make(map[string]string, len(col.Prayers))
+				for _, p := range col.Prayers {
+					jc.Timings[strings.ToLower(p.Name)] = p.Time.Format(goTimeFmt)
+				}
+			}
+			out = append(out, jc)
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	headers := []string{"Prayer"}
+	for _, col := range cols {
+		headers = append(headers, col.Label)
+	}
+	tbl := display.NewTable(headers)
+	for _, name := range selected {
+		row := []string{name}
+		for _, col := range cols {
+			cell := "--:--"
+			for _, p := range col.Prayers {
+				if strings.EqualFold(p.Name, name) {
+					cell = p.Time.Format(goTimeFmt)
+				}
+			}
+			row = append(row, cell)
+		}
+		tbl.AddRow(row)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(title))
+	fmt.Fprintln(w)
+	for _, col := range cols {
+		if col.Err != nil {
+			fmt.Fprintf(w, "  %s  %s\n", col.Label, display.Dim("unavailable"))
+			continue
+		}
+		name := col.Label
+		if col.Location != col.Label {
+			name += ": " + col.Location
+		}
+		fmt.Fprintf(w, "  %s  %s\n", name, display.Dim(col.Date.Format("Mon 02 Jan")+" · "+col.Timezone))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
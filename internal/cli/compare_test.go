@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+)
+
+func TestCompareLocation(t *testing.T) {
+	lat, lon := 51.5, -0.12
+	base := &config.Config{City: "Leeds", Country: "UK"}
+	if err := base.AddProfile("home", map[string]string{"latitude": "51.5", "longitude": "-0.12"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		arg, label, city string
+		coords           bool
+	}{
+		{"London:UK", "London, UK", "London", false},
+		{" New York : US ", "New York, US", "New York", false},
+		{"21.4225,39.8262", "21.4225, 39.8262", "", true},
+		{"home", "home", "", true},
+	} {
+		cfg, label, err := compareLocation(base, tc.arg)
+		if err != nil {
+			t.Errorf("compareLocation(%q) error: %v", tc.arg, err)
+			continue
+		}
+		if label != tc.label || cfg.City != tc.city || cfg.HasCoordinates() != tc.coords {
+			t.Errorf("compareLocation(%q) = %q, city %q, coordinates %v", tc.arg, label, cfg.City, cfg.HasCoordinates())
+		}
+	}
+	if base.City != "Leeds" {
+		t.Error("compareLocation() changed the base config")
+	}
+
+	base.Latitude, base.Longitude = &lat, &lon
+	if cfg, _, _ := compareLocation(base, "Cairo:EG"); cfg.HasCoordinates() {
+		t.Error("a city should replace the config's coordinates")
+	}
+
+	for _, arg := range []string{"London", ":UK", "91,0", "north,east", "work"} {
+		if _, _, err := compareLocation(base, arg); err == nil {
+			t.Errorf("compareLocation(%q) should error", arg)
+		}
+	}
+}
+
+// TestCompare verifies 'compare' shows each location from the mock API side by side.
+func TestCompare(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	cacheDir := t.TempDir()
+
+	out, stderr, code := runCLI(t, "compare", "Mecca:SA", "21.4225,39.8262", "--json", "--cache-dir", cacheDir)
+	if code != 0 {
+		t.Fatalf("compare --json exited with %d: %s", code, stderr)
+	}
+	var cols []compareJSONColumn
+	if err := json.Unmarshal([]byte(out), &cols); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(cols) != 2 || cols[0].Label != "Mecca, SA" || cols[1].Label != "21.4225, 39.8262" {
+		t.Fatalf("compare --json = %+v, want Mecca then the coordinates", cols)
+	}
+	for _, col := range cols {
+		if col.Error != "" || col.Timezone != "Asia/Riyadh" || col.Timings["fajr"] != "05:30" {
+			t.Errorf("%s = %+v, want Fajr at 05:30 in Asia/Riyadh", col.Label, col)
+		}
+	}
+
+	out, stderr, code = runCLI(t, "compare", "Mecca:SA", "London:UK", "--prayers", "Fajr,Maghrib", "--cache-dir", cacheDir)
+	if code != 0 {
+		t.Fatalf("compare exited with %d: %s", code, stderr)
+	}
+	for _, want := range []string{"Mecca, SA", "London, UK", "Maghrib", "18:10"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("compare output should contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Dhuhr") {
+		t.Errorf("compare output should only list --prayers:\n%s", out)
+	}
+
+	if _, _, code := runCLI(t, "compare", "Mecca:SA"); code == 0 {
+		t.Error("compare with one location should fail")
+	}
+}
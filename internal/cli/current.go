@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var flagCurrentFormat string
+
+func newCurrentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Show the prayer window you are in now",
+		Long: `Display the current prayer window: which prayer's time it is, when it began,
+and how long until it ends at the next prayer. The inverse of 'next'.
+
+Before Fajr, the window is the previous night's Isha (or the last tracked
+prayer).
+
+Custom templates get .Name, .ShortName, .Start, .Next, .End, .Elapsed,
+.Remaining, .Hours, .Minutes and .Seconds.
+
+With --date, answers as if run at the current time of day on that date.`,
+		Args: cobra.NoArgs,
+		RunE: runCurrent,
+		Example: `  prayer-times current
+  prayer-times current --format name-and-remaining
+  prayer-times current --json | jq -r .prayer`,
+	}
+
+	cmd.Flags().StringVar(&flagCurrentFormat, "format", prayer.FormatFull, "Display format: name, name-and-start, time-remaining, time-remaining-seconds, name-and-remaining, full, or a custom Go template")
+	addDateFlag(cmd)
+
+	return cmd
+}
+
+// currentJSON is the JSON output structure for the current command.
+type currentJSON struct {
+	Prayer    string `json:"prayer"`
+	Started   string `json:"started"`
+	Next      string `json:"next"`
+	Ends      string `json:"ends"`
+	Elapsed   string `json:"elapsed"`
+	Remaining string `json:"remaining"`
+	Stale     bool   `json:"stale,omitempty"` // times are from the cache; the API was unreachable
+}
+
+func runCurrent(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	now := loc.localTime(currentTime())
+	if now, err = applyDateFlag(now); err != nil {
+		return err
+	}
+
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
+	if err != nil {
+		return err
+	}
+	tz := loc.timezoneFor(result.Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	now = now.In(tzLoc)
+
+	prayers, err := prayer.ParseTimings(result.Timings, now, tzLoc, selectedPrayers)
+	if err != nil {
+		return err
+	}
+
+	current, err := currentPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	if err != nil {
+		return fmt.Errorf("failed to fetch yesterday's times: %w", err)
+	}
+	next, err := nextPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tomorrow's times: %w", err)
+	}
+	if current == nil || next == nil {
+		return fmt.Errorf("could not determine the current prayer")
+	}
+
+	if FlagJSON {
+		out := currentJSON{
+			Prayer:    strings.ToLower(current.Name),
+			Started:   current.Time.Format(goTimeFmt),
+			Next:      strings.ToLower(next.Name),
+			Ends:      next.Time.Format(goTimeFmt),
+			Elapsed:   prayer.FormatRemaining(now.Sub(current.Time)),
+			Remaining: prayer.FormatRemaining(prayer.TimeRemaining(*next, now)),
+			Stale:     result.Stale,
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprint(w, prayer.FormatCurrent(*current, *next, now, flagCurrentFormat, goTimeFmt))
+	return nil
+}
+
+// currentPrayerFrom returns the latest of today's prayers at or before now,
+// falling back to yesterday's last prayer before today's first.
+func currentPrayerFrom(ctx context.Context, prayers []prayer.Prayer, now time.Time, loc resolvedLocation, method, school int, c *cache.Cache, tzLoc *time.Location, selected []string) (*prayer.Prayer, error) {
+	if current := prayer.CurrentPrayer(prayers, now); current != nil {
+		return current, nil
+	}
+
+	yesterday := now.AddDate(0, 0, -1)
+	yResult, err := fetchTimings(ctx, yesterday, loc, method, school, c)
+	if err != nil {
+		return nil, err
+	}
+	yesterdayPrayers, err := prayer.ParseTimings(yResult.Timings, yesterday, tzLoc, selected)
+	if err != nil {
+		return nil, err
+	}
+	if len(yesterdayPrayers) == 0 {
+		return nil, nil
+	}
+	return &yesterdayPrayers[len(yesterdayPrayers)-1], nil
+}
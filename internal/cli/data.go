@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/archive"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/timing"
+	"github.com/spf13/cobra"
+)
+
+var flagDataYes bool
+
+func newDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "data",
+		Short: "Export or delete everything prayer-times stores locally",
+		Long: `Export or delete the personal data prayer-times keeps on this machine:
+
+  config   ~/.config/prayer-times/ (config file, backups, profiles)
+  data     ~/.local/share/prayer-times/ (history archive, khatmah plan)
+  state    ~/.local/state/prayer-times/ (--timing stats)
+  cache    ~/.cache/prayer-times/ or --cache-dir (cached times and location)`,
+		Example: `  prayer-times data export
+  prayer-times data export backup.tar.gz
+  prayer-times data wipe`,
+	}
+	cmd.PersistentFlags().BoolVarP(&flagDataYes, "yes", "y", false, "Don't ask for confirmation")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "export [file]",
+		Short: "Bundle the config, data and cached location into a .tar.gz",
+		Long:  "Write the config, data and state directories and the cached location to a\ngzipped tar archive (default: prayer-times-data-YYYY-MM-DD.tar.gz; - for stdout).\nCached prayer times are left out; they are fetched again on demand.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runDataExport,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "wipe",
+		Short: "Delete the config, data and cache",
+		Long:  "Delete the config, data and state directories and every cache file, including\nthe cached location. This can't be undone; 'data export' first to keep a copy.",
+		Args:  cobra.NoArgs,
+		RunE:  runDataWipe,
+	})
+
+	return cmd
+}
+
+// dataDir returns the directory holding the archive and the khatmah plan.
+func dataDir() (string, error) {
+	dir, err := archive.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(dir), nil
+}
+
+// stateDir returns the directory holding the --timing stats.
+func stateDir() (string, error) {
+	path, err := timing.DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
+// cacheDir returns the cache directory in use, from --cache-dir or the config.
+func cacheDir(cmd *cobra.Command) (string, error) {
+	if dir := effectiveConfig(cmd).CacheDir; dir != "" {
+		return dir, nil
+	}
+	return cache.DefaultDir()
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// Anything but y or yes, including no answer, is a no.
+func confirm(cmd *cobra.Command, prompt string) bool {
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s [y/N] ", prompt)
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr()) // end the prompt line
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// runDataExport writes the local data to a gzipped tar archive.
+func runDataExport(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	path := "prayer-times-data-" + time.Now().Format("2006-01-02") + ".tar.gz"
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	confDir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	dDir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	sDir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	sources := []dataSource{{"config", confDir}, {"data", dDir}, {"state", sDir}}
+	cDir, err := cacheDir(cmd)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cDir); err == nil {
+		if c, err := cache.New(cDir); err == nil {
+			sources = append(sources, dataSource{"cache/" + filepath.Base(c.GeoPath()), c.GeoPath()})
+		}
+	}
+
+	n, err := createDataArchive(cmd, path, flagDataYes, sources)
+	if err != nil {
+		return err
+	}
+	if path != "-" {
+		fmt.Fprintf(w, "Exported %d files to %s.\n", n, path)
+	}
+	return nil
+}
+
+// createDataArchive writes sources as a gzipped tar to the file at path, or
+// to stdout for "-", and returns how many files it holds. An existing file
+// is only replaced once confirmed, or when yes is set.
+func createDataArchive(cmd *cobra.Command, path string, yes bool, sources []dataSource) (int, error) {
+	if path == "-" {
+		return writeDataArchive(cmd.OutOrStdout(), sources)
+	}
+
+	if _, err := os.Stat(path); err == nil && !yes && !confirm(cmd, path+" exists. Overwrite it?") {
+		return 0, errors.New("cancelled; use --yes to overwrite")
+	}
+	// The archive holds personal data, so keep it private.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create %s: %w", path, err)
+	}
+	n, err := writeDataArchive(f, sources)
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return n, nil
+}
+
+// dataSource is a directory or file to export and its name in the archive.
+type dataSource struct {
+	Name string
+	Path string
+}
+
+// writeDataArchive writes the regular files under each source to out as a
+// gzipped tar, named under the source's Name. Missing sources are skipped.
+// It returns how many files were written.
+func writeDataArchive(out io.Writer, sources []dataSource) (int, error) {
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	n := 0
+	for _, src := range sources {
+		root := src.Path
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if err := addTarFile(tw, filepath.ToSlash(filepath.Join(src.Name, rel)), path); err != nil {
+				return err
+			}
+			n++
+			return nil
+		})
+		if err != nil {
+			return n, fmt.Errorf("failed to archive %s: %w", root, err)
+		}
+	}
+	if n == 0 {
+		return 0, errors.New("no local data to export")
+	}
+
+	if err := tw.Close(); err != nil {
+		return n, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return n, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return n, nil
+}
+
+// addTarFile copies the file at path into tw under name.
+func addTarFile(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// runDataWipe deletes the config, data and state directories and the cache
+// files.
+func runDataWipe(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	confDir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	dDir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	sDir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	cDir, err := cacheDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	for _, dir := range []string{confDir, dDir, sDir} {
+		if _, err := os.Stat(dir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	_, cacheErr := os.Stat(cDir)
+	if len(dirs) == 0 && cacheErr != nil {
+		fmt.Fprintln(w, "No local data to delete.")
+		return nil
+	}
+
+	if !flagDataYes {
+		errw := cmd.ErrOrStderr()
+		fmt.Fprintln(errw, "This deletes:")
+		for _, dir := range dirs {
+			fmt.Fprintf(errw, "  %s\n", dir)
+		}
+		if cacheErr == nil {
+			fmt.Fprintf(errw, "  cache files in %s\n", cDir)
+		}
+		if !confirm(cmd, "Delete all of it? This can't be undone.") {
+			return errors.New("wipe cancelled; nothing was deleted (use --yes to skip the prompt)")
+		}
+	}
+
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", dir, err)
+		}
+		fmt.Fprintf(w, "Deleted %s\n", dir)
+	}
+	if cacheErr == nil {
+		c, err := cache.New(cDir)
+		if err != nil {
+			return err
+		}
+		removed, err := c.Clear()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Deleted %d cache files from %s\n", len(removed), cDir)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/geo"
+)
+
+// TestData verifies data export bundles the local files and data wipe
+// deletes them only once confirmed.
+func TestData(t *testing.T) {
+	configDir := isolateConfig(t)
+	cacheDir := t.TempDir()
+
+	if _, stderr, code := runCLI(t, "config", "set", "city", "Riyadh"); code != 0 {
+		t.Fatal(stderr)
+	}
+	dDir, err := dataDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(dDir, "archive"), 0o755)
+	os.WriteFile(filepath.Join(dDir, "archive", "2026-03-01.jsonl"), []byte("{}\n"), 0o644)
+	sDir, err := stateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(sDir, 0o755)
+	os.WriteFile(filepath.Join(sDir, "timing.json"), []byte(`{"runs":[]}`), 0o644)
+	c, _ := cache.New(cacheDir)
+	c.SaveGeo(&geo.Location{City: "Riyadh"})
+
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	out, stderr, code := runCLI(t, "data", "export", path, "--cache-dir", cacheDir)
+	if code != 0 {
+		t.Fatalf("data export exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "Exported 4 files") {
+		t.Errorf("data export = %q, want 4 files", out)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	want := "config/config.json data/archive/2026-03-01.jsonl state/timing.json cache/geolocation.json"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("archive holds %q, want %q", got, want)
+	}
+
+	// An existing file isn't overwritten without confirmation.
+	if _, _, code := runCLI(t, "data", "export", path, "--cache-dir", cacheDir); code == 0 {
+		t.Error("data export over an existing file should need confirmation")
+	}
+
+	if _, _, code := runCLI(t, "data", "wipe", "--cache-dir", cacheDir); code == 0 {
+		t.Error("data wipe without confirmation should fail")
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "prayer-times", "config.json")); err != nil {
+		t.Fatalf("unconfirmed wipe deleted the config: %v", err)
+	}
+
+	if _, stderr, code := runCLI(t, "data", "wipe", "--yes", "--cache-dir", cacheDir); code != 0 {
+		t.Fatalf("data wipe --yes exited with %d: %s", code, stderr)
+	}
+	for _, p := range []string{filepath.Join(configDir, "prayer-times"), dDir, sDir, c.GeoPath()} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s survived data wipe", p)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// flagDate is the --date flag shared by today, next and query.
+var flagDate string
+
+// addDateFlag registers --date on cmd.
+func addDateFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagDate, "date", "", "Show times for another day: YYYY-MM-DD, today, tomorrow, yesterday or a weekday")
+}
+
+// parseDayFlag parses a day given as YYYY-MM-DD, "today", "tomorrow",
+// "yesterday" or a weekday name such as "friday" (the next one, counting
+// today), returning midnight of that day in now's location.
+func parseDayFlag(s string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(s) {
+	case "", "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.EqualFold(s, wd.String()) {
+			return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7), nil
+		}
+	}
+	t, err := parseDateFlag(s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date, today, tomorrow, yesterday or a weekday", s)
+	}
+	return t, nil
+}
+
+// applyDateFlag moves now to the same time of day on the --date day, so
+// commands answer as if run then. now is returned unchanged without --date.
+func applyDateFlag(now time.Time) (time.Time, error) {
+	if flagDate == "" {
+		return now, nil
+	}
+	day, err := parseDayFlag(flagDate, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --date: %w", err)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location()), nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayFlag(t *testing.T) {
+	now := time.Date(2026, 3, 14, 22, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"today", "2026-03-14"},
+		{"Tomorrow", "2026-03-15"},
+		{"yesterday", "2026-03-13"},
+		{"saturday", "2026-03-14"},
+		{"Friday", "2026-03-20"},
+		{"sunday", "2026-03-15"},
+		{"2026-04-01", "2026-04-01"},
+	}
+	for _, tt := range tests {
+		got, err := parseDayFlag(tt.in, now)
+		if err != nil {
+			t.Errorf("parseDayFlag(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got.Format("2006-01-02 15:04") != tt.want+" 00:00" {
+			t.Errorf("parseDayFlag(%q) = %v, want midnight %s", tt.in, got, tt.want)
+		}
+	}
+	if _, err := parseDayFlag("someday", now); err == nil {
+		t.Error("parseDayFlag(someday) expected error")
+	}
+}
+
+func TestApplyDateFlag(t *testing.T) {
+	now := time.Date(2026, 3, 14, 22, 30, 0, 0, time.UTC)
+	t.Cleanup(func() { flagDate = "" })
+
+	flagDate = ""
+	if got, err := applyDateFlag(now); err != nil || !got.Equal(now) {
+		t.Errorf("applyDateFlag() without --date = %v, %v; want now", got, err)
+	}
+
+	flagDate = "2026-04-01"
+	got, err := applyDateFlag(now)
+	if err != nil {
+		t.Fatalf("applyDateFlag() error: %v", err)
+	}
+	if want := time.Date(2026, 4, 1, 22, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("applyDateFlag() = %v, want %v", got, want)
+	}
+
+	flagDate = "someday"
+	if _, err := applyDateFlag(now); err == nil {
+		t.Error("applyDateFlag(someday) expected error")
+	}
+}
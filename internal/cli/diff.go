@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDiffA string
+	flagDiffB string
+)
+
+// diffSpecKeys are the config keys a --a/--b spec may override.
+var diffSpecKeys = []string{"city", "country", "latitude", "longitude", "method", "school"}
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [days]",
+		Short: "Compare prayer times between two locations or methods",
+		Long: `Compare prayer times for two setups over N days (default: 7), showing how
+many minutes each prayer in B falls after (+) or before (-) the same prayer in A.
+
+Each side is a comma-separated list of key=value overrides applied on top of
+your config and flags. Valid keys: ` + strings.Join(diffSpecKeys, ", ") + `.`,
+		Example: `  prayer-times diff --a "city=London,country=UK,method=3" --b "city=London,country=UK,method=15"
+  prayer-times diff 30 --a method=2 --b method=4`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runDiff,
+	}
+
+	cmd.Flags().StringVar(&flagDiffA, "a", "", "Overrides for side A, e.g. \"city=London,country=UK,method=3\"")
+	cmd.Flags().StringVar(&flagDiffB, "b", "", "Overrides for side B, e.g. \"city=London,country=UK,method=15\"")
+
+	return cmd
+}
+
+// parseDiffSpec applies a "key=value,..." spec on top of a copy of base.
+// Naming a city or country drops base's coordinates, which would otherwise
+// take precedence.
+func parseDiffSpec(base *config.Config, spec string) (*config.Config, error) {
+	cfg := *base
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("invalid spec %q: want key=value", part)
+		}
+		valid := false
+		for _, k := range diffSpecKeys {
+			valid = valid || k == key
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid spec key %q: must be one of %s", key, strings.Join(diffSpecKeys, ", "))
+		}
+		if key == "city" || key == "country" {
+			cfg.Latitude, cfg.Longitude = nil, nil
+		}
+		if err := cfg.Set(key, strings.TrimSpace(value)); err != nil {
+			return nil, err
+		}
+	}
+	return &cfg, nil
+}
+
+// diffSide is one side of a comparison: its label and fetched days.
+type diffSide struct {
+	Label string
+	Days  []dayData
+	TZ    *time.Location
+}
+
+// fetchDiffSide fetches days of timings for loc from start, using cfg's method and school.
+func fetchDiffSide(ctx context.Context, cfg *config.Config, loc resolvedLocation, start time.Time, days int, c *cache.Cache) (*diffSide, error) {
+	daysList, err := fetchCalendarDays(ctx, start, days, loc, cfg.MethodOrDefault(-1), cfg.SchoolOrDefault(-1), c)
+	if err != nil {
+		return nil, err
+	}
+
+	tz := loc.timezoneFor(daysList[0].Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	label := buildLocationStr(loc, &fetchResult{Meta: daysList[0].Meta})
+	if name := daysList[0].Meta.Method.Name; name != "" {
+		label += " (" + name + ")"
+	}
+	return &diffSide{Label: label, Days: daysList, TZ: tzLoc}, nil
+}
+
+// diffMinutes returns how many minutes each prayer in b falls after the same
+// prayer in a, comparing local clock times so locations in different
+// timezones line up.
+func diffMinutes(a, b []prayer.Prayer) []int {
+	out := make([]int, len(a))
+	for i := range a {
+		am := a[i].Time.Hour()*60 + a[i].Time.Minute()
+		bm := b[i].Time.Hour()*60 + b[i].Time.Minute()
+		out[i] = bm - am
+	}
+	return out
+}
+
+// formatMinutesDiff renders a signed minute difference, e.g. "+3" or "-12".
+func formatMinutesDiff(m int) string {
+	if m > 0 {
+		return "+" + strconv.Itoa(m)
+	}
+	return strconv.Itoa(m)
+}
+
+// diffJSONDay is one day of the diff command's JSON output.
+type diffJSONDay struct {
+	Date    string         `json:"date"`
+	Minutes map[string]int `json:"minutes"`
+}
+
+// diffJSONOutput is the JSON output structure for the diff command.
+type diffJSONOutput struct {
+	A    string        `json:"a"`
+	B    string        `json:"b"`
+	Days []diffJSONDay `json:"days"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	if flagDiffA == "" && flagDiffB == "" {
+		return fmt.Errorf("nothing to compare: set --a and/or --b")
+	}
+
+	days := 7
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of days: %q (must be a positive integer)", args[0])
+		}
+		days = n
+	}
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+
+	cfgA, err := parseDiffSpec(cfg, flagDiffA)
+	if err != nil {
+		return fmt.Errorf("invalid --a: %w", err)
+	}
+	cfgB, err := parseDiffSpec(cfg, flagDiffB)
+	if err != nil {
+		return fmt.Errorf("invalid --b: %w", err)
+	}
+
+	c := openCache(cfg)
+
+	locA, err := resolveLocation(ctx, cfgA, c)
+	if err != nil {
+		return err
+	}
+	locB, err := resolveLocation(ctx, cfgB, c)
+	if err != nil {
+		return err
+	}
+
+	// Both sides cover the same calendar dates: A's today onwards.
+	start := locA.localTime(currentTime())
+
+	a, err := fetchDiffSide(ctx, cfgA, locA, start, days, c)
+	if err != nil {
+		return err
+	}
+	b, err := fetchDiffSide(ctx, cfgB, locB, start, days, c)
+	if err != nil {
+		return err
+	}
+
+	out := diffJSONOutput{A: a.Label, B: b.Label}
+	headers := append([]string{"Date"}, selectedPrayers...)
+	tbl := display.NewTable(headers)
+	for i := range a.Days {
+		date := calendarDay(a.Days[i].Date, a.TZ)
+		pa, err := prayer.ParseTimings(a.Days[i].Timings, date, a.TZ, selectedPrayers)
+		if err != nil {
+			return err
+		}
+		pb, err := prayer.ParseTimings(b.Days[i].Timings, calendarDay(b.Days[i].Date, b.TZ), b.TZ, selectedPrayers)
+		if err != nil {
+			return err
+		}
+
+		day := diffJSONDay{Date: date.Format("2006-01-02"), Minutes: make(map[string]int)}
+		row := []string{date.Format("Mon 02 Jan")}
+		for j, m := range diffMinutes(pa, pb) {
+			day.Minutes[strings.ToLower(pa[j].Name)] = m
+			row = append(row, formatMinutesDiff(m))
+		}
+		out.Days = append(out.Days, day)
+		tbl.AddRow(row)
+	}
+
+	if FlagJSON {
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(fmt.Sprintf("Prayer Time Differences — %d Days", days)))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  A: %s\n", a.Label)
+	fmt.Fprintf(w, "  B: %s\n", b.Label)
+	fmt.Fprintf(w, "  %s\n", display.Dim("Minutes B is later (+) or earlier (-) than A"))
+	fmt.Fprintln(w)
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+func TestParseDiffSpec(t *testing.T) {
+	lat, lon, method := 51.5, -0.12, 2
+	base := &config.Config{Latitude: &lat, Longitude: &lon, Method: &method}
+
+	cfg, err := parseDiffSpec(base, "city=London, country=UK ,method=15")
+	if err != nil {
+		t.Fatalf("parseDiffSpec() error: %v", err)
+	}
+	if cfg.City != "London" || cfg.Country != "UK" || cfg.MethodOrDefault(-1) != 15 {
+		t.Errorf("parseDiffSpec() = %+v, want London/UK method 15", cfg)
+	}
+	if cfg.HasCoordinates() {
+		t.Error("naming a city should drop the base coordinates")
+	}
+	if *base.Method != 2 || !base.HasCoordinates() {
+		t.Errorf("parseDiffSpec() modified base: %+v", base)
+	}
+
+	for _, spec := range []string{"method", "timezone=UTC", "method=99"} {
+		if _, err := parseDiffSpec(base, spec); err == nil {
+			t.Errorf("parseDiffSpec(%q) expected error", spec)
+		}
+	}
+}
+
+func TestDiffMinutes(t *testing.T) {
+	riyadh, _ := time.LoadLocation("Asia/Riyadh")
+	at := func(loc *time.Location, h, m int) prayer.Prayer {
+		return prayer.Prayer{Time: time.Date(2024, 3, 1, h, m, 0, 0, loc)}
+	}
+
+	a := []prayer.Prayer{at(time.UTC, 5, 30), at(time.UTC, 19, 40)}
+	b := []prayer.Prayer{at(riyadh, 5, 18), at(riyadh, 19, 55)}
+	if got, want := diffMinutes(a, b), []int{-12, 15}; !reflect.DeepEqual(got, want) {
+		t.Errorf("diffMinutes() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffCommand_JSON(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	args := append([]string{"diff", "3", "--a", "method=2", "--b", "method=4", "--prayers", "Fajr,Isha", "--json"}, meccaArgs(t)...)
+	out, stderr, code := runCLI(t, args...)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	var got diffJSONOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got.Days) != 3 {
+		t.Fatalf("got %d days, want 3", len(got.Days))
+	}
+	// The mock API ignores the method, so both sides agree.
+	for _, d := range got.Days {
+		if !reflect.DeepEqual(d.Minutes, map[string]int{"fajr": 0, "isha": 0}) {
+			t.Errorf("%s: minutes = %v, want zeros", d.Date, d.Minutes)
+		}
+	}
+}
+
+func TestDiffCommand_RequiresSpec(t *testing.T) {
+	isolateConfig(t)
+
+	_, _, code := runCLI(t, append([]string{"diff"}, meccaArgs(t)...)...)
+	if code == 0 {
+		t.Error("diff without --a or --b should fail")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+// hideInput can't turn off a terminal's echo here, so it fails for
+// terminals and returns errNotTerminal for other files, such as a pipe.
+func hideInput(f *os.File) (func(), error) {
+	if fi, err := f.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		return nil, errors.New("hiding typed input isn't supported on this system")
+	}
+	return nil, errNotTerminal
+}
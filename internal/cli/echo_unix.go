@@ -0,0 +1,35 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// hideInput turns off the echo of f, when it is a terminal, so a passphrase
+// typed into it isn't shown, and returns a func that turns it back on. It
+// returns errNotTerminal for other files, such as a pipe.
+func hideInput(f *os.File) (func(), error) {
+	var saved syscall.Termios
+	if err := termios(f, ioctlGetTermios, &saved); err != nil {
+		return nil, errNotTerminal
+	}
+	hidden := saved
+	hidden.Lflag &^= syscall.ECHO
+	hidden.Lflag |= syscall.ICANON | syscall.ISIG
+	if err := termios(f, ioctlSetTermios, &hidden); err != nil {
+		return nil, err
+	}
+	return func() { termios(f, ioctlSetTermios, &saved) }, nil
+}
+
+// termios gets or sets the terminal attributes of f with the ioctl req.
+func termios(f *os.File, req uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/spf13/cobra"
+)
+
+// recipe is one real-world use of prayer-times, shown by the examples command.
+type recipe struct {
+	Topic string   `json:"topic"`
+	Title string   `json:"title"`
+	Lines []string `json:"lines"` // the snippet; lines starting with # are comments
+}
+
+// recipes are the examples command's snippets, grouped by topic in the order
+// they're shown.
+var recipes = []recipe{
+	{"tmux", "The next prayer in tmux's status line (~/.tmux.conf)", []string{
+		`set -g status-interval 60`,
+		`set -g status-right "#(prayer-times tmux --format name-and-remaining --icon 🕌)"`,
+		`# or let the plugin build the flags: set -g @plugin 'smokyabdulrahman/prayer-times'`,
+	}},
+	{"waybar", "A Waybar module (~/.config/waybar/config), styled with #custom-prayer.urgent", []string{
+		`"custom/prayer": {`,
+		`  "exec": "prayer-times bar --quiet",`,
+		`  "return-type": "json",`,
+		`  "interval": 60`,
+		`}`,
+	}},
+	{"polybar", "A Polybar module (~/.config/polybar/config.ini)", []string{
+		`[module/prayer]`,
+		`type = custom/script`,
+		`exec = prayer-times bar --output polybar --format name-and-time --quiet`,
+		`interval = 60`,
+	}},
+	{"cron", "Keep the cache warm and check the archive (crontab -e)", []string{
+		`# fetch the coming months at 03:00, so status bars never wait on the API`,
+		`0 3 * * * prayer-times cache warm --quiet`,
+		`# weekly, mail when the API's times for archived days have moved`,
+		`0 4 * * 0 prayer-times verify --threshold 1m || echo "prayer times changed upstream" | mail -s prayer-times "$USER"`,
+	}},
+	{"jq", "Pipe the JSON output through jq", []string{
+		`prayer-times --json | jq -r '.timings | to_entries[] | "\(.key)\t\(.value)"'`,
+		`prayer-times next --json | jq -r '"\(.prayer) in \(.remaining)"'`,
+		`prayer-times list 30 --json | jq -r '.days[] | [.date, .timings.maghrib] | @tsv'`,
+		`prayer-times query Maghrib --days month --json | jq -r '.days[] | "\(.date) \(.time)"' > iftar.txt`,
+	}},
+	{"shell", "The next prayer in a shell prompt or a desktop notification", []string{
+		`PS1='[$(prayer-times next --format short-name-and-remaining --quiet)] \w \$ '`,
+		`notify-send "Prayer times" "$(prayer-times status)"`,
+	}},
+	{"serve", "A local HTTP API for dashboards and home automation", []string{
+		`prayer-times serve --addr 127.0.0.1:8080 &`,
+		`curl -s localhost:8080/next | jq .`,
+	}},
+}
+
+func newExamplesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "examples [topic]",
+		Short: "Show real-world examples: tmux, status bars, cron, jq and more",
+		Long: `Show ready-to-paste snippets that put prayer-times to work: the tmux status
+line, Waybar and Polybar modules, cron jobs, jq pipelines, shell prompts and
+the HTTP server. Give a topic to see only its examples.
+
+Topics: ` + strings.Join(recipeTopics(), ", ") + `.`,
+		Example: `  prayer-times examples
+  prayer-times examples waybar
+  prayer-times examples --json | jq -r '.[].topic'`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: recipeTopics(),
+		RunE:      runExamples,
+	}
+}
+
+func runExamples(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	shown := recipes
+	if len(args) == 1 {
+		shown = nil
+		for _, r := range recipes {
+			if strings.EqualFold(r.Topic, args[0]) {
+				shown = append(shown, r)
+			}
+		}
+		if len(shown) == 0 {
+			return fmt.Errorf("unknown topic %q: must be one of %s", args[0], strings.Join(recipeTopics(), ", "))
+		}
+	}
+
+	if FlagJSON {
+		data, err := json.MarshalIndent(shown, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	for _, r := range shown {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  %s — %s\n", display.Accent(r.Topic), display.Bold(r.Title))
+		for _, line := range r.Lines {
+			if strings.HasPrefix(line, "#") {
+				line = display.Gray(line)
+			}
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// recipeTopics returns the recipes' topics, in order.
+func recipeTopics() []string {
+	var topics []string
+	for _, r := range recipes {
+		if len(topics) == 0 || topics[len(topics)-1] != r.Topic {
+			topics = append(topics, r.Topic)
+		}
+	}
+	return topics
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExamples(t *testing.T) {
+	isolateConfig(t)
+
+	out, stderr, code := runCLI(t, "examples")
+	if code != 0 {
+		t.Fatalf("examples exited with %d: %s", code, stderr)
+	}
+	for _, topic := range recipeTopics() {
+		if !strings.Contains(out, "  "+topic+" — ") {
+			t.Errorf("examples is missing the %s topic:\n%s", topic, out)
+		}
+	}
+
+	out, _, _ = runCLI(t, "examples", "Waybar")
+	if !strings.Contains(out, `"exec": "prayer-times bar --quiet"`) || strings.Contains(out, "crontab") {
+		t.Errorf("examples waybar = %q, want only the Waybar module", out)
+	}
+
+	out, _, _ = runCLI(t, "examples", "cron", "--json")
+	var got []recipe
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got) != 1 || got[0].Topic != "cron" || len(got[0].Lines) == 0 {
+		t.Errorf("examples cron --json = %+v, want the cron recipe", got)
+	}
+
+	if _, stderr, code := runCLI(t, "examples", "emacs"); code == 0 || !strings.Contains(stderr, "unknown topic") {
+		t.Errorf("examples emacs: exit %d, stderr %q; want an unknown topic error", code, stderr)
+	}
+}
+
+// TestCommandExamples checks every command's examples are indented the way
+// cobra's help prints them and use the binary's name.
+func TestCommandExamples(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, line := range strings.Split(cmd.Example, "\n") {
+			if cmd.Example != "" && !strings.HasPrefix(line, "  prayer-times") && !strings.HasPrefix(line, "  curl") {
+				t.Errorf("%s: example %q should be indented two spaces and run prayer-times", cmd.CommandPath(), line)
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(NewRootCmd("test"))
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/ical"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+// maxExportDays caps the size of a single export.
+const maxExportDays = 366
+
+var (
+	flagExportMonth    int
+	flagExportYear     int
+	flagExportFrom     string
+	flagExportTo       string
+	flagExportOut      string
+	flagExportAlarm    time.Duration
+	flagExportDuration time.Duration
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export prayer times to other formats",
+	}
+
+	ical := &cobra.Command{
+		Use:   "ical",
+		Short: "Export prayer times as an iCalendar (.ics) file",
+		Long: `Export prayer times as iCalendar VEVENTs, one per prayer per day.
+
+By default the current month is exported to stdout. Use --month/--year for a
+specific month or --from/--to for an arbitrary date range, and --alarm to add
+a reminder before each prayer. The resulting file can be imported into (or
+served for subscription by) Google Calendar, Apple Calendar, and Outlook.`,
+		Example: `  prayer-times export ical > prayers.ics
+  prayer-times export ical --month 3 --year 2026 --alarm 10m -o march.ics
+  prayer-times export ical --from 2026-03-01 --to 2026-03-31 --prayers Fajr,Maghrib`,
+		Args: cobra.NoArgs,
+		RunE: runExportICal,
+	}
+	addExportRangeFlags(ical)
+	ical.Flags().DurationVar(&flagExportAlarm, "alarm", 0, "Add a reminder this long before each prayer (e.g. 10m)")
+	ical.Flags().DurationVar(&flagExportDuration, "duration", 15*time.Minute, "Length of each calendar event")
+	cmd.AddCommand(ical)
+	cmd.AddCommand(newExportPDFCmd())
+	cmd.AddCommand(newExportImageCmd())
+
+	return cmd
+}
+
+// addExportRangeFlags registers the date range and output flags shared by export formats.
+func addExportRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&flagExportMonth, "month", 0, "Month to export (1-12, default: current)")
+	cmd.Flags().IntVar(&flagExportYear, "year", 0, "Year of --month (default: current)")
+	cmd.Flags().StringVar(&flagExportFrom, "from", "", "Start date (YYYY-MM-DD); use with --to instead of --month")
+	cmd.Flags().StringVar(&flagExportTo, "to", "", "End date, inclusive (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&flagExportOut, "out", "o", "", "Write to this file instead of stdout")
+}
+
+// exportRange resolves the export flags into a start date and day count.
+func exportRange(now time.Time) (time.Time, int, error) {
+	if flagExportFrom != "" || flagExportTo != "" {
+		if flagExportMonth != 0 || flagExportYear != 0 {
+			return time.Time{}, 0, fmt.Errorf("--from/--to cannot be combined with --month/--year")
+		}
+		if flagExportFrom == "" || flagExportTo == "" {
+			return time.Time{}, 0, fmt.Errorf("--from and --to must be used together")
+		}
+		from, err := parseDateFlag(flagExportFrom, now.Location())
+		if err != nil {
+			return time.Time{}, 0, fmt.Errorf("invalid --from: %w", err)
+		}
+		to, err := parseDateFlag(flagExportTo, now.Location())
+		if err != nil {
+			return time.Time{}, 0, fmt.Errorf("invalid --to: %w", err)
+		}
+		if to.Before(from) {
+			return time.Time{}, 0, fmt.Errorf("--to %s is before --from %s", flagExportTo, flagExportFrom)
+		}
+		days := daysBetween(from, to) + 1
+		if days > maxExportDays {
+			return time.Time{}, 0, fmt.Errorf("range of %d days is too large (max %d)", days, maxExportDays)
+		}
+		return from, days, nil
+	}
+
+	year, month := now.Year(), int(now.Month())
+	if flagExportYear != 0 {
+		year = flagExportYear
+	}
+	if flagExportMonth != 0 {
+		if flagExportMonth < 1 || flagExportMonth > 12 {
+			return time.Time{}, 0, fmt.Errorf("invalid --month %d: must be between 1 and 12", flagExportMonth)
+		}
+		month = flagExportMonth
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 1, -1).Day(), nil
+}
+
+// parseDateFlag parses a YYYY-MM-DD date as midnight in loc.
+func parseDateFlag(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date", s)
+	}
+	return t, nil
+}
+
+// daysBetween returns the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	ad := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	bd := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(bd.Sub(ad).Hours() / 24)
+}
+
+// exportWriter returns the destination for export output and a function to close it.
+// stdout is used unless --out names a file.
+func exportWriter(stdout io.Writer) (io.Writer, func() error, error) {
+	if flagExportOut == "" || flagExportOut == "-" {
+		return stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(flagExportOut)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create %s: %w", flagExportOut, err)
+	}
+	return f, f.Close, nil
+}
+
+func runExportICal(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	// The current month is the location's, not the machine's.
+	start, days, err := exportRange(loc.localTime(currentTime()))
+	if err != nil {
+		return err
+	}
+
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	daysList, err := fetchCalendarDays(ctx, start, days, loc, method, school, c)
+	if err != nil {
+		return err
+	}
+
+	var meta api.Meta
+	if len(daysList) > 0 {
+		meta = daysList[0].Meta
+	}
+	tz := loc.Timezone
+	if tz == "" && len(daysList) > 0 {
+		tz = loc.timezoneFor(meta)
+	}
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	locationStr := buildLocationStr(loc, &fetchResult{Meta: meta})
+
+	cal := &ical.Calendar{Name: "Prayer Times — " + locationStr}
+	events, err := prayerEvents(daysList, tzLoc, selectedPrayers, locationStr, flagExportDuration, flagExportAlarm)
+	if err != nil {
+		return err
+	}
+	cal.Events = events
+
+	w, closeFn, err := exportWriter(cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	if err := cal.Encode(w); err != nil {
+		closeFn()
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	if err := closeFn(); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+
+	if flagExportOut != "" && flagExportOut != "-" {
+		notices.Infof("Wrote %d events to %s", len(cal.Events), flagExportOut)
+	}
+	return nil
+}
+
+// prayerEvents returns one calendar event per selected prayer per day, each
+// lasting duration with an optional alarm before it.
+func prayerEvents(daysList []dayData, tzLoc *time.Location, selected []string, locationStr string, duration, alarm time.Duration) ([]ical.Event, error) {
+	uidSuffix := strings.NewReplacer(" ", "", ",", "_").Replace(strings.ToLower(locationStr))
+
+	var events []ical.Event
+	for _, dd := range daysList {
+		day := calendarDay(dd.Date, tzLoc)
+		parsed, err := prayer.ParseTimings(dd.Timings, day, tzLoc, selected)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parsed {
+			events = append(events, ical.Event{
+				UID:         fmt.Sprintf("%s-%s-%s@prayer-times", day.Format("20060102"), strings.ToLower(p.Name), uidSuffix),
+				Summary:     p.Name,
+				Description: fmt.Sprintf("%s prayer — %s", p.Name, dd.DateInfo.Hijri.Format()),
+				Location:    locationStr,
+				Start:       p.Time,
+				Duration:    duration,
+				Alarm:       alarm,
+			})
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func setExportFlags(t *testing.T, month, year int, from, to string) {
+	t.Helper()
+	flagExportMonth, flagExportYear, flagExportFrom, flagExportTo = month, year, from, to
+	t.Cleanup(func() {
+		flagExportMonth, flagExportYear, flagExportFrom, flagExportTo = 0, 0, "", ""
+	})
+}
+
+func TestExportRange(t *testing.T) {
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		month     int
+		year      int
+		from, to  string
+		wantStart string
+		wantDays  int
+	}{
+		{"default current month", 0, 0, "", "", "2026-02-01", 28},
+		{"explicit month", 3, 0, "", "", "2026-03-01", 31},
+		{"month and year", 2, 2028, "", "", "2028-02-01", 29},
+		{"date range", 0, 0, "2026-03-30", "2026-04-02", "2026-03-30", 4},
+		{"single day", 0, 0, "2026-03-01", "2026-03-01", "2026-03-01", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setExportFlags(t, tt.month, tt.year, tt.from, tt.to)
+			start, days, err := exportRange(now)
+			if err != nil {
+				t.Fatalf("exportRange() error: %v", err)
+			}
+			if got := start.Format("2006-01-02"); got != tt.wantStart {
+				t.Errorf("start = %s, want %s", got, tt.wantStart)
+			}
+			if days != tt.wantDays {
+				t.Errorf("days = %d, want %d", days, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestExportRange_Invalid(t *testing.T) {
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		month    int
+		year     int
+		from, to string
+	}{
+		{"month out of range", 13, 0, "", ""},
+		{"from without to", 0, 0, "2026-03-01", ""},
+		{"range with month", 3, 0, "2026-03-01", "2026-03-02"},
+		{"to before from", 0, 0, "2026-03-02", "2026-03-01"},
+		{"bad date", 0, 0, "2026-3-1", "2026-03-02"},
+		{"too long", 0, 0, "2026-01-01", "2027-06-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setExportFlags(t, tt.month, tt.year, tt.from, tt.to)
+			if _, _, err := exportRange(now); err == nil {
+				t.Error("exportRange() should return an error")
+			}
+		})
+	}
+}
+
+// TestExportICal_LocationMonth checks that the default month is the
+// location's, not the machine's, near a month boundary.
+func TestExportICal_LocationMonth(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	// 22:00 UTC on 28 February is already 1 March in Mecca.
+	t.Setenv(fixedNowEnv, "2026-02-28T22:00:00Z")
+
+	out, stderr, code := runCLI(t, append([]string{"export", "ical"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("export ical exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "20260331") || strings.Contains(out, "20260228") {
+		t.Errorf("export ical covers the wrong month:\n%s", out)
+	}
+}
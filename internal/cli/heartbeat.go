@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/webhook"
+)
+
+// lastFetch is when timings were last fetched from the API, in Unix
+// nanoseconds, or zero if they haven't been this run.
+var lastFetch atomic.Int64
+
+// recordFetch notes a successful fetch of timings from the API.
+func recordFetch() {
+	lastFetch.Store(time.Now().UnixNano())
+}
+
+// heartbeatJSON is the status document serve posts to heartbeat_url.
+type heartbeatJSON struct {
+	Version   string     `json:"version"`
+	Host      string     `json:"host"`
+	Time      time.Time  `json:"time"`
+	LastFetch *time.Time `json:"last_fetch"` // null until the API has been reached
+	Next      *nextJSON  `json:"next,omitempty"`
+	Error     string     `json:"error,omitempty"` // why there is no next prayer
+}
+
+// heartbeatStatus reports the version, the last successful fetch and the next
+// prayer. Failing to find the next prayer is reported in the document, since
+// that is what a monitor wants to hear about.
+func (d *serveDaemon) heartbeatStatus(ctx context.Context) heartbeatJSON {
+	status := heartbeatJSON{Version: d.version, Time: time.Now()}
+	status.Host, _ = os.Hostname()
+	if ns := lastFetch.Load(); ns != 0 {
+		t := time.Unix(0, ns)
+		status.LastFetch = &t
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	s := d.srv
+	prayers, result, _, tzLoc, now, err := s.today(ctx)
+	if err == nil {
+		var next *prayer.Prayer
+		next, err = nextPrayerFrom(ctx, prayers, now, s.loc, s.method, s.school, s.cache, tzLoc, s.prayers)
+		if err == nil && next == nil {
+			err = errors.New("could not determine next prayer")
+		}
+		if err == nil {
+			j := buildNextJSON(*next, now, s.timeFmt)
+			j.Stale = result.Stale
+			status.Next = &j
+		}
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// runHeartbeat posts the status to heartbeat_url every heartbeat_interval,
+// until ctx is done. It idles while heartbeat_url isn't set, since the admin
+// API may set it later.
+func (d *serveDaemon) runHeartbeat(ctx context.Context) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	for {
+		d.mu.RLock()
+		url, every := d.heartbeatURL, d.heartbeatEvery
+		d.mu.RUnlock()
+
+		if url != "" {
+			body, err := json.Marshal(d.heartbeatStatus(ctx))
+			if err == nil {
+				err = webhook.Post(client, url, body)
+			}
+			if err != nil && ctx.Err() == nil {
+				notices.Warnf("heartbeat: %v", err)
+			}
+		}
+		if !sleepCtx(ctx, every) {
+			return
+		}
+	}
+}
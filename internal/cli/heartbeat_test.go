@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestHeartbeat verifies serve posts its version and next prayer to
+// heartbeat_url.
+func TestHeartbeat(t *testing.T) {
+	s := newTestServer(t)
+	cacheTodayAndTomorrow(t, s)
+
+	got := make(chan heartbeatJSON, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status heartbeatJSON
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&status) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		select {
+		case got <- status:
+		default:
+		}
+	}))
+	defer ts.Close()
+
+	d := &serveDaemon{version: "1.2.3", srv: s, heartbeatURL: ts.URL, heartbeatEvery: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.runHeartbeat(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case status := <-got:
+		if status.Version != "1.2.3" {
+			t.Errorf("version = %q, want 1.2.3", status.Version)
+		}
+		if status.Next == nil || status.Next.Prayer == "" {
+			t.Errorf("next = %+v (error %q), want the next prayer", status.Next, status.Error)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no heartbeat posted")
+	}
+}
@@ -0,0 +1,445 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/hijri"
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+func newHijriCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hijri [YYYY-MM-DD]",
+		Short: "Show the Hijri date or convert between calendars",
+		Long: `Show the Hijri date for today or for a Gregorian date, convert a Hijri date
+back to Gregorian, or render a full Hijri month.
+
+Conversions use the Al Adhan API, so dates follow its (Umm al-Qura based)
+calendar. When the API can't be reached they fall back, with a warning, to
+Umm al-Qura computed locally from the Sun and Moon for 1423-1500 AH, and to
+the tabular Islamic calendar, which may be a day or two off, outside those
+years; --json names the local calendar in "calendar". Either may differ from
+local moon sighting; 'config set hijri_adjustment 1' (or '-- -1') moves every
+Hijri date by a day to match.`,
+		Example: `  prayer-times hijri                       # today
+  prayer-times hijri 2026-03-01            # Gregorian -> Hijri
+  prayer-times hijri to-gregorian 1447-09-01
+  prayer-times hijri month                 # current Hijri month
+  prayer-times hijri month 10 1447`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runHijri,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "to-gregorian <YYYY-MM-DD>",
+		Short: "Convert a Hijri date to Gregorian",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runHijriToGregorian,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "month [MONTH [YEAR]]",
+		Short: "Show a Hijri month grid (default: current month)",
+		Args:  cobra.MaximumNArgs(2),
+		RunE:  runHijriMonth,
+	})
+
+	return cmd
+}
+
+// hijriJSONDate is the JSON structure for one converted date.
+type hijriJSONDate struct {
+	Gregorian string   `json:"gregorian"` // YYYY-MM-DD
+	Hijri     string   `json:"hijri"`     // YYYY-MM-DD
+	Weekday   string   `json:"weekday"`
+	Formatted string   `json:"formatted"`
+	MonthAr   string   `json:"month_ar,omitempty"`
+	Holidays  []string `json:"holidays,omitempty"`
+	Calendar  string   `json:"calendar,omitempty"` // the local calendar used when the API was unreachable (see localCalendar)
+}
+
+func runHijri(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	date := currentTime()
+	if len(args) > 0 {
+		d, err := parseDateFlag(args[0], time.Local)
+		if err != nil {
+			return err
+		}
+		date = d
+	}
+
+	info, calendar, err := convertToHijri(ctx, date)
+	if err != nil {
+		return fmt.Errorf("failed to convert date: %w", err)
+	}
+	return printHijriDate(cmd.OutOrStdout(), info, calendar)
+}
+
+func runHijriToGregorian(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	day, month, year, err := parseHijriDate(args[0])
+	if err != nil {
+		return err
+	}
+
+	info, calendar, err := convertToGregorian(ctx, hijri.Date{Year: year, Month: month, Day: day})
+	if err != nil {
+		return fmt.Errorf("failed to convert date: %w", err)
+	}
+	return printHijriDate(cmd.OutOrStdout(), info, calendar)
+}
+
+func runHijriMonth(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	var month, year int
+	if len(args) < 2 {
+		// Default to the current Hijri month/year.
+		info, _, err := convertToHijri(ctx, currentTime())
+		if err != nil {
+			return fmt.Errorf("failed to convert date: %w", err)
+		}
+		today := info.Hijri
+		month = today.Month.Number
+		year, _ = strconv.Atoi(today.Year)
+	}
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > 12 {
+			return fmt.Errorf("invalid Hijri month %q: must be between 1 and 12", args[0])
+		}
+		month = n
+	}
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid Hijri year %q", args[1])
+		}
+		year = n
+	}
+
+	days, calendar, err := fetchHijriMonth(ctx, month, year)
+	if err != nil {
+		return err
+	}
+
+	if FlagJSON {
+		out := make([]hijriJSONDate, 0, len(days))
+		for _, d := range days {
+			jd, err := hijriJSON(d)
+			if err != nil {
+				return err
+			}
+			jd.Calendar = calendar
+			out = append(out, jd)
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	rows, highlight, err := hijriMonthGrid(days, currentTime().Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	first := days[0].Hijri
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(fmt.Sprintf("%s %s", first.Month.En, first.Year)))
+	if first.Month.Ar != "" {
+		fmt.Fprintf(w, "  %s\n", display.Dim(first.Month.Ar))
+	}
+	fmt.Fprintln(w)
+
+	lang := displayLang(effectiveConfig(cmd), resolvedLocation{})
+	headers := make([]string, 0, 7)
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		headers = append(headers, i18n.Isolate(lang, i18n.ShortWeekday(lang, wd)))
+	}
+	tbl := display.NewTable(headers)
+	for _, row := range rows {
+		tbl.AddRow(row)
+	}
+	if highlight >= 0 {
+		tbl.SetHighlightRow(highlight)
+	}
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
+
+// printHijriDate prints one converted date as rich text or JSON. calendar
+// is the local calendar it came from, if the API couldn't be reached.
+func printHijriDate(w io.Writer, d api.DateInfo, calendar string) error {
+	jd, err := hijriJSON(d)
+	if err != nil {
+		return err
+	}
+	jd.Calendar = calendar
+
+	if FlagJSON {
+		data, err := json.MarshalIndent(jd, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	g, _ := time.Parse("2006-01-02", jd.Gregorian)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(jd.Formatted))
+	if jd.MonthAr != "" {
+		fmt.Fprintf(w, "  %s\n", display.Dim(fmt.Sprintf("%s %s %s", d.Hijri.Day, jd.MonthAr, d.Hijri.Year)))
+	}
+	fmt.Fprintf(w, "  %s\n", g.Format("Monday, 02 January 2006"))
+	for _, h := range jd.Holidays {
+		fmt.Fprintf(w, "  %s\n", display.Accent(h))
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// hijriJSON converts API date info into its JSON representation.
+func hijriJSON(d api.DateInfo) (hijriJSONDate, error) {
+	g, err := time.Parse("02-01-2006", d.Gregorian.Date)
+	if err != nil {
+		return hijriJSONDate{}, fmt.Errorf("invalid Gregorian date %q in API response", d.Gregorian.Date)
+	}
+	// Hijri dates are DD-MM-YYYY too, but can't go through time.Parse
+	// (e.g. 30-02-1447 is a valid Hijri date).
+	h := strings.Split(d.Hijri.Date, "-")
+	if len(h) != 3 {
+		return hijriJSONDate{}, fmt.Errorf("invalid Hijri date %q in API response", d.Hijri.Date)
+	}
+
+	return hijriJSONDate{
+		Gregorian: g.Format("2006-01-02"),
+		Hijri:     h[2] + "-" + h[1] + "-" + h[0],
+		Weekday:   g.Weekday().String(),
+		Formatted: d.Hijri.Format(),
+		MonthAr:   d.Hijri.Month.Ar,
+		Holidays:  d.Hijri.Holidays,
+	}, nil
+}
+
+// hajjDays names the days of Hajj, keyed by day of Dhul-Hijjah.
+var hajjDays = map[int]string{
+	8:  "Day of Tarwiyah — pilgrims set out for Mina",
+	9:  "Day of Arafah — pilgrims stand at Arafat",
+	10: "Eid al-Adha — Day of Sacrifice",
+	11: "1st Day of Tashreeq — stoning at Mina",
+	12: "2nd Day of Tashreeq — stoning at Mina",
+	13: "3rd Day of Tashreeq — stoning at Mina",
+}
+
+// hijriEvents returns the events on a Hijri date: the days of Hajj in
+// Dhul-Hijjah, otherwise the holidays the API lists.
+func hijriEvents(h api.HijriDate) []string {
+	if day, err := strconv.Atoi(h.Day); err == nil && h.Month.Number == 12 {
+		if name, ok := hajjDays[day]; ok {
+			return []string{name}
+		}
+	}
+	return h.Holidays
+}
+
+// parseHijriDate parses a Hijri date given as YYYY-MM-DD.
+func parseHijriDate(s string) (day, month, year int, err error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid Hijri date %q: expected YYYY-MM-DD", s)
+	}
+	year, errY := strconv.Atoi(parts[0])
+	month, errM := strconv.Atoi(parts[1])
+	day, errD := strconv.Atoi(parts[2])
+	if errY != nil || errM != nil || errD != nil || year < 1 {
+		return 0, 0, 0, fmt.Errorf("invalid Hijri date %q: expected YYYY-MM-DD", s)
+	}
+	if month < 1 || month > 12 {
+		return 0, 0, 0, fmt.Errorf("invalid Hijri month %d: must be between 1 and 12", month)
+	}
+	if day < 1 || day > 30 {
+		return 0, 0, 0, fmt.Errorf("invalid Hijri day %d: must be between 1 and 30", day)
+	}
+	return day, month, year, nil
+}
+
+// hijriMonthGrid lays out the days of a Hijri month in Sunday-first weeks.
+// Each cell shows the Hijri day and its Gregorian date. The returned index is
+// the row containing today (YYYY-MM-DD), or -1.
+func hijriMonthGrid(days []api.DateInfo, today string) ([][]string, int, error) {
+	var rows [][]string
+	highlight := -1
+	week := make([]string, 7)
+
+	for i, d := range days {
+		g, err := time.Parse("02-01-2006", d.Gregorian.Date)
+		if err != nil {
+			return nil, -1, fmt.Errorf("invalid Gregorian date %q in API response", d.Gregorian.Date)
+		}
+		col := int(g.Weekday())
+		if i > 0 && col == 0 {
+			rows = append(rows, week)
+			week = make([]string, 7)
+		}
+		week[col] = fmt.Sprintf("%2s %s", d.Hijri.Day, g.Format("02 Jan"))
+		if g.Format("2006-01-02") == today {
+			highlight = len(rows)
+		}
+	}
+	rows = append(rows, week)
+
+	return rows, highlight, nil
+}
+
+// convertToHijri returns the date info of date's Gregorian day, from the API
+// or, when it can't be reached, the local calendar, with the Hijri date moved
+// by the hijri_adjustment setting. calendar names the local calendar used,
+// if any (see localCalendar).
+func convertToHijri(ctx context.Context, date time.Time) (info api.DateInfo, calendar string, err error) {
+	resp, err := newAPIClient().ConvertToHijri(ctx, date.AddDate(0, 0, hijriAdjustment))
+	if err != nil {
+		if !api.Unreachable(err) {
+			return api.DateInfo{}, "", err
+		}
+		h := hijri.FromGregorian(date, hijriAdjustment)
+		return localDateInfo(date, h), localCalendar(h.Year), nil
+	}
+	return withGregorian(resp.Data, date), "", nil
+}
+
+// convertToGregorian returns the date info of the Gregorian day on which the
+// Hijri date h falls, from the API or, when it can't be reached, the local
+// calendar, taking the hijri_adjustment setting into account. calendar names
+// the local calendar used, if any.
+func convertToGregorian(ctx context.Context, h hijri.Date) (info api.DateInfo, calendar string, err error) {
+	resp, err := newAPIClient().ConvertToGregorian(ctx, h.Day, h.Month, h.Year)
+	if err != nil {
+		if !api.Unreachable(err) {
+			return api.DateInfo{}, "", err
+		}
+		g, localErr := hijri.ToGregorian(h, hijriAdjustment)
+		if localErr != nil {
+			return api.DateInfo{}, "", localErr
+		}
+		return localDateInfo(g, h), localCalendar(h.Year), nil
+	}
+	g, err := time.Parse("02-01-2006", resp.Data.Gregorian.Date)
+	if err != nil {
+		return api.DateInfo{}, "", fmt.Errorf("invalid Gregorian date %q in API response", resp.Data.Gregorian.Date)
+	}
+	return withGregorian(resp.Data, g.AddDate(0, 0, -hijriAdjustment)), "", nil
+}
+
+// fetchHijriMonth returns every day of a Hijri month, from the API or, when it
+// can't be reached, the local calendar, taking the hijri_adjustment setting
+// into account. calendar names the local calendar used, if any.
+func fetchHijriMonth(ctx context.Context, month, year int) (days []api.DateInfo, calendar string, err error) {
+	resp, err := newAPIClient().FetchHijriMonth(ctx, month, year)
+	if err != nil {
+		if !api.Unreachable(err) {
+			return nil, "", err
+		}
+		days := make([]api.DateInfo, 0, 30)
+		for day := 1; day <= hijri.DaysInMonth(year, month); day++ {
+			h := hijri.Date{Year: year, Month: month, Day: day}
+			g, _ := hijri.ToGregorian(h, hijriAdjustment)
+			days = append(days, localDateInfo(g, h))
+		}
+		return days, localCalendar(year), nil
+	}
+	if len(resp.Data) == 0 {
+		return nil, "", fmt.Errorf("no days returned for Hijri month %d/%d", month, year)
+	}
+	days = make([]api.DateInfo, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		g, err := time.Parse("02-01-2006", d.Gregorian.Date)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid Gregorian date %q in API response", d.Gregorian.Date)
+		}
+		days = append(days, withGregorian(d, g.AddDate(0, 0, -hijriAdjustment)))
+	}
+	return days, "", nil
+}
+
+// localCalendar names the calendar hijri computes year's dates with, for
+// output made without the API, and warns that the API couldn't be reached.
+func localCalendar(year int) string {
+	if hijri.UmmAlQura(year) {
+		notices.Warnf("Hijri API unreachable; using Umm al-Qura computed locally")
+		return "umm al-qura"
+	}
+	notices.Warnf("Hijri API unreachable; using the tabular calendar, which may be a day or two off Umm al-Qura")
+	return "tabular"
+}
+
+// adjustHijri moves d's Hijri date by the hijri_adjustment setting, and fills
+// it in from the local calendar when the API left it out.
+func adjustHijri(d api.DateInfo) api.DateInfo {
+	if hijriAdjustment == 0 && d.Hijri.Date != "" {
+		return d
+	}
+	if h, ok := parseAPIHijri(d.Hijri); ok {
+		d.Hijri = apiHijri(h.AddDays(hijriAdjustment))
+		return d
+	}
+	if g, err := time.Parse("02-01-2006", d.Gregorian.Date); err == nil {
+		d.Hijri = apiHijri(hijri.FromGregorian(g, hijriAdjustment))
+	}
+	return d
+}
+
+// parseAPIHijri parses the API's DD-MM-YYYY Hijri date.
+func parseAPIHijri(h api.HijriDate) (hijri.Date, bool) {
+	var d hijri.Date
+	if _, err := fmt.Sscanf(h.Date, "%d-%d-%d", &d.Day, &d.Month, &d.Year); err != nil {
+		return hijri.Date{}, false
+	}
+	return d, d.Valid()
+}
+
+// localDateInfo returns the date info of the Gregorian day g and its Hijri
+// date h, as the API would.
+func localDateInfo(g time.Time, h hijri.Date) api.DateInfo {
+	return withGregorian(api.DateInfo{Hijri: apiHijri(h)}, g)
+}
+
+// withGregorian returns d with its Gregorian date replaced by g's day.
+func withGregorian(d api.DateInfo, g time.Time) api.DateInfo {
+	d.Readable = g.Format("02 Jan 2006")
+	d.Gregorian = api.GregorianDate{
+		Date:    g.Format("02-01-2006"),
+		Day:     g.Format("02"),
+		Weekday: api.GregorianDay{En: g.Weekday().String()},
+		Month:   api.GregorianMonth{Number: int(g.Month()), En: g.Month().String()},
+		Year:    strconv.Itoa(g.Year()),
+	}
+	return d
+}
+
+// apiHijri returns h in the API's form, with the holidays on it.
+func apiHijri(h hijri.Date) api.HijriDate {
+	return api.HijriDate{
+		Date:        fmt.Sprintf("%02d-%02d-%d", h.Day, h.Month, h.Year),
+		Day:         fmt.Sprintf("%02d", h.Day),
+		Month:       api.HijriMonth{Number: h.Month, En: hijri.MonthName(h.Month), Ar: hijri.MonthNameAr(h.Month)},
+		Year:        strconv.Itoa(h.Year),
+		Designation: api.HijriDesignation{Abbreviated: "AH", Expanded: "Anno Hegirae"},
+		Holidays:    hijri.Holidays(h),
+	}
+}
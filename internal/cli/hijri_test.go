@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+)
+
+func TestParseHijriDate(t *testing.T) {
+	day, month, year, err := parseHijriDate("1447-09-30")
+	if err != nil {
+		t.Fatalf("parseHijriDate() error: %v", err)
+	}
+	if day != 30 || month != 9 || year != 1447 {
+		t.Errorf("parseHijriDate() = %d/%d/%d, want 30/9/1447", day, month, year)
+	}
+
+	for _, bad := range []string{"", "1447-09", "1447-13-01", "1447-09-31", "1447-09-00", "abc-09-01"} {
+		if _, _, _, err := parseHijriDate(bad); err == nil {
+			t.Errorf("parseHijriDate(%q) should return an error", bad)
+		}
+	}
+}
+
+func TestHijriJSON(t *testing.T) {
+	d := api.DateInfo{
+		Hijri: api.HijriDate{
+			Date:  "30-02-1447",
+			Day:   "30",
+			Month: api.HijriMonth{Number: 2, En: "Ṣafar", Ar: "صَفَر"},
+			Year:  "1447",
+		},
+		Gregorian: api.GregorianDate{Date: "23-08-2025"},
+	}
+
+	got, err := hijriJSON(d)
+	if err != nil {
+		t.Fatalf("hijriJSON() error: %v", err)
+	}
+	if got.Hijri != "1447-02-30" {
+		t.Errorf("Hijri = %q, want 1447-02-30", got.Hijri)
+	}
+	if got.Gregorian != "2025-08-23" || got.Weekday != "Saturday" {
+		t.Errorf("Gregorian = %q (%s), want 2025-08-23 (Saturday)", got.Gregorian, got.Weekday)
+	}
+}
+
+func TestHijriMonthGrid(t *testing.T) {
+	// 1 Ramadan 1447 is Wednesday 18 Feb 2026; 29 days.
+	var days []api.DateInfo
+	for i := 0; i < 29; i++ {
+		days = append(days, api.DateInfo{
+			Hijri:     api.HijriDate{Day: fmt.Sprintf("%d", i+1)},
+			Gregorian: api.GregorianDate{Date: fmt.Sprintf("%02d-%02d-2026", (17+i)%28+1, 2+(17+i)/28)},
+		})
+	}
+
+	rows, highlight, err := hijriMonthGrid(days, "2026-03-01")
+	if err != nil {
+		t.Fatalf("hijriMonthGrid() error: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("got %d weeks, want 5", len(rows))
+	}
+	if rows[0][3] != " 1 18 Feb" || rows[0][2] != "" {
+		t.Errorf("first week = %q, want day 1 on Wednesday", rows[0])
+	}
+	if rows[4][3] != "29 18 Mar" || rows[4][4] != "" {
+		t.Errorf("last week = %q, want day 29 on Wednesday", rows[4])
+	}
+	if highlight != 2 {
+		t.Errorf("highlight = %d, want 2 (week of 1 Mar)", highlight)
+	}
+}
+
+func TestHijriEvents(t *testing.T) {
+	arafah := api.HijriDate{Day: "9", Month: api.HijriMonth{Number: 12}, Holidays: []string{"Arafa"}}
+	if got := hijriEvents(arafah); len(got) != 1 || got[0] != hajjDays[9] {
+		t.Errorf("hijriEvents(9 Dhul-Hijjah) = %q, want %q", got, hajjDays[9])
+	}
+
+	ashura := api.HijriDate{Day: "10", Month: api.HijriMonth{Number: 1}, Holidays: []string{"Ashura"}}
+	if got := hijriEvents(ashura); len(got) != 1 || got[0] != "Ashura" {
+		t.Errorf("hijriEvents(10 Muharram) = %q, want the API's holidays", got)
+	}
+
+	if got := hijriEvents(api.HijriDate{Day: "20", Month: api.HijriMonth{Number: 12}}); len(got) != 0 {
+		t.Errorf("hijriEvents(20 Dhul-Hijjah) = %q, want none", got)
+	}
+}
+
+func TestAdjustHijri(t *testing.T) {
+	prev := hijriAdjustment
+	t.Cleanup(func() { hijriAdjustment = prev })
+
+	info := api.DateInfo{
+		Gregorian: api.GregorianDate{Date: "18-02-2026"},
+		Hijri:     api.HijriDate{Date: "29-08-1447", Day: "29", Month: api.HijriMonth{Number: 8, En: "Shaʿbān"}, Year: "1447"},
+	}
+
+	hijriAdjustment = 0
+	if got := adjustHijri(info).Hijri; got.Date != "29-08-1447" {
+		t.Errorf("adjustHijri() with no adjustment = %s, want the API's date", got.Date)
+	}
+
+	hijriAdjustment = 1
+	if got := adjustHijri(info).Hijri; got.Format() != "01 Ramaḍān 1447 AH" || len(got.Holidays) != 1 {
+		t.Errorf("adjustHijri(+1) = %q %q, want 01 Ramaḍān 1447 AH and its holiday", got.Format(), got.Holidays)
+	}
+
+	// Without the API's Hijri date, it's computed from the Gregorian one.
+	hijriAdjustment = 0
+	info.Hijri = api.HijriDate{}
+	if got := adjustHijri(info).Hijri; got.Date != "01-09-1447" {
+		t.Errorf("adjustHijri() without a Hijri date = %s, want 01-09-1447", got.Date)
+	}
+}
+
+func TestHijriOffline(t *testing.T) {
+	isolateConfig(t)
+	prev := apiBaseURL
+	apiBaseURL = "http://127.0.0.1:1"
+	t.Cleanup(func() { apiBaseURL = prev })
+
+	out, stderr, code := runCLI(t, "hijri", "2026-03-20", "--json")
+	if code != 0 {
+		t.Fatalf("hijri offline exited with %d: %s", code, stderr)
+	}
+	var got hijriJSONDate
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Hijri != "1447-10-01" || len(got.Holidays) != 1 || got.Holidays[0] != "Eid-ul-Fitr" {
+		t.Errorf("hijri 2026-03-20 offline = %+v, want 1447-10-01, Eid-ul-Fitr", got)
+	}
+	if got.Calendar != "umm al-qura" || !strings.Contains(stderr, "Hijri API unreachable") {
+		t.Errorf("offline calendar = %q, stderr %q; want umm al-qura and a warning", got.Calendar, stderr)
+	}
+	out, stderr, code = runCLI(t, "hijri", "1990-01-01", "--json")
+	if code != 0 {
+		t.Fatalf("hijri offline exited with %d: %s", code, stderr)
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil || got.Calendar != "tabular" {
+		t.Errorf("hijri 1990-01-01 offline calendar = %q (%v), want tabular", got.Calendar, err)
+	}
+
+	if _, stderr, code := runCLI(t, "config", "set", "--", "hijri_adjustment", "-1"); code != 0 {
+		t.Fatalf("config set hijri_adjustment: %s", stderr)
+	}
+	out, stderr, code = runCLI(t, "hijri", "to-gregorian", "1447-10-01", "--json")
+	if code != 0 {
+		t.Fatalf("hijri to-gregorian offline exited with %d: %s", code, stderr)
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Gregorian != "2026-03-21" {
+		t.Errorf("1 Shawwal with hijri_adjustment -1 = %s, want 2026-03-21", got.Gregorian)
+	}
+}
+
+// TestHijriAPIError checks that an error from the API fails the conversion
+// rather than falling back to the local calendar.
+func TestHijriAPIError(t *testing.T) {
+	isolateConfig(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+	prev := apiBaseURL
+	apiBaseURL = srv.URL
+	t.Cleanup(func() { apiBaseURL = prev })
+
+	for _, args := range [][]string{
+		{"hijri", "2026-03-20"},
+		{"hijri", "to-gregorian", "1447-10-01"},
+		{"hijri", "month", "9", "1447"},
+	} {
+		if _, stderr, code := runCLI(t, args...); code == 0 || !strings.Contains(stderr, "400") {
+			t.Errorf("%v with the API failing: exit %d, stderr %q; want the API's error", args, code, stderr)
+		}
+	}
+}
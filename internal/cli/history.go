@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/archive"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Inspect the archive of fetched prayer times",
+		Long: `Inspect the permanent archive of fetched prayer times.
+
+Archiving is opt-in. Enable it with:
+  prayer-times config set archive true
+
+Every day fetched from the API is then recorded under
+~/.local/share/prayer-times/archive/ (respects $XDG_DATA_HOME). A new version
+is stored only when the API returns different times for the same day,
+location, and method, so 'history diff' shows exactly what changed upstream.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show [YYYY-MM-DD]",
+		Short: "Show archived versions for a date (default: today)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runHistoryShow,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "diff [YYYY-MM-DD]",
+		Short: "Show how archived times changed (default: all dates)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runHistoryDiff,
+	})
+
+	return cmd
+}
+
+// archiveMu serializes archive writes from concurrent fetches (see world).
+var archiveMu sync.Mutex
+
+// archiveDay records one freshly fetched day in the archive, if archiving is enabled.
+// client is the one the day was fetched with, for its custom calculation settings.
+// Archiving is best-effort and never interrupts the command.
+func archiveDay(client *api.Client, date string, loc resolvedLocation, method, school int, data api.Data) {
+	if dataArchive == nil {
+		return
+	}
+	rec := archive.Record{
+		Date:      date,
+		Latitude:  loc.Lat,
+		Longitude: loc.Lon,
+		City:      loc.City,
+		Country:   loc.Country,
+		Method:    method,
+		School:    school,
+		Settings:  client.MethodSettings,
+		Tune:      client.Tune,
+		LatAdjust: client.LatitudeAdjustment,
+		Midnight:  client.MidnightMode,
+		TZ:        client.Timezone,
+		Timezone:  data.Meta.Timezone,
+		Timings:   data.Timings,
+	}
+	if method == api.MoonsightingMethod {
+		rec.Shafaq = client.Shafaq // not sent, so not recorded, for other methods
+	}
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+	_, _ = dataArchive.Add(rec)
+}
+
+// archiveCalendar records every day of a freshly fetched month, if archiving is enabled.
+func archiveCalendar(client *api.Client, loc resolvedLocation, method, school int, days []api.Data) {
+	if dataArchive == nil {
+		return
+	}
+	for _, d := range days {
+		date, err := time.Parse("02-01-2006", d.Date.Gregorian.Date)
+		if err != nil {
+			continue
+		}
+		archiveDay(client, date.Format("2006-01-02"), loc, method, school, d)
+	}
+}
+
+// openHistory returns the archive for reading, even when archiving new data is disabled.
+func openHistory() (*archive.Archive, error) {
+	if dataArchive != nil {
+		return dataArchive, nil
+	}
+	return archive.New("")
+}
+
+// historyDateArg validates an optional YYYY-MM-DD argument, defaulting to today.
+func historyDateArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return time.Now().Format("2006-01-02"), nil
+	}
+	if _, err := time.Parse("2006-01-02", args[0]); err != nil {
+		return "", fmt.Errorf("invalid date %q: expected YYYY-MM-DD", args[0])
+	}
+	return args[0], nil
+}
+
+// historyJSONRecord is the JSON structure for one archived version.
+type historyJSONRecord struct {
+	FetchedAt string            `json:"fetched_at"`
+	Location  string            `json:"location"`
+	Timings   map[string]string `json:"timings"`
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	date, err := historyDateArg(args)
+	if err != nil {
+		return err
+	}
+
+	a, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	records, err := a.Load(date)
+	if err != nil {
+		return err
+	}
+
+	if FlagJSON {
+		out := make([]historyJSONRecord, 0, len(records))
+		for _, r := range records {
+			timings := make(map[string]string)
+			for name, v := range prayer.TimingsMap(r.Timings) {
+				timings[strings.ToLower(name)] = v
+			}
+			out = append(out, historyJSONRecord{
+				FetchedAt: r.FetchedAt.Format(time.RFC3339),
+				Location:  r.Label(),
+				Timings:   timings,
+			})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintf(w, "No archived times for %s.\n", date)
+		if dataArchive == nil {
+			fmt.Fprintln(w, "Archiving is disabled; enable it with: prayer-times config set archive true")
+		}
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Archived Prayer Times — "+date))
+	fmt.Fprintln(w)
+
+	headers := append([]string{"Fetched", "Location"}, prayer.DefaultPrayerNames...)
+	tbl := display.NewTable(headers)
+	for _, r := range records {
+		timings := prayer.TimingsMap(r.Timings)
+		row := []string{r.FetchedAt.Local().Format("2006-01-02 15:04"), r.Label()}
+		for _, name := range prayer.DefaultPrayerNames {
+			row = append(row, timings[name])
+		}
+		tbl.AddRow(row)
+	}
+
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
+
+// historyJSONChange is the JSON structure for one detected change.
+type historyJSONChange struct {
+	Date      string `json:"date"`
+	Location  string `json:"location"`
+	FetchedAt string `json:"fetched_at"`
+	Prayer    string `json:"prayer"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	Minutes   int    `json:"minutes"`
+}
+
+func runHistoryDiff(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	a, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	var dates []string
+	if len(args) > 0 {
+		date, err := historyDateArg(args)
+		if err != nil {
+			return err
+		}
+		dates = []string{date}
+	} else {
+		dates, err = a.Dates()
+		if err != nil {
+			return err
+		}
+	}
+
+	var changes []historyJSONChange
+	for _, date := range dates {
+		records, err := a.Load(date)
+		if err != nil {
+			return err
+		}
+
+		// Compare each version with the previous version for the same key.
+		last := make(map[string]archive.Record)
+		for _, r := range records {
+			prev, ok := last[r.Key()]
+			last[r.Key()] = r
+			if !ok {
+				continue
+			}
+			for _, ch := range archive.Diff(prev.Timings, r.Timings) {
+				changes = append(changes, historyJSONChange{
+					Date:      date,
+					Location:  r.Label(),
+					FetchedAt: r.FetchedAt.Format(time.RFC3339),
+					Prayer:    ch.Prayer,
+					Old:       ch.Old,
+					New:       ch.New,
+					Minutes:   ch.Minutes,
+				})
+			}
+		}
+	}
+
+	if FlagJSON {
+		if changes == nil {
+			changes = []historyJSONChange{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No changes recorded across %d archived day(s).\n", len(dates))
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Archived Time Changes"))
+	fmt.Fprintln(w)
+
+	tbl := display.NewTable([]string{"Date", "Location", "Prayer", "Old", "New", "Change"})
+	for _, ch := range changes {
+		tbl.AddRow([]string{ch.Date, ch.Location, ch.Prayer, ch.Old, ch.New, fmt.Sprintf("%+dm", ch.Minutes)})
+	}
+
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
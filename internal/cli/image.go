@@ -0,0 +1,306 @@
+package cli
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/pdf"
+	"github.com/smokyabdulrahman/prayer-times/internal/pixfont"
+	"github.com/spf13/cobra"
+)
+
+var flagExportFormat string
+
+// imageFormats are the values of export image's --format.
+var imageFormats = []string{"png", "svg"}
+
+func newExportImageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image",
+		Short: "Export the monthly timetable as a PNG or SVG image",
+		Long: `Export the same timetable as 'export pdf' as a single image, to share in a
+chat group or post on a website.
+
+The format follows the --out file's extension, .png or .svg, or --format
+when writing to stdout (default: png). PNGs are drawn with a small built-in
+pixel font, so they are in English with letters outside ASCII printed
+without their diacritics, and a heading in another script is rejected; SVGs
+keep the text as text, in the viewer's sans-serif font.`,
+		Example: `  prayer-times export image --month 3 --out march.png
+  prayer-times export image --mosque "Masjid An-Nur" -o timetable.svg
+  prayer-times export image --format svg > timetable.svg`,
+		Args: cobra.NoArgs,
+		RunE: runExportImage,
+	}
+	addExportRangeFlags(cmd)
+	addSheetFlags(cmd)
+	cmd.Flags().StringVar(&flagExportFormat, "format", "", "Image format (png or svg; default: from --out, else png)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(imageFormats, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func runExportImage(cmd *cobra.Command, args []string) error {
+	format, err := imageFormat()
+	if err != nil {
+		return err
+	}
+	if format == "png" {
+		if err := checkSheetText(pixfont.CanDraw, "the PNG's built-in font; use Latin letters, or --format svg"); err != nil {
+			return err
+		}
+	}
+
+	s, err := fetchSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	w, closeFn, err := exportWriter(cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	if format == "svg" {
+		err = s.svg(w)
+	} else {
+		err = png.Encode(w, s.png())
+	}
+	if err != nil {
+		closeFn()
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	if err := closeFn(); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+
+	if flagExportOut != "" && flagExportOut != "-" {
+		notices.Infof("Wrote %d days to %s", len(s.Rows), flagExportOut)
+	}
+	return nil
+}
+
+// imageFormat returns the format to export an image in: --format, or the
+// --out file's extension.
+func imageFormat() (string, error) {
+	format := strings.ToLower(flagExportFormat)
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(flagExportOut), "."))
+		if format == "" || flagExportOut == "-" {
+			return "png", nil
+		}
+	}
+	for _, f := range imageFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	if flagExportFormat == "" {
+		return "", fmt.Errorf("cannot tell the image format of %s: use a .png or .svg file, or --format", flagExportOut)
+	}
+	return "", fmt.Errorf("invalid --format %q: must be png or svg", flagExportFormat)
+}
+
+// Colors of the exported images, matching the PDF's grays.
+var (
+	sheetInk    = color.Gray{Y: 0x11}
+	sheetMuted  = color.Gray{Y: 0x55}
+	sheetRule   = color.Gray{Y: 0xb3}
+	sheetHeader = color.Gray{Y: 0xd9}
+	sheetFriday = color.Gray{Y: 0xed}
+	sheetPaper  = color.White
+)
+
+// sheetPadding is the horizontal padding of an image's cells, in font pixels.
+const sheetPadding = 2 * pixfont.Advance
+
+// png draws s as an image, with each pixel of the font a 2×2 square.
+func (s sheet) png() image.Image {
+	const (
+		scale     = 2
+		margin    = 32
+		rowHeight = pixfont.GlyphHeight*scale + 14
+	)
+	measure := func(text string, bold bool) float64 {
+		return float64(pixfont.Width(text, scale))
+	}
+
+	// The table is at least as wide as the heading.
+	heading := s.heading()
+	width := 0
+	for _, line := range heading {
+		width = max(width, pixfont.Width(line.Text, lineScale(line, scale)))
+	}
+	cols := s.columnWidths(measure, sheetPadding*scale, float64(width))
+	tableWidth := 0
+	for _, w := range cols {
+		tableWidth += int(w)
+	}
+
+	height := margin
+	for _, line := range heading {
+		height += pixfont.GlyphHeight*lineScale(line, scale) + 12
+	}
+	height += 10 + (len(s.Rows)+1)*rowHeight + 14 + pixfont.GlyphHeight*scale + margin
+
+	img := image.NewRGBA(image.Rect(0, 0, tableWidth+2*margin, height))
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	fill(0, 0, img.Bounds().Dx(), height, sheetPaper)
+
+	y := margin
+	center := img.Bounds().Dx() / 2
+	for _, line := range heading {
+		sc := lineScale(line, scale)
+		c := color.Color(sheetInk)
+		if !line.Bold {
+			c = sheetMuted
+		}
+		pixfont.Draw(img, center-pixfont.Width(line.Text, sc)/2, y, line.Text, sc, c, line.Bold)
+		y += pixfont.GlyphHeight*sc + 12
+	}
+	y += 10
+
+	cells := func(top int, values []string, bold bool) {
+		x := margin
+		textY := top + (rowHeight-pixfont.GlyphHeight*scale)/2
+		for i, v := range values {
+			if i < 3 {
+				pixfont.Draw(img, x+sheetPadding*scale/2, textY, v, scale, sheetInk, bold)
+			} else {
+				pixfont.Draw(img, x+(int(cols[i])-pixfont.Width(v, scale))/2, textY, v, scale, sheetInk, bold)
+			}
+			x += int(cols[i])
+		}
+	}
+	top := y
+	fill(margin, top, margin+tableWidth, top+rowHeight, sheetHeader)
+	cells(top, s.Headers, true)
+	for i, row := range s.Rows {
+		rowTop := top + (i+1)*rowHeight
+		if s.Fridays[i] {
+			fill(margin, rowTop, margin+tableWidth, rowTop+rowHeight, sheetFriday)
+		}
+		fill(margin, rowTop, margin+tableWidth, rowTop+1, sheetRule)
+		cells(rowTop, row, false)
+	}
+	bottom := top + (len(s.Rows)+1)*rowHeight
+	fill(margin, top, margin+tableWidth, top+1, sheetRule)
+	fill(margin, bottom, margin+tableWidth+1, bottom+1, sheetRule)
+	x := margin
+	for _, w := range append([]float64{0}, cols...) {
+		x += int(w)
+		fill(x, top, x+1, bottom, sheetRule)
+	}
+
+	pixfont.Draw(img, margin, bottom+14, s.Footer, scale, sheetMuted, false)
+	return img
+}
+
+// lineScale returns the font scale of a heading line over a table drawn at
+// scale.
+func lineScale(line sheetLine, scale int) int {
+	return max(int(line.Size*float64(scale)+0.5), scale)
+}
+
+// svg writes s as an SVG image. Its text is measured with Helvetica's
+// metrics, which Arial and most sans-serif fonts share closely enough for
+// the cells to fit.
+func (s sheet) svg(w io.Writer) error {
+	const (
+		fontSize  = 14.0
+		margin    = 32.0
+		rowHeight = 26.0
+		padding   = 16.0
+	)
+	measure := func(text string, bold bool) float64 {
+		return pdf.TextWidth(text, fontSize, bold)
+	}
+
+	heading := s.heading()
+	width := 0.0
+	for _, line := range heading {
+		width = max(width, pdf.TextWidth(line.Text, fontSize*line.Size, line.Bold))
+	}
+	cols := s.columnWidths(measure, padding, width)
+	tableWidth := 0.0
+	for _, c := range cols {
+		tableWidth += c
+	}
+
+	var b strings.Builder
+	text := func(x, y, size float64, bold bool, anchor, fill, s string) {
+		weight := ""
+		if bold {
+			weight = ` font-weight="bold"`
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.1f"%s text-anchor="%s" fill="%s">%s</text>`+"\n", x, y, size, weight, anchor, fill, html.EscapeString(s))
+	}
+	rect := func(x, y, w, h float64, fill string) {
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, fill)
+	}
+	line := func(x1, y1, x2, y2 float64) {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#b3b3b3"/>`+"\n", x1, y1, x2, y2)
+	}
+
+	y := margin
+	center := margin + tableWidth/2
+	for _, l := range heading {
+		size := fontSize * l.Size
+		y += size
+		fill := "#111"
+		if !l.Bold {
+			fill = "#555"
+		}
+		text(center, y, size, l.Bold, "middle", fill, l.Text)
+		y += size * 0.5
+	}
+	y += 10
+
+	cells := func(top float64, values []string, bold bool) {
+		x := margin
+		baseline := top + rowHeight/2 + fontSize*0.35
+		for i, v := range values {
+			if i < 3 {
+				text(x+padding/2, baseline, fontSize, bold, "start", "#111", v)
+			} else {
+				text(x+cols[i]/2, baseline, fontSize, bold, "middle", "#111", v)
+			}
+			x += cols[i]
+		}
+	}
+	top := y
+	rect(margin, top, tableWidth, rowHeight, "#d9d9d9")
+	cells(top, s.Headers, true)
+	for i, row := range s.Rows {
+		rowTop := top + float64(i+1)*rowHeight
+		if s.Fridays[i] {
+			rect(margin, rowTop, tableWidth, rowHeight, "#ededed")
+		}
+		line(margin, rowTop, margin+tableWidth, rowTop)
+		cells(rowTop, row, false)
+	}
+	bottom := top + float64(len(s.Rows)+1)*rowHeight
+	line(margin, top, margin+tableWidth, top)
+	line(margin, bottom, margin+tableWidth, bottom)
+	x := margin
+	for _, c := range append([]float64{0}, cols...) {
+		x += c
+		line(x, top, x, bottom)
+	}
+	text(margin, bottom+24, fontSize*0.85, false, "start", "#555", s.Footer)
+
+	width, height := tableWidth+2*margin, bottom+24+margin
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">
+<title>%s</title>
+<rect width="100%%" height="100%%" fill="#fff"/>
+%s</svg>
+`, width, height, width, height, html.EscapeString(s.Title), b.String())
+	return err
+}
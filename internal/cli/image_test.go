@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportImagePNG(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	path := filepath.Join(t.TempDir(), "march.png")
+	_, stderr, code := runCLI(t, append([]string{"export", "image", "--month", "3", "--year", "2026", "--mosque", "Masjid An-Nur", "--out", path}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("export image exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "Wrote 31 days to "+path) {
+		t.Errorf("stderr = %q, want a notice of 31 days written", stderr)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("export image wrote an invalid PNG: %v", err)
+	}
+	// A heading and 32 rows of 28 pixels make the image taller than wide.
+	if b := img.Bounds(); b.Dy() < 32*28 || b.Dx() < 600 || b.Dx() > b.Dy() {
+		t.Errorf("PNG is %dx%d, want a portrait page", b.Dx(), b.Dy())
+	}
+}
+
+// TestExportImageArabicMosque checks that an Arabic mosque name is rejected
+// for a PNG, whose font can't draw it, and kept in an SVG.
+func TestExportImageArabicMosque(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	args := append([]string{"export", "image", "--from", "2026-03-19", "--to", "2026-03-21", "--mosque", "مسجد النور"}, meccaArgs(t)...)
+	if _, stderr, code := runCLI(t, append(args, "--format", "png")...); code == 0 || !strings.Contains(stderr, "can't be printed") {
+		t.Errorf("PNG with an Arabic --mosque: exit %d, stderr %q; want it rejected", code, stderr)
+	}
+	out, stderr, code := runCLI(t, append(args, "--format", "svg")...)
+	if code != 0 {
+		t.Fatalf("SVG with an Arabic --mosque exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, ">مسجد النور</text>") {
+		t.Error("SVG is missing the Arabic mosque name")
+	}
+}
+
+func TestExportImageSVG(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"export", "image", "--from", "2026-03-19", "--to", "2026-03-21", "--format", "svg", "--mosque", "Al-Masjid & Co"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("export image --format svg exited with %d: %s", code, stderr)
+	}
+	if err := xml.Unmarshal([]byte(out), new(struct{})); err != nil {
+		t.Fatalf("export image wrote invalid SVG: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		"<title>Prayer Times — 19 Mar – 21 Mar 2026</title>",
+		">Al-Masjid &amp; Co</text>",
+		">Ramaḍān – Shawwāl 1447 AH</text>",
+		">30 Ramaḍān</text>", ">1 Shawwāl</text>",
+		">Fajr</text>", ">05:30</text>",
+		`fill="#ededed"`, // Friday 20 March
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestImageFormat(t *testing.T) {
+	tests := []struct {
+		format, out string
+		want        string
+		wantErr     string
+	}{
+		{"", "", "png", ""},
+		{"", "-", "png", ""},
+		{"", "march.PNG", "png", ""},
+		{"", "march.svg", "svg", ""},
+		{"svg", "", "svg", ""},
+		{"png", "march.img", "png", ""},
+		{"", "march.jpg", "", "cannot tell the image format of march.jpg"},
+		{"gif", "", "", `invalid --format "gif"`},
+	}
+	for _, tt := range tests {
+		flagExportFormat, flagExportOut = tt.format, tt.out
+		got, err := imageFormat()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("imageFormat(%q, %q) error = %v, want %q", tt.format, tt.out, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("imageFormat(%q, %q) = %q, %v; want %q", tt.format, tt.out, got, err, tt.want)
+		}
+	}
+	flagExportFormat, flagExportOut = "", ""
+}
+
+func TestExportImageStdout(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"export", "image", "--from", "2026-03-01", "--to", "2026-03-02"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("export image exited with %d: %s", code, stderr)
+	}
+	if _, err := png.Decode(bytes.NewReader([]byte(out))); err != nil {
+		t.Errorf("export image to stdout is not a PNG: %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/qibla"
+	"github.com/smokyabdulrahman/prayer-times/internal/route"
+	"github.com/smokyabdulrahman/prayer-times/internal/tzlookup"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// maxFlightSamples bounds the number of lookups along one route.
+	maxFlightSamples = 200
+	// maxFlightJump is the largest change in time-to-prayer between two
+	// samples that still counts as the prayer passing; anything bigger is
+	// the local date changing under the aircraft.
+	maxFlightJump = 12 * time.Hour
+)
+
+var (
+	flagInflightFrom   string
+	flagInflightTo     string
+	flagInflightDepart string
+	flagInflightArrive string
+	flagInflightStep   time.Duration
+)
+
+func newInflightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inflight",
+		Short: "Estimate prayer times during a flight (experimental)",
+		Long: `Estimate when each prayer falls while airborne. The route is taken as the
+great circle between --from and --to, flown at constant speed from --depart to
+--arrive; prayer times are looked up at points every --step along it and the
+moment each one is passed is interpolated.
+
+Departure and arrival accept RFC 3339 timestamps, or "YYYY-MM-DD HH:MM" in the
+local time of the origin and destination respectively.
+
+This is experimental: real routes, speeds and altitude all shift the actual
+times, so treat the result as an estimate.`,
+		Example: `  prayer-times inflight --from 51.47,-0.45 --to 25.25,55.36 --depart "2026-03-01 21:30" --arrive "2026-03-02 07:40"
+  prayer-times inflight --from 35.55,139.78 --to 37.62,-122.38 --depart 2026-03-01T08:00:00Z --arrive 2026-03-01T17:30:00Z --json`,
+		Args: cobra.NoArgs,
+		RunE: runInflight,
+	}
+
+	cmd.Flags().StringVar(&flagInflightFrom, "from", "", "Origin as \"lat,lon\"")
+	cmd.Flags().StringVar(&flagInflightTo, "to", "", "Destination as \"lat,lon\"")
+	cmd.Flags().StringVar(&flagInflightDepart, "depart", "", "Departure time (RFC 3339, or \"YYYY-MM-DD HH:MM\" at the origin)")
+	cmd.Flags().StringVar(&flagInflightArrive, "arrive", "", "Arrival time (RFC 3339, or \"YYYY-MM-DD HH:MM\" at the destination)")
+	cmd.Flags().DurationVar(&flagInflightStep, "step", 15*time.Minute, "Interval between lookups along the route")
+
+	return cmd
+}
+
+// parsePoint parses a "lat,lon" pair.
+func parsePoint(s string) (route.Point, error) {
+	latStr, lonStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return route.Point{}, fmt.Errorf("%q is not a \"lat,lon\" pair", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return route.Point{}, fmt.Errorf("%q: latitude must be between -90 and 90", s)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return route.Point{}, fmt.Errorf("%q: longitude must be between -180 and 180", s)
+	}
+	return route.Point{Lat: lat, Lon: lon}, nil
+}
+
+// pointZone returns the timezone at p, falling back to its nautical zone.
+func pointZone(p route.Point) *time.Location {
+	zone, _ := tzlookup.Lookup(p.Lat, p.Lon)
+	tz, err := time.LoadLocation(zone)
+	if err != nil {
+		tz, _ = time.LoadLocation(tzlookup.Nautical(p.Lon))
+	}
+	if tz == nil {
+		return time.UTC
+	}
+	return tz
+}
+
+// parseFlightTime parses an RFC 3339 timestamp, or a local "YYYY-MM-DD HH:MM"
+// time in tz.
+func parseFlightTime(s string, tz *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, tz); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC 3339 or \"YYYY-MM-DD HH:MM\" time", s)
+}
+
+// flightSample is the prayer schedule seen from one point along the route.
+type flightSample struct {
+	At      time.Time
+	Pos     route.Point
+	Prayers []prayer.Prayer
+}
+
+// flightPrayer is a prayer passed in the air.
+type flightPrayer struct {
+	Name string
+	Time time.Time
+	Pos  route.Point
+}
+
+// flightPrayers finds the prayers passed between consecutive samples: a
+// prayer still ahead at one sample and behind at the next was passed in
+// between, at the time found by linear interpolation.
+func flightPrayers(samples []flightSample) []flightPrayer {
+	var out []flightPrayer
+	for i := 0; i+1 < len(samples); i++ {
+		a, b := samples[i], samples[i+1]
+		for _, pa := range a.Prayers {
+			pb := findPrayer(b.Prayers, pa.Name)
+			if pb == nil {
+				continue
+			}
+			ahead, behind := pa.Time.Sub(a.At), pb.Time.Sub(b.At)
+			if ahead < 0 || behind >= 0 || ahead-behind > maxFlightJump {
+				continue
+			}
+			frac := float64(ahead) / float64(ahead-behind)
+			out = append(out, flightPrayer{
+				Name: pa.Name,
+				Time: a.At.Add(time.Duration(frac * float64(b.At.Sub(a.At)))).Round(time.Minute),
+				Pos:  route.Intermediate(a.Pos, b.Pos, frac),
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
+
+// findPrayer returns the prayer called name in prayers, or nil.
+func findPrayer(prayers []prayer.Prayer, name string) *prayer.Prayer {
+	for i := range prayers {
+		if prayers[i].Name == name {
+			return &prayers[i]
+		}
+	}
+	return nil
+}
+
+// fetchFlightSample looks up the prayer schedule at pos for the local date at t.
+func fetchFlightSample(ctx context.Context, t time.Time, pos route.Point, method, school int, selected []string, c *cache.Cache) (flightSample, error) {
+	loc := resolvedLocation{Mode: locationCoords, Lat: pos.Lat, Lon: pos.Lon}
+	local := t.In(pointZone(pos))
+
+	result, err := fetchTimings(ctx, local, loc, method, school, c)
+	if err != nil {
+		return flightSample{}, err
+	}
+	tz := timezoneOverride
+	if tz == "" {
+		tz = loc.timezoneFor(result.Meta)
+	}
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return flightSample{}, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	// The API may place the point in a different zone than our lookup did;
+	// fetch its own date if so.
+	if day := t.In(tzLoc); day.Format("2006-01-02") != local.Format("2006-01-02") {
+		local = day
+		if result, err = fetchTimings(ctx, local, loc, method, school, c); err != nil {
+			return flightSample{}, err
+		}
+	}
+
+	prayers, err := prayer.ParseTimings(result.Timings, calendarDay(t.In(tzLoc), tzLoc), tzLoc, selected)
+	if err != nil {
+		return flightSample{}, err
+	}
+	return flightSample{At: t, Pos: pos, Prayers: prayers}, nil
+}
+
+// inflightJSONPrayer is one prayer of the inflight command's JSON output.
+type inflightJSONPrayer struct {
+	Prayer    string  `json:"prayer"`
+	Time      string  `json:"time"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Qibla     float64 `json:"qibla"`
+}
+
+// inflightJSONOutput is the JSON output structure for the inflight command.
+type inflightJSONOutput struct {
+	Depart     string               `json:"depart"`
+	Arrive     string               `json:"arrive"`
+	DistanceKm float64              `json:"distance_km"`
+	Prayers    []inflightJSONPrayer `json:"prayers"`
+}
+
+func runInflight(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	if flagInflightFrom == "" || flagInflightTo == "" || flagInflightDepart == "" || flagInflightArrive == "" {
+		return fmt.Errorf("--from, --to, --depart and --arrive are all required")
+	}
+	from, err := parsePoint(flagInflightFrom)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to, err := parsePoint(flagInflightTo)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	fromTZ, toTZ := pointZone(from), pointZone(to)
+
+	depart, err := parseFlightTime(flagInflightDepart, fromTZ)
+	if err != nil {
+		return fmt.Errorf("invalid --depart: %w", err)
+	}
+	arrive, err := parseFlightTime(flagInflightArrive, toTZ)
+	if err != nil {
+		return fmt.Errorf("invalid --arrive: %w", err)
+	}
+	duration := arrive.Sub(depart)
+	if duration <= 0 {
+		return fmt.Errorf("--arrive must be after --depart")
+	}
+	if flagInflightStep < time.Minute {
+		return fmt.Errorf("--step must be at least 1m")
+	}
+	n := int(math.Ceil(float64(duration) / float64(flagInflightStep)))
+	if n > maxFlightSamples {
+		return fmt.Errorf("flight of %s needs %d lookups at --step %s (max %d); use a longer --step",
+			prayer.FormatRemaining(duration), n, flagInflightStep, maxFlightSamples)
+	}
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+	c := openCache(cfg)
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	samples := make([]flightSample, 0, n+1)
+	for i := 0; i <= n; i++ {
+		f := float64(i) / float64(n)
+		at := depart.Add(time.Duration(f * float64(duration)))
+		s, err := fetchFlightSample(ctx, at, route.Intermediate(from, to, f), method, school, selectedPrayers, c)
+		if err != nil {
+			return err
+		}
+		samples = append(samples, s)
+	}
+	passed := flightPrayers(samples)
+
+	if FlagJSON {
+		out := inflightJSONOutput{
+			Depart:     depart.UTC().Format(time.RFC3339),
+			Arrive:     arrive.UTC().Format(time.RFC3339),
+			DistanceKm: math.Round(route.Distance(from, to)),
+			Prayers:    []inflightJSONPrayer{},
+		}
+		for _, p := range passed {
+			out.Prayers = append(out.Prayers, inflightJSONPrayer{
+				Prayer:    strings.ToLower(p.Name),
+				Time:      p.Time.UTC().Format(time.RFC3339),
+				Latitude:  math.Round(p.Pos.Lat*100) / 100,
+				Longitude: math.Round(p.Pos.Lon*100) / 100,
+				Qibla:     math.Round(qibla.Direction(p.Pos.Lat, p.Pos.Lon)*10) / 10,
+			})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	printInflightText(w, passed, depart.In(fromTZ), arrive.In(toTZ), route.Distance(from, to), goTimeFmt)
+	return nil
+}
+
+// printInflightText renders the estimated in-flight prayers for the terminal,
+// with times at the origin and destination.
+func printInflightText(w io.Writer, passed []flightPrayer, depart, arrive time.Time, distanceKm float64, goTimeFmt string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("In-Flight Prayer Times")+" "+display.Dim("(experimental)"))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  Depart  %s %s\n", depart.Format("Mon 02 Jan "+goTimeFmt), display.Dim(depart.Location().String()))
+	fmt.Fprintf(w, "  Arrive  %s %s\n", arrive.Format("Mon 02 Jan "+goTimeFmt), display.Dim(arrive.Location().String()))
+	fmt.Fprintf(w, "  %s\n", display.Dim(fmt.Sprintf("%.0f km, %s", distanceKm, prayer.FormatRemaining(arrive.Sub(depart)))))
+	fmt.Fprintln(w)
+
+	if len(passed) == 0 {
+		fmt.Fprintf(w, "  %s\n\n", display.Dim("No prayers fall during the flight."))
+		return
+	}
+
+	tbl := display.NewTable([]string{"Prayer", "Origin", "Destination", "Position", "Qibla"})
+	for _, p := range passed {
+		bearing := qibla.Direction(p.Pos.Lat, p.Pos.Lon)
+		tbl.AddRow([]string{
+			p.Name,
+			p.Time.In(depart.Location()).Format(goTimeFmt),
+			p.Time.In(arrive.Location()).Format(goTimeFmt),
+			fmt.Sprintf("%.2f, %.2f", p.Pos.Lat, p.Pos.Lon),
+			fmt.Sprintf("%.0f° %s", bearing, qibla.Compass(bearing)),
+		})
+	}
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+}
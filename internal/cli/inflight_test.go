@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/route"
+)
+
+func TestParsePoint(t *testing.T) {
+	p, err := parsePoint("51.47, -0.45")
+	if err != nil || p.Lat != 51.47 || p.Lon != -0.45 {
+		t.Errorf("parsePoint() = %+v, %v; want 51.47, -0.45", p, err)
+	}
+	for _, s := range []string{"", "51.47", "abc,1", "91,0", "0,181"} {
+		if _, err := parsePoint(s); err == nil {
+			t.Errorf("parsePoint(%q) expected error", s)
+		}
+	}
+}
+
+func TestParseFlightTime(t *testing.T) {
+	dubai, _ := time.LoadLocation("Asia/Dubai")
+	want := time.Date(2026, 3, 2, 3, 40, 0, 0, time.UTC)
+
+	for _, s := range []string{"2026-03-02T03:40:00Z", "2026-03-02T07:40:00+04:00", "2026-03-02 07:40", "2026-03-02T07:40"} {
+		got, err := parseFlightTime(s, dubai)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseFlightTime(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := parseFlightTime("tomorrow", dubai); err == nil {
+		t.Error("parseFlightTime(tomorrow) expected error")
+	}
+}
+
+func TestFlightPrayers(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return base.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	a, b := route.Point{Lat: 40, Lon: 10}, route.Point{Lat: 40, Lon: 0}
+
+	samples := []flightSample{
+		// Flying west, Maghrib moves later: passed halfway between samples.
+		{At: at(0, 0), Pos: a, Prayers: []prayer.Prayer{{Name: "Maghrib", Time: at(0, 20)}, {Name: "Isha", Time: at(1, 50)}}},
+		{At: at(0, 40), Pos: b, Prayers: []prayer.Prayer{{Name: "Maghrib", Time: at(0, 30)}, {Name: "Isha", Time: at(2, 0)}}},
+		// The local date changes: Maghrib jumps a day ahead and must not count.
+		{At: at(1, 20), Pos: b, Prayers: []prayer.Prayer{{Name: "Maghrib", Time: at(24, 30)}, {Name: "Isha", Time: at(-22, 0)}}},
+	}
+	got := flightPrayers(samples)
+	if len(got) != 1 {
+		t.Fatalf("flightPrayers() = %+v, want only Maghrib", got)
+	}
+	if got[0].Name != "Maghrib" || !got[0].Time.Equal(at(0, 27)) {
+		t.Errorf("flightPrayers()[0] = %s at %v, want Maghrib at %v", got[0].Name, got[0].Time, at(0, 27))
+	}
+	if got[0].Pos.Lon >= a.Lon || got[0].Pos.Lon <= b.Lon {
+		t.Errorf("flightPrayers()[0] position = %+v, want between the samples", got[0].Pos)
+	}
+}
+
+func TestInflightJSON(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, "inflight", "--from", "24.71,46.68", "--to", "21.42,39.83",
+		"--depart", "2026-03-01 11:00", "--arrive", "2026-03-01T13:00:00Z", "--step", "30m",
+		"--json", "--cache-dir", t.TempDir())
+	if code != 0 {
+		t.Fatalf("inflight --json exited with %d: %s", code, stderr)
+	}
+
+	var got inflightJSONOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Depart != "2026-03-01T08:00:00Z" {
+		t.Errorf("depart = %q, want the origin's local time as UTC", got.Depart)
+	}
+	// The mock has Dhuhr at 12:30 and Asr at 15:45 Riyadh time everywhere.
+	if len(got.Prayers) != 2 {
+		t.Fatalf("prayers = %+v, want Dhuhr and Asr", got.Prayers)
+	}
+	if got.Prayers[0].Prayer != "dhuhr" || got.Prayers[0].Time != "2026-03-01T09:30:00Z" {
+		t.Errorf("prayers[0] = %+v, want dhuhr at 09:30Z", got.Prayers[0])
+	}
+	if got.Prayers[1].Prayer != "asr" || got.Prayers[1].Time != "2026-03-01T12:45:00Z" {
+		t.Errorf("prayers[1] = %+v, want asr at 12:45Z", got.Prayers[1])
+	}
+}
+
+func TestInflightErrors(t *testing.T) {
+	isolateConfig(t)
+
+	for _, args := range [][]string{
+		{"inflight", "--from", "24.71,46.68", "--to", "21.42,39.83"},
+		{"inflight", "--from", "24.71,46.68", "--to", "21.42,39.83", "--depart", "2026-03-01 11:00", "--arrive", "2026-03-01 10:00"},
+		{"inflight", "--from", "24.71,46.68", "--to", "21.42,39.83", "--depart", "2026-03-01 11:00", "--arrive", "2026-03-05 10:00", "--step", "5m"},
+	} {
+		if _, _, code := runCLI(t, args...); code == 0 {
+			t.Errorf("%v: expected non-zero exit", args)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/khatmah"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagKhatmahDays int
+	flagKhatmahFrom string
+)
+
+func newKhatmahCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "khatmah",
+		Short: "Plan and track a complete Quran reading split across prayers",
+		Long: `Plan a khatmah (complete reading of the Quran) over a number of days.
+
+The 604 pages of the Madinah Mushaf are divided evenly into portions read
+after each of the five daily prayers. While a plan is active, today's
+portions are also shown beneath the default schedule.`,
+		Example: `  prayer-times khatmah start --days 30
+  prayer-times khatmah               # today's portions and progress
+  prayer-times khatmah done          # mark the next portion as read
+  prayer-times khatmah read 120      # record reading up to page 120
+  prayer-times khatmah stop`,
+		Args: cobra.NoArgs,
+		RunE: runKhatmahShow,
+	}
+
+	start := &cobra.Command{
+		Use:   "start",
+		Short: "Start a new khatmah plan (replaces any existing plan)",
+		Args:  cobra.NoArgs,
+		RunE:  runKhatmahStart,
+	}
+	start.Flags().IntVar(&flagKhatmahDays, "days", 30, "Number of days to complete the khatmah in")
+	start.Flags().StringVar(&flagKhatmahFrom, "from", "", "First day of the plan (YYYY-MM-DD, default: today)")
+	cmd.AddCommand(start)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "done",
+		Short: "Mark the next unread portion as read",
+		Args:  cobra.NoArgs,
+		RunE:  runKhatmahDone,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "read <page>",
+		Short: "Record reading progress up to a page",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runKhatmahRead,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stop",
+		Short: "Delete the current khatmah plan",
+		Args:  cobra.NoArgs,
+		RunE:  runKhatmahStop,
+	})
+
+	return cmd
+}
+
+// loadKhatmah returns the plan file path and the active plan, if any.
+func loadKhatmah() (string, *khatmah.Plan, error) {
+	path, err := khatmah.DefaultPath()
+	if err != nil {
+		return "", nil, err
+	}
+	plan, err := khatmah.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, plan, nil
+}
+
+// requireKhatmah is loadKhatmah for commands that need an active plan.
+func requireKhatmah() (string, *khatmah.Plan, error) {
+	path, plan, err := loadKhatmah()
+	if err != nil {
+		return "", nil, err
+	}
+	if plan == nil {
+		return "", nil, fmt.Errorf("no khatmah plan; start one with: prayer-times khatmah start --days 30")
+	}
+	return path, plan, nil
+}
+
+func runKhatmahStart(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	start := time.Now()
+	if flagKhatmahFrom != "" {
+		d, err := parseDateFlag(flagKhatmahFrom, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+		start = d
+	}
+
+	plan, err := khatmah.NewPlan(start, flagKhatmahDays, nil)
+	if err != nil {
+		return err
+	}
+
+	path, err := khatmah.DefaultPath()
+	if err != nil {
+		return err
+	}
+	if err := plan.Save(path); err != nil {
+		return err
+	}
+
+	perDay := float64(khatmah.TotalPages) / float64(plan.Days)
+	fmt.Fprintf(w, "Khatmah started: %d days from %s (about %.1f pages a day)\n", plan.Days, plan.Start, perDay)
+	return nil
+}
+
+// khatmahJSON is the JSON structure for the khatmah status.
+type khatmahJSON struct {
+	Start     string            `json:"start"`
+	Days      int               `json:"days"`
+	PagesRead int               `json:"pages_read"`
+	Expected  int               `json:"expected"`
+	Today     []khatmah.Portion `json:"today"`
+}
+
+func runKhatmahShow(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	_, plan, err := requireKhatmah()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	today := plan.PortionsFor(now)
+
+	if FlagJSON {
+		out := khatmahJSON{
+			Start:     plan.Start,
+			Days:      plan.Days,
+			PagesRead: plan.PagesRead,
+			Expected:  plan.Expected(now),
+			Today:     today,
+		}
+		if out.Today == nil {
+			out.Today = []khatmah.Portion{}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Khatmah"))
+	fmt.Fprintln(w)
+
+	pct := plan.PagesRead * 100 / khatmah.TotalPages
+	fmt.Fprintf(w, "  %d / %d pages (%d%%)\n", plan.PagesRead, khatmah.TotalPages, pct)
+	if day := plan.Day(now); day >= 0 {
+		fmt.Fprintf(w, "  Day %d of %d\n", day+1, plan.Days)
+	}
+	switch diff := plan.PagesRead - plan.Expected(now); {
+	case plan.Complete():
+		fmt.Fprintf(w, "  %s\n", display.Green("Completed, alhamdulillah"))
+	case diff < 0:
+		fmt.Fprintf(w, "  %s\n", display.Yellow(fmt.Sprintf("%d pages behind schedule", -diff)))
+	case diff > 0:
+		fmt.Fprintf(w, "  %s\n", display.Green(fmt.Sprintf("%d pages ahead of schedule", diff)))
+	default:
+		fmt.Fprintf(w, "  %s\n", display.Green("On schedule"))
+	}
+	fmt.Fprintln(w)
+
+	if len(today) > 0 {
+		printKhatmahPortions(w, today, plan.PagesRead)
+	}
+	return nil
+}
+
+// printKhatmahPortions lists portions with a check mark on those already read.
+func printKhatmahPortions(w io.Writer, portions []khatmah.Portion, pagesRead int) {
+	fmt.Fprintf(w, "  %s\n", display.Bold("Quran today"))
+	maxNameLen := 0
+	for _, p := range portions {
+		if len(p.Prayer) > maxNameLen {
+			maxNameLen = len(p.Prayer)
+		}
+	}
+	for _, p := range portions {
+		line := fmt.Sprintf("  %-*s  pages %d-%d", maxNameLen, p.Prayer, p.From, p.To)
+		if p.To <= pagesRead {
+			fmt.Fprintln(w, display.Dim(line+"  ✓"))
+		} else {
+			fmt.Fprintln(w, line)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func runKhatmahDone(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	path, plan, err := requireKhatmah()
+	if err != nil {
+		return err
+	}
+
+	portion, ok := plan.NextPortion()
+	if !ok {
+		fmt.Fprintln(w, "Khatmah already complete.")
+		return nil
+	}
+	if err := plan.MarkRead(portion.To); err != nil {
+		return err
+	}
+	if err := plan.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Marked pages %d-%d (%s) as read. %d / %d pages.\n",
+		portion.From, portion.To, portion.Prayer, plan.PagesRead, khatmah.TotalPages)
+	return nil
+}
+
+func runKhatmahRead(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	page, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid page %q: must be a number", args[0])
+	}
+
+	path, plan, err := requireKhatmah()
+	if err != nil {
+		return err
+	}
+	if err := plan.MarkRead(page); err != nil {
+		return err
+	}
+	if err := plan.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Progress saved: %d / %d pages.\n", plan.PagesRead, khatmah.TotalPages)
+	return nil
+}
+
+func runKhatmahStop(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	path, err := khatmah.DefaultPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no khatmah plan to stop")
+		}
+		return fmt.Errorf("failed to remove khatmah plan: %w", err)
+	}
+	fmt.Fprintln(w, "Khatmah plan removed.")
+	return nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+var (
+	flagKids          bool
+	flagTransliterate bool
+)
+
+// prayerGuide holds the kid-friendly wording and pronunciation for a prayer.
+type prayerGuide struct {
+	Emoji  string
+	Simple string // plain-language description
+	Arabic string
+	Sounds string // pronunciation guide
+}
+
+var prayerGuides = map[string]prayerGuide{
+	"Fajr":       {"🌅", "Dawn prayer", "الفجر", "FAJ-r"},
+	"Sunrise":    {"☀️", "The sun comes up", "الشروق", "shu-ROOQ"},
+	"Dhuhr":      {"🌞", "Midday prayer", "الظهر", "DHUH-r"},
+	"Asr":        {"🌤️", "Afternoon prayer", "العصر", "AH-sr"},
+	"Sunset":     {"🌇", "The sun goes down", "الغروب", "ghu-ROOB"},
+	"Maghrib":    {"🌆", "Sunset prayer", "المغرب", "MAGH-rib"},
+	"Isha":       {"🌙", "Night prayer", "العشاء", "i-SHAA"},
+	"Imsak":      {"🥛", "Time to stop eating", "الإمساك", "im-SAAK"},
+	"Midnight":   {"🌌", "Middle of the night", "منتصف الليل", "mun-TA-saf al-LAIL"},
+	"Firstthird": {"✨", "First part of the night", "الثلث الأول", "ath-THU-luth al-AW-wal"},
+	"Lastthird":  {"⭐", "Last part of the night", "الثلث الأخير", "ath-THU-luth al-a-KHEER"},
+}
+
+// transliteration returns "Arabic · pronunciation" for name, or "" if unknown.
+func transliteration(name string) string {
+	g, ok := prayerGuides[name]
+	if !ok {
+		return ""
+	}
+	return g.Arabic + " · " + g.Sounds
+}
+
+// printTodayKids renders today's schedule with simple wording, emoji cues,
+// and extra spacing for children and kiosk displays.
+func printTodayKids(w io.Writer, prayers []prayer.Prayer, next *prayer.Prayer, now time.Time, locationStr, goTimeFmt string, transliterate bool) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("🕌  Today's Prayers"))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  📍 %s\n", locationStr)
+	fmt.Fprintf(w, "  📅 %s\n", now.Format("Monday, 2 January"))
+	fmt.Fprintln(w)
+
+	maxNameLen := 0
+	for _, p := range prayers {
+		if len(p.Name) > maxNameLen {
+			maxNameLen = len(p.Name)
+		}
+	}
+
+	for _, p := range prayers {
+		g := prayerGuides[p.Name]
+		line := fmt.Sprintf("  %s  %-*s  %s   %s", g.Emoji, maxNameLen, p.Name, p.Time.Format(goTimeFmt), g.Simple)
+
+		switch {
+		case next != nil && p.Name == next.Name:
+			fmt.Fprintln(w, display.Accent(line+"  ⏳"))
+		case p.Time.Before(now):
+			fmt.Fprintln(w, display.Dim(line+"  ✅"))
+		default:
+			fmt.Fprintln(w, line)
+		}
+		if transliterate && g.Arabic != "" {
+			fmt.Fprintf(w, "      %s\n", display.Dim(transliteration(p.Name)))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if next != nil {
+		remaining := prayer.TimeRemaining(*next, now)
+		fmt.Fprintf(w, "  👉 %s\n", display.Bold(fmt.Sprintf("%s is next! %s to go.", next.Name, kidsDuration(remaining))))
+		fmt.Fprintln(w)
+	}
+}
+
+// kidsDuration spells out a duration in words, e.g. "2 hours and 15 minutes".
+func kidsDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	switch {
+	case h == 0:
+		return plural(m, "minute")
+	case m == 0:
+		return plural(h, "hour")
+	}
+	return plural(h, "hour") + " and " + plural(m, "minute")
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+func TestKidsDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "less than a minute"},
+		{time.Minute, "1 minute"},
+		{45 * time.Minute, "45 minutes"},
+		{time.Hour, "1 hour"},
+		{2*time.Hour + 15*time.Minute, "2 hours and 15 minutes"},
+		{time.Hour + time.Minute, "1 hour and 1 minute"},
+	}
+	for _, tt := range tests {
+		if got := kidsDuration(tt.d); got != tt.want {
+			t.Errorf("kidsDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestPrayerGuides_CoverAllPrayers(t *testing.T) {
+	for _, name := range prayer.AllPrayerNames {
+		g, ok := prayerGuides[name]
+		if !ok {
+			t.Errorf("no guide for %s", name)
+			continue
+		}
+		if g.Emoji == "" || g.Simple == "" || g.Arabic == "" || g.Sounds == "" {
+			t.Errorf("guide for %s is incomplete: %+v", name, g)
+		}
+	}
+}
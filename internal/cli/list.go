@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,7 +13,9 @@ import (
 	"github.com/smokyabdulrahman/prayer-times/internal/api"
 	"github.com/smokyabdulrahman/prayer-times/internal/cache"
 	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
 	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/timing"
 	"github.com/spf13/cobra"
 )
 
@@ -23,41 +27,102 @@ type dayData struct {
 	Meta     api.Meta
 }
 
-// runList is the handler for the list subcommand.
-func runList(cmd *cobra.Command, args []string, defaultDays int) error {
+var (
+	flagListFrom string
+	flagListTo   string
+	flagListDays int
+
+	flagListOutput string
+)
+
+// listRange resolves the list arguments and --days/--from/--to into a start
+// date and day count. Without --from the range starts at now.
+func listRange(now time.Time, args []string, defaultDays int) (time.Time, int, error) {
 	days := defaultDays
+	if flagListDays > 0 {
+		days = flagListDays
+	}
 	if len(args) > 0 {
 		n, err := strconv.Atoi(args[0])
 		if err != nil || n < 1 {
-			return fmt.Errorf("invalid number of days: %q (must be a positive integer)", args[0])
+			return time.Time{}, 0, fmt.Errorf("invalid number of days: %q (must be a positive integer)", args[0])
 		}
 		days = n
 	}
 
-	cfg := effectiveConfig(cmd)
-
-	selectedPrayers := prayer.DefaultPrayerNames
-	if cfg.Prayers != "" {
-		selectedPrayers = strings.Split(cfg.Prayers, ",")
-		for i := range selectedPrayers {
-			selectedPrayers[i] = strings.TrimSpace(selectedPrayers[i])
+	if flagListFrom == "" {
+		if flagListTo != "" {
+			return time.Time{}, 0, fmt.Errorf("--to requires --from")
 		}
+		return now, days, nil
 	}
 
-	goTimeFmt := "15:04"
-	if cfg.TimeFormat == "12h" {
-		goTimeFmt = "3:04 PM"
+	from, err := parseDateFlag(flagListFrom, now.Location())
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid --from: %w", err)
 	}
-
-	c, err := cache.New(cfg.CacheDir)
+	if flagListTo == "" {
+		return from, days, nil
+	}
+	if len(args) > 0 {
+		return time.Time{}, 0, fmt.Errorf("a day count cannot be combined with --to")
+	}
+	to, err := parseDateFlag(flagListTo, now.Location())
 	if err != nil {
-		c = nil
-		fmt.Fprintf(os.Stderr, "warning: cache disabled: %v\n", err)
+		return time.Time{}, 0, fmt.Errorf("invalid --to: %w", err)
 	}
+	if to.Before(from) {
+		return time.Time{}, 0, fmt.Errorf("--to %s is before --from %s", flagListTo, flagListFrom)
+	}
+	days = daysBetween(from, to) + 1
+	if days > maxExportDays {
+		return time.Time{}, 0, fmt.Errorf("range of %d days is too large (max %d)", days, maxExportDays)
+	}
+	return from, days, nil
+}
 
-	now := time.Now()
+// listSpan is the run of days a list shows, with its heading.
+type listSpan struct {
+	Start time.Time
+	Days  int
+	Title string // e.g. "7 Days" or "March 2026"
+}
+
+// runList is the handler for the list subcommand.
+func runList(cmd *cobra.Command, args []string, defaultDays int) error {
+	return runListSpan(cmd, func(now time.Time) (listSpan, error) {
+		start, days, err := listRange(now, args, defaultDays)
+		return listSpan{Start: start, Days: days, Title: fmt.Sprintf("%d Days", days)}, err
+	})
+}
+
+// runListSpan prints the grid for the days chosen by span, which is given
+// the current time at the resolved location.
+func runListSpan(cmd *cobra.Command, span func(now time.Time) (listSpan, error)) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	if !slices.Contains(listOutputs, flagListOutput) {
+		return fmt.Errorf("invalid --output %q: must be one of %s", flagListOutput, strings.Join(listOutputs, ", "))
+	}
+
+	cfg := effectiveConfig(cmd)
 
-	loc, err := resolveLocation(cfg.Latitude, cfg.Longitude, cfg.City, cfg.Country, c)
+	selectedPrayers := selectedPrayerNames(cfg)
+
+	goTimeFmt := goTimeFormat(cfg)
+
+	c := openCache(cfg)
+
+	now := currentTime()
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	now = loc.localTime(now)
+
+	sp, err := span(now)
 	if err != nil {
 		return err
 	}
@@ -66,7 +131,7 @@ func runList(cmd *cobra.Command, args []string, defaultDays int) error {
 	school := cfg.SchoolOrDefault(-1)
 
 	// Fetch calendar data for the needed days.
-	daysList, err := fetchCalendarDays(now, days, loc, method, school, c)
+	daysList, err := fetchCalendarDays(ctx, sp.Start, sp.Days, loc, method, school, c)
 	if err != nil {
 		return err
 	}
@@ -74,7 +139,7 @@ func runList(cmd *cobra.Command, args []string, defaultDays int) error {
 	// Determine timezone from the first day's meta.
 	tz := loc.Timezone
 	if tz == "" && len(daysList) > 0 {
-		tz = daysList[0].Meta.Timezone
+		tz = loc.timezoneFor(daysList[0].Meta)
 	}
 	tzLoc, err := time.LoadLocation(tz)
 	if err != nil {
@@ -88,24 +153,24 @@ func runList(cmd *cobra.Command, args []string, defaultDays int) error {
 	locationStr := buildLocationStr(loc, &fetchResult{Meta: daysList[0].Meta})
 
 	if FlagJSON {
-		return printListJSON(daysList, selectedPrayers, locationStr, tz, goTimeFmt, tzLoc)
+		return printListJSON(w, daysList, selectedPrayers, locationStr, tz, goTimeFmt, tzLoc)
 	}
 
-	// Rich terminal output.
-	fmt.Println()
-	fmt.Printf("  %s\n", display.Bold(fmt.Sprintf("Prayer Times \u2014 %d Days", days)))
-	fmt.Println()
-	fmt.Printf("  %s\n", locationStr)
-	fmt.Println()
+	// Rich terminal output, in the display language.
+	lang := displayLang(cfg, loc)
+	title := i18n.Sprintf(lang, "ui.title") + " \u2014 " + sp.Title
 
 	// Build table.
-	headers := []string{"Date"}
-	headers = append(headers, selectedPrayers...)
+	headers := []string{i18n.Sprintf(lang, "ui.date")}
+	for _, name := range selectedPrayers {
+		headers = append(headers, prayerLabel(lang, name))
+	}
 	tbl := display.NewTable(headers)
+	tt := timetable{Title: title, Location: locationStr, Lang: lang, Headers: headers}
 
 	for i, dd := range daysList {
-		dateInTZ := dd.Date.In(tzLoc)
-		dateLabel := dateInTZ.Format("Mon 02 Jan")
+		dateInTZ := calendarDay(dd.Date, tzLoc)
+		dateLabel := listDateLabel(lang, dateInTZ)
 
 		parsed, err := prayer.ParseTimings(dd.Timings, dateInTZ, tzLoc, selectedPrayers)
 		if err != nil {
@@ -113,10 +178,17 @@ func runList(cmd *cobra.Command, args []string, defaultDays int) error {
 		}
 
 		row := []string{dateLabel}
+		plain := []string{dateLabel}
 		for _, p := range parsed {
-			row = append(row, p.Time.Format(goTimeFmt))
+			clock := i18n.Clock(lang, p.Time, goTimeFmt)
+			plain = append(plain, clock)
+			if p.Time.Before(now) {
+				clock = display.Dim(clock)
+			}
+			row = append(row, clock)
 		}
 		tbl.AddRow(row)
+		tt.Rows = append(tt.Rows, plain)
 
 		// Highlight today's row.
 		if dateInTZ.Format("2006-01-02") == todayStr {
@@ -124,15 +196,118 @@ func runList(cmd *cobra.Command, args []string, defaultDays int) error {
 		}
 	}
 
-	fmt.Print(tbl.Render())
-	fmt.Println()
+	if flagListOutput != "table" {
+		return writeTimetable(w, tt, flagListOutput)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(title))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", locationStr)
+	fmt.Fprintln(w)
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
 	return nil
 }
 
+// addListOutputFlag registers --output on a command that prints a list.
+func addListOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagListOutput, "output", "table", "Output format: table, markdown, or html (a standalone printable page)")
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(listOutputs, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// listDateLabel labels a day's row, e.g. "Mon 02 Jan"; languages other than
+// English get their own weekday and a numeric date, e.g. "Pzt 02/01", since
+// month names aren't translated.
+func listDateLabel(lang string, date time.Time) string {
+	if lang == i18n.Default {
+		return date.Format("Mon 02 Jan")
+	}
+	return i18n.Isolate(lang, i18n.ShortWeekday(lang, date.Weekday())+" "+i18n.Digits(lang, date.Format("02/01")))
+}
+
+// annualFetchThreshold is the number of uncached months in one year at which
+// fetchCalendarDays switches from monthly requests to a single annual one.
+const annualFetchThreshold = 3
+
+// fetchAnnualCalendar fetches a whole year of timings for loc.
+func fetchAnnualCalendar(ctx context.Context, client *api.Client, year int, loc resolvedLocation, method, school int) (*api.AnnualCalendarResponse, error) {
+	if loc.Mode == locationCity {
+		return client.FetchAnnualCalendarByCity(ctx, year, loc.City, loc.Country, method, school)
+	}
+	return client.FetchAnnualCalendarByCoordinates(ctx, year, loc.Lat, loc.Lon, method, school)
+}
+
+// fetchMonthDays fetches a month of timings from the API, caching and
+// archiving them. Another process may be fetching the same month, so it
+// holds the month's cache lock and checks the cache again once it's ours.
+func fetchMonthDays(ctx context.Context, client *api.Client, year, month int, loc resolvedLocation, method, school int, c *cache.Cache) ([]api.Data, error) {
+	if c != nil {
+		if unlock, err := c.LockCalendar(year, month, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); err == nil {
+			defer unlock()
+			if entry := c.LoadCalendar(year, month, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
+				return entry.Days, nil
+			}
+		}
+	}
+
+	var resp *api.CalendarResponse
+	var err error
+
+	switch loc.Mode {
+	case locationCity:
+		resp, err = client.FetchCalendarByCity(ctx, year, month, loc.City, loc.Country, method, school)
+	default:
+		resp, err = client.FetchCalendarByCoordinates(ctx, year, month, loc.Lat, loc.Lon, method, school)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar for %d-%02d: %w", year, month, err)
+	}
+	recordFetch()
+
+	// Cache (best-effort).
+	if c != nil {
+		_ = c.SaveCalendar(year, month, loc.Lat, loc.Lon, loc.City, loc.Country, method, school, resp)
+	}
+	archiveCalendar(client, loc, method, school, resp.Data)
+	return resp.Data, nil
+}
+
+// fetchAnnualDays is fetchMonthDays for a whole year, returning its days by
+// month (1-12). Only the given months are archived.
+func fetchAnnualDays(ctx context.Context, client *api.Client, year int, months []int, loc resolvedLocation, method, school int, c *cache.Cache) (map[int][]api.Data, error) {
+	if c != nil {
+		if unlock, err := c.LockAnnual(year, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); err == nil {
+			defer unlock()
+			if entry := c.LoadAnnual(year, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
+				return entry.Months, nil
+			}
+		}
+	}
+
+	resp, err := fetchAnnualCalendar(ctx, client, year, loc, method, school)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar for %d: %w", year, err)
+	}
+	recordFetch()
+	if c != nil {
+		_ = c.SaveAnnual(year, loc.Lat, loc.Lon, loc.City, loc.Country, method, school, resp)
+	}
+	byMonth := make(map[int][]api.Data, 12)
+	for m := 1; m <= 12; m++ {
+		byMonth[m] = resp.Month(m)
+	}
+	for _, m := range months {
+		archiveCalendar(client, loc, method, school, byMonth[m])
+	}
+	return byMonth, nil
+}
+
 // fetchCalendarDays fetches prayer data for `days` consecutive days starting from `start`.
-// It uses the calendar endpoint for efficiency (fetches whole months) with caching.
-func fetchCalendarDays(start time.Time, days int, loc resolvedLocation, method, school int, c *cache.Cache) ([]dayData, error) {
-	client := api.NewClient()
+// It uses the calendar endpoint for efficiency (fetches whole months, or whole
+// years for long ranges) with caching.
+func fetchCalendarDays(ctx context.Context, start time.Time, days int, loc resolvedLocation, method, school int, c *cache.Cache) ([]dayData, error) {
+	client := newAPIClient()
 
 	// Determine which year/month combos we need.
 	type yearMonth struct {
@@ -144,38 +319,53 @@ func fetchCalendarDays(start time.Time, days int, loc resolvedLocation, method,
 		needed[yearMonth{d.Year(), int(d.Month())}] = true
 	}
 
-	// Fetch each needed month (from cache or API).
+	// Fetch each needed month, trying the monthly cache, then the annual
+	// cache. Whatever is left is fetched from the API below.
 	// monthData maps year/month -> slice of api.Data (one per day).
 	monthData := make(map[yearMonth][]api.Data)
+	annual := make(map[int]*cache.AnnualCacheEntry)
+	missing := make(map[int][]int) // year -> uncached months
 
+	stop := timer.Start(timing.Cache)
 	for ym := range needed {
-		// Try cache first.
 		if c != nil {
 			if entry := c.LoadCalendar(ym.year, ym.month, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
 				monthData[ym] = entry.Days
 				continue
 			}
+			entry, ok := annual[ym.year]
+			if !ok {
+				entry = c.LoadAnnual(ym.year, loc.Lat, loc.Lon, loc.City, loc.Country, method, school)
+				annual[ym.year] = entry
+			}
+			if entry != nil && entry.Months[ym.month] != nil {
+				monthData[ym] = entry.Months[ym.month]
+				continue
+			}
 		}
-
-		// Fetch from API.
-		var resp *api.CalendarResponse
-		var err error
-
-		switch loc.Mode {
-		case locationCity:
-			resp, err = client.FetchCalendarByCity(ym.year, ym.month, loc.City, loc.Country, method, school)
-		default:
-			resp, err = client.FetchCalendarByCoordinates(ym.year, ym.month, loc.Lat, loc.Lon, method, school)
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch calendar for %d-%02d: %w", ym.year, ym.month, err)
+		missing[ym.year] = append(missing[ym.year], ym.month)
+	}
+	stop()
+
+	for year, months := range missing {
+		// One annual request beats several monthly ones.
+		if len(months) >= annualFetchThreshold {
+			yearData, err := fetchAnnualDays(ctx, client, year, months, loc, method, school, c)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range months {
+				monthData[yearMonth{year, m}] = yearData[m]
+			}
+			continue
 		}
 
-		monthData[ym] = resp.Data
-
-		// Cache (best-effort).
-		if c != nil {
-			_ = c.SaveCalendar(ym.year, ym.month, loc.Lat, loc.Lon, loc.City, loc.Country, method, school, resp)
+		for _, m := range months {
+			data, err := fetchMonthDays(ctx, client, year, m, loc, method, school, c)
+			if err != nil {
+				return nil, err
+			}
+			monthData[yearMonth{year, m}] = data
 		}
 	}
 
@@ -195,7 +385,7 @@ func fetchCalendarDays(start time.Time, days int, loc resolvedLocation, method,
 		result = append(result, dayData{
 			Date:     d,
 			Timings:  apiData.Timings,
-			DateInfo: apiData.Date,
+			DateInfo: adjustHijri(apiData.Date),
 			Meta:     apiData.Meta,
 		})
 	}
@@ -203,6 +393,13 @@ func fetchCalendarDays(start time.Time, days int, loc resolvedLocation, method,
 	return result, nil
 }
 
+// calendarDay returns midnight of d's calendar date in loc.
+// Calendar data is indexed by the date it was fetched for, which may fall on a
+// different day than d.In(loc) when the location's timezone differs from d's.
+func calendarDay(d time.Time, loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
 // listJSONOutput is the JSON structure for the list command.
 type listJSONOutput struct {
 	Location todayJSONLocation `json:"location"`
@@ -215,7 +412,22 @@ type listJSONDay struct {
 	Timings map[string]string `json:"timings"`
 }
 
-func printListJSON(daysList []dayData, selectedPrayers []string, locationStr, tz, goTimeFmt string, tzLoc *time.Location) error {
+func printListJSON(w io.Writer, daysList []dayData, selectedPrayers []string, locationStr, tz, goTimeFmt string, tzLoc *time.Location) error {
+	out, err := buildListJSON(daysList, selectedPrayers, locationStr, tz, goTimeFmt, tzLoc)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// buildListJSON assembles the JSON structure shared by the list command and the HTTP server.
+func buildListJSON(daysList []dayData, selectedPrayers []string, locationStr, tz, goTimeFmt string, tzLoc *time.Location) (listJSONOutput, error) {
 	out := listJSONOutput{
 		Location: todayJSONLocation{
 			Timezone:  tz,
@@ -230,10 +442,10 @@ func printListJSON(daysList []dayData, selectedPrayers []string, locationStr, tz
 	}
 
 	for _, dd := range daysList {
-		dateInTZ := dd.Date.In(tzLoc)
+		dateInTZ := calendarDay(dd.Date, tzLoc)
 		parsed, err := prayer.ParseTimings(dd.Timings, dateInTZ, tzLoc, selectedPrayers)
 		if err != nil {
-			return err
+			return listJSONOutput{}, err
 		}
 
 		timings := make(map[string]string)
@@ -248,10 +460,5 @@ func printListJSON(daysList []dayData, selectedPrayers []string, locationStr, tz
 		})
 	}
 
-	data, err := json.MarshalIndent(out, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	fmt.Println(string(data))
-	return nil
+	return out, nil
 }
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+)
+
+func TestFetchCalendarDays_UsesAnnualCache(t *testing.T) {
+	c, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := resolvedLocation{Mode: locationCoords, Lat: 21.4225, Lon: 39.8262}
+	resp := &api.AnnualCalendarResponse{Code: 200, Status: "OK", Data: map[string][]api.Data{}}
+	for m := 1; m <= 12; m++ {
+		days := time.Date(2026, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		for d := 1; d <= days; d++ {
+			resp.Data[fmt.Sprint(m)] = append(resp.Data[fmt.Sprint(m)], api.Data{
+				Timings: api.Timings{Fajr: fmt.Sprintf("05:%02d", d)},
+				Meta:    api.Meta{Timezone: "Asia/Riyadh"},
+			})
+		}
+	}
+	if err := c.SaveAnnual(2026, loc.Lat, loc.Lon, "", "", -1, -1, resp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spans several months with no monthly cache entries. The synthetic
+	// Fajr times show the data came from the annual entry, not the API.
+	start := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	days, err := fetchCalendarDays(context.Background(), start, 100, loc, -1, -1, c)
+	if err != nil {
+		t.Fatalf("fetchCalendarDays() error: %v", err)
+	}
+	if len(days) != 100 {
+		t.Fatalf("got %d days, want 100", len(days))
+	}
+	if got := days[2].Timings.Fajr; got != "05:01" {
+		t.Errorf("1 April Fajr = %q, want %q", got, "05:01")
+	}
+}
+
+// TestFetchCalendarDays_SingleFlight verifies that concurrent fetches of the
+// same uncached month, each with its own handle on the cache directory as
+// separate processes would have, call the API once.
+func TestFetchCalendarDays_SingleFlight(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(100 * time.Millisecond) // let the others queue up
+		resp := api.CalendarResponse{Code: 200, Status: "OK"}
+		for d := 1; d <= 28; d++ {
+			resp.Data = append(resp.Data, api.Data{
+				Timings: api.Timings{Fajr: "05:30"},
+				Meta:    api.Meta{Timezone: "Asia/Riyadh"},
+			})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	prev := apiBaseURL
+	apiBaseURL = srv.URL
+	t.Cleanup(func() { apiBaseURL = prev })
+
+	dir := t.TempDir()
+	loc := resolvedLocation{Mode: locationCoords, Lat: 21.4225, Lon: 39.8262}
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := cache.New(dir)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := fetchCalendarDays(context.Background(), start, 28, loc, -1, -1, c); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("the API was called %d times, want 1", got)
+	}
+}
+
+func TestListRange(t *testing.T) {
+	now := time.Date(2026, 2, 20, 14, 0, 0, 0, time.UTC)
+	t.Cleanup(func() { flagListFrom, flagListTo = "", "" })
+
+	tests := []struct {
+		from, to  string
+		args      []string
+		wantStart string
+		wantDays  int
+	}{
+		{"", "", nil, "2026-02-20", 7},
+		{"", "", []string{"3"}, "2026-02-20", 3},
+		{"2026-03-01", "", nil, "2026-03-01", 7},
+		{"2026-03-01", "", []string{"10"}, "2026-03-01", 10},
+		{"2026-02-25", "2026-03-15", nil, "2026-02-25", 19},
+		{"2026-03-01", "2026-03-01", nil, "2026-03-01", 1},
+	}
+	for _, tt := range tests {
+		flagListFrom, flagListTo = tt.from, tt.to
+		start, days, err := listRange(now, tt.args, 7)
+		if err != nil {
+			t.Errorf("listRange(%q, %q, %v) error: %v", tt.from, tt.to, tt.args, err)
+			continue
+		}
+		if start.Format("2006-01-02") != tt.wantStart || days != tt.wantDays {
+			t.Errorf("listRange(%q, %q, %v) = %s, %d; want %s, %d", tt.from, tt.to, tt.args, start.Format("2006-01-02"), days, tt.wantStart, tt.wantDays)
+		}
+	}
+
+	for _, tt := range []struct {
+		from, to string
+		args     []string
+	}{
+		{"", "2026-03-01", nil},
+		{"2026-03-15", "2026-03-01", nil},
+		{"2026-03-01", "2026-03-15", []string{"5"}},
+		{"March", "", nil},
+		{"2026-01-01", "2027-06-01", nil},
+		{"", "", []string{"0"}},
+	} {
+		flagListFrom, flagListTo = tt.from, tt.to
+		if _, _, err := listRange(now, tt.args, 7); err == nil {
+			t.Errorf("listRange(%q, %q, %v) expected error", tt.from, tt.to, tt.args)
+		}
+	}
+}
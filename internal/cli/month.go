@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+func newMonthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "month [MONTH [YEAR]]",
+		Short: "Show prayer times for a month (default: the next 30 days)",
+		Long: `Display a grid of prayer times for a month. Without arguments this is an
+alias for 'list 30'.
+
+MONTH is a Gregorian month (number or name) or a Hijri month name; YEAR is in
+the same calendar and defaults to the current one.
+
+--output markdown prints a Markdown table to paste into a wiki or newsletter;
+--output html prints a standalone page that prints as a monthly timetable.`,
+		Example: `  prayer-times month
+  prayer-times month 3 2026
+  prayer-times month march
+  prayer-times month ramadan
+  prayer-times month dhul-hijjah 1447
+  prayer-times month ramadan --output html > ramadan.html`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: runMonth,
+	}
+	addListOutputFlag(cmd)
+	return cmd
+}
+
+// hijriMonthNames maps normalized Hijri month names, with common alternative
+// spellings, to month numbers.
+var hijriMonthNames = map[string]int{
+	"muharram":    1,
+	"safar":       2,
+	"rabialawwal": 3, "rabiulawwal": 3, "rabi1": 3,
+	"rabialthani": 4, "rabialakhir": 4, "rabiulakhir": 4, "rabiuthani": 4, "rabi2": 4,
+	"jumadaalula": 5, "jumadaalawwal": 5, "jumadaulula": 5, "jumada1": 5,
+	"jumadaalthaniyah": 6, "jumadaalthani": 6, "jumadaalakhirah": 6, "jumadaulakhirah": 6, "jumada2": 6,
+	"rajab":  7,
+	"shaban": 8, "shaaban": 8,
+	"ramadan": 9, "ramadhan": 9,
+	"shawwal":   10,
+	"dhulqadah": 11, "dhualqadah": 11, "dhulqidah": 11, "zulqadah": 11,
+	"dhulhijjah": 12, "dhualhijjah": 12, "zulhijjah": 12,
+}
+
+// monthArg is a parsed month command argument.
+type monthArg struct {
+	Month int
+	Hijri bool
+}
+
+// parseMonthArg parses a Gregorian month number or name ("3", "mar",
+// "March") or a Hijri month name ("ramadan", "Dhul-Hijjah").
+func parseMonthArg(s string) (monthArg, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 || n > 12 {
+			return monthArg{}, fmt.Errorf("invalid month %q: must be between 1 and 12", s)
+		}
+		return monthArg{Month: n}, nil
+	}
+
+	key := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+	if n, ok := hijriMonthNames[key]; ok {
+		return monthArg{Month: n, Hijri: true}, nil
+	}
+	if len(key) >= 3 {
+		for m := time.January; m <= time.December; m++ {
+			if strings.HasPrefix(strings.ToLower(m.String()), key) {
+				return monthArg{Month: int(m)}, nil
+			}
+		}
+	}
+	return monthArg{}, fmt.Errorf("unknown month %q: use 1-12, a month name or a Hijri month such as ramadan", s)
+}
+
+func runMonth(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return runList(cmd, nil, 30)
+	}
+
+	m, err := parseMonthArg(args[0])
+	if err != nil {
+		return err
+	}
+	year := 0
+	if len(args) > 1 {
+		if year, err = strconv.Atoi(args[1]); err != nil || year < 1 {
+			return fmt.Errorf("invalid year %q", args[1])
+		}
+	}
+
+	return runListSpan(cmd, func(now time.Time) (listSpan, error) {
+		if m.Hijri {
+			return hijriMonthSpan(cmd.Context(), m.Month, year, now)
+		}
+		if year == 0 {
+			year = now.Year()
+		}
+		start := time.Date(year, time.Month(m.Month), 1, 0, 0, 0, 0, now.Location())
+		return listSpan{
+			Start: start,
+			Days:  daysBetween(start, start.AddDate(0, 1, 0)),
+			Title: start.Format("January 2006"),
+		}, nil
+	})
+}
+
+// hijriMonthSpan looks up the Gregorian days of a Hijri month. A zero year
+// means the current Hijri year at now.
+func hijriMonthSpan(ctx context.Context, month, year int, now time.Time) (listSpan, error) {
+	if year == 0 {
+		info, _, err := convertToHijri(ctx, now)
+		if err != nil {
+			return listSpan{}, err
+		}
+		year, _ = strconv.Atoi(info.Hijri.Year)
+	}
+
+	days, _, err := fetchHijriMonth(ctx, month, year)
+	if err != nil {
+		return listSpan{}, err
+	}
+
+	first := days[0]
+	start, err := time.ParseInLocation("02-01-2006", first.Gregorian.Date, now.Location())
+	if err != nil {
+		return listSpan{}, fmt.Errorf("invalid Gregorian date %q in API response", first.Gregorian.Date)
+	}
+	return listSpan{
+		Start: start,
+		Days:  len(days),
+		Title: fmt.Sprintf("%s %d", first.Hijri.Month.En, year),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMonthArg(t *testing.T) {
+	tests := []struct {
+		in   string
+		want monthArg
+	}{
+		{"3", monthArg{Month: 3}},
+		{"12", monthArg{Month: 12}},
+		{"march", monthArg{Month: 3}},
+		{"Sep", monthArg{Month: 9}},
+		{"ramadan", monthArg{Month: 9, Hijri: true}},
+		{"Ramadhan", monthArg{Month: 9, Hijri: true}},
+		{"Dhul-Hijjah", monthArg{Month: 12, Hijri: true}},
+		{"rabi' al-awwal", monthArg{Month: 3, Hijri: true}},
+		{"muharram", monthArg{Month: 1, Hijri: true}},
+	}
+	for _, tt := range tests {
+		got, err := parseMonthArg(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseMonthArg(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, s := range []string{"0", "13", "ma", "smarch", "ramadam"} {
+		if _, err := parseMonthArg(s); err == nil {
+			t.Errorf("parseMonthArg(%q) expected error", s)
+		}
+	}
+}
+
+func TestMonthGregorian(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"month", "2", "2026", "--json"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("month 2 2026 exited with %d: %s", code, stderr)
+	}
+	var got listJSONOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got.Days) != 28 {
+		t.Fatalf("got %d days, want 28", len(got.Days))
+	}
+	if got.Days[0].Date != "01 Feb 2026" || got.Days[27].Date != "28 Feb 2026" {
+		t.Errorf("days run %s to %s, want 01 to 28 Feb 2026", got.Days[0].Date, got.Days[27].Date)
+	}
+}
+
+func TestMonthHijri(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"month", "ramadan", "1447", "--json"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("month ramadan 1447 exited with %d: %s", code, stderr)
+	}
+	var got listJSONOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got.Days) != 29 {
+		t.Fatalf("got %d days, want 29", len(got.Days))
+	}
+	if got.Days[0].Date != "18 Feb 2026" || got.Days[28].Date != "18 Mar 2026" {
+		t.Errorf("days run %s to %s, want 18 Feb to 18 Mar 2026", got.Days[0].Date, got.Days[28].Date)
+	}
+
+	if _, _, code := runCLI(t, append([]string{"month", "ramadan", "x"}, meccaArgs(t)...)...); code == 0 {
+		t.Error("month ramadan x: expected non-zero exit")
+	}
+
+	// With hijri_adjustment, the month starts a day later.
+	if _, stderr, code := runCLI(t, "config", "set", "--", "hijri_adjustment", "-1"); code != 0 {
+		t.Fatalf("config set hijri_adjustment: %s", stderr)
+	}
+	out, stderr, code = runCLI(t, append([]string{"month", "ramadan", "1447", "--json"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("month ramadan 1447 exited with %d: %s", code, stderr)
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Days[0].Date != "19 Feb 2026" {
+		t.Errorf("adjusted Ramadan starts %s, want 19 Feb 2026", got.Days[0].Date)
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+func newNamesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "names [spelling]",
+		Short: "List the accepted spellings of each prayer's name",
+		Long: `Print each prayer with the other spellings accepted wherever a prayer is
+named: 'query', --prayers, the prayers, chime_prayers and tune config keys, and
+serve's API. Case, accents, spaces, hyphens and apostrophes don't matter, so
+"zuhr", "Thohr" and "öğle" all mean Dhuhr.
+
+Given a spelling, print the prayer it means instead.`,
+		Example: `  prayer-times names
+  prayer-times names thohr`,
+		Args:              cobra.MaximumNArgs(1),
+		RunE:              runNames,
+		ValidArgsFunction: completePrayerNames,
+	}
+}
+
+// nameJSON is the JSON structure for one prayer's accepted spellings.
+type nameJSON struct {
+	Prayer  string   `json:"prayer"`
+	Aliases []string `json:"aliases"`
+}
+
+func runNames(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	if len(args) == 1 {
+		name, ok := prayer.Lookup(args[0])
+		if !ok {
+			return unknownPrayerError(args[0])
+		}
+		if FlagJSON {
+			return printNamesJSON(w, nameJSON{Prayer: name, Aliases: prayer.Aliases[name]})
+		}
+		fmt.Fprintln(w, name)
+		return nil
+	}
+
+	if FlagJSON {
+		out := make([]nameJSON, 0, len(prayer.AllPrayerNames))
+		for _, name := range prayer.AllPrayerNames {
+			out = append(out, nameJSON{Prayer: name, Aliases: prayer.Aliases[name]})
+		}
+		return printNamesJSON(w, out)
+	}
+
+	tbl := display.NewTable([]string{"Prayer", "Also accepted"})
+	for _, name := range prayer.AllPrayerNames {
+		tbl.AddRow([]string{name, strings.Join(prayer.Aliases[name], ", ")})
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
+
+func printNamesJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// unknownPrayerError reports a prayer name prayer.Lookup doesn't know.
+func unknownPrayerError(name string) error {
+	return fmt.Errorf("unknown prayer %q; valid names: %s (see 'prayer-times names' for other spellings)", name, strings.Join(prayer.AllPrayerNames, ", "))
+}
+
+// completePrayerNames completes a prayer's name: the API's names, and once
+// something has been typed, the other spellings that start with it.
+func completePrayerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return prayerCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePrayerList completes the last name of a comma-separated list of
+// prayers, as for --prayers.
+func completePrayerList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	head, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		head, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	var out []string
+	for _, name := range prayerCompletions(last) {
+		out = append(out, head+name)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// prayerCompletions returns the prayer names, and when prefix isn't empty
+// the aliases, that start with prefix, ignoring case.
+func prayerCompletions(prefix string) []string {
+	var out []string
+	for _, name := range prayer.AllPrayerNames {
+		if hasPrefixFold(name, prefix) {
+			out = append(out, name)
+		}
+		if prefix == "" {
+			continue
+		}
+		for _, alias := range prayer.Aliases[name] {
+			if hasPrefixFold(alias, prefix) {
+				out = append(out, alias)
+			}
+		}
+	}
+	return out
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	isolateConfig(t)
+
+	out, stderr, code := runCLI(t, "names")
+	if code != 0 {
+		t.Fatalf("names exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "Dhuhr") || !strings.Contains(out, "Zuhr, Zohr") {
+		t.Errorf("names = %q, want Dhuhr with its spellings", out)
+	}
+
+	out, _, code = runCLI(t, "names", "thohr")
+	if code != 0 || strings.TrimSpace(out) != "Dhuhr" {
+		t.Errorf("names thohr = %q (exit %d), want Dhuhr", out, code)
+	}
+
+	out, _, _ = runCLI(t, "names", "maghreb", "--json")
+	var got nameJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Prayer != "Maghrib" || len(got.Aliases) == 0 {
+		t.Errorf("names maghreb --json = %+v, want Maghrib and its aliases", got)
+	}
+
+	if _, stderr, code := runCLI(t, "names", "Tahajjud"); code == 0 || !strings.Contains(stderr, "unknown prayer") {
+		t.Errorf("names Tahajjud: exit %d, stderr %q; want an unknown prayer error", code, stderr)
+	}
+}
+
+func TestQueryAlias(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"query", "zuhr", "--json"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("query zuhr exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, `"12:30"`) {
+		t.Errorf("query zuhr = %q, want Dhuhr's 12:30", out)
+	}
+
+	out, stderr, code = runCLI(t, append([]string{"list", "1", "--prayers", "Subuh,Maghreb", "--json"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("list --prayers Subuh,Maghreb exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, `"fajr"`) || !strings.Contains(out, `"maghrib"`) {
+		t.Errorf("list --prayers Subuh,Maghreb = %q, want Fajr and Maghrib", out)
+	}
+}
+
+func TestCompletePrayerNames(t *testing.T) {
+	isolateConfig(t)
+
+	out, _, _ := runCLI(t, "__complete", "query", "Zu")
+	if !strings.Contains(out, "Zuhr\n") || !strings.Contains(out, "Zuhur\n") {
+		t.Errorf("completing query Zu = %q, want Zuhr and Zuhur", out)
+	}
+	out, _, _ = runCLI(t, "__complete", "query", "")
+	if !strings.Contains(out, "Dhuhr\n") || strings.Contains(out, "Zuhr\n") {
+		t.Errorf("completing query = %q, want only the API's names", out)
+	}
+	out, _, _ = runCLI(t, "__complete", "next", "--prayers", "Fajr,Ma")
+	if !strings.Contains(out, "Fajr,Maghrib\n") {
+		t.Errorf("completing --prayers Fajr,Ma = %q, want Fajr,Maghrib", out)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,8 +10,12 @@ import (
 
 	"github.com/smokyabdulrahman/prayer-times/internal/api"
 	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
 	"github.com/smokyabdulrahman/prayer-times/internal/geo"
+	"github.com/smokyabdulrahman/prayer-times/internal/hijri"
 	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/timing"
+	"github.com/smokyabdulrahman/prayer-times/internal/tzlookup"
 	"github.com/spf13/cobra"
 )
 
@@ -22,11 +27,17 @@ func newNextCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "next",
 		Short: "Show the next prayer with countdown",
-		Long:  "Display the next upcoming prayer time with a countdown.\nThis is equivalent to the old tmux-prayer-times default behavior.",
-		RunE:  runNext,
+		Long:  "Display the next upcoming prayer time with a countdown.\nThis is equivalent to the old tmux-prayer-times default behavior.\n\nWith --alert-threshold, the output is styled with --alert-style (red and bold\nby default) when the next prayer is closer than that, e.g. --alert-threshold 15m.\n\nWith --date, answers as if run at the current time of day on that date.",
+		Example: `  prayer-times next
+  prayer-times next --format name-and-remaining --alert-threshold 15m
+  prayer-times next --format "{{.Name}} in {{.Remaining}}"
+  prayer-times next --json | jq -r .remaining`,
+		RunE: runNext,
 	}
 
-	cmd.Flags().StringVar(&flagFormat, "format", prayer.FormatFull, "Display format: time-remaining, next-prayer-time, name-and-time, name-and-remaining, short-name-and-time, short-name-and-remaining, full, or a custom Go template")
+	cmd.Flags().StringVar(&flagFormat, "format", prayer.FormatFull, "Display format: time-remaining, time-remaining-seconds, next-prayer-time, name-and-time, name-and-remaining, short-name-and-time, short-name-and-remaining, full, or a custom Go template")
+	addDateFlag(cmd)
+	addAlertFlags(cmd)
 
 	return cmd
 }
@@ -46,7 +57,42 @@ type resolvedLocation struct {
 	Lat, Lon float64
 	City     string
 	Country  string
-	Timezone string // optional hint from geo-detection
+	Timezone string // optional: the user's override, or a hint from geo-detection or the timezone index
+}
+
+// localTime returns t in the location's timezone when it is known up front,
+// so "today" is the location's date rather than the system's.
+func (l resolvedLocation) localTime(t time.Time) time.Time {
+	if l.Timezone == "" {
+		return t
+	}
+	tzLoc, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return t
+	}
+	return t.In(tzLoc)
+}
+
+// timezoneFor returns the timezone to show the location's times in: its own
+// when known, else the API's from meta. Without either, as for entries
+// cached without metadata, it is looked up from the coordinates in the
+// bundled boundary index, so times still render in local time offline.
+// It returns "" (UTC) when there are no coordinates to go on.
+func (l resolvedLocation) timezoneFor(meta api.Meta) string {
+	if l.Timezone != "" {
+		return l.Timezone
+	}
+	if meta.Timezone != "" {
+		return meta.Timezone
+	}
+	lat, lon := meta.Latitude, meta.Longitude
+	if l.Mode == locationCoords {
+		lat, lon = l.Lat, l.Lon
+	} else if lat == 0 && lon == 0 {
+		return ""
+	}
+	zone, _ := tzlookup.Lookup(lat, lon)
+	return zone
 }
 
 // fetchResult holds the data returned from a prayer times fetch.
@@ -54,61 +100,53 @@ type fetchResult struct {
 	Timings  api.Timings
 	Meta     api.Meta
 	DateInfo api.DateInfo
+	// Stale is set when the API was unreachable and the timings come from
+	// the cache in its place, possibly for the day before (see staleTimings).
+	Stale bool
 }
 
 func runNext(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
 	// Get merged config (CLI flags > config file > defaults).
 	cfg := effectiveConfig(cmd)
 
 	// Determine which prayers to track.
 	// Priority: --prayers flag > config > defaults (handled by effectiveConfig).
-	selectedPrayers := prayer.DefaultPrayerNames
-	if cfg.Prayers != "" {
-		selectedPrayers = strings.Split(cfg.Prayers, ",")
-		for i := range selectedPrayers {
-			selectedPrayers[i] = strings.TrimSpace(selectedPrayers[i])
-		}
-	}
+	selectedPrayers := selectedPrayerNames(cfg)
 
 	// Determine time format from merged config (already merged via effectiveConfig).
-	timeFmt := cfg.TimeFormat
-	goTimeFmt := "15:04" // 24h
-	if timeFmt == "12h" {
-		goTimeFmt = "3:04 PM"
-	}
+	goTimeFmt := goTimeFormat(cfg)
 
 	// Initialize cache.
-	c, err := cache.New(cfg.CacheDir)
-	if err != nil {
-		// Cache init failure is non-fatal; we just skip caching.
-		c = nil
-		fmt.Fprintf(os.Stderr, "warning: cache disabled: %v\n", err)
-	}
+	c := openCache(cfg)
 
-	now := time.Now()
+	now := currentTime()
 
 	// Resolve location mode and coordinates.
 	// Priority: CLI flags > config > cached geo > IP auto-detect.
-	loc, err := resolveLocation(cfg.Latitude, cfg.Longitude, cfg.City, cfg.Country, c)
+	loc, err := resolveLocation(ctx, cfg, c)
 	if err != nil {
 		return err
 	}
+	now = loc.localTime(now)
+	if now, err = applyDateFlag(now); err != nil {
+		return err
+	}
 
 	// Get method/school from merged config.
 	method := cfg.MethodOrDefault(-1)
 	school := cfg.SchoolOrDefault(-1)
 
 	// Fetch today's timings (from cache or API).
-	result, err := fetchTimings(now, loc, method, school, c)
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
 	if err != nil {
 		return err
 	}
 
 	// Determine timezone.
-	tz := loc.Timezone
-	if tz == "" {
-		tz = result.Meta.Timezone
-	}
+	tz := loc.timezoneFor(result.Meta)
 	tzLoc, err := time.LoadLocation(tz)
 	if err != nil {
 		return fmt.Errorf("invalid timezone %q: %w", tz, err)
@@ -125,33 +163,17 @@ func runNext(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Find the next prayer.
-	next := prayer.NextPrayer(prayers, now)
-
 	// If all today's prayers have passed, fetch tomorrow's first prayer.
-	if next == nil {
-		tomorrow := today.AddDate(0, 0, 1)
-
-		tResult, fetchErr := fetchTimings(tomorrow, loc, method, school, c)
-		if fetchErr != nil {
-			// Network failure for tomorrow's data: show last prayer with
-			// a "done" indicator rather than crashing the status bar.
-			if len(prayers) > 0 {
-				last := prayers[len(prayers)-1]
-				fmt.Printf("%s --:--", last.Name)
-				return nil
-			}
-			return fmt.Errorf("failed to fetch tomorrow's times: %w", fetchErr)
-		}
-
-		tomorrowPrayers, err := prayer.ParseTimings(tResult.Timings, tomorrow, tzLoc, selectedPrayers)
-		if err != nil {
-			return err
-		}
-
-		if len(tomorrowPrayers) > 0 {
-			next = &tomorrowPrayers[0]
+	next, err := nextPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	if err != nil {
+		// Network failure for tomorrow's data: show last prayer with
+		// a "done" indicator rather than crashing the status bar.
+		if len(prayers) > 0 {
+			last := prayers[len(prayers)-1]
+			fmt.Fprintf(w, "%s --:--", last.Name)
+			return nil
 		}
+		return fmt.Errorf("failed to fetch tomorrow's times: %w", err)
 	}
 
 	if next == nil {
@@ -160,60 +182,191 @@ func runNext(cmd *cobra.Command, args []string) error {
 
 	// JSON output.
 	if FlagJSON {
-		remaining := prayer.FormatRemaining(prayer.TimeRemaining(*next, now))
-		out := nextJSON{
-			Prayer:    strings.ToLower(next.Name),
-			Time:      next.Time.Format(goTimeFmt),
-			Remaining: remaining,
-		}
+		out := buildNextJSON(*next, now, goTimeFmt)
+		out.Stale = result.Stale
 		data, err := json.MarshalIndent(out, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(w, string(data))
 		return nil
 	}
 
 	// Format and print.
-	output := prayer.FormatOutput(*next, now, flagFormat, goTimeFmt)
-	fmt.Print(output)
+	start := windowStart(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	output, err := applyAlert(prayer.FormatOutputSince(*next, start, now, flagFormat, goTimeFmt), *next, now)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(w, output)
 
 	return nil
 }
 
+// windowStart returns when the window ending at the next prayer began: the
+// current prayer's time, or zero when it can't be found, which leaves the
+// progress fields of --format templates empty.
+func windowStart(ctx context.Context, prayers []prayer.Prayer, now time.Time, loc resolvedLocation, method, school int, c *cache.Cache, tzLoc *time.Location, selected []string) time.Time {
+	current, err := currentPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selected)
+	if err != nil || current == nil {
+		return time.Time{}
+	}
+	return current.Time
+}
+
+// nextPrayerFrom returns the next of today's prayers after now, falling back to
+// tomorrow's first prayer once all of today's have passed.
+func nextPrayerFrom(ctx context.Context, prayers []prayer.Prayer, now time.Time, loc resolvedLocation, method, school int, c *cache.Cache, tzLoc *time.Location, selected []string) (*prayer.Prayer, error) {
+	if next := prayer.NextPrayer(prayers, now); next != nil {
+		return next, nil
+	}
+
+	tomorrow := now.AddDate(0, 0, 1)
+	tResult, err := fetchTimings(ctx, tomorrow, loc, method, school, c)
+	if err != nil {
+		return nil, err
+	}
+	tomorrowPrayers, err := prayer.ParseTimings(tResult.Timings, tomorrow, tzLoc, selected)
+	if err != nil {
+		return nil, err
+	}
+	if len(tomorrowPrayers) == 0 {
+		return nil, nil
+	}
+	return &tomorrowPrayers[0], nil
+}
+
+// cachedCalendarDay returns date's timings from a cached month or year (see
+// fetchCalendarDays), or nil when neither is cached.
+func cachedCalendarDay(date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) *api.Data {
+	month := int(date.Month())
+	var days []api.Data
+	if entry := c.LoadCalendar(date.Year(), month, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
+		days = entry.Days
+	} else if entry := c.LoadAnnual(date.Year(), loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
+		days = entry.Months[month]
+	}
+	if i := date.Day() - 1; i < len(days) {
+		return &days[i]
+	}
+	return nil
+}
+
+// staleTimings stands in for timings the API couldn't be reached for with
+// the day before's cached timings, which differ by a minute or so, dated
+// date, and warns that it did. Nothing is written back, so the next successful run
+// fetches and caches the day afresh. It returns nil when nothing is cached.
+func staleTimings(date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) *fetchResult {
+	if c == nil {
+		return nil
+	}
+	yesterday := date.AddDate(0, 0, -1)
+	entry := c.LoadTimings(yesterday, loc.Lat, loc.Lon, loc.City, loc.Country, method, school)
+	if entry == nil {
+		return nil
+	}
+	cached := ""
+	if !entry.CachedAt.IsZero() {
+		cached = ", cached " + entry.CachedAt.Format("2006-01-02 15:04")
+	}
+	notices.Warnf("prayer times API unreachable; using %s's times%s", entry.Date, cached)
+
+	// The times are yesterday's, but the date shown is today's.
+	info := withGregorian(entry.DateInfo, date)
+	if h, ok := parseAPIHijri(entry.DateInfo.Hijri); ok {
+		info.Hijri = apiHijri(h.AddDays(1))
+	} else {
+		info.Hijri = apiHijri(hijri.FromGregorian(date, 0))
+	}
+	return &fetchResult{Timings: entry.Timings, Meta: entry.Meta, DateInfo: info, Stale: true}
+}
+
 // nextJSON is the JSON output structure for the next command.
 type nextJSON struct {
 	Prayer    string `json:"prayer"`
 	Time      string `json:"time"`
 	Remaining string `json:"remaining"`
+	Stale     bool   `json:"stale,omitempty"` // times are from the cache; the API was unreachable
+}
+
+// buildNextJSON assembles the JSON structure shared by the next command and the HTTP server.
+func buildNextJSON(next prayer.Prayer, now time.Time, goTimeFmt string) nextJSON {
+	return nextJSON{
+		Prayer:    strings.ToLower(next.Name),
+		Time:      next.Time.Format(goTimeFmt),
+		Remaining: prayer.FormatRemaining(prayer.TimeRemaining(next, now)),
+	}
 }
 
 // resolveLocation determines the effective location based on user flags, config, or auto-detection.
 // Priority: CLI flags > config > cached geolocation > IP auto-detect.
-func resolveLocation(lat, lon float64, city, country string, c *cache.Cache) (resolvedLocation, error) {
+// A timezone override (--timezone or the timezone key) replaces the location's own.
+func resolveLocation(ctx context.Context, cfg *config.Config, c *cache.Cache) (resolvedLocation, error) {
+	loc, err := locateFromConfig(ctx, cfg, c)
+	if err != nil {
+		return resolvedLocation{}, err
+	}
+	if cfg.Timezone != "" {
+		loc.Timezone = cfg.Timezone
+	}
+	return loc, nil
+}
+
+// locateFromConfig finds the location itself, with the timezone it implies, if known.
+func locateFromConfig(ctx context.Context, cfg *config.Config, c *cache.Cache) (resolvedLocation, error) {
+	city, country := cfg.City, cfg.Country
 	switch {
-	case lat != 0 || lon != 0:
-		return resolvedLocation{Mode: locationCoords, Lat: lat, Lon: lon}, nil
+	case cfg.HasCoordinates():
+		lat, lon := cfg.Coordinates()
+		loc := resolvedLocation{Mode: locationCoords, Lat: lat, Lon: lon}
+		// Prefer the bundled boundary index over the API's guess; points it
+		// doesn't cover are left for the API to resolve.
+		if tz, ok := tzlookup.Lookup(lat, lon); ok {
+			loc.Timezone = tz
+		}
+		return loc, nil
 	case city != "":
 		if country == "" {
 			return resolvedLocation{}, fmt.Errorf("--country is required when using --city")
 		}
 		return resolvedLocation{Mode: locationCity, City: city, Country: country}, nil
 	default:
-		// Try cached geolocation first.
+		// $PRAYER_TIMES_GEO_URL stands in for IP geolocation, uncached.
+		if u := os.Getenv(geoURLEnv); u != "" {
+			stop := timer.Start(timing.Network)
+			detected, err := geo.DetectLocation(ctx, geo.NewFixedProvider(u))
+			stop()
+			if err != nil {
+				return resolvedLocation{}, fmt.Errorf("no location specified and $%s failed: %w", geoURLEnv, err)
+			}
+			return geoLocation(detected), nil
+		}
+
+		// Try cached geolocation first, then IP-based geolocation. Hold the
+		// cache's lock while detecting, so concurrent processes look up the
+		// location once.
 		if c != nil {
-			if cached := c.LoadGeo(); cached != nil {
-				return resolvedLocation{
-					Mode:     locationCoords,
-					Lat:      cached.Latitude,
-					Lon:      cached.Longitude,
-					Timezone: cached.Timezone,
-				}, nil
+			stop := timer.Start(timing.Cache)
+			cached := c.LoadGeo()
+			stop()
+			if cached != nil {
+				return geoLocation(cached), nil
+			}
+			if unlock, err := c.LockGeo(); err == nil {
+				defer unlock()
+				if cached := c.LoadGeo(); cached != nil {
+					return geoLocation(cached), nil
+				}
 			}
 		}
 
-		// Fall back to IP-based geolocation.
-		detected, err := geo.DetectLocation()
+		providers, err := geo.ParseProviders(cfg.GeoProvider, cfg.GeoAPIKey, FlagAllowInsecureGeo)
+		if err != nil {
+			return resolvedLocation{}, err
+		}
+		stop := timer.Start(timing.Network)
+		detected, err := geo.DetectLocation(ctx, providers...)
+		stop()
 		if err != nil {
 			return resolvedLocation{}, fmt.Errorf("no location specified and auto-detection failed: %w", err)
 		}
@@ -223,30 +376,91 @@ func resolveLocation(lat, lon float64, city, country string, c *cache.Cache) (re
 			_ = c.SaveGeo(detected) // best-effort
 		}
 
-		return resolvedLocation{
-			Mode:     locationCoords,
-			Lat:      detected.Latitude,
-			Lon:      detected.Longitude,
-			Timezone: detected.Timezone,
-		}, nil
+		return geoLocation(detected), nil
+	}
+}
+
+// geoLocation converts a detected location into a resolvedLocation.
+func geoLocation(l *geo.Location) resolvedLocation {
+	return resolvedLocation{
+		Mode:     locationCoords,
+		Lat:      l.Latitude,
+		Lon:      l.Longitude,
+		Timezone: l.Timezone,
+	}
+}
+
+// fetchTimings returns prayer timings for the given date, using the cache when
+// available, with the Hijri date adjusted by the hijri_adjustment setting.
+func fetchTimings(ctx context.Context, date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) (*fetchResult, error) {
+	result, err := loadTimings(ctx, date, loc, method, school, c)
+	if err != nil {
+		return nil, err
 	}
+	result.DateInfo = adjustHijri(result.DateInfo)
+	return result, nil
 }
 
-// fetchTimings returns prayer timings for the given date, using the cache when available.
-func fetchTimings(date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) (*fetchResult, error) {
+// loadTimings is fetchTimings without the Hijri adjustment.
+func loadTimings(ctx context.Context, date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) (*fetchResult, error) {
 	// Try cache first.
 	if c != nil {
-		if entry := c.LoadTimings(date, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
+		stop := timer.Start(timing.Cache)
+		entry := c.LoadTimings(date, loc.Lat, loc.Lon, loc.City, loc.Country, method, school)
+		var day *api.Data
+		if entry == nil {
+			day = cachedCalendarDay(date, loc, method, school, c)
+		}
+		stop()
+		if entry != nil {
 			return &fetchResult{
 				Timings:  entry.Timings,
 				Meta:     entry.Meta,
 				DateInfo: entry.DateInfo,
 			}, nil
 		}
+		if day != nil {
+			return &fetchResult{Timings: day.Timings, Meta: day.Meta, DateInfo: day.Date}, nil
+		}
 	}
 
-	// Cache miss -- fetch from API.
-	client := api.NewClient()
+	// Cache miss -- fetch from API. Another process may be fetching the same
+	// day (a status bar refresh alongside a manual call), so hold the entry's
+	// lock and check the cache again once it's ours.
+	if c != nil {
+		if unlock, err := c.LockTimings(date, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); err == nil {
+			defer unlock()
+			if entry := c.LoadTimings(date, loc.Lat, loc.Lon, loc.City, loc.Country, method, school); entry != nil {
+				return &fetchResult{
+					Timings:  entry.Timings,
+					Meta:     entry.Meta,
+					DateInfo: entry.DateInfo,
+				}, nil
+			}
+		}
+	}
+	resp, err := fetchDay(ctx, date, loc, method, school, c)
+	if err != nil {
+		if !api.Unreachable(err) {
+			return nil, err
+		}
+		if stale := staleTimings(date, loc, method, school, c); stale != nil {
+			return stale, nil
+		}
+		return nil, err
+	}
+	prefetchAhead(ctx, date, loc, method, school, c)
+
+	return &fetchResult{
+		Timings:  resp.Data.Timings,
+		Meta:     resp.Data.Meta,
+		DateInfo: resp.Data.Date,
+	}, nil
+}
+
+// fetchDay fetches one day's timings from the API, caching and archiving them.
+func fetchDay(ctx context.Context, date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) (*api.Response, error) {
+	client := newAPIClient()
 	var (
 		resp *api.Response
 		err  error
@@ -254,23 +468,23 @@ func fetchTimings(date time.Time, loc resolvedLocation, method, school int, c *c
 
 	switch loc.Mode {
 	case locationCity:
-		resp, err = client.FetchByCity(date, loc.City, loc.Country, method, school)
+		resp, err = client.FetchByCity(ctx, date, loc.City, loc.Country, method, school)
 	default:
-		resp, err = client.FetchByCoordinates(date, loc.Lat, loc.Lon, method, school)
+		resp, err = client.FetchByCoordinates(ctx, date, loc.Lat, loc.Lon, method, school)
 	}
 
 	if err != nil {
 		return nil, err
 	}
+	recordFetch()
 
 	// Write to cache (best-effort).
 	if c != nil {
+		stop := timer.Start(timing.Cache)
 		_ = c.SaveTimings(date, loc.Lat, loc.Lon, loc.City, loc.Country, method, school, resp)
+		stop()
 	}
+	archiveDay(client, date.Format("2006-01-02"), loc, method, school, resp.Data)
 
-	return &fetchResult{
-		Timings:  resp.Data.Timings,
-		Meta:     resp.Data.Meta,
-		DateInfo: resp.Data.Date,
-	}, nil
+	return resp, nil
 }
@@ -0,0 +1,547 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/gpio"
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+	"github.com/smokyabdulrahman/prayer-times/internal/matrix"
+	"github.com/smokyabdulrahman/prayer-times/internal/mqtt"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/push"
+	"github.com/smokyabdulrahman/prayer-times/internal/webhook"
+)
+
+const (
+	// notifyRetry is how long the notifier waits after failing to fetch
+	// timings.
+	notifyRetry = 5 * time.Minute
+	// notifyLate is how late an event may be (e.g. after the machine wakes
+	// from sleep) and still be delivered.
+	notifyLate = 5 * time.Minute
+)
+
+// scheduledEvent is an event and when to deliver it.
+type scheduledEvent struct {
+	At    time.Time
+	Event webhook.Event
+}
+
+// eventSink delivers serve's prayer events.
+type eventSink interface {
+	// send delivers an event as it comes due.
+	send(e webhook.Event) error
+	// announce tells the sink which prayer is next: at start, and after
+	// each prayer begins.
+	announce(next webhook.Event) error
+	String() string
+}
+
+// eventNotifier delivers prayer and reminder events to its sinks while
+// serve runs.
+type eventNotifier struct {
+	srv    *server
+	before time.Duration
+	sinks  []eventSink
+	lang   string // language of the events' text
+	layout string // layout of the events' clock times
+}
+
+// notifyTarget is a kind of target serve sends events to, by the name
+// notify_targets picks it with.
+type notifyTarget struct {
+	name string
+	// build returns the sink for cfg's settings, or nil when the target
+	// isn't configured.
+	build func(cfg *config.Config) (eventSink, error)
+}
+
+// notifyTargets is the registry of targets, in delivery order. Its names are
+// config.NotifyTargets.
+var notifyTargets = []notifyTarget{
+	{"webhooks", sinkFrom(newWebhookSink)},
+	{"mqtt", sinkFrom(newMQTTSink)},
+	{"matrix", sinkFrom(newMatrixSink)},
+	{"ntfy", sinkFrom(newNtfySink)},
+	{"gotify", sinkFrom(newGotifySink)},
+	{"pushover", sinkFrom(newPushoverSink)},
+	{"gpio", sinkFrom(newGPIOSink)},
+}
+
+// sinkFrom adapts a sink constructor for the registry, turning the nil
+// pointer it returns for an unconfigured target into a nil eventSink.
+func sinkFrom[S any, P interface {
+	*S
+	eventSink
+}](build func(*config.Config) (P, error)) func(*config.Config) (eventSink, error) {
+	return func(cfg *config.Config) (eventSink, error) {
+		s, err := build(cfg)
+		if err != nil || s == nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// newEventNotifier returns a notifier for the targets configured in cfg,
+// limited to those in notify_targets when it is set, or nil when there are
+// none.
+func newEventNotifier(cfg *config.Config, srv *server) (*eventNotifier, error) {
+	names, err := config.ParseNotifyTargets(cfg.NotifyTargets)
+	if err != nil {
+		return nil, err
+	}
+	chosen := make(map[string]bool, len(names))
+	for _, name := range names {
+		chosen[name] = true
+	}
+
+	var sinks []eventSink
+	for _, t := range notifyTargets {
+		if len(chosen) > 0 && !chosen[t.name] {
+			continue
+		}
+		sink, err := t.build(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if sink == nil {
+			if chosen[t.name] {
+				return nil, fmt.Errorf("notify_targets includes %s, but it isn't configured", t.name)
+			}
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	before, err := config.ParseWebhookBefore(cfg.WebhookBefore)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := i18n.Parse(cfg.Lang); err != nil {
+		return nil, err
+	}
+	country := cfg.Country
+	if srv != nil && srv.loc.Country != "" {
+		country = srv.loc.Country
+	}
+	lang := i18n.Resolve(cfg.Lang, country)
+	return &eventNotifier{srv: srv, before: before, sinks: sinks, lang: lang, layout: goTimeFormat(cfg)}, nil
+}
+
+// notifyEvents schedules a prayer event for each prayer and, when before is
+// positive, a reminder that long ahead of it, in time order.
+func notifyEvents(prayers []prayer.Prayer, before time.Duration, location, hijri string) []scheduledEvent {
+	var events []scheduledEvent
+	for _, p := range prayers {
+		e := webhook.Event{Kind: webhook.KindPrayer, Prayer: p.Name, Time: p.Time, Location: location, Hijri: hijri}
+		events = append(events, scheduledEvent{At: p.Time, Event: e})
+		if before > 0 {
+			e.Kind, e.MinutesBefore = webhook.KindReminder, int(before.Round(time.Minute)/time.Minute)
+			events = append(events, scheduledEvent{At: p.Time.Add(-before), Event: e})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events
+}
+
+// upcoming returns today's and tomorrow's events after after.
+func (n *eventNotifier) upcoming(ctx context.Context, after time.Time) ([]scheduledEvent, error) {
+	var events []scheduledEvent
+	for _, date := range []time.Time{after, after.AddDate(0, 0, 1)} {
+		result, err := fetchTimings(ctx, date, n.srv.loc, n.srv.method, n.srv.school, n.srv.cache)
+		if err != nil {
+			return nil, err
+		}
+		_, tzLoc, err := n.srv.timezone(result.Meta)
+		if err != nil {
+			return nil, err
+		}
+		prayers, err := prayer.ParseTimings(result.Timings, date.In(tzLoc), tzLoc, n.srv.prayers)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range notifyEvents(prayers, n.before, buildLocationStr(n.srv.loc, result), result.DateInfo.Hijri.Format()) {
+			if e.At.After(after) {
+				e.Event.Lang, e.Event.Layout = n.lang, n.layout
+				events = append(events, e)
+			}
+		}
+	}
+	return events, nil
+}
+
+// run delivers each event as it comes due, until ctx is done.
+func (n *eventNotifier) run(ctx context.Context) {
+	last := time.Now()
+	announce := true
+	for {
+		events, err := n.upcoming(ctx, last)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil || len(events) == 0 {
+			if err == nil {
+				err = fmt.Errorf("no upcoming prayers")
+			}
+			notices.Warnf("notifications: %v; retrying in %s", err, notifyRetry)
+			if !sleepCtx(ctx, notifyRetry) {
+				return
+			}
+			continue
+		}
+		if announce {
+			n.announce(events)
+			announce = false
+		}
+
+		next := events[0]
+		// Sleep in short steps so a suspended machine catches up on waking.
+		for d := time.Until(next.At); d > 0; d = time.Until(next.At) {
+			if !sleepCtx(ctx, min(d, time.Minute)) {
+				return
+			}
+		}
+		last = next.At
+		if next.Event.Kind == webhook.KindPrayer {
+			announce = true
+		}
+
+		if late := time.Since(next.At); late > notifyLate {
+			notices.Warnf("notifications: skipped %s %s, %s late", next.Event.Prayer, next.Event.Kind, late.Round(time.Minute))
+			continue
+		}
+		n.deliver(next.Event)
+	}
+}
+
+// sleepCtx waits for d, returning false if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// deliver sends e to every sink, reporting failures without giving up on
+// the rest.
+func (n *eventNotifier) deliver(e webhook.Event) {
+	for _, s := range n.sinks {
+		if err := s.send(e); err != nil {
+			notices.Warnf("%s: %s %s: %v", s, e.Prayer, e.Kind, err)
+			continue
+		}
+		notices.Infof("Sent %s %s to %s", e.Prayer, e.Kind, s)
+	}
+}
+
+// announce tells every sink about the first prayer in events.
+func (n *eventNotifier) announce(events []scheduledEvent) {
+	for _, e := range events {
+		if e.Event.Kind != webhook.KindPrayer {
+			continue
+		}
+		for _, s := range n.sinks {
+			if err := s.announce(e.Event); err != nil {
+				notices.Warnf("%s: next prayer: %v", s, err)
+			}
+		}
+		return
+	}
+}
+
+// webhookSink POSTs events to the configured webhooks.
+type webhookSink struct {
+	urls     []string
+	template string
+	client   *http.Client
+}
+
+// newWebhookSink returns the sink for cfg's webhooks, or nil when none are
+// set.
+func newWebhookSink(cfg *config.Config) (*webhookSink, error) {
+	urls, err := webhook.ParseURLs(cfg.Webhooks)
+	if err != nil || len(urls) == 0 {
+		return nil, err
+	}
+	if err := webhook.ParseTemplate(cfg.WebhookTemplate); err != nil {
+		return nil, err
+	}
+	return &webhookSink{urls: urls, template: cfg.WebhookTemplate, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (s *webhookSink) String() string { return "webhooks" }
+
+// send posts e to every webhook; one failing doesn't stop the rest.
+func (s *webhookSink) send(e webhook.Event) error {
+	var errs []error
+	for _, url := range s.urls {
+		body, err := webhook.Body(s.template, url, e)
+		if err == nil {
+			err = webhook.Post(s.client, url, body)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *webhookSink) announce(webhook.Event) error { return nil }
+
+// mqttSink publishes prayer events and the retained next prayer to an MQTT
+// broker, under a topic prefix:
+//
+//	<prefix>/prayer  each prayer as it begins (the event JSON)
+//	<prefix>/next    the next prayer, retained
+type mqttSink struct {
+	broker string
+	topic  string
+	opts   mqtt.Options
+}
+
+// defaultMQTTTopic is the topic prefix when mqtt_topic isn't set.
+const defaultMQTTTopic = "prayer-times"
+
+// newMQTTSink returns the sink for cfg's MQTT broker, or nil when none is
+// set.
+func newMQTTSink(cfg *config.Config) (*mqttSink, error) {
+	if cfg.MQTTBroker == "" {
+		return nil, nil
+	}
+	if _, err := mqtt.ParseBroker(cfg.MQTTBroker); err != nil {
+		return nil, err
+	}
+	topic := cfg.MQTTTopic
+	if topic == "" {
+		topic = defaultMQTTTopic
+	}
+	clientID := "prayer-times"
+	if host, err := os.Hostname(); err == nil {
+		clientID += "-" + host
+	}
+	return &mqttSink{
+		broker: cfg.MQTTBroker,
+		topic:  topic,
+		opts:   mqtt.Options{ClientID: clientID, Username: cfg.MQTTUsername, Password: cfg.MQTTPassword},
+	}, nil
+}
+
+func (s *mqttSink) String() string { return "mqtt" }
+
+// send publishes prayer events; reminders are left to webhooks, since
+// subscribers can count down from the retained next prayer.
+func (s *mqttSink) send(e webhook.Event) error {
+	if e.Kind != webhook.KindPrayer {
+		return nil
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return mqtt.Publish(s.broker, s.opts, mqtt.Message{Topic: s.topic + "/prayer", Payload: payload})
+}
+
+// mqttNext is the payload of the retained next-prayer topic.
+type mqttNext struct {
+	Prayer   string    `json:"prayer"`
+	Time     time.Time `json:"time"`
+	Location string    `json:"location"`
+}
+
+func (s *mqttSink) announce(next webhook.Event) error {
+	payload, err := json.Marshal(mqttNext{Prayer: next.Prayer, Time: next.Time, Location: next.Location})
+	if err != nil {
+		return err
+	}
+	return mqtt.Publish(s.broker, s.opts, mqtt.Message{Topic: s.topic + "/next", Payload: payload, Retain: true})
+}
+
+// matrixSink posts prayers and reminders to a Matrix room as chat messages.
+type matrixSink struct {
+	client   *matrix.Client
+	room     string
+	template string // see webhook.Text
+}
+
+// newMatrixSink returns the sink for cfg's Matrix room, or nil when no
+// homeserver is set.
+func newMatrixSink(cfg *config.Config) (*matrixSink, error) {
+	if cfg.MatrixHomeserver == "" {
+		return nil, nil
+	}
+	homeserver, err := matrix.ParseHomeserver(cfg.MatrixHomeserver)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MatrixToken == "" || cfg.MatrixRoom == "" {
+		return nil, fmt.Errorf("matrix_homeserver is set, but matrix_token and matrix_room are needed too")
+	}
+	room, err := matrix.ParseRoom(cfg.MatrixRoom)
+	if err != nil {
+		return nil, err
+	}
+	if err := webhook.ParseMessageTemplate("matrix_template", cfg.MatrixTemplate); err != nil {
+		return nil, err
+	}
+	return &matrixSink{
+		client:   &matrix.Client{Homeserver: homeserver, Token: cfg.MatrixToken, HTTP: &http.Client{Timeout: 10 * time.Second}},
+		room:     room,
+		template: cfg.MatrixTemplate,
+	}, nil
+}
+
+func (s *matrixSink) String() string { return "matrix" }
+
+func (s *matrixSink) send(e webhook.Event) error {
+	text, err := webhook.Text(s.template, e)
+	if err != nil {
+		return err
+	}
+	return s.client.Send(s.room, text)
+}
+
+func (s *matrixSink) announce(webhook.Event) error { return nil }
+
+// pushSink sends prayers and reminders as phone push notifications through
+// ntfy, Gotify or Pushover.
+type pushSink struct {
+	service  string // "ntfy", "gotify" or "pushover"
+	url      string // ntfy topic or Gotify server
+	user     string // Pushover user or group key
+	token    string
+	template string // see webhook.Text
+	title    string // see webhook.Title
+	client   *http.Client
+}
+
+// newNtfySink returns the sink for cfg's ntfy topic, or nil when none is
+// set.
+func newNtfySink(cfg *config.Config) (*pushSink, error) {
+	if cfg.NtfyURL == "" {
+		return nil, nil
+	}
+	topic, err := push.ParseNtfyTopic(cfg.NtfyURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseMessageTemplates("ntfy", cfg.NtfyTemplate, cfg.NtfyTitle); err != nil {
+		return nil, err
+	}
+	return &pushSink{service: "ntfy", url: topic, token: cfg.NtfyToken, template: cfg.NtfyTemplate, title: cfg.NtfyTitle, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// newGotifySink returns the sink for cfg's Gotify server, or nil when none
+// is set.
+func newGotifySink(cfg *config.Config) (*pushSink, error) {
+	if cfg.GotifyURL == "" {
+		return nil, nil
+	}
+	server, err := push.ParseURL("gotify_url", cfg.GotifyURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.GotifyToken == "" {
+		return nil, fmt.Errorf("gotify_url is set, but gotify_token is needed too")
+	}
+	if err := parseMessageTemplates("gotify", cfg.GotifyTemplate, cfg.GotifyTitle); err != nil {
+		return nil, err
+	}
+	return &pushSink{service: "gotify", url: server, token: cfg.GotifyToken, template: cfg.GotifyTemplate, title: cfg.GotifyTitle, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// newPushoverSink returns the sink for cfg's Pushover user key, or nil when
+// none is set.
+func newPushoverSink(cfg *config.Config) (*pushSink, error) {
+	if cfg.PushoverUser == "" {
+		return nil, nil
+	}
+	if cfg.PushoverToken == "" {
+		return nil, fmt.Errorf("pushover_user is set, but pushover_token is needed too")
+	}
+	if err := parseMessageTemplates("pushover", cfg.PushoverTemplate, cfg.PushoverTitle); err != nil {
+		return nil, err
+	}
+	return &pushSink{service: "pushover", user: cfg.PushoverUser, token: cfg.PushoverToken, template: cfg.PushoverTemplate, title: cfg.PushoverTitle, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// parseMessageTemplates checks a push service's <service>_template and
+// <service>_title.
+func parseMessageTemplates(service, message, title string) error {
+	if err := webhook.ParseMessageTemplate(service+"_template", message); err != nil {
+		return err
+	}
+	return webhook.ParseMessageTemplate(service+"_title", title)
+}
+
+func (s *pushSink) String() string { return s.service }
+
+func (s *pushSink) send(e webhook.Event) error {
+	text, err := webhook.Text(s.template, e)
+	if err != nil {
+		return err
+	}
+	title, err := webhook.Title(s.title, e)
+	if err != nil {
+		return err
+	}
+	switch s.service {
+	case "gotify":
+		return push.Gotify(s.client, s.url, s.token, title, text)
+	case "pushover":
+		return push.Pushover(s.client, s.user, s.token, title, text)
+	}
+	return push.Ntfy(s.client, s.url, s.token, title, text)
+}
+
+func (s *pushSink) announce(webhook.Event) error { return nil }
+
+// gpioSink pulses a GPIO pin, wired to an LED or buzzer, as each prayer
+// begins.
+type gpioSink struct {
+	pin     int
+	pattern gpio.Pattern
+}
+
+// newGPIOSink returns the sink for cfg's GPIO pin, or nil when none is set.
+func newGPIOSink(cfg *config.Config) (*gpioSink, error) {
+	if cfg.GPIOPin == "" {
+		return nil, nil
+	}
+	pin, err := gpio.ParsePin(cfg.GPIOPin)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := gpio.ParsePattern(cfg.GPIOPattern)
+	if err != nil {
+		return nil, err
+	}
+	return &gpioSink{pin: pin, pattern: pattern}, nil
+}
+
+func (s *gpioSink) String() string { return fmt.Sprintf("gpio %d", s.pin) }
+
+// send pulses the pin at each prayer; reminders stay silent.
+func (s *gpioSink) send(e webhook.Event) error {
+	if e.Kind != webhook.KindPrayer {
+		return nil
+	}
+	return gpio.Pulse(s.pin, s.pattern)
+}
+
+func (s *gpioSink) announce(webhook.Event) error { return nil }
@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/diag"
+	"github.com/smokyabdulrahman/prayer-times/internal/webhook"
+)
+
+func TestEventNotifier_Upcoming(t *testing.T) {
+	s := newTestServer(t)
+	riyadh, err := time.LoadLocation("Asia/Riyadh")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	for _, day := range []int{10, 11} {
+		resp := &api.Response{Code: 200, Status: "OK", Data: api.Data{
+			Timings: api.Timings{Fajr: "05:30", Dhuhr: "12:30", Asr: "15:45", Maghrib: "18:10", Isha: "19:40"},
+			Meta:    api.Meta{Latitude: s.loc.Lat, Longitude: s.loc.Lon, Timezone: "Asia/Riyadh"},
+			Date:    api.DateInfo{Hijri: api.HijriDate{Day: "22", Month: api.HijriMonth{En: "Shaʿbān"}, Year: "1447"}},
+		}}
+		if err := s.cache.SaveTimings(time.Date(2026, 2, day, 12, 0, 0, 0, riyadh), s.loc.Lat, s.loc.Lon, "", "", -1, -1, resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n := &eventNotifier{srv: s, before: 10 * time.Minute}
+	events, err := n.upcoming(context.Background(), time.Date(2026, 2, 10, 19, 0, 0, 0, riyadh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Isha's reminder and prayer today, then all ten of tomorrow's events.
+	if len(events) != 12 {
+		t.Fatalf("got %d events, want 12", len(events))
+	}
+	want := []struct {
+		at, kind, prayer string
+	}{
+		{"2026-02-10 19:30", webhook.KindReminder, "Isha"},
+		{"2026-02-10 19:40", webhook.KindPrayer, "Isha"},
+		{"2026-02-11 05:20", webhook.KindReminder, "Fajr"},
+		{"2026-02-11 05:30", webhook.KindPrayer, "Fajr"},
+	}
+	for i, w := range want {
+		e := events[i]
+		if got := e.At.In(riyadh).Format("2006-01-02 15:04"); got != w.at || e.Event.Kind != w.kind || e.Event.Prayer != w.prayer {
+			t.Errorf("event %d = %s %s %s, want %s %s %s", i, got, e.Event.Prayer, e.Event.Kind, w.at, w.prayer, w.kind)
+		}
+	}
+	if e := events[0].Event; e.MinutesBefore != 10 || e.Hijri != "22 Shaʿbān 1447 AH" || e.Location == "" {
+		t.Errorf("reminder event = %+v", e)
+	}
+}
+
+// recordingSink records what the notifier sends it.
+type recordingSink struct {
+	sent, announced []string
+}
+
+func (r *recordingSink) String() string { return "recorder" }
+
+func (r *recordingSink) send(e webhook.Event) error {
+	r.sent = append(r.sent, e.Prayer+" "+e.Kind)
+	return nil
+}
+
+func (r *recordingSink) announce(next webhook.Event) error {
+	r.announced = append(r.announced, next.Prayer)
+	return nil
+}
+
+// TestEventNotifier_RunStops verifies run returns once its context is done,
+// even while waiting to retry a failed fetch.
+func TestEventNotifier_RunStops(t *testing.T) {
+	s := newTestServer(t)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	prev := apiBaseURL
+	apiBaseURL = srv.URL
+	t.Cleanup(func() { apiBaseURL = prev })
+
+	var log bytes.Buffer
+	orig := notices
+	notices = diag.New(&log, &log)
+	t.Cleanup(func() { notices = orig })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		(&eventNotifier{srv: s}).run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run() did not return after its context was canceled")
+	}
+}
+
+func TestEventNotifier_Deliver(t *testing.T) {
+	rec := &recordingSink{}
+	failing := &webhookSink{urls: []string{"http://127.0.0.1:1/unreachable"}, client: &http.Client{Timeout: time.Second}}
+	n := &eventNotifier{sinks: []eventSink{failing, rec}}
+
+	var log bytes.Buffer
+	orig := notices
+	notices = diag.New(&log, &log)
+	t.Cleanup(func() { notices = orig })
+
+	// A failing sink doesn't stop the rest.
+	n.deliver(webhook.Event{Kind: webhook.KindPrayer, Prayer: "Asr"})
+	if len(rec.sent) != 1 || rec.sent[0] != "Asr prayer" {
+		t.Errorf("sent = %q, want [Asr prayer]", rec.sent)
+	}
+	if !strings.Contains(log.String(), "webhooks: Asr prayer") {
+		t.Errorf("the failing sink should be reported, got %q", log.String())
+	}
+
+	// Only prayers are announced as next, not reminders.
+	n.announce([]scheduledEvent{
+		{Event: webhook.Event{Kind: webhook.KindReminder, Prayer: "Maghrib"}},
+		{Event: webhook.Event{Kind: webhook.KindPrayer, Prayer: "Maghrib"}},
+		{Event: webhook.Event{Kind: webhook.KindPrayer, Prayer: "Isha"}},
+	})
+	if len(rec.announced) != 1 || rec.announced[0] != "Maghrib" {
+		t.Errorf("announced = %q, want [Maghrib]", rec.announced)
+	}
+}
+
+func TestWebhookSink_Send(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	s := &webhookSink{
+		urls:     []string{srv.URL + "/down", srv.URL + "/hook"},
+		template: "{{.Prayer}} {{.Event.Kind}}",
+		client:   srv.Client(),
+	}
+	err := s.send(webhook.Event{Kind: webhook.KindPrayer, Prayer: "Asr"})
+
+	// A failing webhook doesn't stop the rest.
+	if len(bodies) != 2 || !strings.Contains(bodies[1], "Asr prayer") {
+		t.Errorf("bodies = %q, want both webhooks to get \"Asr prayer\"", bodies)
+	}
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("send() = %v, want the failing webhook reported", err)
+	}
+}
+
+func TestMatrixSink_Send(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got = string(data)
+	}))
+	defer srv.Close()
+
+	s, err := newMatrixSink(&config.Config{MatrixHomeserver: srv.URL, MatrixToken: "tok", MatrixRoom: "!room:example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := webhook.Event{Kind: webhook.KindReminder, Prayer: "Asr", MinutesBefore: 10, Location: "Mecca, SA"}
+	if err := s.send(e); err != nil {
+		t.Fatalf("send() error: %v", err)
+	}
+	if !strings.Contains(got, "Asr in 10 minutes") {
+		t.Errorf("message = %s, want the event's message", got)
+	}
+
+	if _, err := newMatrixSink(&config.Config{MatrixHomeserver: srv.URL}); err == nil {
+		t.Error("newMatrixSink() without a token and room should error")
+	}
+}
+
+func TestPushSinks(t *testing.T) {
+	var paths, bodies, titles []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		paths, bodies, titles = append(paths, r.URL.Path), append(bodies, string(data)), append(titles, r.Header.Get("Title"))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{NtfyURL: srv.URL + "/prayers", NtfyTemplate: "🕌 {{.Prayer}}", NtfyTitle: "Salah: {{.Name}}", GotifyURL: srv.URL, GotifyToken: "AbC"}
+	ntfy, err := newNtfySink(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotify, err := newGotifySink(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []eventSink{ntfy, gotify} {
+		if err := s.send(webhook.Event{Kind: webhook.KindPrayer, Prayer: "Asr"}); err != nil {
+			t.Errorf("%s: send() error: %v", s, err)
+		}
+	}
+	if len(paths) != 2 || paths[0] != "/prayers" || paths[1] != "/message" {
+		t.Errorf("paths = %v, want the ntfy topic then Gotify's /message", paths)
+	}
+	if len(bodies) != 2 || bodies[0] != "🕌 Asr" || !strings.Contains(bodies[1], "It's time for Asr") {
+		t.Errorf("bodies = %q, want ntfy's template and Gotify's default message", bodies)
+	}
+	if titles[0] != "Salah: Asr" {
+		t.Errorf("ntfy title = %q, want ntfy_title", titles[0])
+	}
+
+	if _, err := newGotifySink(&config.Config{GotifyURL: srv.URL}); err == nil {
+		t.Error("newGotifySink() without gotify_token should error")
+	}
+	if _, err := newPushoverSink(&config.Config{PushoverUser: "u123"}); err == nil {
+		t.Error("newPushoverSink() without pushover_token should error")
+	}
+	if _, err := newNtfySink(&config.Config{NtfyURL: srv.URL + "/prayers", NtfyTemplate: "{{.Prayer"}); err == nil {
+		t.Error("newNtfySink() with a bad template should error")
+	}
+	if _, err := newNtfySink(&config.Config{NtfyURL: srv.URL + "/prayers", NtfyTitle: "{{.Prayer"}); err == nil || !strings.Contains(err.Error(), "ntfy_title") {
+		t.Errorf("newNtfySink() with a bad title error = %v, want one naming ntfy_title", err)
+	}
+}
+
+func TestNewEventNotifier_Targets(t *testing.T) {
+	if len(notifyTargets) != len(config.NotifyTargets) {
+		t.Fatalf("registry has %d targets, config.NotifyTargets %d", len(notifyTargets), len(config.NotifyTargets))
+	}
+	for i, target := range notifyTargets {
+		if target.name != config.NotifyTargets[i] {
+			t.Errorf("target %d = %s, want %s", i, target.name, config.NotifyTargets[i])
+		}
+	}
+
+	cfg := &config.Config{
+		NtfyURL:      "https://ntfy.sh/my-prayers",
+		PushoverUser: "u123", PushoverToken: "a456",
+		GPIOPin: "17",
+	}
+	sinkNames := func(n *eventNotifier) []string {
+		var names []string
+		for _, s := range n.sinks {
+			names = append(names, s.String())
+		}
+		return names
+	}
+
+	n, err := newEventNotifier(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(sinkNames(n), ","); got != "ntfy,pushover,gpio 17" {
+		t.Errorf("sinks = %s, want every configured target", got)
+	}
+
+	cfg.NotifyTargets = "pushover, ntfy"
+	if n, err = newEventNotifier(cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(sinkNames(n), ","); got != "ntfy,pushover" {
+		t.Errorf("sinks = %s, want only notify_targets", got)
+	}
+
+	cfg.Lang, cfg.TimeFormat = "ar", "12h"
+	if n, err = newEventNotifier(cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.lang != "ar" || n.layout != "3:04 PM" {
+		t.Errorf("lang, layout = %q, %q; want ar, 3:04 PM", n.lang, n.layout)
+	}
+
+	// Without a lang, the location's country picks it when the locale
+	// doesn't.
+	t.Setenv("LC_ALL", "C")
+	cfg.Lang, cfg.Country = "", "Egypt"
+	if n, err = newEventNotifier(cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.lang != "ar" {
+		t.Errorf("lang = %q, want ar for Egypt", n.lang)
+	}
+
+	cfg.NotifyTargets = "matrix"
+	if _, err := newEventNotifier(cfg, nil); err == nil || !strings.Contains(err.Error(), "matrix") {
+		t.Errorf("newEventNotifier() with an unconfigured target error = %v, want one naming it", err)
+	}
+}
+
+func TestNewGPIOSink(t *testing.T) {
+	if s, err := newGPIOSink(&config.Config{}); s != nil || err != nil {
+		t.Errorf("newGPIOSink() without gpio_pin = %v, %v; want nil", s, err)
+	}
+	s, err := newGPIOSink(&config.Config{GPIOPin: "17", GPIOPattern: "1x3s"})
+	if err != nil || s.pin != 17 || s.pattern.Count != 1 || s.pattern.Pulse != 3*time.Second {
+		t.Errorf("newGPIOSink() = %+v, %v; want pin 17 pulsed once for 3s", s, err)
+	}
+	if _, err := newGPIOSink(&config.Config{GPIOPin: "17", GPIOPattern: "blink"}); err == nil {
+		t.Error("newGPIOSink() with a bad pattern should error")
+	}
+}
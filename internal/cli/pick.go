@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/spf13/cobra"
+)
+
+func newMethodsPickCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pick",
+		Short: "Compare today's times under every method and save one to config",
+		Long: `Show today's prayer times for your location under every calculation method,
+one row per method, then ask which to use and save it as the method config
+key. Your current method is highlighted; press Enter to keep it.
+
+Methods are fetched concurrently and cached. With --json, prints the
+comparison without asking.`,
+		Example: `  prayer-times methods pick
+  prayer-times methods pick --city Toronto --country CA
+  prayer-times methods pick --json | jq -r '.[] | "\(.id) \(.timings.fajr) \(.timings.isha)"'`,
+		Args: cobra.NoArgs,
+		RunE: runMethodsPick,
+	}
+}
+
+// methodPickJSON is one method of 'methods pick --json'.
+type methodPickJSON struct {
+	ID      int               `json:"id"`
+	Name    string            `json:"name"`
+	Timings map[string]string `json:"timings,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+func runMethodsPick(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	school := cfg.SchoolOrDefault(-1)
+	targets := make([]compareTarget, len(CalculationMethods))
+	for i, m := range CalculationMethods {
+		targets[i] = compareTarget{Label: m.Name, Loc: loc, Method: m.ID, School: school}
+	}
+	cols := fetchCompareColumns(ctx, targets, currentTime(), selectedPrayers, c)
+
+	location := ""
+	for _, col := range cols {
+		if col.Err == nil {
+			location = col.Location
+			break
+		}
+	}
+	if location == "" {
+		return fmt.Errorf("failed to fetch prayer times for any method: %w", cols[0].Err)
+	}
+
+	if FlagJSON {
+		out := make([]methodPickJSON, len(cols))
+		for i, col := range cols {
+			out[i] = methodPickJSON{ID: CalculationMethods[i].ID, Name: CalculationMethods[i].Name}
+			if col.Err != nil {
+				out[i].Error = col.Err.Error()
+				continue
+			}
+			out[i].Timings = make(map[string]string, len(col.Prayers))
+			for _, p := range col.Prayers {
+				out[i].Timings[strings.ToLower(p.Name)] = p.Time.Format(goTimeFmt)
+			}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	current := cfg.MethodOrDefault(-1)
+	tbl := display.NewTable(append([]string{"ID", "Method"}, selectedPrayers...))
+	for i, col := range cols {
+		m := CalculationMethods[i]
+		if m.ID == current {
+			tbl.SetHighlightRow(i)
+		}
+		row := []string{strconv.Itoa(m.ID), m.Name}
+		for _, name := range selectedPrayers {
+			cell := "--:--"
+			for _, p := range col.Prayers {
+				if strings.EqualFold(p.Name, name) {
+					cell = p.Time.Format(goTimeFmt)
+				}
+			}
+			row = append(row, cell)
+		}
+		tbl.AddRow(row)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Calculation Methods Compared"))
+	fmt.Fprintf(w, "  %s\n", display.Dim(location))
+	fmt.Fprintln(w)
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+
+	id, ok, err := askMethod(cmd, current)
+	if err != nil || !ok {
+		return err
+	}
+	saved, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := saved.Set("method", strconv.Itoa(id)); err != nil {
+		return err
+	}
+	if err := saved.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Set method = %d (%s)\n", id, methodName(id))
+	return nil
+}
+
+// askMethod asks on stderr for the ID of a method to save, reading the answer
+// from stdin until it is a known method. It reports false when there is no
+// answer, or the answer is the current method.
+func askMethod(cmd *cobra.Command, current int) (int, bool, error) {
+	prompt := "Method to save (ID): "
+	if current >= 0 {
+		prompt = fmt.Sprintf("Method to save (ID, Enter to keep %d): ", current)
+	}
+	in := bufio.NewReader(cmd.InOrStdin())
+	for {
+		fmt.Fprint(cmd.ErrOrStderr(), prompt)
+		line, err := in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr()) // end the prompt line
+			}
+			return 0, false, nil
+		}
+		if id, convErr := strconv.Atoi(answer); convErr == nil && methodName(id) != "" {
+			return id, id != current, nil
+		}
+		if err != nil {
+			return 0, false, fmt.Errorf("unknown method %q; see 'prayer-times methods'", answer)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Unknown method %q.\n", answer)
+	}
+}
+
+// methodName returns the name of the calculation method with id, or "".
+func methodName(id int) string {
+	for _, m := range CalculationMethods {
+		if m.ID == id {
+			return m.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+)
+
+func TestMethodsPick(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	pick := func(input string) (string, string, int) {
+		var stdout, stderr bytes.Buffer
+		args := append([]string{"methods", "pick"}, meccaArgs(t)...)
+		code := Execute("dev", args, strings.NewReader(input), &stdout, &stderr)
+		return stdout.String(), stderr.String(), code
+	}
+
+	out, stderr, code := pick("42\n4\n")
+	if code != 0 {
+		t.Fatalf("methods pick exited with %d: %s", code, stderr)
+	}
+	for _, want := range []string{"Muslim World League", "Umm Al-Qura University, Makkah", "05:30", "Set method = 4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("methods pick output missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(stderr, `Unknown method "42"`) {
+		t.Errorf("methods pick stderr = %q, want the unknown method reported", stderr)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MethodOrDefault(-1) != 4 {
+		t.Errorf("saved method = %d, want 4", cfg.MethodOrDefault(-1))
+	}
+
+	// Enter, or no answer at all, keeps the method.
+	for _, input := range []string{"\n", ""} {
+		out, stderr, code = pick(input)
+		if code != 0 || strings.Contains(out, "Set method") || !strings.Contains(stderr, "Enter to keep 4") {
+			t.Errorf("methods pick with %q = %d, %q, %q; want nothing saved", input, code, out, stderr)
+		}
+	}
+}
+
+func TestMethodsPickJSON(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"methods", "pick", "--json"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("methods pick --json exited with %d: %s", code, stderr)
+	}
+	var methods []methodPickJSON
+	if err := json.Unmarshal([]byte(out), &methods); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(methods) != len(CalculationMethods) || methods[0].Timings["fajr"] != "05:30" {
+		t.Errorf("methods pick --json = %+v", methods)
+	}
+}
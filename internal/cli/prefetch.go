@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+)
+
+// prefetchMonthDays is how close to the end of a month fetching a day also
+// prefetches the next month.
+const prefetchMonthDays = 3
+
+// prefetchWait bounds how long Execute lets prefetches finish after the
+// command is done.
+const prefetchWait = 5 * time.Second
+
+// prefetches tracks the background prefetches started by prefetchAhead.
+var prefetches sync.WaitGroup
+
+// prefetchAhead caches, in the background, the day after date and, in the
+// last prefetchMonthDays of a month, the whole next month, so the after-Isha
+// lookup of tomorrow and the month rollover are answered from the cache.
+// It is best-effort: failures are ignored.
+func prefetchAhead(ctx context.Context, date time.Time, loc resolvedLocation, method, school int, c *cache.Cache) {
+	if c == nil {
+		return
+	}
+
+	prefetches.Add(1)
+	go func() {
+		defer prefetches.Done()
+
+		tomorrow := date.AddDate(0, 0, 1)
+		if c.LoadTimings(tomorrow, loc.Lat, loc.Lon, loc.City, loc.Country, method, school) == nil &&
+			cachedCalendarDay(tomorrow, loc, method, school, c) == nil {
+			_, _ = fetchDay(ctx, tomorrow, loc, method, school, c)
+		}
+
+		next := time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, date.Location())
+		if date.Day() <= next.AddDate(0, 0, -1).Day()-prefetchMonthDays {
+			return
+		}
+		_, _ = fetchCalendarDays(ctx, next, next.AddDate(0, 1, -1).Day(), loc, method, school, c)
+	}()
+}
+
+// waitForPrefetches waits up to prefetchWait for background prefetches.
+func waitForPrefetches() {
+	done := make(chan struct{})
+	go func() {
+		prefetches.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(prefetchWait):
+	}
+}
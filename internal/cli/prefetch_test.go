@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+)
+
+// TestPrefetchAhead verifies fetching a day caches the next one, and near
+// the end of a month the next month.
+func TestPrefetchAhead(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	tests := []struct {
+		date      time.Time
+		wantMonth bool
+	}{
+		{time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		c, err := cache.New(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		loc := resolvedLocation{Mode: locationCoords, Lat: 21.4225, Lon: 39.8262}
+
+		if _, err := fetchTimings(context.Background(), tt.date, loc, -1, -1, c); err != nil {
+			t.Fatal(err)
+		}
+		prefetches.Wait()
+
+		if c.LoadTimings(tt.date.AddDate(0, 0, 1), loc.Lat, loc.Lon, "", "", -1, -1) == nil {
+			t.Errorf("%s: the next day wasn't prefetched", tt.date.Format("2006-01-02"))
+		}
+		gotMonth := c.LoadCalendar(2026, 4, loc.Lat, loc.Lon, "", "", -1, -1) != nil
+		if gotMonth != tt.wantMonth {
+			t.Errorf("%s: April cached = %v, want %v", tt.date.Format("2006-01-02"), gotMonth, tt.wantMonth)
+		}
+	}
+}
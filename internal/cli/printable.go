@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/pdf"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagExportMosque   string
+	flagExportLogoText string
+	flagExportPageSize string
+)
+
+// pageSizes are the values of export pdf's --page-size, in points.
+var pageSizes = map[string][2]float64{
+	"a4":     {pdf.A4Width, pdf.A4Height},
+	"letter": {pdf.LetterWidth, pdf.LetterHeight},
+}
+
+// sheetRowsPerPage is how many days a printed page holds: a month.
+const sheetRowsPerPage = 31
+
+func newExportPDFCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pdf",
+		Short: "Export a printable monthly timetable as a PDF",
+		Long: `Export a printable prayer timetable as a PDF, one row per day with its
+Hijri date, under a header naming the location and the Hijri months the
+dates fall in. Fridays are shaded.
+
+By default the current month is exported to stdout; use --month/--year or
+--from/--to as with 'export ical'. A range longer than a month continues on
+further pages. --mosque and --logo-text add a heading for a notice board.
+
+The PDF uses the standard Helvetica fonts, so it is in English and letters
+outside Latin-1 are printed without their diacritics. A --mosque or
+--logo-text in another script, such as Arabic, is rejected; 'export image
+--format svg' can show it.`,
+		Example: `  prayer-times export pdf -o timetable.pdf
+  prayer-times export pdf --month 3 --year 2026 --mosque "Masjid An-Nur" -o march.pdf
+  prayer-times export pdf --from 2026-02-18 --to 2026-03-19 --logo-text "Ramadan Mubarak" --page-size letter -o ramadan.pdf`,
+		Args: cobra.NoArgs,
+		RunE: runExportPDF,
+	}
+	addExportRangeFlags(cmd)
+	addSheetFlags(cmd)
+	cmd.Flags().StringVar(&flagExportPageSize, "page-size", "a4", "Paper size (a4 or letter)")
+	cmd.RegisterFlagCompletionFunc("page-size", cobra.FixedCompletions([]string{"a4", "letter"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// addSheetFlags registers the heading flags shared by the timetable exports.
+func addSheetFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagExportMosque, "mosque", "", "Mosque name to print above the timetable")
+	cmd.Flags().StringVar(&flagExportLogoText, "logo-text", "", "Short line of text to print above the mosque name")
+}
+
+func runExportPDF(cmd *cobra.Command, args []string) error {
+	size, ok := pageSizes[strings.ToLower(flagExportPageSize)]
+	if !ok {
+		return fmt.Errorf("invalid --page-size %q: must be a4 or letter", flagExportPageSize)
+	}
+	if err := checkSheetText(pdf.CanPrint, "the PDF's Latin fonts; use Latin letters, or export image --format svg"); err != nil {
+		return err
+	}
+
+	s, err := fetchSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	w, closeFn, err := exportWriter(cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	doc := s.pdf(size[0], size[1])
+	if err := doc.Encode(w); err != nil {
+		closeFn()
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	if err := closeFn(); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+
+	if flagExportOut != "" && flagExportOut != "-" {
+		notices.Infof("Wrote %d days to %s", len(s.Rows), flagExportOut)
+	}
+	return nil
+}
+
+// checkSheetText rejects a --mosque or --logo-text that canPrint says the
+// export's fonts can't print, rather than printing it as "?". where names
+// the fonts and what to do instead.
+func checkSheetText(canPrint func(string) bool, where string) error {
+	for _, f := range []struct{ name, value string }{
+		{"--mosque", flagExportMosque},
+		{"--logo-text", flagExportLogoText},
+	} {
+		if !canPrint(f.value) {
+			return fmt.Errorf("%s %q can't be printed in %s", f.name, f.value, where)
+		}
+	}
+	return nil
+}
+
+// fetchSheet fetches the days of the export range and lays them out as a
+// sheet headed by the --mosque and --logo-text flags.
+func fetchSheet(cmd *cobra.Command) (sheet, error) {
+	ctx := cmd.Context()
+	cfg := effectiveConfig(cmd)
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return sheet{}, err
+	}
+	// The current month is the location's, not the machine's.
+	start, days, err := exportRange(loc.localTime(currentTime()))
+	if err != nil {
+		return sheet{}, err
+	}
+	method := cfg.MethodOrDefault(-1)
+	daysList, err := fetchCalendarDays(ctx, start, days, loc, method, cfg.SchoolOrDefault(-1), c)
+	if err != nil {
+		return sheet{}, err
+	}
+
+	var meta api.Meta
+	if len(daysList) > 0 {
+		meta = daysList[0].Meta
+	}
+	tz := loc.Timezone
+	if tz == "" && len(daysList) > 0 {
+		tz = loc.timezoneFor(meta)
+	}
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return sheet{}, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	s, err := buildSheet(daysList, tzLoc, selectedPrayerNames(cfg), goTimeFormat(cfg))
+	if err != nil {
+		return sheet{}, err
+	}
+	s.Logo, s.Mosque = flagExportLogoText, flagExportMosque
+	s.Location = buildLocationStr(loc, &fetchResult{Meta: meta})
+	if name := meta.Method.Name; name != "" {
+		s.Footer = "Method: " + name
+	} else if name := methodName(method); name != "" {
+		s.Footer = "Method: " + name
+	}
+	return s, nil
+}
+
+// sheet is a printable timetable: a heading over a grid of days.
+type sheet struct {
+	Logo     string
+	Mosque   string
+	Title    string // e.g. "Prayer Times — March 2026"
+	Location string
+	Hijri    string // the Hijri months the days fall in, e.g. "Ramaḍān – Shawwāl 1447 AH"
+	Footer   string
+	Headers  []string
+	Rows     [][]string
+	Fridays  []bool // which rows are Fridays, to shade
+}
+
+// buildSheet lays out daysList as a sheet of the selected prayers' times,
+// with each day's Gregorian and Hijri dates.
+func buildSheet(daysList []dayData, tzLoc *time.Location, selected []string, goTimeFmt string) (sheet, error) {
+	s := sheet{Headers: append([]string{"Date", "Day", "Hijri"}, selected...)}
+	var first, last api.HijriDate
+	for i, dd := range daysList {
+		day := calendarDay(dd.Date, tzLoc)
+		parsed, err := prayer.ParseTimings(dd.Timings, day, tzLoc, selected)
+		if err != nil {
+			return sheet{}, err
+		}
+		h := adjustHijri(dd.DateInfo).Hijri
+		if i == 0 {
+			first = h
+		}
+		last = h
+
+		hijriCell := ""
+		if n, err := strconv.Atoi(h.Day); err == nil && h.Month.En != "" {
+			hijriCell = fmt.Sprintf("%d %s", n, h.Month.En)
+		}
+		row := []string{day.Format("02 Jan"), day.Format("Mon"), hijriCell}
+		for _, p := range parsed {
+			row = append(row, p.Time.Format(goTimeFmt))
+		}
+		s.Rows = append(s.Rows, row)
+		s.Fridays = append(s.Fridays, day.Weekday() == time.Friday)
+	}
+	if len(daysList) > 0 {
+		s.Title = "Prayer Times — " + sheetPeriod(daysList[0].Date, daysList[len(daysList)-1].Date)
+		s.Hijri = hijriSpan(first, last)
+	}
+	return s, nil
+}
+
+// sheetPeriod names the dates from a to b: their month when they span one
+// whole month, otherwise the two dates.
+func sheetPeriod(a, b time.Time) string {
+	if a.Day() == 1 && b.Equal(a.AddDate(0, 1, -1)) {
+		return a.Format("January 2006")
+	}
+	if a.Year() == b.Year() {
+		return a.Format("2 Jan") + " – " + b.Format("2 Jan 2006")
+	}
+	return a.Format("2 Jan 2006") + " – " + b.Format("2 Jan 2006")
+}
+
+// hijriSpan names the Hijri months from a to b, e.g. "Ramaḍān – Shawwāl
+// 1447 AH", or "" when either is unknown.
+func hijriSpan(a, b api.HijriDate) string {
+	if a.Month.En == "" || b.Month.En == "" {
+		return ""
+	}
+	switch {
+	case a.Month.En == b.Month.En && a.Year == b.Year:
+		return fmt.Sprintf("%s %s AH", a.Month.En, a.Year)
+	case a.Year == b.Year:
+		return fmt.Sprintf("%s – %s %s AH", a.Month.En, b.Month.En, b.Year)
+	}
+	return fmt.Sprintf("%s %s – %s %s AH", a.Month.En, a.Year, b.Month.En, b.Year)
+}
+
+// pdf lays s out on pages of the given size, a month of rows to a page,
+// repeating the heading on each.
+func (s sheet) pdf(width, height float64) *pdf.Document {
+	const (
+		margin   = 40.0
+		fontSize = 9.0
+		padding  = 12.0
+	)
+	doc := pdf.New(width, height)
+	doc.Title = s.Title
+
+	tableWidth := width - 2*margin
+	cols := s.columnWidths(func(text string, bold bool) float64 {
+		return pdf.TextWidth(text, fontSize, bold)
+	}, padding, tableWidth)
+
+	pages := (len(s.Rows) + sheetRowsPerPage - 1) / sheetRowsPerPage
+	for n := range max(pages, 1) {
+		page := doc.AddPage()
+		y := height - margin
+
+		for _, line := range s.heading() {
+			size := fontSize * line.Size
+			y -= size * 1.5
+			page.TextCenter(width/2, y, size, line.Bold, line.Text)
+		}
+		y -= 18
+
+		rows := s.Rows[min(n*sheetRowsPerPage, len(s.Rows)):min((n+1)*sheetRowsPerPage, len(s.Rows))]
+		fridays := s.Fridays[min(n*sheetRowsPerPage, len(s.Fridays)):]
+		rowHeight := min(18, (y-margin-20)/float64(sheetRowsPerPage+1))
+		size := min(fontSize, rowHeight*0.6)
+		cells := func(top float64, values []string, bold bool) {
+			x := margin
+			baseline := top - rowHeight/2 - size*0.35
+			for i, v := range values {
+				if i < 3 {
+					page.Text(x+padding/2, baseline, size, bold, v)
+				} else {
+					page.TextCenter(x+cols[i]/2, baseline, size, bold, v)
+				}
+				x += cols[i]
+			}
+		}
+
+		top := y
+		page.Rect(margin, top-rowHeight, tableWidth, rowHeight, 0.85)
+		cells(top, s.Headers, true)
+		for i, row := range rows {
+			rowTop := top - float64(i+1)*rowHeight
+			if fridays[i] {
+				page.Rect(margin, rowTop-rowHeight, tableWidth, rowHeight, 0.93)
+			}
+			page.Line(margin, rowTop, margin+tableWidth, rowTop, 0.5, 0.7)
+			cells(rowTop, row, false)
+		}
+		bottom := top - float64(len(rows)+1)*rowHeight
+		page.Line(margin, bottom, margin+tableWidth, bottom, 0.5, 0.7)
+		x := margin
+		for _, w := range append([]float64{0}, cols...) {
+			x += w
+			page.Line(x, top, x, bottom, 0.5, 0.7)
+		}
+		page.Line(margin, top, margin+tableWidth, top, 0.5, 0.7)
+
+		footer := s.Footer
+		if pages > 1 {
+			footer = strings.TrimPrefix(footer+fmt.Sprintf(" · Page %d of %d", n+1, pages), " · ")
+		}
+		page.Text(margin, margin-16, 8, false, footer)
+	}
+	return doc
+}
+
+// sheetLine is a line of a sheet's heading, sized relative to the table's text.
+type sheetLine struct {
+	Text string
+	Size float64
+	Bold bool
+}
+
+// heading returns the lines above s's table.
+func (s sheet) heading() []sheetLine {
+	var lines []sheetLine
+	if s.Logo != "" {
+		lines = append(lines, sheetLine{s.Logo, 1.75, true})
+	}
+	if s.Mosque != "" {
+		lines = append(lines, sheetLine{s.Mosque, 2.25, true})
+	}
+	lines = append(lines, sheetLine{s.Title, 1.5, true})
+	for _, text := range []string{s.Location, s.Hijri} {
+		if text != "" {
+			lines = append(lines, sheetLine{text, 1.1, false})
+		}
+	}
+	return lines
+}
+
+// columnWidths sizes each of s's columns to its widest cell as measured,
+// plus padding, then shares out what's left of width between them.
+func (s sheet) columnWidths(measure func(text string, bold bool) float64, padding, width float64) []float64 {
+	cols := make([]float64, len(s.Headers))
+	for i, h := range s.Headers {
+		cols[i] = measure(h, true) + padding
+	}
+	for _, row := range s.Rows {
+		for i, cell := range row {
+			cols[i] = max(cols[i], measure(cell, false)+padding)
+		}
+	}
+	used := 0.0
+	for _, w := range cols {
+		used += w
+	}
+	for i := range cols {
+		cols[i] += max(width-used, 0) / float64(len(cols))
+	}
+	return cols
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportPDF(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	path := filepath.Join(t.TempDir(), "march.pdf")
+	_, stderr, code := runCLI(t, append([]string{"export", "pdf", "--month", "3", "--year", "2026", "--mosque", "Masjid An-Nur", "--logo-text", "Ramadan Mubarak", "-o", path}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("export pdf exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "Wrote 31 days to "+path) {
+		t.Errorf("stderr = %q, want a notice of 31 days written", stderr)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"%PDF-1.4",
+		"/Count 1 ",
+		"(Masjid An-Nur) Tj",
+		"(Ramadan Mubarak) Tj",
+		"(Prayer Times \x97 March 2026) Tj",
+		"(21.4225, 39.8262) Tj",
+		"(Ramadan \x96 Shawwal 1447 AH) Tj",
+		"(01 Mar) Tj", "(Sun) Tj", "(31 Mar) Tj",
+		"(Fajr) Tj", "(05:30) Tj", "(18:10) Tj",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PDF missing %q", want)
+		}
+	}
+	// The Hijri date of each day.
+	if !strings.Contains(out, "(1 Shawwal) Tj") {
+		t.Errorf("PDF has no Hijri day 1 Shawwal")
+	}
+	// Four Fridays in March 2026 are shaded.
+	if n := strings.Count(out, "0.93 g"); n != 4 {
+		t.Errorf("PDF shades %d rows, want 4 Fridays", n)
+	}
+
+	_, stderr, code = runCLI(t, append([]string{"export", "pdf", "--page-size", "a3"}, meccaArgs(t)...)...)
+	if code == 0 || !strings.Contains(stderr, `invalid --page-size "a3"`) {
+		t.Errorf("export pdf --page-size a3 = %d, %q; want an error", code, stderr)
+	}
+}
+
+func TestExportPDFPages(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"export", "pdf", "--from", "2026-03-01", "--to", "2026-04-15", "--page-size", "letter"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("export pdf exited with %d: %s", code, stderr)
+	}
+	for _, want := range []string{"%PDF-1.4", "/Count 2 /MediaBox [0 0 612 792]", "(Prayer Times \x97 1 Mar \x96 15 Apr 2026) Tj", "Page 2 of 2", "(15 Apr) Tj"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PDF missing %q", want)
+		}
+	}
+}
+
+// TestExportPDFArabicMosque checks that a mosque name the PDF's fonts can't
+// print is rejected rather than printed as "?".
+func TestExportPDFArabicMosque(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"export", "pdf", "--month", "3", "--year", "2026", "--mosque", "مسجد النور"}, meccaArgs(t)...)...)
+	if code == 0 || out != "" {
+		t.Fatalf("export pdf with an Arabic --mosque exited with %d, wrote %d bytes", code, len(out))
+	}
+	if !strings.Contains(stderr, `--mosque "مسجد النور" can't be printed`) || !strings.Contains(stderr, "--format svg") {
+		t.Errorf("stderr = %q, want the mosque name rejected and SVG suggested", stderr)
+	}
+}
+
+func TestSheetPeriod(t *testing.T) {
+	d := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02", s)
+		return t
+	}
+	tests := []struct {
+		a, b, want string
+	}{
+		{"2026-02-01", "2026-02-28", "February 2026"},
+		{"2026-02-01", "2026-02-27", "1 Feb – 27 Feb 2026"},
+		{"2026-12-20", "2027-01-10", "20 Dec 2026 – 10 Jan 2027"},
+	}
+	for _, tt := range tests {
+		if got := sheetPeriod(d(tt.a), d(tt.b)); got != tt.want {
+			t.Errorf("sheetPeriod(%s, %s) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named location profiles",
+		Long: `Save locations under a name and use them with --profile on any command.
+A profile sets some of: ` + strings.Join(config.ProfileKeys, ", ") + `. It applies over the
+config file and --preset; other flags still override it.
+
+Repeating --profile without a command compares today's times side by side.`,
+		Example: `  prayer-times config profile add makkah --city Makkah --country SA --method 4
+  prayer-times config profile add home --latitude 51.5074 --longitude -0.1278
+  prayer-times next --profile makkah
+  prayer-times --profile home --profile makkah`,
+		Args: cobra.NoArgs,
+		RunE: runProfileList,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add <name>",
+		Short: "Save the location given by --city/--country or --latitude/--longitude",
+		Long:  "Save a profile from the global flags " + strings.Join(config.ProfileKeys, ", ") + ".\nAn existing profile of the same name is replaced.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runProfileAdd,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List saved profiles",
+		Args:  cobra.NoArgs,
+		RunE:  runProfileList,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a profile",
+		Args:    cobra.ExactArgs(1),
+		RunE:    runProfileRemove,
+	})
+
+	return cmd
+}
+
+// runProfileAdd saves the profile keys given as flags under the name in args.
+func runProfileAdd(cmd *cobra.Command, args []string) error {
+	values := make(map[string]string)
+	for _, key := range config.ProfileKeys {
+		if f := cmd.Flags().Lookup(key); f != nil && f.Changed {
+			values[key] = f.Value.String()
+		}
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no location given; use --city and --country, or --latitude and --longitude")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.AddProfile(args[0], values); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved profile %s: %s\n", args[0], describeProfile(values))
+	return nil
+}
+
+// runProfileRemove deletes the profile named in args.
+func runProfileRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.RemoveProfile(args[0]); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed profile %s.\n", args[0])
+	return nil
+}
+
+// runProfileList prints the saved profiles.
+func runProfileList(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if FlagJSON {
+		profiles := cfg.Profiles
+		if profiles == nil {
+			profiles = map[string]map[string]string{}
+		}
+		data, err := json.MarshalIndent(profiles, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	names := cfg.ProfileNames()
+	if len(names) == 0 {
+		fmt.Fprintln(w, "No profiles. Add one with 'prayer-times config profile add <name> --city <city> --country <country>'.")
+		return nil
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, describeProfile(cfg.Profiles[name]))
+	}
+	return nil
+}
+
+// describeProfile summarises a profile's values, e.g. "Makkah, SA (method 4)".
+func describeProfile(values map[string]string) string {
+	var desc string
+	if values["latitude"] != "" {
+		desc = values["latitude"] + ", " + values["longitude"]
+	} else {
+		desc = values["city"] + ", " + values["country"]
+	}
+	var extra []string
+	if v := values["method"]; v != "" {
+		extra = append(extra, "method "+v)
+	}
+	if v := values["school"]; v != "" {
+		extra = append(extra, "school "+v)
+	}
+	if len(extra) > 0 {
+		desc += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return desc
+}
+
+// runProfileCompare shows today's times for several profiles side by side.
+// Each profile applies over a copy of the merged config.
+func runProfileCompare(cmd *cobra.Command, names []string) error {
+	ctx := cmd.Context()
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	c := openCache(cfg)
+
+	targets := make([]compareTarget, 0, len(names))
+	for _, name := range names {
+		pc := *cfg
+		if err := pc.ApplyProfile(name); err != nil {
+			return err
+		}
+		t, err := newCompareTarget(ctx, name, &pc, c)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		targets = append(targets, t)
+	}
+
+	cols := fetchCompareColumns(ctx, targets, currentTime(), selectedPrayers, c)
+	return printCompare(cmd.OutOrStdout(), "Prayer Times by Profile", cols, selectedPrayers, goTimeFormat(cfg))
+}
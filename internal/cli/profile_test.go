@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestProfiles verifies profiles can be saved, used, compared and removed.
+func TestProfiles(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	cacheDir := t.TempDir()
+
+	for _, args := range [][]string{
+		{"config", "profile", "add", "makkah", "--city", "Makkah", "--country", "SA", "--method", "4"},
+		{"config", "profile", "add", "home", "--latitude", "21.4225", "--longitude", "39.8262"},
+	} {
+		if _, stderr, code := runCLI(t, args...); code != 0 {
+			t.Fatalf("%s exited with %d: %s", strings.Join(args, " "), code, stderr)
+		}
+	}
+	if _, _, code := runCLI(t, "config", "profile", "add", "work", "--method", "4"); code == 0 {
+		t.Error("a profile without a location should be rejected")
+	}
+
+	out, _, _ := runCLI(t, "config", "profile", "list")
+	if !strings.Contains(out, "makkah") || !strings.Contains(out, "Makkah, SA (method 4)") || !strings.Contains(out, "home") {
+		t.Errorf("config profile list = %q, want both profiles", out)
+	}
+
+	// The profile supplies the location; without it auto-detection would run.
+	if _, stderr, code := runCLI(t, "next", "--profile", "makkah", "--cache-dir", cacheDir); code != 0 {
+		t.Fatalf("next --profile makkah exited with %d: %s", code, stderr)
+	}
+	if _, _, code := runCLI(t, "next", "--profile", "work", "--cache-dir", cacheDir); code == 0 {
+		t.Error("an unknown --profile should fail")
+	}
+	if _, _, code := runCLI(t, "next", "--profile", "makkah", "--profile", "home", "--cache-dir", cacheDir); code == 0 {
+		t.Error("repeating --profile on a subcommand should fail")
+	}
+
+	out, stderr, code := runCLI(t, "--profile", "home", "--profile", "makkah", "--json", "--cache-dir", cacheDir)
+	if code != 0 {
+		t.Fatalf("comparing profiles exited with %d: %s", code, stderr)
+	}
+	var cols []compareJSONColumn
+	if err := json.Unmarshal([]byte(out), &cols); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(cols) != 2 || cols[0].Label != "home" || cols[1].Label != "makkah" {
+		t.Fatalf("compare = %+v, want home then makkah", cols)
+	}
+	if cols[1].Location != "Makkah, SA" || cols[1].Timings["maghrib"] != "18:10" {
+		t.Errorf("makkah column = %+v, want Makkah, SA with Maghrib at 18:10", cols[1])
+	}
+
+	if _, stderr, code := runCLI(t, "config", "profile", "remove", "home"); code != 0 {
+		t.Fatalf("config profile remove exited with %d: %s", code, stderr)
+	}
+	if out, _, _ := runCLI(t, "config", "profile", "list"); strings.Contains(out, "home") {
+		t.Errorf("config profile list after remove = %q", out)
+	}
+}
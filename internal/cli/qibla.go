@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/qibla"
+	"github.com/spf13/cobra"
+)
+
+func newQiblaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "qibla",
+		Short: "Show the Qibla direction for your location",
+		Long: `Show the direction of the Kaaba as a compass bearing from true north,
+along with the great-circle distance to Mecca.`,
+		Args: cobra.NoArgs,
+		RunE: runQibla,
+		Example: `  prayer-times qibla
+  prayer-times qibla --city Istanbul --country TR --json`,
+	}
+}
+
+// qiblaJSON is the JSON output structure for the qibla command.
+type qiblaJSON struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Direction  float64 `json:"direction"` // degrees clockwise from true north
+	Compass    string  `json:"compass"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// buildQiblaJSON assembles the JSON structure shared by the qibla command and the HTTP server.
+func buildQiblaJSON(lat, lon float64) qiblaJSON {
+	dir := qibla.Direction(lat, lon)
+	return qiblaJSON{
+		Latitude:   lat,
+		Longitude:  lon,
+		Direction:  dir,
+		Compass:    qibla.Compass(dir),
+		DistanceKm: qibla.Distance(lat, lon),
+	}
+}
+
+// locationCoordinates returns coordinates for loc. City locations have none
+// until fetched, so today's timings are used to read them from the API metadata.
+func locationCoordinates(ctx context.Context, loc resolvedLocation, method, school int, c *cache.Cache) (float64, float64, error) {
+	if loc.Mode != locationCity {
+		return loc.Lat, loc.Lon, nil
+	}
+	result, err := fetchTimings(ctx, currentTime(), loc, method, school, c)
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.Meta.Latitude, result.Meta.Longitude, nil
+}
+
+func runQibla(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	cfg := effectiveConfig(cmd)
+
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+
+	lat, lon, err := locationCoordinates(ctx, loc, cfg.MethodOrDefault(-1), cfg.SchoolOrDefault(-1), c)
+	if err != nil {
+		return err
+	}
+	out := buildQiblaJSON(lat, lon)
+
+	if FlagJSON {
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Qibla Direction"))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Accent(fmt.Sprintf("%.1f° %s", out.Direction, out.Compass)))
+	fmt.Fprintf(w, "  %s\n", display.Dim(fmt.Sprintf("from true north, %.0f km to Mecca", out.DistanceKm)))
+	fmt.Fprintf(w, "  %s\n", display.Dim(fmt.Sprintf("%.4f, %.4f", lat, lon)))
+	fmt.Fprintln(w)
+	return nil
+}
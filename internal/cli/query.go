@@ -3,7 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
@@ -19,51 +19,45 @@ func newQueryCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "query <prayer>",
 		Short: "Query a specific prayer time",
-		Long:  "Query a specific prayer time for today, or across multiple days with --days.\n\nValid prayer names: Fajr, Sunrise, Dhuhr, Asr, Sunset, Maghrib, Isha, Imsak, Midnight, Firstthird, Lastthird",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runQuery,
+		Long:  "Query a specific prayer time for today (or --date), or across multiple days with --days.\n\nValid prayer names: Fajr, Sunrise, Dhuhr, Asr, Sunset, Maghrib, Isha, Imsak, Midnight, Firstthird, Lastthird,\nor another spelling such as Zuhr or Maghreb; see 'prayer-times names'.",
+		Example: `  prayer-times query Maghrib
+  prayer-times query fajr --days week
+  prayer-times query Isha --date 2026-03-20 --json | jq -r .time`,
+		Args: cobra.ExactArgs(1),
+		RunE: runQuery,
+
+		ValidArgsFunction: completePrayerNames,
 	}
 
 	cmd.Flags().StringVar(&flagQueryDays, "days", "", "Number of days to show (or 'week'/'month')")
+	addDateFlag(cmd)
 
 	return cmd
 }
 
 func runQuery(cmd *cobra.Command, args []string) error {
-	prayerName := args[0]
-
-	// Validate prayer name.
-	valid := false
-	for _, name := range prayer.AllPrayerNames {
-		if strings.EqualFold(name, prayerName) {
-			prayerName = name // normalize case
-			valid = true
-			break
-		}
-	}
-	if !valid {
-		return fmt.Errorf("unknown prayer %q; valid names: %s", args[0], strings.Join(prayer.AllPrayerNames, ", "))
+	ctx := cmd.Context()
+	prayerName, ok := prayer.Lookup(args[0])
+	if !ok {
+		return unknownPrayerError(args[0])
 	}
 
 	cfg := effectiveConfig(cmd)
 
-	goTimeFmt := "15:04"
-	if cfg.TimeFormat == "12h" {
-		goTimeFmt = "3:04 PM"
-	}
+	goTimeFmt := goTimeFormat(cfg)
 
-	c, err := cache.New(cfg.CacheDir)
-	if err != nil {
-		c = nil
-		fmt.Fprintf(os.Stderr, "warning: cache disabled: %v\n", err)
-	}
+	c := openCache(cfg)
 
-	now := time.Now()
+	now := currentTime()
 
-	loc, err := resolveLocation(cfg.Latitude, cfg.Longitude, cfg.City, cfg.Country, c)
+	loc, err := resolveLocation(ctx, cfg, c)
 	if err != nil {
 		return err
 	}
+	now = loc.localTime(now)
+	if now, err = applyDateFlag(now); err != nil {
+		return err
+	}
 
 	method := cfg.MethodOrDefault(-1)
 	school := cfg.SchoolOrDefault(-1)
@@ -94,15 +88,15 @@ func runQuery(cmd *cobra.Command, args []string) error {
 }
 
 func runQuerySingleDay(cmd *cobra.Command, prayerName string, now time.Time, loc resolvedLocation, method, school int, c *cache.Cache, goTimeFmt string) error {
-	result, err := fetchTimings(now, loc, method, school, c)
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
 	if err != nil {
 		return err
 	}
 
-	tz := loc.Timezone
-	if tz == "" {
-		tz = result.Meta.Timezone
-	}
+	tz := loc.timezoneFor(result.Meta)
 	tzLoc, err := time.LoadLocation(tz)
 	if err != nil {
 		return fmt.Errorf("invalid timezone %q: %w", tz, err)
@@ -132,23 +126,26 @@ func runQuerySingleDay(cmd *cobra.Command, prayerName string, now time.Time, loc
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(w, string(data))
 		return nil
 	}
 
-	fmt.Printf("%s %s\n", prayerName, timeStr)
+	fmt.Fprintf(w, "%s %s\n", prayerName, timeStr)
 	return nil
 }
 
 func runQueryMultiDay(cmd *cobra.Command, prayerName string, days int, now time.Time, loc resolvedLocation, method, school int, c *cache.Cache, goTimeFmt string) error {
-	daysList, err := fetchCalendarDays(now, days, loc, method, school, c)
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	daysList, err := fetchCalendarDays(ctx, now, days, loc, method, school, c)
 	if err != nil {
 		return err
 	}
 
 	tz := loc.Timezone
 	if tz == "" && len(daysList) > 0 {
-		tz = daysList[0].Meta.Timezone
+		tz = loc.timezoneFor(daysList[0].Meta)
 	}
 	tzLoc, err := time.LoadLocation(tz)
 	if err != nil {
@@ -161,15 +158,15 @@ func runQueryMultiDay(cmd *cobra.Command, prayerName string, days int, now time.
 	locationStr := buildLocationStr(loc, &fetchResult{Meta: daysList[0].Meta})
 
 	if FlagJSON {
-		return printQueryJSON(daysList, prayerName, locationStr, tz, goTimeFmt, tzLoc)
+		return printQueryJSON(w, daysList, prayerName, locationStr, tz, goTimeFmt, tzLoc)
 	}
 
 	// Rich terminal output.
-	fmt.Println()
-	fmt.Printf("  %s\n", display.Bold(fmt.Sprintf("%s Times \u2014 %d Days", prayerName, days)))
-	fmt.Println()
-	fmt.Printf("  %s\n", locationStr)
-	fmt.Println()
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(fmt.Sprintf("%s Times \u2014 %d Days", prayerName, days)))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", locationStr)
+	fmt.Fprintln(w)
 
 	tbl := display.NewTable([]string{"Date", prayerName})
 
@@ -185,6 +182,9 @@ func runQueryMultiDay(cmd *cobra.Command, prayerName string, days int, now time.
 		timeStr := ""
 		if len(parsed) > 0 {
 			timeStr = parsed[0].Time.Format(goTimeFmt)
+			if parsed[0].Time.Before(now) {
+				timeStr = display.Dim(timeStr)
+			}
 		}
 
 		tbl.AddRow([]string{dateLabel, timeStr})
@@ -194,8 +194,8 @@ func runQueryMultiDay(cmd *cobra.Command, prayerName string, days int, now time.
 		}
 	}
 
-	fmt.Print(tbl.Render())
-	fmt.Println()
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
 	return nil
 }
 
@@ -218,7 +218,7 @@ type queryJSONDay struct {
 	Time  string `json:"time"`
 }
 
-func printQueryJSON(daysList []dayData, prayerName, locationStr, tz, goTimeFmt string, tzLoc *time.Location) error {
+func printQueryJSON(w io.Writer, daysList []dayData, prayerName, locationStr, tz, goTimeFmt string, tzLoc *time.Location) error {
 	out := queryJSONMulti{
 		Location: todayJSONLocation{
 			Timezone:  tz,
@@ -256,6 +256,6 @@ func printQueryJSON(daysList []dayData, prayerName, locationStr, tz, goTimeFmt s
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
-	fmt.Println(string(data))
+	fmt.Fprintln(w, string(data))
 	return nil
 }
@@ -2,9 +2,21 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/archive"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
 	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/diag"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/hijri"
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/timing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -15,32 +27,156 @@ var (
 	FlagCountry    string
 	FlagLatitude   float64
 	FlagLongitude  float64
+	FlagTimezone   string
 	FlagMethod     int
 	FlagSchool     int
 	FlagJSON       bool
 	FlagCacheDir   string
 	FlagTimeFormat string
+	FlagLang       string
 	FlagPrayers    string
+	FlagQuiet      bool
+	FlagNoWarning  bool
+	FlagNoColor    bool
+	FlagTiming     bool
+	FlagTimeout    time.Duration
+	FlagRetries    int
+
+	FlagAllowInsecureGeo bool
+
+	FlagFajrAngle    float64
+	FlagMaghribAngle float64
+	FlagIshaAngle    float64
+	FlagTune         string
+
+	FlagLatitudeAdjustment string
+	FlagMidnightMode       string
+	FlagShafaq             string
+
+	FlagPreset   string
+	FlagProfiles []string
 )
 
 // loadedConfig holds the config loaded during PersistentPreRunE.
 // Available to all subcommand handlers.
 var loadedConfig *config.Config
 
+// notices receives warnings and status messages; primary output goes to stdout.
+// Commands that run unattended may redirect it (see serve --log-file).
+var notices = diag.Stderr()
+
+// methodSettings is the Al Adhan methodSettings value for custom angles, or ""
+// when the chosen method is used as-is. Set during PersistentPreRunE.
+var methodSettings string
+
+// tuneSettings is the Al Adhan tune value for per-prayer offsets, or "" when
+// none are set. Set during PersistentPreRunE.
+var tuneSettings string
+
+// latitudeAdjustment is the Al Adhan latitudeAdjustmentMethod ID, or 0 to
+// leave it to the API. Set during PersistentPreRunE.
+var latitudeAdjustment int
+
+// midnightMode is the Al Adhan midnightMode value, or "" to leave it to the
+// method. Set during PersistentPreRunE.
+var midnightMode string
+
+// shafaq is the Moonsighting Committee shafaq, or "" to leave it to the API.
+// Set during PersistentPreRunE.
+var shafaq string
+
+// timezoneOverride is the IANA timezone the user asked for with --timezone
+// or the timezone config key, or "" to use the location's own. Set during
+// PersistentPreRunE.
+var timezoneOverride string
+
+// hijriAdjustment is the number of days added to Hijri dates, from the
+// hijri_adjustment config key. Set during PersistentPreRunE.
+var hijriAdjustment int
+
+// apiRetries and apiRetryBackoff configure retrying failed API requests, from
+// --retries and the retries and retry_backoff config keys. Set during
+// PersistentPreRunE.
+var (
+	apiRetries      int
+	apiRetryBackoff time.Duration
+)
+
+// apiBaseURL overrides the Al Adhan API endpoint, and $PRAYER_TIMES_API_URL,
+// when set. Tests point it at an httptest server.
+var apiBaseURL string
+
+// timer adds up where the current run's time goes, for --timing. Execute
+// starts it; timedCommand is the command path it is recorded under.
+var (
+	timer        *timing.Recorder
+	timedCommand string
+)
+
+// dataArchive is the permanent timings archive, or nil when archiving is disabled.
+var dataArchive *archive.Archive
+
 // NewRootCmd creates the root command for the prayer-times CLI.
 // The version parameter is set by the calling binary via ldflags.
 func NewRootCmd(version string) *cobra.Command {
 	rootCmd := &cobra.Command{
-		Use:     "prayer-times",
-		Short:   "Islamic prayer times CLI",
-		Long:    "A full-featured CLI for Islamic prayer times powered by the Al Adhan API.",
+		Use:   "prayer-times",
+		Short: "Islamic prayer times CLI",
+		Long:  "A full-featured CLI for Islamic prayer times powered by the Al Adhan API.",
+		Example: `  prayer-times                                  # today's schedule
+  prayer-times --city London --country UK next
+  prayer-times list 7 --method 2
+  prayer-times examples                         # more, for tmux, bars, cron and jq`,
 		Version: version,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			defer timer.Start(timing.Config)()
+			timedCommand = cmd.CommandPath()
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 			loadedConfig = cfg
+			notices = noticeChannel(cmd.ErrOrStderr())
+			if FlagNoColor {
+				display.SetEnabled(false)
+			}
+
+			if FlagTimeout < 0 {
+				return fmt.Errorf("invalid --timeout %s: must not be negative", FlagTimeout)
+			}
+			if err := applyTestEnv(); err != nil {
+				return err
+			}
+
+			applyCommandDefaults(cmd, cfg)
+
+			if _, ok := config.Presets[FlagPreset]; FlagPreset != "" && !ok {
+				return fmt.Errorf("invalid --preset %q: must be one of %s", FlagPreset, strings.Join(config.PresetNames(), ", "))
+			}
+			for _, name := range FlagProfiles {
+				if _, ok := cfg.Profiles[name]; !ok {
+					return fmt.Errorf("unknown profile %q; see 'prayer-times config profile list'", name)
+				}
+			}
+			if len(FlagProfiles) > 1 && cmd.HasParent() {
+				return fmt.Errorf("--profile can only be repeated without a command, to compare profiles")
+			}
+
+			if err := applySettings(effectiveConfig(cmd)); err != nil {
+				return err
+			}
+
+			// Open the permanent archive when enabled (best-effort).
+			dataArchive = nil
+			if cfg.Archive {
+				a, err := archive.New("")
+				if err != nil {
+					notices.Warnf("archive disabled: %v", err)
+				} else {
+					dataArchive = a
+				}
+			}
 			return nil
 		},
 		// Default action: show today's prayer schedule.
@@ -55,39 +191,236 @@ func NewRootCmd(version string) *cobra.Command {
 	pf.StringVar(&FlagCountry, "country", "", "Override country")
 	pf.Float64Var(&FlagLatitude, "latitude", 0, "Override latitude")
 	pf.Float64Var(&FlagLongitude, "longitude", 0, "Override longitude")
+	pf.StringVar(&FlagTimezone, "timezone", "", "Show times in this IANA timezone, e.g. Europe/London (overrides config)")
 	pf.IntVar(&FlagMethod, "method", -1, "Override calculation method (0-23)")
 	pf.IntVar(&FlagSchool, "school", -1, "Override school (0=Shafi, 1=Hanafi)")
+	pf.Float64Var(&FlagFajrAngle, "fajr-angle", 0, "Custom Fajr angle in degrees (uses the custom method)")
+	pf.Float64Var(&FlagMaghribAngle, "maghrib-angle", 0, "Custom Maghrib angle in degrees (uses the custom method)")
+	pf.Float64Var(&FlagIshaAngle, "isha-angle", 0, "Custom Isha angle in degrees (uses the custom method)")
+	pf.StringVar(&FlagTune, "tune", "", "Per-prayer minute offsets, e.g. Fajr:+3,Asr:-2 (overrides config)")
+	pf.StringVar(&FlagLatitudeAdjustment, "latitude-adjustment", "", "High-latitude rule: middle-of-night, one-seventh or angle-based")
+	pf.StringVar(&FlagMidnightMode, "midnight-mode", "", "Midnight calculation: standard or jafari")
+	pf.StringVar(&FlagShafaq, "shafaq", "", "Isha twilight for method 15: general, ahmer or abyad")
+	pf.StringVar(&FlagPreset, "preset", "", "Apply a settings bundle over the config: hajj or umrah")
+	pf.StringArrayVar(&FlagProfiles, "profile", nil, "Use a saved location profile (see 'config profile'); repeat to compare today's times")
 	pf.BoolVar(&FlagJSON, "json", false, "Output as JSON (where supported)")
+	pf.DurationVar(&FlagTimeout, "timeout", api.DefaultTimeout, "Give up on each request to the prayer times API after this long (0 for no limit)")
+	pf.IntVar(&FlagRetries, "retries", api.DefaultRetries, "Retry failed prayer times API requests this many times (overrides config)")
+	pf.BoolVar(&FlagAllowInsecureGeo, "allow-insecure-geo", false, "Allow location detection over plain HTTP (ip-api.com without geo_api_key)")
 	pf.StringVar(&FlagCacheDir, "cache-dir", "", "Cache directory (default: ~/.cache/prayer-times/)")
 	pf.StringVar(&FlagTimeFormat, "time-format", "", "Time format: 12h or 24h (overrides config)")
+	pf.StringVar(&FlagLang, "lang", "", "Language of the schedule and notifications: auto, en, ar, tr, ur, id or fr (overrides config; auto follows $LANG, then the country)")
 	pf.StringVar(&FlagPrayers, "prayers", "", "Comma-separated list of prayers to track (overrides config)")
+	rootCmd.RegisterFlagCompletionFunc("prayers", completePrayerList)
+	pf.BoolVarP(&FlagQuiet, "quiet", "q", false, "Suppress warnings and status messages on stderr")
+	pf.BoolVar(&FlagNoWarning, "no-warning", false, "Suppress warnings on stderr")
+	pf.BoolVar(&FlagNoColor, "no-color", false, "Plain output without colors, even on a terminal")
+	pf.BoolVar(&FlagTiming, "timing", false, "Print how long the config, cache, network and rendering took on stderr, with p95s over recent runs")
+
+	// Flags for the default (today) action.
+	rootCmd.Flags().BoolVar(&flagReminder, "reminder", false, "Show a daily verse/hadith about prayer under the schedule")
+	rootCmd.Flags().BoolVar(&flagKids, "kids", false, "Kid-friendly display with simple wording and emoji")
+	rootCmd.Flags().BoolVar(&flagTransliterate, "transliterate", false, "Show Arabic prayer names with pronunciation")
+	rootCmd.Flags().BoolVar(&flagEvents, "events", false, "Show Hijri events such as the days of Hajj under the schedule")
+	addDateFlag(rootCmd)
 
 	// Register subcommands.
 	rootCmd.AddCommand(newNextCmd())
+	rootCmd.AddCommand(newCurrentCmd())
+	rootCmd.AddCommand(newStatusCmd())
 	rootCmd.AddCommand(newListCmd())
 	rootCmd.AddCommand(newWeekCmd())
 	rootCmd.AddCommand(newMonthCmd())
 	rootCmd.AddCommand(newQueryCmd())
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newMethodsCmd())
+	rootCmd.AddCommand(newNamesCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newVerifyCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newHijriCmd())
+	rootCmd.AddCommand(newKhatmahCmd())
+	rootCmd.AddCommand(newDataCmd())
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newWatchCmd())
+	rootCmd.AddCommand(newAdhanCmd())
+	rootCmd.AddCommand(newQiblaCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newWorldCmd())
+	rootCmd.AddCommand(newCompareCmd())
+	rootCmd.AddCommand(newSlotsCmd())
+	rootCmd.AddCommand(newTripCmd())
+	rootCmd.AddCommand(newInflightCmd())
+	rootCmd.AddCommand(newBarCmd())
+	rootCmd.AddCommand(newTmuxCmd())
+	rootCmd.AddCommand(newExamplesCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 
 	return rootCmd
 }
 
+// Execute runs the CLI with the given arguments (excluding the program name)
+// and streams, and returns the process exit code. Errors are reported on
+// stderr. The binaries call it with the process's own streams; tests and
+// programs embedding the CLI call it in-process.
+func Execute(version string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	timer, timedCommand = timing.New(), ""
+	rootCmd := NewRootCmd(version)
+	addAliases(rootCmd)
+	rootCmd.SetArgs(args)
+	rootCmd.SetIn(stdin)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	err := rootCmd.Execute()
+	waitForPrefetches()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+	}
+	if FlagTiming {
+		reportTiming(stderr)
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// settings are the calculation and API settings every API request reads,
+// parsed from the merged config.
+type settings struct {
+	methodSettings     string
+	tuneSettings       string
+	latitudeAdjustment int
+	midnightMode       string
+	shafaq             string
+	timezone           string
+	theme              display.Theme
+	retries            int
+	retryBackoff       time.Duration
+	hijriAdjustment    int
+}
+
+// applySettings validates the calculation and API settings in merged and
+// stores them in the package globals every API request reads.
+func applySettings(merged *config.Config) error {
+	s, err := parseSettings(merged)
+	if err != nil {
+		return err
+	}
+	s.apply()
+	return nil
+}
+
+// parseSettings validates the calculation and API settings in merged,
+// leaving the ones in use untouched.
+func parseSettings(merged *config.Config) (settings, error) {
+	var s settings
+	var err error
+	s.methodSettings, err = customMethodSettings(merged)
+	if err != nil {
+		return s, err
+	}
+	offsets, err := config.ParseTune(merged.Tune)
+	if err != nil {
+		return s, err
+	}
+	s.tuneSettings = api.TuneParam(offsets)
+	s.latitudeAdjustment, err = config.ParseLatitudeAdjustment(merged.LatitudeAdjustment)
+	if err != nil {
+		return s, err
+	}
+	s.midnightMode, err = config.ParseMidnightMode(merged.MidnightMode)
+	if err != nil {
+		return s, err
+	}
+	if merged.Shafaq != "" && !config.ValidShafaq(merged.Shafaq) {
+		return s, fmt.Errorf("invalid --shafaq %q: must be one of %s", merged.Shafaq, strings.Join(config.ShafaqOptions, ", "))
+	}
+	s.shafaq = merged.Shafaq
+	if err := config.ValidateTimezone(merged.Timezone); err != nil {
+		return s, err
+	}
+	s.timezone = merged.Timezone
+	s.theme, err = display.ParseTheme(merged.Theme)
+	if err != nil {
+		return s, err
+	}
+	s.retries = api.DefaultRetries
+	if merged.Retries != nil {
+		s.retries = *merged.Retries
+	}
+	if s.retries < 0 || s.retries > config.MaxRetries {
+		return s, fmt.Errorf("invalid --retries %d: must be between 0 and %d", s.retries, config.MaxRetries)
+	}
+	s.retryBackoff, err = config.ParseRetryBackoff(merged.RetryBackoff)
+	if err != nil {
+		return s, err
+	}
+	if s.retryBackoff == 0 {
+		s.retryBackoff = api.DefaultRetryBackoff
+	}
+	if merged.HijriAdjustment < -hijri.MaxAdjustment || merged.HijriAdjustment > hijri.MaxAdjustment {
+		return s, fmt.Errorf("invalid hijri_adjustment %d: must be between -%d and %d", merged.HijriAdjustment, hijri.MaxAdjustment, hijri.MaxAdjustment)
+	}
+	s.hijriAdjustment = merged.HijriAdjustment
+	if _, err := i18n.Parse(merged.Lang); err != nil {
+		return s, fmt.Errorf("invalid --lang %q: must be auto or one of %s", merged.Lang, strings.Join(i18n.Langs, ", "))
+	}
+	return s, nil
+}
+
+// currentSettings returns the settings in use.
+func currentSettings() settings {
+	return settings{
+		methodSettings:     methodSettings,
+		tuneSettings:       tuneSettings,
+		latitudeAdjustment: latitudeAdjustment,
+		midnightMode:       midnightMode,
+		shafaq:             shafaq,
+		timezone:           timezoneOverride,
+		theme:              display.CurrentTheme(),
+		retries:            apiRetries,
+		retryBackoff:       apiRetryBackoff,
+		hijriAdjustment:    hijriAdjustment,
+	}
+}
+
+// apply stores s in the package globals. Nothing may be fetching meanwhile.
+func (s settings) apply() {
+	methodSettings = s.methodSettings
+	tuneSettings = s.tuneSettings
+	latitudeAdjustment = s.latitudeAdjustment
+	midnightMode = s.midnightMode
+	shafaq = s.shafaq
+	timezoneOverride = s.timezone
+	display.SetTheme(s.theme)
+	apiRetries = s.retries
+	apiRetryBackoff = s.retryBackoff
+	hijriAdjustment = s.hijriAdjustment
+}
+
 // PrintVersion prints the version string in the expected format.
 func PrintVersion(version string) string {
 	return fmt.Sprintf("prayer-times %s\n", version)
 }
 
 // effectiveConfig returns the merged configuration values,
-// applying the priority: CLI flags > config file > defaults.
+// applying the priority: CLI flags > command defaults > --profile > --preset >
+// config file > defaults. Command defaults are set as flags in PersistentPreRunE.
 // It uses cobra's Changed() to detect whether a flag was explicitly set.
 func effectiveConfig(cmd *cobra.Command) *config.Config {
-	cfg := loadedConfig
-	if cfg == nil {
-		empty := config.Config{}
-		cfg = &empty
+	return mergeConfig(cmd, loadedConfig)
+}
+
+// mergeConfig returns a copy of file merged as effectiveConfig merges the
+// loaded config. file itself is left as loaded, so it can still be saved.
+func mergeConfig(cmd *cobra.Command, file *config.Config) *config.Config {
+	cfg := &config.Config{}
+	if file != nil {
+		merged := *file
+		cfg = &merged
 	}
 
 	// Apply defaults for unset config values.
@@ -100,6 +433,15 @@ func effectiveConfig(cmd *cobra.Command) *config.Config {
 	flags := cmd.Flags()
 	root := cmd.Root().PersistentFlags()
 
+	if flagWasSet(flags, root, "preset") {
+		// Unknown names are rejected in PersistentPreRunE.
+		_ = cfg.ApplyPreset(FlagPreset)
+	}
+	if len(FlagProfiles) == 1 {
+		// Unknown names are rejected in PersistentPreRunE; several profiles
+		// are compared side by side instead (see runProfileCompare).
+		_ = cfg.ApplyProfile(FlagProfiles[0])
+	}
 	if flagWasSet(flags, root, "city") {
 		cfg.City = FlagCity
 	}
@@ -107,10 +449,13 @@ func effectiveConfig(cmd *cobra.Command) *config.Config {
 		cfg.Country = FlagCountry
 	}
 	if flagWasSet(flags, root, "latitude") {
-		cfg.Latitude = FlagLatitude
+		cfg.Latitude = &FlagLatitude
 	}
 	if flagWasSet(flags, root, "longitude") {
-		cfg.Longitude = FlagLongitude
+		cfg.Longitude = &FlagLongitude
+	}
+	if flagWasSet(flags, root, "timezone") {
+		cfg.Timezone = FlagTimezone
 	}
 	if flagWasSet(flags, root, "method") {
 		cfg.Method = &FlagMethod
@@ -122,9 +467,33 @@ func effectiveConfig(cmd *cobra.Command) *config.Config {
 	} else if cfg.School == nil {
 		cfg.School = defaults.School
 	}
+	if flagWasSet(flags, root, "fajr-angle") {
+		cfg.FajrAngle = FlagFajrAngle
+	}
+	if flagWasSet(flags, root, "maghrib-angle") {
+		cfg.MaghribAngle = FlagMaghribAngle
+	}
+	if flagWasSet(flags, root, "isha-angle") {
+		cfg.IshaAngle = FlagIshaAngle
+	}
+	if flagWasSet(flags, root, "tune") {
+		cfg.Tune = FlagTune
+	}
+	if flagWasSet(flags, root, "latitude-adjustment") {
+		cfg.LatitudeAdjustment = FlagLatitudeAdjustment
+	}
+	if flagWasSet(flags, root, "midnight-mode") {
+		cfg.MidnightMode = FlagMidnightMode
+	}
+	if flagWasSet(flags, root, "shafaq") {
+		cfg.Shafaq = FlagShafaq
+	}
 	if flagWasSet(flags, root, "cache-dir") {
 		cfg.CacheDir = FlagCacheDir
 	}
+	if flagWasSet(flags, root, "retries") {
+		cfg.Retries = &FlagRetries
+	}
 
 	// Time format: CLI flag > config > default ("24h").
 	if flagWasSet(flags, root, "time-format") {
@@ -134,14 +503,203 @@ func effectiveConfig(cmd *cobra.Command) *config.Config {
 		cfg.TimeFormat = defaults.TimeFormat
 	}
 
+	// Language: CLI flag > config > auto (resolved where it's used).
+	if flagWasSet(flags, root, "lang") {
+		cfg.Lang = FlagLang
+	}
+
 	// Prayers: CLI flag > config > leave empty (commands default to DefaultPrayerNames).
 	if flagWasSet(flags, root, "prayers") {
 		cfg.Prayers = FlagPrayers
 	}
+	if flagWasSet(flags, root, "reminder") {
+		cfg.Reminder = flagReminder
+	}
+	if flagWasSet(flags, root, "kids") {
+		cfg.Kids = flagKids
+	}
+	if flagWasSet(flags, root, "transliterate") {
+		cfg.Transliterate = flagTransliterate
+	}
+	if flagWasSet(flags, root, "events") {
+		cfg.Events = flagEvents
+	}
 
 	return cfg
 }
 
+// customMethodSettings validates the merged custom angles and returns the
+// methodSettings value sent to the API, or "" when none are set.
+func customMethodSettings(cfg *config.Config) (string, error) {
+	for _, a := range []struct {
+		name  string
+		value float64
+	}{
+		{"fajr-angle", cfg.FajrAngle},
+		{"maghrib-angle", cfg.MaghribAngle},
+		{"isha-angle", cfg.IshaAngle},
+	} {
+		if a.value < 0 || a.value > 30 {
+			return "", fmt.Errorf("invalid --%s %v: must be between 0 and 30", a.name, a.value)
+		}
+	}
+	return api.CustomMethodSettings(cfg.FajrAngle, cfg.MaghribAngle, cfg.IshaAngle), nil
+}
+
+// newAPIClient returns an API client configured with --timeout, the retry
+// settings and the custom calculation settings: angles, tune offsets,
+// latitude adjustment, midnight mode, shafaq and the timezone override.
+func newAPIClient() *api.Client {
+	client := api.NewClient()
+	if u := os.Getenv(apiURLEnv); u != "" {
+		client.BaseURL = strings.TrimSuffix(u, "/")
+	}
+	if apiBaseURL != "" {
+		client.BaseURL = apiBaseURL
+	}
+	client.Timeout = FlagTimeout
+	client.Retries = apiRetries
+	client.RetryBackoff = apiRetryBackoff
+	client.MethodSettings = methodSettings
+	client.Tune = tuneSettings
+	client.LatitudeAdjustment = latitudeAdjustment
+	client.MidnightMode = midnightMode
+	client.Shafaq = shafaq
+	client.Timezone = timezoneOverride
+	client.Observe = func(d time.Duration) { timer.Add(timing.Network, d) }
+	return client
+}
+
+// openCache opens the cache for cfg. Failure is non-fatal: it warns and
+// returns nil, and callers skip caching.
+func openCache(cfg *config.Config) *cache.Cache {
+	defer timer.Start(timing.Cache)()
+	c, err := cache.New(cfg.CacheDir)
+	if err != nil {
+		notices.Warnf("cache disabled: %v", err)
+		return nil
+	}
+	c.Settings = calculationSettings()
+	c.Limit = cacheLimit(cfg)
+	return c
+}
+
+// cacheLimit returns the cache_max_size setting, warning about and ignoring
+// an invalid one.
+func cacheLimit(cfg *config.Config) cache.Limit {
+	limit, err := cache.ParseLimit(cfg.CacheMaxSize)
+	if err != nil {
+		notices.Warnf("ignoring cache_max_size: %v", err)
+	}
+	return limit
+}
+
+// calculationSettings combines the custom calculation settings into the
+// cache's settings key.
+func calculationSettings() string {
+	settings := methodSettings
+	if tuneSettings != "" {
+		settings += "|tune=" + tuneSettings
+	}
+	if latitudeAdjustment > 0 {
+		settings += fmt.Sprintf("|latadj=%d", latitudeAdjustment)
+	}
+	if midnightMode != "" {
+		settings += "|midnight=" + midnightMode
+	}
+	if shafaq != "" {
+		settings += "|shafaq=" + shafaq
+	}
+	if timezoneOverride != "" {
+		settings += "|tz=" + timezoneOverride
+	}
+	return settings
+}
+
+// noticeChannel returns the channel for warnings and status messages written
+// to stderr, as selected by --quiet and --no-warning. Status bars and prompt
+// segments capture stderr too, so anything that isn't an error must go through it.
+func noticeChannel(stderr io.Writer) *diag.Channel {
+	if FlagQuiet {
+		return diag.Discard()
+	}
+	c := diag.New(stderr, stderr)
+	if FlagNoWarning {
+		c.SuppressWarnings()
+	}
+	return c
+}
+
+// selectedPrayerNames returns the prayers to track from the merged config,
+// falling back to prayer.DefaultPrayerNames when none are configured.
+func selectedPrayerNames(cfg *config.Config) []string {
+	if cfg.Prayers == "" {
+		return prayer.DefaultPrayerNames
+	}
+	names := strings.Split(cfg.Prayers, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+		if name, ok := prayer.Lookup(names[i]); ok {
+			names[i] = name
+		}
+	}
+	return names
+}
+
+// displayLang resolves the merged lang setting for text shown about loc,
+// whose country settles "auto" when the locale doesn't.
+func displayLang(cfg *config.Config, loc resolvedLocation) string {
+	country := loc.Country
+	if country == "" {
+		country = cfg.Country
+	}
+	return i18n.Resolve(cfg.Lang, country)
+}
+
+// prayerLabel returns the name of a prayer in lang for a table or list,
+// isolated so right-to-left names don't reorder the columns around them.
+func prayerLabel(lang, name string) string {
+	return i18n.Isolate(lang, i18n.PrayerName(lang, name))
+}
+
+// goTimeFormat maps the configured time format ("12h" or "24h") to a Go layout.
+func goTimeFormat(cfg *config.Config) string {
+	if cfg.TimeFormat == "12h" {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+// commandDefaultsName returns the name cmd's defaults are stored under in
+// the config's commands section: its path below the root, or "today" for
+// the root command itself.
+func commandDefaultsName(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return "today"
+	}
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// applyCommandDefaults sets cmd's flags from the config's commands section,
+// skipping flags given on the command line. Defaults then count as flags, so
+// they override the rest of the config.
+func applyCommandDefaults(cmd *cobra.Command, cfg *config.Config) {
+	name := commandDefaultsName(cmd)
+	for flag, value := range cfg.Commands[name] {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil {
+			notices.Warnf("config: %s has no --%s flag; ignoring its default", name, flag)
+			continue
+		}
+		if f.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			notices.Warnf("config: invalid default for %s --%s: %v", name, flag, err)
+		}
+	}
+}
+
 // flagWasSet checks if a flag was explicitly set on either the local or persistent flag set.
 func flagWasSet(local, persistent *pflag.FlagSet, name string) bool {
 	if f := local.Lookup(name); f != nil && f.Changed {
@@ -190,13 +748,13 @@ PowerShell:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			switch args[0] {
 			case "bash":
-				return cmd.Root().GenBashCompletion(os.Stdout)
+				return cmd.Root().GenBashCompletion(cmd.OutOrStdout())
 			case "zsh":
-				return cmd.Root().GenZshCompletion(os.Stdout)
+				return cmd.Root().GenZshCompletion(cmd.OutOrStdout())
 			case "fish":
-				return cmd.Root().GenFishCompletion(os.Stdout, true)
+				return cmd.Root().GenFishCompletion(cmd.OutOrStdout(), true)
 			case "powershell":
-				return cmd.Root().GenPowerShellCompletionWithDesc(os.Stdout)
+				return cmd.Root().GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
 			default:
 				return fmt.Errorf("unsupported shell: %s", args[0])
 			}
@@ -204,3 +762,31 @@ PowerShell:
 	}
 	return cmd
 }
+
+// reportTiming writes the run's time breakdown to w, records it in the
+// timing stats and adds the p95s of the command's recent runs.
+func reportTiming(w io.Writer) {
+	b := timer.Breakdown()
+	fmt.Fprintf(w, "timing: %s\n", b)
+	if timedCommand == "" {
+		return
+	}
+
+	path, err := timing.DefaultPath()
+	if err != nil {
+		notices.Warnf("timing stats not saved: %v", err)
+		return
+	}
+	stats, err := timing.Load(path)
+	if err != nil {
+		notices.Warnf("timing stats not saved: %v", err)
+		return
+	}
+	stats.Add(timing.Run{Command: timedCommand, At: time.Now(), Ms: b})
+	if err := stats.Save(path); err != nil {
+		notices.Warnf("timing stats not saved: %v", err)
+	}
+	if p95, n := stats.P95(timedCommand); n > 1 {
+		fmt.Fprintf(w, "p95 of the last %d runs: %s\n", n, p95)
+	}
+}
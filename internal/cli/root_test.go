@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCommandOutput_Injected verifies commands write to the command's
+// configured writers rather than the process's stdout.
+func TestCommandOutput_Injected(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	tests := []struct {
+		args  []string
+		check func(t *testing.T, out string)
+	}{
+		{[]string{"methods"}, func(t *testing.T, out string) {
+			if !strings.Contains(out, "Umm Al-Qura") {
+				t.Errorf("methods output missing Umm Al-Qura:\n%s", out)
+			}
+		}},
+		{[]string{"methods", "--json"}, func(t *testing.T, out string) {
+			var methods []methodJSON
+			if err := json.Unmarshal([]byte(out), &methods); err != nil {
+				t.Fatalf("methods --json is not valid JSON: %v\n%s", err, out)
+			}
+			if len(methods) != len(CalculationMethods) {
+				t.Errorf("got %d methods, want %d", len(methods), len(CalculationMethods))
+			}
+		}},
+		{[]string{"config", "path"}, func(t *testing.T, out string) {
+			want := filepath.Join(configHome, "prayer-times", "config.json")
+			if strings.TrimSpace(out) != want {
+				t.Errorf("config path = %q, want %q", strings.TrimSpace(out), want)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := NewRootCmd("test")
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute(%v) error: %v", tt.args, err)
+			}
+			tt.check(t, stdout.String())
+			if stderr.Len() != 0 {
+				t.Errorf("unexpected stderr: %q", stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/diag"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+// maxRangeDays caps how many days a single range request may span.
+const maxRangeDays = 366
+
+// serveMemoryTTL is how long serve keeps cache entries in memory, so
+// requests are answered without reading the cache files.
+const serveMemoryTTL = time.Hour
+
+// serveShutdownTimeout is how long serve waits for requests in progress when
+// interrupted.
+const serveShutdownTimeout = 15 * time.Second
+
+var (
+	flagServeAddr    string
+	flagServeLogFile string
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an HTTP server exposing prayer times",
+		Long: `Run a small HTTP server that exposes prayer times for the configured location.
+
+These endpoints return the same JSON as the matching CLI commands with --json:
+
+  GET  /today                    today's schedule (prayer-times --json)
+  GET  /next                     next prayer (prayer-times next --json)
+  GET  /calendar/{year}/{month}  a whole month (prayer-times list --json)
+  GET  /qibla                    Qibla direction (prayer-times qibla --json)
+
+The /api/range endpoints implement the Grafana JSON datasource protocol, so
+prayer times can be overlaid on dashboards as annotations or time series:
+
+  GET  /api/range              health check ("Test connection")
+  POST /api/range/metrics      list selectable prayers (alias: /api/range/search)
+  POST /api/range/query        time series: minutes after midnight per prayer
+  POST /api/range/annotations  one event per prayer in the requested range
+
+When the webhooks config key is set, serve also POSTs a JSON event to each
+webhook at every prayer and, with webhook_before, a reminder ahead of it.
+webhook_template picks the body: auto (Slack and Discord URLs get their chat
+format, anything else the raw event), json, slack, discord, or a Go template.
+
+When mqtt_broker is set, each prayer is also published to <mqtt_topic>/prayer
+as it begins, and the next prayer to <mqtt_topic>/next (retained), for Home
+Assistant and other home automation.
+
+When matrix_homeserver, matrix_token and matrix_room are set, each prayer and
+reminder is also posted to that Matrix room as a chat message.
+
+When ntfy_url (a topic URL, with ntfy_token for a protected topic),
+gotify_url and gotify_token, or pushover_user and pushover_token are set,
+each prayer and reminder is also pushed to phones through ntfy, Gotify or
+Pushover. matrix_template, ntfy_template, gotify_template and
+pushover_template are Go templates for each one's message, and ntfy_title,
+gotify_title and pushover_title for the title, e.g.
+"{{if .Reminder}}{{.Name}} in {{.Remaining}}{{else}}{{.Name}} at {{.Clock}}{{end}}".
+
+When gpio_pin is set, serve pulses that GPIO pin (through Linux sysfs) as each
+prayer begins, to blink an LED or sound a buzzer; gpio_pattern sets the
+pulses, e.g. 5x500ms or 1x3s.
+
+Every configured target gets every event. To send to only some, list them
+in notify_targets, e.g. "ntfy,matrix", from: webhooks, mqtt, matrix, ntfy,
+gotify, pushover and gpio.
+
+Messages and titles are in the language of lang (or --lang): en, ar, tr, ur,
+id or fr. By default (auto) it follows $LC_ALL,
+$LC_MESSAGES or $LANG, and failing those the location's country.
+
+When admin_token is set, an admin API lets a central script manage a fleet of
+displays. Requests need the header "Authorization: Bearer <admin_token>":
+
+  GET    /admin/version       the running version and when it started
+  PATCH  /admin/config        set config keys from a JSON object and apply them
+  POST   /admin/refresh       re-read the config file and refetch today's times
+  PUT    /admin/announcement  show {"text": "...", "expires": "<RFC 3339>"}
+  DELETE /admin/announcement  remove the announcement
+
+Displays poll GET /announcement (no token; 204 when there is none).
+
+When heartbeat_url is set, serve POSTs a status document (version, last
+successful fetch, next prayer) to it every heartbeat_interval (default 5m),
+so unattended displays can be monitored.`,
+		Example: `  prayer-times serve
+  prayer-times serve --addr 0.0.0.0:8080 --log-file ~/.local/state/prayer-times.log
+  curl -s localhost:8080/next | jq .`,
+		RunE: runServe,
+	}
+
+	cmd.Flags().StringVar(&flagServeAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().StringVar(&flagServeLogFile, "log-file", "", "Append timestamped warnings and status messages to this file instead of stderr")
+
+	return cmd
+}
+
+// server holds the state shared by all HTTP handlers.
+// The location is resolved once at startup and reused for every request.
+type server struct {
+	loc     resolvedLocation
+	method  int
+	school  int
+	prayers []string
+	timeFmt string
+	cache   *cache.Cache
+}
+
+// newServer resolves cfg's location and settings into a server.
+func newServer(ctx context.Context, cfg *config.Config) (*server, error) {
+	c := openCache(cfg)
+	if c != nil {
+		c.KeepInMemory(serveMemoryTTL)
+	}
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return nil, err
+	}
+	return &server{
+		loc:     loc,
+		method:  cfg.MethodOrDefault(-1),
+		school:  cfg.SchoolOrDefault(-1),
+		prayers: selectedPrayerNames(cfg),
+		timeFmt: goTimeFormat(cfg),
+		cache:   c,
+	}, nil
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if flagServeLogFile != "" {
+		f, err := os.OpenFile(flagServeLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer f.Close()
+		notices = diag.Log(f)
+		if FlagNoWarning {
+			notices.SuppressWarnings()
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := effectiveConfig(cmd)
+
+	s, err := newServer(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	d, err := newServeDaemon(ctx, cmd, cfg, s)
+	if err != nil {
+		return err
+	}
+
+	go d.runHeartbeat(ctx)
+
+	hs := &http.Server{Addr: flagServeAddr, Handler: d.handler()}
+	errc := make(chan error, 1)
+	go func() { errc <- hs.ListenAndServe() }()
+	notices.Infof("Listening on http://%s", flagServeAddr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Let requests in progress finish; their API calls are bounded by --timeout.
+	notices.Infof("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	return hs.Shutdown(shutdownCtx)
+}
+
+// routes registers all HTTP endpoints.
+func (s *server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /today", s.handleToday)
+	mux.HandleFunc("GET /next", s.handleNext)
+	mux.HandleFunc("GET /calendar/{year}/{month}", s.handleCalendar)
+	mux.HandleFunc("GET /qibla", s.handleQibla)
+	mux.HandleFunc("GET /api/range", s.handleRangeHealth)
+	mux.HandleFunc("POST /api/range/metrics", s.handleRangeMetrics)
+	mux.HandleFunc("POST /api/range/search", s.handleRangeMetrics)
+	mux.HandleFunc("POST /api/range/query", s.handleRangeQuery)
+	mux.HandleFunc("POST /api/range/annotations", s.handleRangeAnnotations)
+	return mux
+}
+
+// timezone returns the location's timezone, falling back to the API
+// metadata and then the coordinates (see resolvedLocation.timezoneFor).
+func (s *server) timezone(meta api.Meta) (string, *time.Location, error) {
+	tz := s.loc.timezoneFor(meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	return tz, tzLoc, nil
+}
+
+// today fetches and parses today's prayers in the location's timezone.
+// The returned time is now re-anchored to that timezone.
+func (s *server) today(ctx context.Context) ([]prayer.Prayer, *fetchResult, string, *time.Location, time.Time, error) {
+	now := currentTime()
+	result, err := fetchTimings(ctx, now, s.loc, s.method, s.school, s.cache)
+	if err != nil {
+		return nil, nil, "", nil, now, err
+	}
+	tz, tzLoc, err := s.timezone(result.Meta)
+	if err != nil {
+		return nil, nil, "", nil, now, err
+	}
+	now = now.In(tzLoc)
+	prayers, err := prayer.ParseTimings(result.Timings, now, tzLoc, s.prayers)
+	if err != nil {
+		return nil, nil, "", nil, now, err
+	}
+	return prayers, result, tz, tzLoc, now, nil
+}
+
+func (s *server) handleToday(w http.ResponseWriter, r *http.Request) {
+	prayers, result, tz, _, now, err := s.today(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	current := prayer.CurrentPrayer(prayers, now)
+	next := prayer.NextPrayer(prayers, now)
+	locationStr := buildLocationStr(s.loc, result)
+
+	writeJSON(w, http.StatusOK, buildTodayJSON(prayers, current, next, now, result, locationStr, tz, s.timeFmt, "", nil, nil))
+}
+
+func (s *server) handleNext(w http.ResponseWriter, r *http.Request) {
+	prayers, _, _, tzLoc, now, err := s.today(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	next, err := nextPrayerFrom(r.Context(), prayers, now, s.loc, s.method, s.school, s.cache, tzLoc, s.prayers)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if next == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("could not determine next prayer"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buildNextJSON(*next, now, s.timeFmt))
+}
+
+func (s *server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil || year < 1 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid year %q", r.PathValue("year")))
+		return
+	}
+	month, err := strconv.Atoi(r.PathValue("month"))
+	if err != nil || month < 1 || month > 12 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid month %q: must be between 1 and 12", r.PathValue("month")))
+		return
+	}
+
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	days := start.AddDate(0, 1, -1).Day()
+	daysList, err := fetchCalendarDays(r.Context(), start, days, s.loc, s.method, s.school, s.cache)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	tz, tzLoc, err := s.timezone(daysList[0].Meta)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	locationStr := buildLocationStr(s.loc, &fetchResult{Meta: daysList[0].Meta})
+
+	// Anchor each day to its calendar date so the location's timezone
+	// can't shift it onto a neighbouring day.
+	for i := range daysList {
+		daysList[i].Date = calendarDay(daysList[i].Date, tzLoc)
+	}
+
+	out, err := buildListJSON(daysList, s.prayers, locationStr, tz, s.timeFmt, tzLoc)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *server) handleQibla(w http.ResponseWriter, r *http.Request) {
+	lat, lon, err := locationCoordinates(r.Context(), s.loc, s.method, s.school, s.cache)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildQiblaJSON(lat, lon))
+}
+
+// grafanaRange is the time range sent by Grafana with every query.
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// grafanaQueryRequest is the body of a /query request.
+type grafanaQueryRequest struct {
+	Range   grafanaRange `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+		RefID  string `json:"refId"`
+	} `json:"targets"`
+}
+
+// grafanaAnnotationRequest is the body of an /annotations request.
+type grafanaAnnotationRequest struct {
+	Range      grafanaRange `json:"range"`
+	Annotation struct {
+		Name  string `json:"name"`
+		Query string `json:"query"`
+	} `json:"annotation"`
+}
+
+// grafanaSeries is one time series in a /query response.
+// Each datapoint is [value, unix-millis].
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// grafanaAnnotation is one event in an /annotations response.
+type grafanaAnnotation struct {
+	Time  int64    `json:"time"`
+	Title string   `json:"title"`
+	Text  string   `json:"text"`
+	Tags  []string `json:"tags"`
+}
+
+// grafanaMetric is one selectable entry in a /metrics response.
+type grafanaMetric struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+func (s *server) handleRangeHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *server) handleRangeMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics := make([]grafanaMetric, len(prayer.AllPrayerNames))
+	for i, name := range prayer.AllPrayerNames {
+		metrics[i] = grafanaMetric{Label: name, Value: name}
+	}
+	writeJSON(w, http.StatusOK, metrics)
+}
+
+func (s *server) handleRangeQuery(w http.ResponseWriter, r *http.Request) {
+	var req grafanaQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var names []string
+	for _, t := range req.Targets {
+		names = append(names, t.Target)
+	}
+	names, err := normalizePrayerNames(names, s.prayers)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := s.rangeEvents(r.Context(), req.Range.From, req.Range.To, names)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	// One series per prayer, in the requested order.
+	series := make([]grafanaSeries, len(names))
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		series[i] = grafanaSeries{Target: name, Datapoints: [][2]float64{}}
+		index[name] = i
+	}
+	for _, e := range events {
+		minutes := float64(e.Time.Hour()*60 + e.Time.Minute())
+		i := index[e.Name]
+		series[i].Datapoints = append(series[i].Datapoints, [2]float64{minutes, float64(e.Time.UnixMilli())})
+	}
+
+	writeJSON(w, http.StatusOK, series)
+}
+
+func (s *server) handleRangeAnnotations(w http.ResponseWriter, r *http.Request) {
+	var req grafanaAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	// The annotation query is an optional comma-separated prayer list.
+	var names []string
+	if q := strings.TrimSpace(req.Annotation.Query); q != "" {
+		names = strings.Split(q, ",")
+	}
+	names, err := normalizePrayerNames(names, s.prayers)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := s.rangeEvents(r.Context(), req.Range.From, req.Range.To, names)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	annotations := make([]grafanaAnnotation, 0, len(events))
+	for _, e := range events {
+		annotations = append(annotations, grafanaAnnotation{
+			Time:  e.Time.UnixMilli(),
+			Title: e.Name,
+			Text:  fmt.Sprintf("%s %s", e.Name, e.Time.Format("15:04")),
+			Tags:  []string{"prayer", strings.ToLower(e.Name)},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, annotations)
+}
+
+// rangeEvents returns every occurrence of the named prayers between from and to (inclusive),
+// ordered by time.
+func (s *server) rangeEvents(ctx context.Context, from, to time.Time, names []string) ([]prayer.Prayer, error) {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return nil, fmt.Errorf("invalid range: from=%s to=%s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	// Pad by a day on each side so boundary days are covered in any timezone.
+	start := from.UTC().AddDate(0, 0, -1)
+	days := int(to.Sub(start).Hours()/24) + 2
+	if days > maxRangeDays {
+		return nil, fmt.Errorf("range too large: %d days (max %d)", days, maxRangeDays)
+	}
+
+	daysList, err := fetchCalendarDays(ctx, start, days, s.loc, s.method, s.school, s.cache)
+	if err != nil {
+		return nil, err
+	}
+
+	tz := s.loc.Timezone
+	if tz == "" && len(daysList) > 0 {
+		tz = s.loc.timezoneFor(daysList[0].Meta)
+	}
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	var events []prayer.Prayer
+	for _, dd := range daysList {
+		parsed, err := prayer.ParseTimings(dd.Timings, calendarDay(dd.Date, tzLoc), tzLoc, names)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parsed {
+			if p.Time.Before(from) || p.Time.After(to) {
+				continue
+			}
+			events = append(events, p)
+		}
+	}
+
+	return events, nil
+}
+
+// normalizePrayerNames validates names, in any spelling prayer.Lookup accepts,
+// and returns them as the API names them, each once. Empty entries are
+// skipped; if nothing remains, fallback is returned.
+func normalizePrayerNames(names []string, fallback []string) ([]string, error) {
+	var out []string
+	for _, raw := range names {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		found, ok := prayer.Lookup(raw)
+		if !ok {
+			return nil, unknownPrayerError(raw)
+		}
+		if !slices.Contains(out, found) {
+			out = append(out, found)
+		}
+	}
+	if len(out) == 0 {
+		return fallback, nil
+	}
+	return out, nil
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
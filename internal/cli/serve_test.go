@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+)
+
+// newTestServer returns a server whose February 2026 calendar is pre-cached,
+// so handlers never touch the network.
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+
+	c, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := resolvedLocation{Mode: locationCoords, Lat: 21.4225, Lon: 39.8262}
+	resp := &api.CalendarResponse{Code: 200, Status: "OK"}
+	for day := 1; day <= 28; day++ {
+		resp.Data = append(resp.Data, api.Data{
+			Timings: api.Timings{
+				Fajr: "05:30", Sunrise: "06:50", Dhuhr: "12:30", Asr: "15:45",
+				Sunset: "18:10", Maghrib: "18:10", Isha: "19:40",
+				Imsak: "05:20", Midnight: "00:30", Firstthird: "22:20", Lastthird: "02:40",
+			},
+			Meta: api.Meta{Latitude: loc.Lat, Longitude: loc.Lon, Timezone: "Asia/Riyadh"},
+		})
+	}
+	if err := c.SaveCalendar(2026, 2, loc.Lat, loc.Lon, "", "", -1, -1, resp); err != nil {
+		t.Fatal(err)
+	}
+
+	return &server{
+		loc:     loc,
+		method:  -1,
+		school:  -1,
+		prayers: []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"},
+		timeFmt: "15:04",
+		cache:   c,
+	}
+}
+
+// cacheTodayAndTomorrow pre-caches daily timings so /today and /next stay offline.
+func cacheTodayAndTomorrow(t *testing.T, s *server) {
+	t.Helper()
+	now := time.Now()
+	for _, date := range []time.Time{now, now.AddDate(0, 0, 1)} {
+		resp := &api.Response{Code: 200, Status: "OK", Data: api.Data{
+			Timings: api.Timings{Fajr: "05:30", Dhuhr: "12:30", Asr: "15:45", Maghrib: "18:10", Isha: "19:40"},
+			Meta:    api.Meta{Latitude: s.loc.Lat, Longitude: s.loc.Lon, Timezone: "Asia/Riyadh"},
+		}}
+		if err := s.cache.SaveTimings(date, s.loc.Lat, s.loc.Lon, "", "", -1, -1, resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestServeRange_Health(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/range", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestServeRange_Metrics(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/range/search", strings.NewReader("{}")))
+
+	var metrics []grafanaMetric
+	if err := json.Unmarshal(rec.Body.Bytes(), &metrics); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if len(metrics) == 0 || metrics[0].Value != "Fajr" {
+		t.Errorf("metrics = %v, want Fajr first", metrics)
+	}
+}
+
+func TestServeRange_Query(t *testing.T) {
+	s := newTestServer(t)
+
+	body := `{"range":{"from":"2026-02-10T00:00:00Z","to":"2026-02-12T23:59:59Z"},"targets":[{"target":"fajr","refId":"A"}]}`
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/range/query", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var series []grafanaSeries
+	if err := json.Unmarshal(rec.Body.Bytes(), &series); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if len(series) != 1 || series[0].Target != "Fajr" {
+		t.Fatalf("series = %+v, want a single Fajr series", series)
+	}
+	// Fajr at 05:30 Riyadh time (02:30 UTC): Feb 10, 11, 12 fall inside the range.
+	if got := len(series[0].Datapoints); got != 3 {
+		t.Fatalf("got %d datapoints, want 3", got)
+	}
+	if v := series[0].Datapoints[0][0]; v != 330 {
+		t.Errorf("value = %v, want 330 minutes after midnight", v)
+	}
+}
+
+// TestServeRange_QueryDuplicate checks that a prayer requested twice, in
+// any spelling, comes back as one full series.
+func TestServeRange_QueryDuplicate(t *testing.T) {
+	s := newTestServer(t)
+
+	body := `{"range":{"from":"2026-02-10T00:00:00Z","to":"2026-02-12T23:59:59Z"},"targets":[{"target":"fajr","refId":"A"},{"target":"Fajr","refId":"B"}]}`
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/range/query", strings.NewReader(body)))
+
+	var series []grafanaSeries
+	if err := json.Unmarshal(rec.Body.Bytes(), &series); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if len(series) != 1 || series[0].Target != "Fajr" || len(series[0].Datapoints) != 3 {
+		t.Errorf("series = %+v, want one Fajr series of 3 datapoints", series)
+	}
+}
+
+func TestServeRange_Annotations(t *testing.T) {
+	s := newTestServer(t)
+
+	body := `{"range":{"from":"2026-02-10T00:00:00Z","to":"2026-02-10T23:59:59Z"},"annotation":{"name":"prayers","query":"Maghrib,Isha"}}`
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/range/annotations", strings.NewReader(body)))
+
+	var annotations []grafanaAnnotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &annotations); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if len(annotations) != 2 {
+		t.Fatalf("got %d annotations, want 2: %+v", len(annotations), annotations)
+	}
+	if annotations[0].Title != "Maghrib" || annotations[0].Text != "Maghrib 18:10" {
+		t.Errorf("first annotation = %+v, want Maghrib 18:10", annotations[0])
+	}
+}
+
+func TestServeRange_InvalidTarget(t *testing.T) {
+	s := newTestServer(t)
+
+	body := `{"range":{"from":"2026-02-10T00:00:00Z","to":"2026-02-11T00:00:00Z"},"targets":[{"target":"Brunch"}]}`
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/range/query", strings.NewReader(body)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestServe_Today(t *testing.T) {
+	s := newTestServer(t)
+	cacheTodayAndTomorrow(t, s)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/today", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var out todayJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if out.Timings["fajr"] != "05:30" || out.Location.Timezone != "Asia/Riyadh" {
+		t.Errorf("today = %+v", out)
+	}
+}
+
+func TestServe_Next(t *testing.T) {
+	s := newTestServer(t)
+	cacheTodayAndTomorrow(t, s)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/next", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var out nextJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if out.Prayer == "" || out.Time == "" {
+		t.Errorf("next = %+v, want a prayer and time", out)
+	}
+}
+
+func TestServe_Calendar(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar/2026/2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var out listJSONOutput
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if len(out.Days) != 28 {
+		t.Fatalf("got %d days, want 28", len(out.Days))
+	}
+	if out.Days[0].Date != "01 Feb 2026" || out.Days[0].Timings["isha"] != "19:40" {
+		t.Errorf("first day = %+v", out.Days[0])
+	}
+}
+
+func TestServe_CalendarInvalidMonth(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar/2026/13", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestServe_Qibla(t *testing.T) {
+	s := newTestServer(t)
+	s.loc = resolvedLocation{Mode: locationCoords, Lat: 51.5074, Lon: -0.1278}
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/qibla", nil))
+
+	var out qiblaJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body)
+	}
+	if out.Compass != "ESE" || out.Direction < 118 || out.Direction > 120 {
+		t.Errorf("qibla = %+v, want ~119° ESE", out)
+	}
+}
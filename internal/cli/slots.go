@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/ical"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSlotsDay     string
+	flagSlotsBetween string
+	flagSlotsBuffer  time.Duration
+	flagSlotsFormat  string
+)
+
+func newSlotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "slots",
+		Short: "List free time slots that avoid prayer times",
+		Long: `List the time ranges within working hours that stay clear of every prayer,
+keeping --buffer free before and after each one. Useful for scheduling
+meetings by hand or from scripts and assistants.
+
+Output is plain text by default; use --format json (or --json) or
+--format ics for a calendar of the free slots.`,
+		Example: `  prayer-times slots
+  prayer-times slots --day tomorrow --between 09:00-17:00 --buffer 20m
+  prayer-times slots --day 2026-03-20 --format ics > free.ics`,
+		Args: cobra.NoArgs,
+		RunE: runSlots,
+	}
+
+	cmd.Flags().StringVar(&flagSlotsDay, "day", "today", "Day to plan: YYYY-MM-DD, today, tomorrow or a weekday")
+	cmd.Flags().StringVar(&flagSlotsBetween, "between", "09:00-17:00", "Working hours to search, as HH:MM-HH:MM")
+	cmd.Flags().DurationVar(&flagSlotsBuffer, "buffer", 15*time.Minute, "Time to keep free before and after each prayer")
+	cmd.Flags().StringVar(&flagSlotsFormat, "format", "text", "Output format: text, json or ics")
+
+	return cmd
+}
+
+// slot is a free time range.
+type slot struct {
+	Start, End time.Time
+}
+
+// parseBetween parses "HH:MM-HH:MM" into start and end times on day.
+func parseBetween(s string, day time.Time) (time.Time, time.Time, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --between %q: want HH:MM-HH:MM", s)
+	}
+	var bounds [2]time.Time
+	for i, part := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --between %q: want HH:MM-HH:MM", s)
+		}
+		bounds[i] = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+	}
+	if !bounds[1].After(bounds[0]) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --between %q: end must be after start", s)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// freeSlots returns the parts of [from, to) at least buffer away from every prayer.
+func freeSlots(prayers []prayer.Prayer, from, to time.Time, buffer time.Duration) []slot {
+	// Midnight and the night thirds can fall early in the day's list.
+	sorted := append([]prayer.Prayer(nil), prayers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var slots []slot
+	cursor := from
+	for _, p := range sorted {
+		busyStart, busyEnd := p.Time.Add(-buffer), p.Time.Add(buffer)
+		if !busyStart.Before(to) {
+			break
+		}
+		if busyStart.After(cursor) {
+			slots = append(slots, slot{Start: cursor, End: busyStart})
+		}
+		if busyEnd.After(cursor) {
+			cursor = busyEnd
+		}
+	}
+	if cursor.Before(to) {
+		slots = append(slots, slot{Start: cursor, End: to})
+	}
+	return slots
+}
+
+// slotsJSONSlot is one free slot in the slots command's JSON output.
+type slotsJSONSlot struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Minutes int    `json:"minutes"`
+}
+
+// slotsJSONOutput is the JSON output structure for the slots command.
+type slotsJSONOutput struct {
+	Date     string          `json:"date"`
+	Location string          `json:"location"`
+	Timezone string          `json:"timezone"`
+	Slots    []slotsJSONSlot `json:"slots"`
+}
+
+func runSlots(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	format := flagSlotsFormat
+	if FlagJSON {
+		format = "json"
+	}
+	if format != "text" && format != "json" && format != "ics" {
+		return fmt.Errorf("invalid --format %q: must be text, json or ics", format)
+	}
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	now := loc.localTime(currentTime())
+
+	day, err := parseDayFlag(flagSlotsDay, now)
+	if err != nil {
+		return fmt.Errorf("invalid --day: %w", err)
+	}
+
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	result, err := fetchTimings(ctx, day, loc, method, school, c)
+	if err != nil {
+		return err
+	}
+
+	tz := loc.timezoneFor(result.Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	day = calendarDay(day, tzLoc)
+
+	from, to, err := parseBetween(flagSlotsBetween, day)
+	if err != nil {
+		return err
+	}
+
+	prayers, err := prayer.ParseTimings(result.Timings, day, tzLoc, selectedPrayers)
+	if err != nil {
+		return err
+	}
+	slots := freeSlots(prayers, from, to, flagSlotsBuffer)
+	locationStr := buildLocationStr(loc, result)
+
+	switch format {
+	case "json":
+		out := slotsJSONOutput{Date: day.Format("2006-01-02"), Location: locationStr, Timezone: tz, Slots: []slotsJSONSlot{}}
+		for _, s := range slots {
+			out.Slots = append(out.Slots, slotsJSONSlot{
+				Start:   s.Start.Format(time.RFC3339),
+				End:     s.End.Format(time.RFC3339),
+				Minutes: int(s.End.Sub(s.Start).Minutes()),
+			})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	case "ics":
+		cal := &ical.Calendar{Name: "Free Slots — " + locationStr}
+		for _, s := range slots {
+			cal.Events = append(cal.Events, ical.Event{
+				UID:      fmt.Sprintf("%s-free@prayer-times", s.Start.UTC().Format("20060102T1504")),
+				Summary:  "Free",
+				Location: locationStr,
+				Start:    s.Start,
+				Duration: s.End.Sub(s.Start),
+			})
+		}
+		if err := cal.Encode(w); err != nil {
+			return fmt.Errorf("failed to write calendar: %w", err)
+		}
+		return nil
+	}
+
+	printSlotsText(w, slots, day, locationStr, goTimeFmt)
+	return nil
+}
+
+// printSlotsText renders the free slots for the terminal.
+func printSlotsText(w io.Writer, slots []slot, day time.Time, locationStr, goTimeFmt string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Free Slots — "+day.Format("Mon 02 Jan 2006")))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", locationStr)
+	fmt.Fprintf(w, "  %s\n", display.Dim(fmt.Sprintf("%s, %s around each prayer", flagSlotsBetween, prayer.FormatRemaining(flagSlotsBuffer))))
+	fmt.Fprintln(w)
+
+	if len(slots) == 0 {
+		fmt.Fprintf(w, "  %s\n\n", display.Dim("No free slots."))
+		return
+	}
+	for _, s := range slots {
+		fmt.Fprintf(w, "  %s – %s  %s\n", s.Start.Format(goTimeFmt), s.End.Format(goTimeFmt), display.Dim(prayer.FormatRemaining(s.End.Sub(s.Start))))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+func TestParseBetween(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	from, to, err := parseBetween("09:00-17:30", day)
+	if err != nil {
+		t.Fatalf("parseBetween() error: %v", err)
+	}
+	if from.Format("15:04") != "09:00" || to.Format("15:04") != "17:30" || from.Day() != 14 {
+		t.Errorf("parseBetween() = %v, %v", from, to)
+	}
+
+	for _, s := range []string{"09:00", "9-5", "17:00-09:00", "09:00-09:00"} {
+		if _, _, err := parseBetween(s, day); err == nil {
+			t.Errorf("parseBetween(%q) expected error", s)
+		}
+	}
+}
+
+func TestFreeSlots(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	prayers := []prayer.Prayer{
+		{Name: "Fajr", Time: at(5, 30)},
+		{Name: "Dhuhr", Time: at(12, 30)},
+		{Name: "Asr", Time: at(15, 45)},
+		{Name: "Maghrib", Time: at(18, 10)},
+		{Name: "Midnight", Time: at(0, 30)},
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		buffer   time.Duration
+		want     []slot
+	}{
+		{"working hours", at(9, 0), at(17, 0), 20 * time.Minute, []slot{
+			{at(9, 0), at(12, 10)}, {at(12, 50), at(15, 25)}, {at(16, 5), at(17, 0)},
+		}},
+		{"starts inside a buffer", at(12, 40), at(15, 0), 20 * time.Minute, []slot{
+			{at(12, 50), at(15, 0)},
+		}},
+		{"ends inside a buffer", at(9, 0), at(12, 20), 20 * time.Minute, []slot{
+			{at(9, 0), at(12, 10)},
+		}},
+		{"no room", at(12, 15), at(12, 45), 20 * time.Minute, nil},
+	}
+	for _, tt := range tests {
+		if got := freeSlots(prayers, tt.from, tt.to, tt.buffer); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: freeSlots() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlotsJSON(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	args := append([]string{"slots", "--day", "tomorrow", "--between", "09:00-17:00", "--buffer", "20m", "--json"}, meccaArgs(t)...)
+	out, stderr, code := runCLI(t, args...)
+	if code != 0 {
+		t.Fatalf("slots --json exited with %d: %s", code, stderr)
+	}
+
+	var got slotsJSONOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	var minutes []int
+	for _, s := range got.Slots {
+		minutes = append(minutes, s.Minutes)
+	}
+	// Dhuhr 12:30 and Asr 15:45 split the day.
+	if want := []int{190, 155, 55}; !reflect.DeepEqual(minutes, want) {
+		t.Errorf("slot minutes = %v, want %v", minutes, want)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var flagStatusFormat string
+
+func newStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current and next prayer together",
+		Long: `Display the current prayer, with the time since it began, and the next
+prayer, with the countdown to it, in one call: 'current' and 'next' together,
+so a widget needs to run only one command.
+
+Formats:
+  full (default)            Dhuhr 12:30 · Asr 15:45 (2h 15m)
+  name-and-remaining        Dhuhr 45m · Asr 2h 15m
+  short-name-and-remaining  D 45m · A 2h 15m
+
+Custom templates get the current prayer's fields (as for 'current') under
+.Current and the next prayer's (as for 'next') under .Next, e.g.
+"{{.Current.Name}} {{.Current.Elapsed}} | {{.Next.Name}} {{.Next.Remaining}}".
+.Next's progress fields cover the window from the current prayer.
+
+As for 'next', --alert-threshold styles the output with --alert-style when
+the next prayer is closer than that.
+
+With --date, answers as if run at the current time of day on that date.`,
+		Args: cobra.NoArgs,
+		RunE: runStatus,
+		Example: `  prayer-times status
+  prayer-times status --format short-name-and-remaining
+  prayer-times status --json | jq -r '"\(.current.prayer) → \(.next.prayer) in \(.next.remaining)"'`,
+	}
+
+	cmd.Flags().StringVar(&flagStatusFormat, "format", prayer.FormatFull, "Display format: full, name-and-remaining, short-name-and-remaining, or a custom Go template")
+	addDateFlag(cmd)
+	addAlertFlags(cmd)
+
+	return cmd
+}
+
+// statusJSON is the JSON output structure for the status command.
+type statusJSON struct {
+	Current statusCurrentJSON `json:"current"`
+	Next    nextJSON          `json:"next"`
+	Stale   bool              `json:"stale,omitempty"` // times are from the cache; the API was unreachable
+}
+
+// statusCurrentJSON is the current prayer in statusJSON.
+type statusCurrentJSON struct {
+	Prayer  string `json:"prayer"`
+	Time    string `json:"time"`
+	Elapsed string `json:"elapsed"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+	now := loc.localTime(currentTime())
+	if now, err = applyDateFlag(now); err != nil {
+		return err
+	}
+
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
+	if err != nil {
+		return err
+	}
+	tz := loc.timezoneFor(result.Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	now = now.In(tzLoc)
+
+	prayers, err := prayer.ParseTimings(result.Timings, now, tzLoc, selectedPrayers)
+	if err != nil {
+		return err
+	}
+
+	current, err := currentPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	if err != nil {
+		return fmt.Errorf("failed to fetch yesterday's times: %w", err)
+	}
+	next, err := nextPrayerFrom(ctx, prayers, now, loc, method, school, c, tzLoc, selectedPrayers)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tomorrow's times: %w", err)
+	}
+	if current == nil || next == nil {
+		return fmt.Errorf("could not determine the current and next prayer")
+	}
+
+	if FlagJSON {
+		out := statusJSON{
+			Current: statusCurrentJSON{
+				Prayer:  strings.ToLower(current.Name),
+				Time:    current.Time.Format(goTimeFmt),
+				Elapsed: prayer.FormatRemaining(now.Sub(current.Time)),
+			},
+			Next:  buildNextJSON(*next, now, goTimeFmt),
+			Stale: result.Stale,
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	output, err := applyAlert(prayer.FormatStatus(*current, *next, now, flagStatusFormat, goTimeFmt), *next, now)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(w, output)
+	return nil
+}
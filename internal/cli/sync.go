@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/configsync"
+	"github.com/spf13/cobra"
+)
+
+// passphraseEnv holds the sync passphrase for unattended use.
+const passphraseEnv = "PRAYER_TIMES_SYNC_PASSPHRASE"
+
+var flagSyncURL string
+
+func newConfigSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Share the config between machines through your own remote",
+		Long: `Push the config file to a remote you control, encrypted with a passphrase,
+and pull it on your other machines.
+
+The remote is the sync_url config key (or --url):
+
+  https://dav.example.com/prayer-times.sync   a WebDAV (HTTP PUT/GET) file URL
+  git+https://github.com/you/dotfiles         a Git repository; also git@host:repo
+                                              and URLs ending in .git
+
+The passphrase is read from $` + passphraseEnv + ` or asked for. Pulling backs up
+the current config first, so 'config undo' restores it.`,
+		Example: `  prayer-times config set sync_url git@github.com:you/prayer-sync.git
+  prayer-times config sync push
+  prayer-times config sync pull`,
+	}
+	cmd.PersistentFlags().StringVar(&flagSyncURL, "url", "", "Sync remote (overrides the sync_url config key)")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "push",
+		Short: "Encrypt the config and upload it",
+		Args:  cobra.NoArgs,
+		RunE:  runSyncPush,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "pull",
+		Short: "Download and decrypt the config, replacing this one",
+		Args:  cobra.NoArgs,
+		RunE:  runSyncPull,
+	})
+
+	return cmd
+}
+
+// syncRemote returns the remote from --url or the config, and a form of its
+// URL that is safe to print.
+func syncRemote(cmd *cobra.Command) (configsync.Remote, string, error) {
+	rawURL := flagSyncURL
+	if rawURL == "" {
+		rawURL = effectiveConfig(cmd).SyncURL
+	}
+	if rawURL == "" {
+		return nil, "", fmt.Errorf("no sync remote; use --url or 'prayer-times config set sync_url <url>'")
+	}
+	r, err := configsync.NewRemote(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	shown := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.User != nil {
+		shown = u.Redacted()
+	}
+	return r, shown, nil
+}
+
+// errNotTerminal is returned by hideInput for a file that isn't a terminal.
+var errNotTerminal = errors.New("not a terminal")
+
+// readPassphrase returns $PRAYER_TIMES_SYNC_PASSPHRASE, or asks for the
+// passphrase on stdin, twice when confirm is set. Typing into a terminal
+// isn't echoed.
+func readPassphrase(cmd *cobra.Command, confirm bool) (string, error) {
+	if p := os.Getenv(passphraseEnv); p != "" {
+		return p, nil
+	}
+
+	hidden := false
+	if f, ok := cmd.InOrStdin().(*os.File); ok {
+		restore, err := hideInput(f)
+		switch {
+		case err == nil:
+			defer restore()
+			hidden = true
+		case !errors.Is(err, errNotTerminal):
+			return "", fmt.Errorf("cannot hide the passphrase as it is typed (%v); set $%s instead", err, passphraseEnv)
+		}
+	}
+
+	in := bufio.NewReader(cmd.InOrStdin())
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(cmd.ErrOrStderr(), prompt)
+		line, err := in.ReadString('\n')
+		if hidden {
+			// The Enter that ended the line wasn't echoed either.
+			fmt.Fprintln(cmd.ErrOrStderr())
+		}
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return "", fmt.Errorf("no passphrase given (set $%s for unattended use)", passphraseEnv)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	p, err := ask("Sync passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", errors.New("empty passphrase")
+	}
+	if confirm {
+		again, err := ask("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != p {
+			return "", errors.New("passphrases don't match")
+		}
+	}
+	return p, nil
+}
+
+func runSyncPush(cmd *cobra.Command, args []string) error {
+	remote, shown, err := syncRemote(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no config file to push at %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	passphrase, err := readPassphrase(cmd, true)
+	if err != nil {
+		return err
+	}
+	sealed, err := configsync.Seal(data, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt config: %w", err)
+	}
+	if err := remote.Push(sealed); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Pushed config to %s\n", shown)
+	return nil
+}
+
+func runSyncPull(cmd *cobra.Command, args []string) error {
+	remote, shown, err := syncRemote(cmd)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := remote.Pull()
+	if err != nil {
+		return err
+	}
+	passphrase, err := readPassphrase(cmd, false)
+	if err != nil {
+		return err
+	}
+	data, err := configsync.Open(sealed, passphrase)
+	if err != nil {
+		return err
+	}
+
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("pulled config is invalid: %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Pulled config from %s ('config undo' restores the previous one)\n", shown)
+	return nil
+}
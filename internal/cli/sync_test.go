@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfigSync(t *testing.T) {
+	isolateConfig(t)
+
+	var (
+		mu     sync.Mutex
+		stored []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPut {
+			stored, _ = io.ReadAll(r.Body)
+			return
+		}
+		w.Write(stored)
+	}))
+	defer srv.Close()
+	remote := srv.URL + "/prayer-times.sync"
+
+	if _, stderr, code := runCLI(t, "config", "set", "city", "Riyadh"); code != 0 {
+		t.Fatal(stderr)
+	}
+	t.Setenv(passphraseEnv, "correct horse")
+	if out, stderr, code := runCLI(t, "config", "sync", "push", "--url", remote); code != 0 || !strings.Contains(out, "Pushed") {
+		t.Fatalf("sync push = %d %q %q", code, out, stderr)
+	}
+	if strings.Contains(string(stored), "Riyadh") {
+		t.Error("the remote got the config unencrypted")
+	}
+
+	// A second machine: empty config, same passphrase.
+	isolateConfig(t)
+	if _, stderr, code := runCLI(t, "config", "sync", "pull", "--url", remote); code != 0 {
+		t.Fatalf("sync pull exited with %d: %s", code, stderr)
+	}
+	out, _, _ := runCLI(t, "config")
+	if !strings.Contains(out, "Riyadh") {
+		t.Errorf("config after pull should contain Riyadh:\n%s", out)
+	}
+
+	t.Setenv(passphraseEnv, "wrong")
+	if _, stderr, code := runCLI(t, "config", "sync", "pull", "--url", remote); code == 0 || !strings.Contains(stderr, "passphrase") {
+		t.Errorf("pull with a wrong passphrase = %d %q, want a passphrase error", code, stderr)
+	}
+}
+
+// TestReadPassphrase_Pipe verifies a passphrase piped in is read as it is,
+// without trying to hide it.
+func TestReadPassphrase_Pipe(t *testing.T) {
+	t.Setenv(passphraseEnv, "")
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	w.WriteString("correct horse\ncorrect horse\n")
+	w.Close()
+
+	cmd := &cobra.Command{}
+	cmd.SetIn(r)
+	cmd.SetErr(io.Discard)
+	if p, err := readPassphrase(cmd, true); err != nil || p != "correct horse" {
+		t.Errorf("readPassphrase() = %q, %v; want the piped passphrase", p, err)
+	}
+}
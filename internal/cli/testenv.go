@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Environment variables that freeze the network and the clock, so packagers
+// and plugin authors can run integration tests of the binary hermetically.
+const (
+	// apiURLEnv replaces the Al Adhan API's base URL, e.g. with a fixture
+	// server's.
+	apiURLEnv = "PRAYER_TIMES_API_URL"
+	// geoURLEnv replaces IP geolocation with a URL serving a location as
+	// {"lat", "lon", "city", "country", "timezone"}; the result isn't cached.
+	geoURLEnv = "PRAYER_TIMES_GEO_URL"
+	// fixedNowEnv pins the current time, in RFC 3339 form.
+	fixedNowEnv = "PRAYER_TIMES_FIXED_NOW"
+)
+
+// fixedNow is the time from $PRAYER_TIMES_FIXED_NOW, or zero to use the
+// clock. Set during PersistentPreRunE.
+var fixedNow time.Time
+
+// applyTestEnv checks the test environment variables and sets fixedNow.
+func applyTestEnv() error {
+	for _, name := range []string{apiURLEnv, geoURLEnv} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid $%s %q: must be an http or https URL", name, value)
+		}
+	}
+
+	fixedNow = time.Time{}
+	if value := os.Getenv(fixedNowEnv); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid $%s %q: must be an RFC 3339 time, e.g. 2026-02-28T13:00:00+03:00", fixedNowEnv, value)
+		}
+		fixedNow = t
+	}
+	return nil
+}
+
+// currentTime returns the time now, or $PRAYER_TIMES_FIXED_NOW when set.
+// Commands answering "what's next" use it; timers and file names keep the
+// real clock.
+func currentTime() time.Time {
+	if !fixedNow.IsZero() {
+		return fixedNow
+	}
+	return time.Now()
+}
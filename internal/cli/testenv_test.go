@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestTestEnv runs next with the API, geolocation and clock all frozen
+// through the environment, as a packager's integration test would.
+func TestTestEnv(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+	t.Setenv(apiURLEnv, apiBaseURL)
+	apiBaseURL = "" // mockAPI's cleanup restores it
+
+	geoSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"lat":21.4225,"lon":39.8262,"city":"Mecca","country":"SA","timezone":"Asia/Riyadh"}`))
+	}))
+	defer geoSrv.Close()
+	t.Setenv(geoURLEnv, geoSrv.URL)
+	t.Setenv(fixedNowEnv, "2026-02-28T13:00:00+03:00")
+
+	out, stderr, code := runCLI(t, "next", "--format", "{{.Name}} {{.Remaining}}", "--cache-dir", t.TempDir())
+	if code != 0 {
+		t.Fatalf("next exited with %d: %s", code, stderr)
+	}
+	if out != "Asr 2h 45m" {
+		t.Errorf("next = %q, want %q", out, "Asr 2h 45m")
+	}
+
+	for name, value := range map[string]string{fixedNowEnv: "yesterday", apiURLEnv: "localhost:8080"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, stderr, code := runCLI(t, "next", "--cache-dir", t.TempDir()); code == 0 || !strings.Contains(stderr, name) {
+				t.Errorf("next with $%s=%q = %d, %q; want an error naming it", name, value, code, stderr)
+			}
+		})
+	}
+}
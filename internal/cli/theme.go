@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+// watchThemes are the colour schemes of watch --theme, as the escape code
+// that sets the foreground and background of the whole screen.
+var watchThemes = map[string]string{
+	"light": "\033[30;107m",  // black on white
+	"dark":  "\033[97;40m",   // white on black
+	"dim":   "\033[2;37;40m", // faint grey on black, without highlights
+}
+
+// themeOptions are the values of watch --theme.
+var themeOptions = []string{"none", "auto", "light", "dark", "dim"}
+
+// defaultThemeSchedule is the --theme-schedule used by --theme auto: light
+// from sunrise, dark from Maghrib, and dimmed from Isha until the next
+// sunrise.
+const defaultThemeSchedule = "Sunrise:light,Maghrib:dark,Isha:dim"
+
+// resetStyle restores the terminal's own colours.
+const resetStyle = "\033[0m"
+
+// ansiCode matches the escape codes display uses for styling.
+var ansiCode = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// themeStep switches to Theme when Prayer begins.
+type themeStep struct {
+	Prayer string
+	Theme  string
+}
+
+// parseThemeSchedule parses a comma-separated list of Prayer:theme steps,
+// such as "Sunrise:light,Maghrib:dark,Isha:dim".
+func parseThemeSchedule(value string) ([]themeStep, error) {
+	var steps []themeStep
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, theme, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid --theme-schedule entry %q: must be Prayer:theme, e.g. Isha:dim", part)
+		}
+		names, err := normalizePrayerNames([]string{name}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --theme-schedule entry %q: %w", part, err)
+		}
+		theme = strings.ToLower(strings.TrimSpace(theme))
+		if _, ok := watchThemes[theme]; !ok {
+			return nil, fmt.Errorf("invalid --theme-schedule entry %q: theme must be light, dark or dim", part)
+		}
+		steps = append(steps, themeStep{Prayer: names[0], Theme: theme})
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("invalid --theme-schedule %q: no entries", value)
+	}
+	return steps, nil
+}
+
+// scheduledTheme returns the theme of the latest step to have begun by now,
+// given each step's time today. Before the day's first step the night before
+// carries on, so that is the theme of the step that begins last.
+func scheduledTheme(steps []themeStep, times []prayer.Prayer, now time.Time) string {
+	begun, last := -1, 0
+	for i := range steps {
+		t := times[i].Time
+		if t.After(times[last].Time) {
+			last = i
+		}
+		if !t.After(now) && (begun < 0 || !t.Before(times[begun].Time)) {
+			begun = i
+		}
+	}
+	if begun < 0 {
+		return steps[last].Theme
+	}
+	return steps[begun].Theme
+}
+
+// applyTheme paints a watch frame, which starts by clearing the screen, in
+// theme. Styling inside the frame ends with a reset, so the theme is set
+// again after each one; dim drops the styling altogether.
+func applyTheme(frame, theme string) string {
+	code, ok := watchThemes[theme]
+	if !ok || !display.Enabled() {
+		return frame
+	}
+	if theme == "dim" {
+		frame = ansiCode.ReplaceAllString(frame, "")
+	}
+	return code + strings.ReplaceAll(frame, resetStyle, resetStyle+code)
+}
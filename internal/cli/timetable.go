@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+)
+
+// listOutputs are the values of list's and month's --output.
+var listOutputs = []string{"table", "markdown", "html"}
+
+// timetable is a list's grid of prayer times, without terminal styling, for
+// the markdown and html outputs.
+type timetable struct {
+	Title    string
+	Location string
+	Lang     string
+	Headers  []string
+	Rows     [][]string
+}
+
+// writeTimetable writes t in output, markdown or html.
+func writeTimetable(w io.Writer, t timetable, output string) error {
+	if output == "markdown" {
+		writeTimetableMarkdown(w, t)
+		return nil
+	}
+	return timetableHTML.Execute(w, t)
+}
+
+// writeTimetableMarkdown writes t as a heading and a GitHub-flavored
+// Markdown table, ready to paste into a wiki or newsletter.
+func writeTimetableMarkdown(w io.Writer, t timetable) {
+	cells := func(values []string) string {
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = strings.ReplaceAll(v, "|", `\|`)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
+	}
+
+	fmt.Fprintf(w, "## %s\n\n", t.Title)
+	fmt.Fprintf(w, "%s\n\n", t.Location)
+	fmt.Fprintln(w, cells(t.Headers))
+	rule := make([]string, len(t.Headers))
+	for i := range rule {
+		rule[i] = "---"
+	}
+	fmt.Fprintln(w, "|"+strings.Join(rule, "|")+"|")
+	for _, row := range t.Rows {
+		fmt.Fprintln(w, cells(row))
+	}
+}
+
+// timetableHTML is a standalone page for a timetable, styled to fit an A4
+// or Letter page when printed.
+var timetableHTML = template.Must(template.New("timetable").Funcs(template.FuncMap{
+	"rtl": i18n.RTL,
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}"{{if rtl .Lang}} dir="rtl"{{end}}>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; color: #111; }
+  h1 { font-size: 1.4rem; margin: 0 0 .25rem; }
+  p { margin: 0 0 1rem; color: #555; }
+  table { border-collapse: collapse; width: 100%; font-variant-numeric: tabular-nums; }
+  th, td { border: 1px solid #ccc; padding: .3rem .6rem; text-align: center; }
+  th { background: #f2f2f2; }
+  tbody tr:nth-child(even) { background: #fafafa; }
+  td:first-child, th:first-child { text-align: start; white-space: nowrap; }
+  @media print { body { margin: 0; } @page { margin: 1.5cm; } tr { break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Location}}</p>
+<table>
+<thead>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
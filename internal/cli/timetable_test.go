@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestListOutputMarkdown(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"list", "--from", "2026-03-01", "--days", "3", "--prayers", "Fajr,Maghrib", "--output", "markdown"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("list --output markdown exited with %d: %s", code, stderr)
+	}
+	want := `## Prayer Times — 3 Days
+
+21.4225, 39.8262
+
+| Date | Fajr | Maghrib |
+|---|---|---|
+| Sun 01 Mar | 05:30 | 18:10 |
+| Mon 02 Mar | 05:30 | 18:10 |
+| Tue 03 Mar | 05:30 | 18:10 |
+`
+	if out != want {
+		t.Errorf("list --output markdown =\n%s\nwant\n%s", out, want)
+	}
+	if strings.Contains(out, "\033[") {
+		t.Errorf("markdown output has ANSI codes: %q", out)
+	}
+}
+
+func TestMonthOutputHTML(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"month", "2", "2026", "--output", "html"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("month --output html exited with %d: %s", code, stderr)
+	}
+	for _, want := range []string{"<!DOCTYPE html>", `<html lang="en">`, "<title>Prayer Times — February 2026</title>", "<style>", "@media print", "<th>Fajr</th>", "<td>Sat 28 Feb</td>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("month --output html missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "<tr>"); n != 29 {
+		t.Errorf("month --output html has %d rows, want a header and 28 days", n)
+	}
+
+	_, stderr, code = runCLI(t, append([]string{"month", "--output", "pdf"}, meccaArgs(t)...)...)
+	if code == 0 || !strings.Contains(stderr, `invalid --output "pdf"`) {
+		t.Errorf("month --output pdf = %d, %q; want an error", code, stderr)
+	}
+}
+
+func TestTimetableHTMLEscapes(t *testing.T) {
+	var buf bytes.Buffer
+	tt := timetable{Title: "<b>Times</b>", Location: "Al-Masjid & Co", Lang: "ar", Headers: []string{"Date"}, Rows: [][]string{{"1"}}}
+	if err := writeTimetable(&buf, tt, "html"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{`dir="rtl"`, "&lt;b&gt;Times&lt;/b&gt;", "Al-Masjid &amp; Co"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTmuxFormat      string
+	flagTmuxIcon        string
+	flagTmuxUrgent      time.Duration
+	flagTmuxUrgentStyle string
+)
+
+func newTmuxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tmux",
+		Short: "Print the next prayer for the tmux status line",
+		Long: `Print the next prayer as a single uncolored line without a trailing newline,
+for #(...) in tmux's status-left or status-right. This is what the tmux plugin
+runs; repeated calls are answered from the cache.
+
+When the next prayer is less than --urgent away, the line is wrapped in
+--urgent-style, a tmux style such as "fg=red,bold". "#" in the text is
+escaped so tmux doesn't read it as a format.`,
+		Example: `  prayer-times tmux
+  prayer-times tmux --format name-and-remaining --icon 🕌
+  prayer-times tmux --urgent 10m --urgent-style "fg=colour196,bold"`,
+		Args: cobra.NoArgs,
+		RunE: runTmux,
+	}
+
+	cmd.Flags().StringVar(&flagTmuxFormat, "format", prayer.FormatFull, "Display format (see 'next --format')")
+	cmd.Flags().StringVar(&flagTmuxIcon, "icon", "", "Text to put before the prayer, e.g. 🕌")
+	cmd.Flags().DurationVar(&flagTmuxUrgent, "urgent", 15*time.Minute, "Apply --urgent-style when the next prayer is less than this away (0 to disable)")
+	cmd.Flags().StringVar(&flagTmuxUrgentStyle, "urgent-style", "fg=red", "tmux style for an imminent prayer, e.g. \"fg=red,bold\"")
+
+	return cmd
+}
+
+// tmuxLine builds the status line: one line, with tmux's "#" escaped and the
+// urgent style applied when style is non-empty.
+func tmuxLine(icon, text, style string) string {
+	line := strings.Join(strings.Fields(text), " ")
+	if icon != "" {
+		line = icon + " " + line
+	}
+	line = strings.ReplaceAll(line, "#", "##")
+	if style != "" {
+		line = fmt.Sprintf("#[%s]%s#[default]", style, line)
+	}
+	return line
+}
+
+func runTmux(cmd *cobra.Command, args []string) error {
+	st, err := loadStatus(cmd)
+	if err != nil {
+		return err
+	}
+
+	text, style := st.staleText(), ""
+	if st.Next != nil {
+		text = prayer.FormatOutputSince(*st.Next, st.Start, st.Now, flagTmuxFormat, st.GoTimeFmt)
+		if prayer.Imminent(*st.Next, st.Now, flagTmuxUrgent) {
+			style = flagTmuxUrgentStyle
+		}
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), tmuxLine(flagTmuxIcon, text, style))
+	return nil
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTmuxLine(t *testing.T) {
+	tests := []struct {
+		icon, text, style string
+		want              string
+	}{
+		{"", "Asr 15:45 (2h 15m)", "", "Asr 15:45 (2h 15m)"},
+		{"🕌", "Asr 15:45", "", "🕌 Asr 15:45"},
+		{"", "Asr\n15:45", "", "Asr 15:45"},
+		{"", "Asr #1", "", "Asr ##1"},
+		{"", "Asr 10m", "fg=red,bold", "#[fg=red,bold]Asr 10m#[default]"},
+	}
+	for _, tt := range tests {
+		if got := tmuxLine(tt.icon, tt.text, tt.style); got != tt.want {
+			t.Errorf("tmuxLine(%q, %q, %q) = %q, want %q", tt.icon, tt.text, tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestTmuxCmd(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, append([]string{"tmux", "--icon", "*", "--urgent", "0", "--format", "name-and-time"}, meccaArgs(t)...)...)
+	if code != 0 {
+		t.Fatalf("tmux exited with %d: %s", code, stderr)
+	}
+	if strings.Contains(out, "\n") || strings.Contains(out, "\x1b") || strings.Contains(out, "#[") {
+		t.Errorf("tmux output should be one plain line, got %q", out)
+	}
+	if !strings.HasPrefix(out, "* ") {
+		t.Errorf("tmux output = %q, want the icon first", out)
+	}
+}
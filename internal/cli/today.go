@@ -3,65 +3,67 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
-	"github.com/smokyabdulrahman/prayer-times/internal/cache"
 	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+	"github.com/smokyabdulrahman/prayer-times/internal/khatmah"
 	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/reminder"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagReminder bool
+	flagEvents   bool
+)
+
 func runToday(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if len(FlagProfiles) > 1 {
+		return runProfileCompare(cmd, FlagProfiles)
+	}
+
+	w := cmd.OutOrStdout()
+
 	// Get merged config (CLI flags > config file > defaults).
 	cfg := effectiveConfig(cmd)
 
 	// Determine which prayers to track.
-	selectedPrayers := prayer.DefaultPrayerNames
-	if cfg.Prayers != "" {
-		selectedPrayers = strings.Split(cfg.Prayers, ",")
-		for i := range selectedPrayers {
-			selectedPrayers[i] = strings.TrimSpace(selectedPrayers[i])
-		}
-	}
+	selectedPrayers := selectedPrayerNames(cfg)
 
 	// Determine Go time format from config.
-	goTimeFmt := "15:04"
-	if cfg.TimeFormat == "12h" {
-		goTimeFmt = "3:04 PM"
-	}
+	goTimeFmt := goTimeFormat(cfg)
 
 	// Initialize cache.
-	c, err := cache.New(cfg.CacheDir)
-	if err != nil {
-		c = nil
-		fmt.Fprintf(os.Stderr, "warning: cache disabled: %v\n", err)
-	}
+	c := openCache(cfg)
 
-	now := time.Now()
+	now := currentTime()
 
 	// Resolve location.
-	loc, err := resolveLocation(cfg.Latitude, cfg.Longitude, cfg.City, cfg.Country, c)
+	loc, err := resolveLocation(ctx, cfg, c)
 	if err != nil {
 		return err
 	}
+	now = loc.localTime(now)
+	if now, err = applyDateFlag(now); err != nil {
+		return err
+	}
 
 	// Get method/school from merged config.
 	method := cfg.MethodOrDefault(-1)
 	school := cfg.SchoolOrDefault(-1)
 
 	// Fetch today's timings.
-	result, err := fetchTimings(now, loc, method, school, c)
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
 	if err != nil {
 		return err
 	}
 
 	// Determine timezone.
-	tz := loc.Timezone
-	if tz == "" {
-		tz = result.Meta.Timezone
-	}
+	tz := loc.timezoneFor(result.Meta)
 	tzLoc, err := time.LoadLocation(tz)
 	if err != nil {
 		return fmt.Errorf("invalid timezone %q: %w", tz, err)
@@ -83,16 +85,81 @@ func runToday(cmd *cobra.Command, args []string) error {
 	// Build location display string.
 	locationStr := buildLocationStr(loc, result)
 
+	lang := displayLang(cfg, loc)
+
+	// Optional daily reminder (opt-in via --reminder or config).
+	var rem *reminder.Reminder
+	if cfg.Reminder {
+		r := reminder.ForDate(now)
+		rem = &r
+	}
+
+	// Today's Quran portions while a khatmah plan is active (best-effort).
+	var quran []khatmah.Portion
+	pagesRead := 0
+	if _, plan, err := loadKhatmah(); err != nil {
+		notices.Warnf("khatmah: %v", err)
+	} else if plan != nil {
+		quran = plan.PortionsFor(now)
+		pagesRead = plan.PagesRead
+	}
+
+	// Hijri events such as the days of Hajj (opt-in via --events or config).
+	var events []string
+	if cfg.Events {
+		events = hijriEvents(result.DateInfo.Hijri)
+	}
+
 	// JSON output.
 	if FlagJSON {
-		return printTodayJSON(prayers, current, next, now, result, locationStr, tz, goTimeFmt)
+		return printTodayJSON(w, prayers, current, next, now, result, locationStr, tz, goTimeFmt, lang, rem, quran, events)
 	}
 
 	// Rich terminal output.
-	printTodayRich(prayers, current, next, now, result, locationStr, tz, goTimeFmt)
+	if cfg.Kids {
+		printTodayKids(w, prayers, next, now, locationStr, goTimeFmt, cfg.Transliterate)
+	} else {
+		printTodayRich(w, prayers, current, next, now, result, locationStr, tz, goTimeFmt, lang, cfg.Transliterate)
+	}
+	if len(events) > 0 {
+		printEvents(w, events)
+	}
+	if len(quran) > 0 {
+		printKhatmahPortions(w, quran, pagesRead)
+	}
+	if rem != nil {
+		printReminder(w, *rem, lang)
+	}
 	return nil
 }
 
+// printEvents renders the day's Hijri events beneath today's schedule.
+func printEvents(w io.Writer, events []string) {
+	for _, e := range events {
+		fmt.Fprintf(w, "  %s\n", display.Accent(e))
+	}
+	fmt.Fprintln(w)
+}
+
+// printReminder renders the daily reminder beneath today's schedule, in
+// Arabic and translated into lang.
+func printReminder(w io.Writer, r reminder.Reminder, lang string) {
+	fmt.Fprint(w, reminderText(r, lang))
+	fmt.Fprintln(w)
+}
+
+// reminderText returns the lines of r as the schedule shows them: the Arabic
+// original, its translation into lang unless that is Arabic, and the source.
+func reminderText(r reminder.Reminder, lang string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  %s\n", r.Arabic)
+	if lang != "ar" {
+		fmt.Fprintf(&sb, "  %s\n", r.Text(lang))
+	}
+	fmt.Fprintf(&sb, "  %s\n", display.Dim("— "+r.Source))
+	return sb.String()
+}
+
 // buildLocationStr builds a "City, Country" string from available data.
 func buildLocationStr(loc resolvedLocation, result *fetchResult) string {
 	if loc.City != "" && loc.Country != "" {
@@ -102,57 +169,59 @@ func buildLocationStr(loc resolvedLocation, result *fetchResult) string {
 	return fmt.Sprintf("%.4f, %.4f", result.Meta.Latitude, result.Meta.Longitude)
 }
 
-// printTodayRich renders the colored terminal output for today's prayer schedule.
-func printTodayRich(prayers []prayer.Prayer, current, next *prayer.Prayer, now time.Time, result *fetchResult, locationStr, tz, goTimeFmt string) {
-	fmt.Println()
-	fmt.Printf("  %s\n", display.Bold("Prayer Times"))
-	fmt.Println()
+// printTodayRich renders the colored terminal output for today's prayer
+// schedule, with its title, prayer names, times and countdown in lang.
+func printTodayRich(w io.Writer, prayers []prayer.Prayer, current, next *prayer.Prayer, now time.Time, result *fetchResult, locationStr, tz, goTimeFmt, lang string, transliterate bool) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(i18n.Sprintf(lang, "ui.title")))
+	fmt.Fprintln(w)
 
 	// Location and date info.
-	fmt.Printf("  %s\n", locationStr)
-	fmt.Printf("  %s\n", tz)
+	fmt.Fprintf(w, "  %s\n", locationStr)
+	fmt.Fprintf(w, "  %s\n", tz)
 
 	// Gregorian date.
 	gregStr := formatGregorianDate(now, result)
-	fmt.Printf("  %s\n", gregStr)
+	fmt.Fprintf(w, "  %s\n", gregStr)
 
 	// Hijri date.
 	hijriStr := result.DateInfo.Hijri.Format()
 	if hijriStr != "" {
-		fmt.Printf("  %s\n", hijriStr)
+		fmt.Fprintf(w, "  %s\n", hijriStr)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	// Find the max prayer name length for alignment.
+	// Find the max prayer name width for alignment.
 	maxNameLen := 0
 	for _, p := range prayers {
-		if len(p.Name) > maxNameLen {
-			maxNameLen = len(p.Name)
-		}
+		maxNameLen = max(maxNameLen, display.Width(prayerLabel(lang, p.Name)))
 	}
 
 	// Print each prayer.
 	for _, p := range prayers {
-		timeStr := p.Time.Format(goTimeFmt)
-		nameStr := padRight(p.Name, maxNameLen)
+		timeStr := i18n.Clock(lang, p.Time, goTimeFmt)
+		nameStr := padRight(prayerLabel(lang, p.Name), maxNameLen)
 		line := fmt.Sprintf("  %-*s  %s", maxNameLen, nameStr, timeStr)
+		if t := transliteration(p.Name); transliterate && t != "" {
+			line += "  " + t
+		}
 
 		switch {
 		case current != nil && p.Name == current.Name:
 			// Current prayer: dimmed.
-			fmt.Println(display.Dim(line))
+			fmt.Fprintln(w, display.Dim(line))
 		case next != nil && p.Name == next.Name:
 			// Next prayer: accent color + countdown.
-			remaining := prayer.FormatRemaining(prayer.TimeRemaining(p, now))
-			suffix := fmt.Sprintf("  <- next in %s", remaining)
-			fmt.Println(display.Accent(line) + display.Accent(suffix))
+			remaining := i18n.Duration(lang, prayer.TimeRemaining(p, now))
+			suffix := "  " + i18n.Sprintf(lang, "ui.next", remaining)
+			fmt.Fprintln(w, display.Accent(line)+display.Accent(suffix))
 		default:
-			fmt.Println(line)
+			fmt.Fprintln(w, line)
 		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // formatGregorianDate returns a formatted Gregorian date string.
@@ -167,10 +236,7 @@ func formatGregorianDate(now time.Time, result *fetchResult) string {
 
 // padRight pads a string to the given width with spaces.
 func padRight(s string, width int) string {
-	if len(s) >= width {
-		return s
-	}
-	return s + strings.Repeat(" ", width-len(s))
+	return display.PadRight(s, width)
 }
 
 // todayJSON is the JSON output structure for the root command.
@@ -180,6 +246,10 @@ type todayJSON struct {
 	Timings  map[string]string `json:"timings"`
 	Current  string            `json:"current"`
 	Next     *todayJSONNext    `json:"next"`
+	Reminder *todayJSONRemind  `json:"reminder,omitempty"`
+	Khatmah  []khatmah.Portion `json:"khatmah,omitempty"`
+	Events   []string          `json:"events,omitempty"`
+	Stale    bool              `json:"stale,omitempty"` // times are from the cache; the API was unreachable
 }
 
 type todayJSONLocation struct {
@@ -195,6 +265,12 @@ type todayJSONDate struct {
 	Hijri     string `json:"hijri"`
 }
 
+type todayJSONRemind struct {
+	Arabic string `json:"arabic"`
+	Text   string `json:"text"`
+	Source string `json:"source"`
+}
+
 type todayJSONNext struct {
 	Prayer    string `json:"prayer"`
 	Time      string `json:"time"`
@@ -202,7 +278,21 @@ type todayJSONNext struct {
 }
 
 // printTodayJSON renders structured JSON output.
-func printTodayJSON(prayers []prayer.Prayer, current, next *prayer.Prayer, now time.Time, result *fetchResult, locationStr, tz, goTimeFmt string) error {
+func printTodayJSON(w io.Writer, prayers []prayer.Prayer, current, next *prayer.Prayer, now time.Time, result *fetchResult, locationStr, tz, goTimeFmt, lang string, rem *reminder.Reminder, quran []khatmah.Portion, events []string) error {
+	out := buildTodayJSON(prayers, current, next, now, result, locationStr, tz, goTimeFmt, lang, rem, quran)
+	out.Events = events
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// buildTodayJSON assembles the JSON structure shared by the root command and the HTTP server.
+// The reminder, if any, is translated into lang.
+func buildTodayJSON(prayers []prayer.Prayer, current, next *prayer.Prayer, now time.Time, result *fetchResult, locationStr, tz, goTimeFmt, lang string, rem *reminder.Reminder, quran []khatmah.Portion) todayJSON {
 	timings := make(map[string]string)
 	for _, p := range prayers {
 		timings[strings.ToLower(p.Name)] = p.Time.Format(goTimeFmt)
@@ -219,6 +309,8 @@ func printTodayJSON(prayers []prayer.Prayer, current, next *prayer.Prayer, now t
 			Hijri:     result.DateInfo.Hijri.Format(),
 		},
 		Timings: timings,
+		Khatmah: quran,
+		Stale:   result.Stale,
 	}
 
 	// Set city/country if available from meta or location string.
@@ -240,10 +332,13 @@ func printTodayJSON(prayers []prayer.Prayer, current, next *prayer.Prayer, now t
 		}
 	}
 
-	data, err := json.MarshalIndent(out, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+	if rem != nil {
+		out.Reminder = &todayJSONRemind{
+			Arabic: rem.Arabic,
+			Text:   rem.Text(lang),
+			Source: rem.Source,
+		}
 	}
-	fmt.Println(string(data))
-	return nil
+
+	return out
 }
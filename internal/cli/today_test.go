@@ -1,10 +1,18 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/hijri"
 	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
 )
 
@@ -117,3 +125,159 @@ func TestCurrentAndNext_Consistency(t *testing.T) {
 		t.Errorf("next = %s, want Asr", next.Name)
 	}
 }
+
+// coords returns a config with the given coordinates set.
+func coords(lat, lon float64) *config.Config {
+	return &config.Config{Latitude: &lat, Longitude: &lon}
+}
+
+func TestResolveLocation_ZeroCoordinates(t *testing.T) {
+	// 0°N 0°E is a real location, not "unset".
+	loc, err := resolveLocation(context.Background(), coords(0, 0), nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	if loc.Mode != locationCoords || loc.Lat != 0 || loc.Lon != 0 {
+		t.Errorf("resolveLocation(0, 0) = %+v, want coordinates 0, 0", loc)
+	}
+
+	// A location on the prime meridian only needs its latitude.
+	lat := 5.6037
+	loc, err = resolveLocation(context.Background(), &config.Config{Latitude: &lat, City: "Accra", Country: "GH"}, nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	if loc.Mode != locationCoords || loc.Lat != lat || loc.Lon != 0 {
+		t.Errorf("resolveLocation(latitude only) = %+v, want coordinates %v, 0", loc, lat)
+	}
+}
+
+func TestResolveLocation_CoordinatesTimezone(t *testing.T) {
+	loc, err := resolveLocation(context.Background(), coords(51.5074, -0.1278), nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	if loc.Timezone != "Europe/London" {
+		t.Errorf("Timezone = %q, want Europe/London", loc.Timezone)
+	}
+
+	// Late evening UTC is already tomorrow in Tokyo.
+	loc, err = resolveLocation(context.Background(), coords(35.6762, 139.6503), nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	now := time.Date(2026, 2, 28, 20, 0, 0, 0, time.UTC)
+	if got := loc.localTime(now).Format("2006-01-02"); got != "2026-03-01" {
+		t.Errorf("localTime() date = %s, want 2026-03-01", got)
+	}
+
+	// Points outside the index leave the timezone to the API.
+	loc, err = resolveLocation(context.Background(), coords(0, -150), nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	if loc.Timezone != "" {
+		t.Errorf("Timezone = %q, want empty", loc.Timezone)
+	}
+}
+
+func TestTimezoneFor(t *testing.T) {
+	tests := []struct {
+		name string
+		loc  resolvedLocation
+		meta api.Meta
+		want string
+	}{
+		{"own", resolvedLocation{Mode: locationCoords, Timezone: "Asia/Tokyo"}, api.Meta{Timezone: "Europe/London"}, "Asia/Tokyo"},
+		{"API", resolvedLocation{Mode: locationCity}, api.Meta{Timezone: "Europe/London"}, "Europe/London"},
+		{"coordinates", resolvedLocation{Mode: locationCoords, Lat: 0, Lon: -150}, api.Meta{}, "Etc/GMT+10"},
+		{"meta coordinates", resolvedLocation{Mode: locationCity}, api.Meta{Latitude: 30.0444, Longitude: 31.2357}, "Africa/Cairo"},
+		{"nothing", resolvedLocation{Mode: locationCity}, api.Meta{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.loc.timezoneFor(tt.meta); got != tt.want {
+			t.Errorf("%s: timezoneFor() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveLocation_TimezoneOverride(t *testing.T) {
+	cfg := coords(51.5074, -0.1278)
+	cfg.Timezone = "Asia/Tokyo"
+	loc, err := resolveLocation(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	if loc.Timezone != "Asia/Tokyo" {
+		t.Errorf("Timezone = %q, want Asia/Tokyo", loc.Timezone)
+	}
+
+	loc, err = resolveLocation(context.Background(), &config.Config{City: "London", Country: "UK", Timezone: "Asia/Tokyo"}, nil)
+	if err != nil {
+		t.Fatalf("resolveLocation() error: %v", err)
+	}
+	if loc.Mode != locationCity || loc.Timezone != "Asia/Tokyo" {
+		t.Errorf("resolveLocation(city) = %+v, want Asia/Tokyo override", loc)
+	}
+}
+
+// TestStaleFallback verifies an unreachable API falls back to the day
+// before's cached timings, marked stale.
+func TestStaleFallback(t *testing.T) {
+	isolateConfig(t)
+	prev := apiBaseURL
+	apiBaseURL = "http://127.0.0.1:1"
+	t.Cleanup(func() { apiBaseURL = prev })
+
+	cacheDir := t.TempDir()
+	args := []string{"--latitude", "21.4225", "--longitude", "39.8262", "--cache-dir", cacheDir, "--json", "--retries", "0"}
+	if _, _, code := runCLI(t, args...); code == 0 {
+		t.Fatal("with nothing cached, an unreachable API should fail")
+	}
+
+	riyadh, _ := time.LoadLocation("Asia/Riyadh")
+	c, err := cache.New(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yesterday := time.Now().In(riyadh).AddDate(0, 0, -1)
+	h := hijri.FromGregorian(yesterday, 0)
+	resp := &api.Response{Code: 200, Data: api.Data{
+		Timings: api.Timings{Fajr: "05:29", Sunrise: "06:49", Dhuhr: "12:30", Asr: "15:45", Maghrib: "18:09", Isha: "19:39"},
+		Date:    localDateInfo(yesterday, h),
+		Meta:    api.Meta{Latitude: 21.4225, Longitude: 39.8262, Timezone: "Asia/Riyadh"},
+	}}
+	if err := c.SaveTimings(yesterday, 21.4225, 39.8262, "", "", -1, -1, resp); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, code := runCLI(t, args...)
+	if code != 0 {
+		t.Fatalf("stale fallback exited with %d: %s", code, stderr)
+	}
+	var got todayJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !got.Stale || got.Timings["fajr"] != "05:29" {
+		t.Errorf("today = %+v, want yesterday's Fajr 05:29 marked stale", got)
+	}
+	if !strings.Contains(stderr, "unreachable") {
+		t.Errorf("stderr = %q, want a warning about the API", stderr)
+	}
+	// The times are yesterday's, but the date is today's.
+	today := time.Now().In(riyadh)
+	if want := apiHijri(h.AddDays(1)).Format(); got.Date.Gregorian != today.Format("02 January 2006") || got.Date.Hijri != want {
+		t.Errorf("stale date = %+v, want today's, %q", got.Date, want)
+	}
+
+	// An error from the API isn't hidden behind the cache.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":400,"status":"Bad Request","data":"Invalid method"}`, http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+	apiBaseURL = srv.URL
+	if _, stderr, code := runCLI(t, args...); code == 0 || strings.Contains(stderr, "unreachable") {
+		t.Errorf("with the API answering 400: exit %d, stderr %q; want the API's error", code, stderr)
+	}
+}
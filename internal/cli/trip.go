@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/ical"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTripLegs     string
+	flagTripUntil    string
+	flagTripFormat   string
+	flagTripAlarm    time.Duration
+	flagTripDuration time.Duration
+)
+
+func newTripCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trip",
+		Short: "Show a combined schedule for a multi-city itinerary",
+		Long: `Show prayer times for a trip, switching city and timezone at each leg.
+
+Legs are semicolon-separated "date,city,country" entries in date order. Each
+leg lasts until the next one starts; the last one covers its own date, or up
+to --until.
+
+Output is a table per leg by default; use --format json (or --json) or
+--format ics for a calendar to import before travelling.`,
+		Example: `  prayer-times trip --legs "2026-03-01,Istanbul,TR;2026-03-04,Kuala Lumpur,MY"
+  prayer-times trip --legs "2026-05-20,Jeddah,SA;2026-05-22,Mecca,SA" --until 2026-05-30
+  prayer-times trip --legs "2026-03-01,Istanbul,TR;2026-03-04,Kuala Lumpur,MY" --format ics --alarm 10m > trip.ics`,
+		Args: cobra.NoArgs,
+		RunE: runTrip,
+	}
+
+	cmd.Flags().StringVar(&flagTripLegs, "legs", "", "Itinerary as \"YYYY-MM-DD,City,Country;...\" (required)")
+	cmd.Flags().StringVar(&flagTripUntil, "until", "", "Last day of the trip, inclusive (YYYY-MM-DD; default: the last leg's date)")
+	cmd.Flags().StringVar(&flagTripFormat, "format", "text", "Output format: text, json or ics")
+	cmd.Flags().DurationVar(&flagTripAlarm, "alarm", 0, "With --format ics, add a reminder this long before each prayer")
+	cmd.Flags().DurationVar(&flagTripDuration, "duration", 15*time.Minute, "With --format ics, length of each calendar event")
+
+	return cmd
+}
+
+// tripLeg is one stop of an itinerary.
+type tripLeg struct {
+	Start         time.Time // first day at this stop
+	Days          int
+	City, Country string
+}
+
+// parseLegs parses "date,city,country;..." legs and sizes each to run until
+// the next one. The last leg runs until until, or for one day when until is zero.
+func parseLegs(s string, until time.Time) ([]tripLeg, error) {
+	var legs []tripLeg
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields := strings.Split(part, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid leg %q: want YYYY-MM-DD,City,Country", part)
+		}
+		start, err := parseDateFlag(strings.TrimSpace(fields[0]), time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid leg %q: %w", part, err)
+		}
+		city, country := strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2])
+		if city == "" || country == "" {
+			return nil, fmt.Errorf("invalid leg %q: want YYYY-MM-DD,City,Country", part)
+		}
+		if n := len(legs); n > 0 && !start.After(legs[n-1].Start) {
+			return nil, fmt.Errorf("invalid leg %q: legs must be in date order", part)
+		}
+		legs = append(legs, tripLeg{Start: start, City: city, Country: country})
+	}
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("no legs given: want \"YYYY-MM-DD,City,Country;...\"")
+	}
+
+	for i := range legs[:len(legs)-1] {
+		legs[i].Days = daysBetween(legs[i].Start, legs[i+1].Start)
+	}
+	last := &legs[len(legs)-1]
+	last.Days = 1
+	if !until.IsZero() {
+		if until.Before(last.Start) {
+			return nil, fmt.Errorf("--until %s is before the last leg", until.Format("2006-01-02"))
+		}
+		last.Days = daysBetween(last.Start, until) + 1
+	}
+
+	total := daysBetween(legs[0].Start, last.Start) + last.Days
+	if total > maxExportDays {
+		return nil, fmt.Errorf("trip of %d days is too long (max %d)", total, maxExportDays)
+	}
+	return legs, nil
+}
+
+// tripLegData is one leg with its fetched days.
+type tripLegData struct {
+	Leg         tripLeg
+	LocationStr string
+	Timezone    string
+	TZ          *time.Location
+	Days        []dayData
+}
+
+// tripJSONLeg is one leg of the trip command's JSON output.
+type tripJSONLeg struct {
+	City     string        `json:"city"`
+	Country  string        `json:"country"`
+	Timezone string        `json:"timezone"`
+	Days     []listJSONDay `json:"days"`
+}
+
+func runTrip(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	format := flagTripFormat
+	if FlagJSON {
+		format = "json"
+	}
+	if format != "text" && format != "json" && format != "ics" {
+		return fmt.Errorf("invalid --format %q: must be text, json or ics", format)
+	}
+
+	var until time.Time
+	if flagTripUntil != "" {
+		var err error
+		if until, err = parseDateFlag(flagTripUntil, time.UTC); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	legs, err := parseLegs(flagTripLegs, until)
+	if err != nil {
+		return err
+	}
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+	c := openCache(cfg)
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+
+	var data []tripLegData
+	for _, leg := range legs {
+		loc := resolvedLocation{Mode: locationCity, City: leg.City, Country: leg.Country, Timezone: timezoneOverride}
+		daysList, err := fetchCalendarDays(ctx, leg.Start, leg.Days, loc, method, school, c)
+		if err != nil {
+			return fmt.Errorf("%s, %s: %w", leg.City, leg.Country, err)
+		}
+
+		tz := loc.timezoneFor(daysList[0].Meta)
+		tzLoc, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+		data = append(data, tripLegData{
+			Leg:         leg,
+			LocationStr: buildLocationStr(loc, &fetchResult{Meta: daysList[0].Meta}),
+			Timezone:    tz,
+			TZ:          tzLoc,
+			Days:        daysList,
+		})
+	}
+
+	switch format {
+	case "json":
+		out := make([]tripJSONLeg, 0, len(data))
+		for _, d := range data {
+			list, err := buildListJSON(d.Days, selectedPrayers, d.LocationStr, d.Timezone, goTimeFmt, d.TZ)
+			if err != nil {
+				return err
+			}
+			out = append(out, tripJSONLeg{City: d.Leg.City, Country: d.Leg.Country, Timezone: d.Timezone, Days: list.Days})
+		}
+		buf, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(buf))
+		return nil
+	case "ics":
+		cal := &ical.Calendar{Name: "Prayer Times — Trip"}
+		for _, d := range data {
+			events, err := prayerEvents(d.Days, d.TZ, selectedPrayers, d.LocationStr, flagTripDuration, flagTripAlarm)
+			if err != nil {
+				return err
+			}
+			cal.Events = append(cal.Events, events...)
+		}
+		if err := cal.Encode(w); err != nil {
+			return fmt.Errorf("failed to write calendar: %w", err)
+		}
+		return nil
+	}
+
+	return printTripText(w, data, selectedPrayers, goTimeFmt)
+}
+
+// printTripText renders one table per leg for the terminal.
+func printTripText(w io.Writer, data []tripLegData, selected []string, goTimeFmt string) error {
+	first, last := data[0].Leg, data[len(data)-1].Leg
+	end := last.Start.AddDate(0, 0, last.Days-1)
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold(fmt.Sprintf("Trip Prayer Times — %s to %s", first.Start.Format("02 Jan"), end.Format("02 Jan 2006"))))
+
+	for _, d := range data {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  %s  %s\n", display.Accent(d.LocationStr), display.Dim(d.Timezone))
+		fmt.Fprintln(w)
+
+		tbl := display.NewTable(append([]string{"Date"}, selected...))
+		for _, dd := range d.Days {
+			day := calendarDay(dd.Date, d.TZ)
+			parsed, err := prayer.ParseTimings(dd.Timings, day, d.TZ, selected)
+			if err != nil {
+				return err
+			}
+			row := []string{day.Format("Mon 02 Jan")}
+			for _, p := range parsed {
+				row = append(row, p.Time.Format(goTimeFmt))
+			}
+			tbl.AddRow(row)
+		}
+		fmt.Fprint(w, tbl.Render())
+	}
+	fmt.Fprintln(w)
+	return nil
+}
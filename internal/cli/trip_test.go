@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLegs(t *testing.T) {
+	legs, err := parseLegs("2026-03-01,Istanbul,TR; 2026-03-04, Kuala Lumpur, MY", time.Time{})
+	if err != nil {
+		t.Fatalf("parseLegs() error: %v", err)
+	}
+	if len(legs) != 2 {
+		t.Fatalf("parseLegs() returned %d legs, want 2", len(legs))
+	}
+	if legs[0].City != "Istanbul" || legs[0].Days != 3 {
+		t.Errorf("legs[0] = %+v, want Istanbul for 3 days", legs[0])
+	}
+	if legs[1].City != "Kuala Lumpur" || legs[1].Country != "MY" || legs[1].Days != 1 {
+		t.Errorf("legs[1] = %+v, want Kuala Lumpur, MY for 1 day", legs[1])
+	}
+
+	until := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	if legs, err = parseLegs("2026-03-01,Istanbul,TR;2026-03-04,Kuala Lumpur,MY", until); err != nil || legs[1].Days != 7 {
+		t.Errorf("parseLegs(until 10 Mar) last leg = %+v, %v; want 7 days", legs[1], err)
+	}
+
+	for _, s := range []string{
+		"",
+		"2026-03-01,Istanbul",
+		"01-03-2026,Istanbul,TR",
+		"2026-03-01,,TR",
+		"2026-03-04,Istanbul,TR;2026-03-01,Kuala Lumpur,MY",
+		"2026-03-01,Istanbul,TR;2026-03-01,Kuala Lumpur,MY",
+		"2026-01-01,Istanbul,TR;2027-06-01,Kuala Lumpur,MY",
+	} {
+		if _, err := parseLegs(s, time.Time{}); err == nil {
+			t.Errorf("parseLegs(%q) expected error", s)
+		}
+	}
+	if _, err := parseLegs("2026-03-04,Istanbul,TR", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("parseLegs() with --until before the last leg expected error")
+	}
+}
+
+func TestTripJSON(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, "trip", "--legs", "2026-02-27,Istanbul,TR;2026-03-01,Kuala Lumpur,MY", "--until", "2026-03-02", "--json", "--cache-dir", t.TempDir())
+	if code != 0 {
+		t.Fatalf("trip --json exited with %d: %s", code, stderr)
+	}
+
+	var legs []tripJSONLeg
+	if err := json.Unmarshal([]byte(out), &legs); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(legs) != 2 || len(legs[0].Days) != 2 || len(legs[1].Days) != 2 {
+		t.Fatalf("trip --json = %+v, want 2 legs of 2 days", legs)
+	}
+	if legs[1].City != "Kuala Lumpur" || legs[1].Days[0].Date != "01 Mar 2026" {
+		t.Errorf("legs[1] = %+v, want Kuala Lumpur from 01 Mar 2026", legs[1])
+	}
+}
+
+func TestTripICS(t *testing.T) {
+	isolateConfig(t)
+	mockAPI(t)
+
+	out, stderr, code := runCLI(t, "trip", "--legs", "2026-02-27,Istanbul,TR;2026-02-28,Kuala Lumpur,MY", "--format", "ics", "--prayers", "Fajr,Isha", "--cache-dir", t.TempDir())
+	if code != 0 {
+		t.Fatalf("trip --format ics exited with %d: %s", code, stderr)
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 4 {
+		t.Errorf("got %d events, want 4", n)
+	}
+	if !strings.Contains(out, "LOCATION:Kuala Lumpur\\, MY") {
+		t.Errorf("calendar missing Kuala Lumpur events:\n%s", out)
+	}
+}
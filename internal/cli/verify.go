@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/archive"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagVerifySample    int
+	flagVerifyThreshold time.Duration
+)
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Re-fetch archived days and report upstream changes",
+		Long: `Re-fetch a sample of archived days from the API and compare them with the
+archived times, reporting any prayer whose time moved by more than --threshold.
+
+This detects subtle upstream changes to the calculation for your method and
+location. It requires the archive (prayer-times config set archive true) and
+exits with a non-zero status when discrepancies are found, so it can run from cron.`,
+		Args: cobra.NoArgs,
+		RunE: runVerify,
+		Example: `  prayer-times verify
+  prayer-times verify --sample 30 --threshold 1m`,
+	}
+
+	cmd.Flags().IntVar(&flagVerifySample, "sample", 7, "Number of archived days to re-check, spread across the archive")
+	cmd.Flags().DurationVar(&flagVerifyThreshold, "threshold", 0, "Ignore differences up to this duration (e.g. 1m)")
+
+	return cmd
+}
+
+// verifyJSONDiscrepancy is the JSON structure for one detected discrepancy.
+type verifyJSONDiscrepancy struct {
+	Date     string `json:"date"`
+	Location string `json:"location"`
+	Prayer   string `json:"prayer"`
+	Archived string `json:"archived"`
+	Current  string `json:"current"`
+	Minutes  int    `json:"minutes"`
+}
+
+// verifyJSONOutput is the JSON output structure for the verify command.
+type verifyJSONOutput struct {
+	Checked       int                     `json:"checked"`
+	Discrepancies []verifyJSONDiscrepancy `json:"discrepancies"`
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	if flagVerifySample < 1 {
+		return fmt.Errorf("invalid --sample %d: must be a positive integer", flagVerifySample)
+	}
+
+	a, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	dates, err := a.Dates()
+	if err != nil {
+		return err
+	}
+	if len(dates) == 0 {
+		return fmt.Errorf("the archive is empty; enable it with: prayer-times config set archive true")
+	}
+
+	client := newAPIClient()
+	thresholdMin := int(flagVerifyThreshold.Minutes())
+
+	out := verifyJSONOutput{Discrepancies: []verifyJSONDiscrepancy{}}
+	for _, date := range sampleDates(dates, flagVerifySample) {
+		records, err := a.Load(date)
+		if err != nil {
+			return err
+		}
+
+		for _, rec := range latestPerKey(records) {
+			current, err := refetchRecord(ctx, client, rec)
+			if err != nil {
+				return fmt.Errorf("failed to re-fetch %s for %s: %w", date, rec.Label(), err)
+			}
+			out.Checked++
+
+			for _, ch := range archive.Diff(rec.Timings, current.Timings) {
+				if abs(ch.Minutes) <= thresholdMin {
+					continue
+				}
+				out.Discrepancies = append(out.Discrepancies, verifyJSONDiscrepancy{
+					Date:     date,
+					Location: rec.Label(),
+					Prayer:   ch.Prayer,
+					Archived: ch.Old,
+					Current:  ch.New,
+					Minutes:  ch.Minutes,
+				})
+			}
+		}
+	}
+
+	if FlagJSON {
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+	} else if len(out.Discrepancies) == 0 {
+		fmt.Fprintf(w, "Verified %d archived day(s): no discrepancies.\n", out.Checked)
+	} else {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  %s\n", display.Bold("Upstream Discrepancies"))
+		fmt.Fprintln(w)
+
+		tbl := display.NewTable([]string{"Date", "Location", "Prayer", "Archived", "Current", "Change"})
+		for _, d := range out.Discrepancies {
+			tbl.AddRow([]string{d.Date, d.Location, d.Prayer, d.Archived, d.Current, fmt.Sprintf("%+dm", d.Minutes)})
+		}
+		fmt.Fprint(w, tbl.Render())
+		fmt.Fprintln(w)
+	}
+
+	if len(out.Discrepancies) > 0 {
+		return fmt.Errorf("%d discrepancies found across %d archived day(s)", len(out.Discrepancies), out.Checked)
+	}
+	return nil
+}
+
+// sampleDates picks up to n dates spread evenly across the (sorted) archive,
+// always including the most recent one.
+func sampleDates(dates []string, n int) []string {
+	if n >= len(dates) {
+		return dates
+	}
+	if n == 1 {
+		return dates[len(dates)-1:]
+	}
+	step := float64(len(dates)-1) / float64(n-1)
+	picked := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		picked = append(picked, dates[int(float64(i)*step+0.5)])
+	}
+	return picked
+}
+
+// latestPerKey returns the most recent record for each location/method key,
+// in order of first appearance.
+func latestPerKey(records []archive.Record) []archive.Record {
+	index := make(map[string]int)
+	var out []archive.Record
+	for _, r := range records {
+		if i, ok := index[r.Key()]; ok {
+			out[i] = r
+			continue
+		}
+		index[r.Key()] = len(out)
+		out = append(out, r)
+	}
+	return out
+}
+
+// refetchRecord fetches the archived day again straight from the API, bypassing the cache.
+// The fresh result is archived too, so later 'history diff' runs see the change.
+func refetchRecord(ctx context.Context, client *api.Client, rec archive.Record) (*api.Data, error) {
+	date, err := time.Parse("2006-01-02", rec.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-fetch with the record's own calculation settings, not the current ones.
+	withSettings := *client
+	withSettings.MethodSettings = rec.Settings
+	withSettings.Tune = rec.Tune
+	withSettings.LatitudeAdjustment = rec.LatAdjust
+	withSettings.MidnightMode = rec.Midnight
+	withSettings.Shafaq = rec.Shafaq
+	withSettings.Timezone = rec.TZ
+	client = &withSettings
+
+	loc := resolvedLocation{Mode: locationCoords, Lat: rec.Latitude, Lon: rec.Longitude}
+	var resp *api.Response
+	if rec.City != "" {
+		loc = resolvedLocation{Mode: locationCity, City: rec.City, Country: rec.Country}
+		resp, err = client.FetchByCity(ctx, date, rec.City, rec.Country, rec.Method, rec.School)
+	} else {
+		resp, err = client.FetchByCoordinates(ctx, date, rec.Latitude, rec.Longitude, rec.Method, rec.School)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	archiveDay(client, rec.Date, loc, rec.Method, rec.School, resp.Data)
+	return &resp.Data, nil
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/archive"
+)
+
+func TestSampleDates(t *testing.T) {
+	dates := []string{"d1", "d2", "d3", "d4", "d5", "d6", "d7", "d8", "d9"}
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{1, []string{"d9"}},
+		{2, []string{"d1", "d9"}},
+		{3, []string{"d1", "d5", "d9"}},
+		{20, dates},
+	}
+
+	for _, tt := range tests {
+		got := sampleDates(dates, tt.n)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sampleDates(n=%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLatestPerKey(t *testing.T) {
+	records := []archive.Record{
+		{City: "London", Country: "UK", Method: 2, Timezone: "v1"},
+		{City: "Leeds", Country: "UK", Method: 2, Timezone: "v1"},
+		{City: "London", Country: "UK", Method: 2, Timezone: "v2"},
+	}
+
+	got := latestPerKey(records)
+	if len(got) != 2 {
+		t.Fatalf("latestPerKey() returned %d records, want 2", len(got))
+	}
+	if got[0].City != "London" || got[0].Timezone != "v2" {
+		t.Errorf("got[0] = %+v, want latest London record", got[0])
+	}
+	if got[1].City != "Leeds" {
+		t.Errorf("got[1] = %+v, want Leeds", got[1])
+	}
+}
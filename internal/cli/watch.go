@@ -0,0 +1,436 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/reminder"
+	"github.com/spf13/cobra"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	hideCursor  = "\033[?25l"
+	showCursor  = "\033[?25h"
+
+	progressBarWidth = 30
+)
+
+var (
+	flagWatchInterval      time.Duration
+	flagWatchTheme         string
+	flagWatchThemeSchedule string
+	flagWatchShift         bool
+	flagWatchBlankFrom     string
+	flagWatchBlankUntil    string
+)
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Full-screen live dashboard with a countdown to the next prayer",
+		Long: `Render a full-screen, auto-refreshing dashboard: today's schedule, a live
+countdown to the next prayer, a progress bar for the current prayer window,
+and the Hijri date.
+
+When adhan_sound is set, the adhan plays as each prayer begins, and when
+chime_sound is set, a chime plays shortly before (see 'prayer-times adhan').
+
+--theme paints the screen light, dark or dim; --theme auto switches between
+them through the day following --theme-schedule, so a display left on in a
+hallway isn't blinding at night.
+
+--reminder (or the reminder config key) adds the day's verse or hadith
+beneath the countdown, translated into --lang.
+
+For screens left on around the clock, --shift moves the dashboard by a
+character or line every few minutes, and --blank-from/--blank-until blank
+the screen overnight, e.g. from Isha+2h until Fajr-1h.
+
+Timings are fetched once per day (from the cache when possible); refreshes
+never hit the API. Press Ctrl+C to exit.`,
+		Args: cobra.NoArgs,
+		RunE: runWatch,
+		Example: `  prayer-times watch
+  prayer-times watch --theme auto --shift
+  prayer-times watch --blank-from Isha+2h --blank-until Fajr-1h`,
+	}
+
+	cmd.Flags().DurationVar(&flagWatchInterval, "interval", time.Second, "Refresh interval")
+	cmd.Flags().StringVar(&flagWatchTheme, "theme", "none", "Screen colours: none (the terminal's), auto, light, dark or dim")
+	cmd.Flags().StringVar(&flagWatchThemeSchedule, "theme-schedule", defaultThemeSchedule, "Theme switches for --theme auto, as Prayer:theme pairs")
+	cmd.Flags().BoolVar(&flagWatchShift, "shift", false, "Move the dashboard slightly every few minutes to prevent burn-in")
+	cmd.Flags().StringVar(&flagWatchBlankFrom, "blank-from", "", "Blank the screen from this time, e.g. Isha+2h or 23:30")
+	cmd.Flags().StringVar(&flagWatchBlankUntil, "blank-until", "", "Blank the screen until this time, e.g. Fajr-1h or 05:00")
+	cmd.Flags().BoolVar(&flagReminder, "reminder", false, "Show a daily verse/hadith about prayer under the countdown")
+
+	return cmd
+}
+
+// watchSource loads and memoizes one day of timings at a time for the dashboard.
+type watchSource struct {
+	loc      resolvedLocation
+	method   int
+	school   int
+	prayers  []string
+	cache    *cache.Cache
+	tzLoc    *time.Location
+	days     map[string]*fetchResult
+	location string
+	// reminder shows the day's reminder, translated into lang.
+	reminder bool
+	lang     string
+	// staleUntil is when a stale day (see fetchResult.Stale) is next
+	// fetched again, in case the API is back.
+	staleUntil time.Time
+}
+
+// staleRetryInterval is how often watch retries the API while showing
+// stale timings.
+const staleRetryInterval = time.Minute
+
+// day returns the timings for date, fetching them (cache first) on first use
+// and again every staleRetryInterval while they are stale.
+func (s *watchSource) day(ctx context.Context, date time.Time) (*fetchResult, error) {
+	key := date.Format("2006-01-02")
+	if r, ok := s.days[key]; ok && (!r.Stale || time.Now().Before(s.staleUntil)) {
+		return r, nil
+	}
+	r, err := fetchTimings(ctx, date, s.loc, s.method, s.school, s.cache)
+	if err != nil {
+		return nil, err
+	}
+	if r.Stale {
+		s.staleUntil = time.Now().Add(staleRetryInterval)
+	}
+	// Only today, yesterday, and tomorrow are ever needed.
+	if len(s.days) > 3 {
+		s.days = make(map[string]*fetchResult)
+	}
+	s.days[key] = r
+	return r, nil
+}
+
+// parsed returns the selected prayers for date.
+func (s *watchSource) parsed(ctx context.Context, date time.Time) ([]prayer.Prayer, *fetchResult, error) {
+	r, err := s.day(ctx, date)
+	if err != nil {
+		return nil, nil, err
+	}
+	prayers, err := prayer.ParseTimings(r.Timings, date, s.tzLoc, s.prayers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prayers, r, nil
+}
+
+// theme returns the --theme-schedule theme for now, or "" if the day's
+// timings lack a scheduled prayer.
+func (s *watchSource) theme(ctx context.Context, now time.Time, steps []themeStep) string {
+	r, err := s.day(ctx, now)
+	if err != nil {
+		return ""
+	}
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.Prayer
+	}
+	times, err := prayer.ParseTimings(r.Timings, now, s.tzLoc, names)
+	if err != nil {
+		return ""
+	}
+	return scheduledTheme(steps, times, now)
+}
+
+// blanked reports whether now falls in the --blank-from/--blank-until
+// period. The screen stays on if the day's timings can't be read.
+func (s *watchSource) blanked(ctx context.Context, now time.Time, from, until blankPoint) bool {
+	var names []string
+	for _, p := range []blankPoint{from, until} {
+		if p.Prayer != "" {
+			names = append(names, p.Prayer)
+		}
+	}
+	times := make(map[string]time.Time)
+	if len(names) > 0 {
+		r, err := s.day(ctx, now)
+		if err != nil {
+			return false
+		}
+		prayers, err := prayer.ParseTimings(r.Timings, now, s.tzLoc, names)
+		if err != nil {
+			return false
+		}
+		for _, p := range prayers {
+			times[p.Name] = p.Time
+		}
+	}
+	return inBlankPeriod(from, until, times, now)
+}
+
+// watchFrame holds everything needed to draw one dashboard frame.
+type watchFrame struct {
+	Now      time.Time
+	Location string
+	Hijri    string
+	Prayers  []prayer.Prayer
+	// Prev and Next bound the current prayer window. Either may fall on
+	// the previous or next day.
+	Prev, Next *prayer.Prayer
+	// Reminder is the day's reminder, if shown, translated into Lang.
+	Reminder *reminder.Reminder
+	Lang     string
+}
+
+// frame builds the dashboard state for now.
+func (s *watchSource) frame(ctx context.Context, now time.Time) (watchFrame, error) {
+	prayers, today, err := s.parsed(ctx, now)
+	if err != nil {
+		return watchFrame{}, err
+	}
+
+	f := watchFrame{
+		Now:      now,
+		Location: s.location,
+		Hijri:    today.DateInfo.Hijri.Format(),
+		Prayers:  prayers,
+		Prev:     prayer.CurrentPrayer(prayers, now),
+		Next:     prayer.NextPrayer(prayers, now),
+	}
+	if s.reminder {
+		r := reminder.ForDate(now)
+		f.Reminder, f.Lang = &r, s.lang
+	}
+
+	// Window edges outside today come from the neighbouring days. Failures
+	// here only cost the progress bar, so they are not fatal.
+	if f.Prev == nil {
+		if y, _, err := s.parsed(ctx, now.AddDate(0, 0, -1)); err == nil && len(y) > 0 {
+			f.Prev = &y[len(y)-1]
+		}
+	}
+	if f.Next == nil {
+		if t, _, err := s.parsed(ctx, now.AddDate(0, 0, 1)); err == nil && len(t) > 0 {
+			f.Next = &t[0]
+		}
+	}
+	return f, nil
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	if flagWatchInterval < 100*time.Millisecond {
+		return fmt.Errorf("invalid --interval %s: must be at least 100ms", flagWatchInterval)
+	}
+	if _, ok := watchThemes[flagWatchTheme]; !ok && flagWatchTheme != "none" && flagWatchTheme != "auto" {
+		return fmt.Errorf("invalid --theme %q: must be one of %s", flagWatchTheme, strings.Join(themeOptions, ", "))
+	}
+	var themeSteps []themeStep
+	if flagWatchTheme == "auto" {
+		var err error
+		if themeSteps, err = parseThemeSchedule(flagWatchThemeSchedule); err != nil {
+			return err
+		}
+	}
+	if (flagWatchBlankFrom == "") != (flagWatchBlankUntil == "") {
+		return fmt.Errorf("--blank-from and --blank-until must be set together")
+	}
+	var blankFrom, blankUntil blankPoint
+	blank := flagWatchBlankFrom != ""
+	if blank {
+		var err error
+		if blankFrom, err = parseBlankPoint("--blank-from", flagWatchBlankFrom); err != nil {
+			return err
+		}
+		if blankUntil, err = parseBlankPoint("--blank-until", flagWatchBlankUntil); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := effectiveConfig(cmd)
+	goTimeFmt := goTimeFormat(cfg)
+
+	c := openCache(cfg)
+
+	loc, err := resolveLocation(ctx, cfg, c)
+	if err != nil {
+		return err
+	}
+
+	src := &watchSource{
+		loc:      loc,
+		method:   cfg.MethodOrDefault(-1),
+		school:   cfg.SchoolOrDefault(-1),
+		prayers:  selectedPrayerNames(cfg),
+		cache:    c,
+		days:     make(map[string]*fetchResult),
+		reminder: cfg.Reminder,
+		lang:     displayLang(cfg, loc),
+	}
+
+	// Resolve the timezone and location label from the first fetch.
+	first, err := src.day(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	tz := loc.timezoneFor(first.Meta)
+	src.tzLoc, err = time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	src.location = buildLocationStr(loc, first)
+
+	alert := newAdhanAlert(cfg)
+	chime, err := newChimeAlert(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, hideCursor)
+	defer fmt.Fprint(w, showCursor)
+	if flagWatchTheme != "none" || blank {
+		defer fmt.Fprint(w, resetStyle+clearScreen)
+	}
+
+	ticker := time.NewTicker(flagWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		f, err := src.frame(ctx, time.Now().In(src.tzLoc))
+		switch {
+		case ctx.Err() != nil:
+			// Interrupted mid-fetch; leave the last frame and exit below.
+		case err != nil:
+			// Keep the last frame on screen and retry on the next tick;
+			// a transient network failure shouldn't kill the dashboard.
+			notices.Warnf("%v", err)
+		default:
+			if blank && src.blanked(ctx, f.Now, blankFrom, blankUntil) {
+				fmt.Fprint(w, blankScreen())
+			} else {
+				theme := flagWatchTheme
+				if themeSteps != nil {
+					theme = src.theme(ctx, f.Now, themeSteps)
+				}
+				frame := renderWatchFrame(f, goTimeFmt)
+				if flagWatchShift {
+					dx, dy := shiftOffset(f.Now)
+					frame = shiftFrame(frame, dx, dy)
+				}
+				fmt.Fprint(w, applyTheme(clearScreen+frame, theme))
+			}
+			// The adhan and chime play even while the screen is blank.
+			if chime != nil && chime.due(f) {
+				if err := chime.play(); err != nil {
+					notices.Warnf("chime: %v", err)
+				}
+			}
+			if alert != nil {
+				if file := alert.due(f); file != "" {
+					if err := alert.play(file); err != nil {
+						notices.Warnf("adhan: %v", err)
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(w)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderWatchFrame draws one dashboard frame.
+func renderWatchFrame(f watchFrame, goTimeFmt string) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "  %s\n", display.Bold("Prayer Times"))
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "  %s\n", f.Location)
+	fmt.Fprintf(&sb, "  %s\n", f.Now.Format("Monday, 02 January 2006"))
+	if f.Hijri != "" {
+		fmt.Fprintf(&sb, "  %s\n", f.Hijri)
+	}
+	fmt.Fprintf(&sb, "  %s\n", display.Bold(f.Now.Format("15:04:05")))
+	sb.WriteString("\n")
+
+	maxNameLen := 0
+	for _, p := range f.Prayers {
+		if len(p.Name) > maxNameLen {
+			maxNameLen = len(p.Name)
+		}
+	}
+	for _, p := range f.Prayers {
+		line := fmt.Sprintf("  %-*s  %s", maxNameLen, p.Name, p.Time.Format(goTimeFmt))
+		switch {
+		case f.Next != nil && p.Name == f.Next.Name && p.Time.Equal(f.Next.Time):
+			fmt.Fprintln(&sb, display.Accent(line+"  <- next"))
+		case f.Prev != nil && p.Name == f.Prev.Name && p.Time.Equal(f.Prev.Time):
+			fmt.Fprintln(&sb, display.Bold(line))
+		case p.Time.Before(f.Now):
+			fmt.Fprintln(&sb, display.Dim(line))
+		default:
+			fmt.Fprintln(&sb, line)
+		}
+	}
+	sb.WriteString("\n")
+
+	if f.Next != nil {
+		remaining := f.Next.Time.Sub(f.Now)
+		fmt.Fprintf(&sb, "  %s in %s\n", display.Accent(f.Next.Name), display.Bold(formatCountdown(remaining)))
+		if f.Prev != nil {
+			window := f.Next.Time.Sub(f.Prev.Time)
+			elapsed := f.Now.Sub(f.Prev.Time)
+			fmt.Fprintf(&sb, "  %s  %s\n", progressBar(elapsed, window, progressBarWidth), display.Dim(f.Prev.Name+" → "+f.Next.Name))
+		}
+		sb.WriteString("\n")
+	}
+
+	if f.Reminder != nil {
+		sb.WriteString(reminderText(*f.Reminder, f.Lang))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// formatCountdown formats d as H:MM:SS, clamping negative values to zero.
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Truncate(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
+
+// progressBar renders elapsed/total as a fixed-width bar with a percentage.
+func progressBar(elapsed, total time.Duration, width int) string {
+	frac := 0.0
+	if total > 0 {
+		frac = float64(elapsed) / float64(total)
+	}
+	frac = max(0, min(1, frac))
+
+	filled := int(frac * float64(width))
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]" +
+		fmt.Sprintf(" %3d%%", int(frac*100))
+}
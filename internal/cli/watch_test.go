@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/reminder"
+)
+
+// newTestWatchSource returns a source with three days of timings preloaded,
+// so frames never touch the network.
+func newTestWatchSource(t *testing.T) *watchSource {
+	t.Helper()
+
+	riyadh, err := time.LoadLocation("Asia/Riyadh")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	s := &watchSource{
+		prayers:  []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"},
+		tzLoc:    riyadh,
+		days:     make(map[string]*fetchResult),
+		location: "Mecca, SA",
+	}
+	for _, date := range []string{"2026-02-09", "2026-02-10", "2026-02-11"} {
+		s.days[date] = &fetchResult{
+			Timings: api.Timings{Fajr: "05:30", Dhuhr: "12:30", Asr: "15:45", Maghrib: "18:10", Isha: "19:40"},
+			DateInfo: api.DateInfo{Hijri: api.HijriDate{
+				Day: "22", Month: api.HijriMonth{En: "Shaʿbān"}, Year: "1447",
+			}},
+		}
+	}
+	return s
+}
+
+func TestWatchFrame_Midday(t *testing.T) {
+	s := newTestWatchSource(t)
+	now := time.Date(2026, 2, 10, 14, 0, 0, 0, s.tzLoc)
+
+	f, err := s.frame(context.Background(), now)
+	if err != nil {
+		t.Fatalf("frame() error: %v", err)
+	}
+	if f.Prev == nil || f.Prev.Name != "Dhuhr" || f.Next == nil || f.Next.Name != "Asr" {
+		t.Errorf("window = %v -> %v, want Dhuhr -> Asr", f.Prev, f.Next)
+	}
+}
+
+func TestWatchFrame_WindowsCrossMidnight(t *testing.T) {
+	s := newTestWatchSource(t)
+
+	// Before Fajr, the window started at yesterday's Isha.
+	f, err := s.frame(context.Background(), time.Date(2026, 2, 10, 3, 0, 0, 0, s.tzLoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Prev == nil || f.Prev.Name != "Isha" || f.Prev.Time.Day() != 9 {
+		t.Errorf("Prev = %v, want Isha on the 9th", f.Prev)
+	}
+
+	// After Isha, the window ends at tomorrow's Fajr.
+	f, err = s.frame(context.Background(), time.Date(2026, 2, 10, 22, 0, 0, 0, s.tzLoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Next == nil || f.Next.Name != "Fajr" || f.Next.Time.Day() != 11 {
+		t.Errorf("Next = %v, want Fajr on the 11th", f.Next)
+	}
+}
+
+func TestRenderWatchFrame(t *testing.T) {
+	s := newTestWatchSource(t)
+	f, _ := s.frame(context.Background(), time.Date(2026, 2, 10, 14, 0, 0, 0, s.tzLoc))
+
+	out := renderWatchFrame(f, "15:04")
+	for _, want := range []string{"Mecca, SA", "22 Shaʿbān 1447 AH", "Asr", "1:45:00", "Dhuhr → Asr"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("frame missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderWatchFrame_Reminder(t *testing.T) {
+	s := newTestWatchSource(t)
+	s.reminder, s.lang = true, "tr"
+	now := time.Date(2026, 2, 10, 14, 0, 0, 0, s.tzLoc)
+	f, _ := s.frame(context.Background(), now)
+
+	r := reminder.ForDate(now)
+	out := renderWatchFrame(f, "15:04")
+	for _, want := range []string{r.Arabic, r.Translations["tr"], r.Source} {
+		if !strings.Contains(out, want) {
+			t.Errorf("frame missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00:00"},
+		{-time.Second, "0:00:00"},
+		{90*time.Second + 500*time.Millisecond, "0:01:30"},
+		{2*time.Hour + 5*time.Minute + 9*time.Second, "2:05:09"},
+	}
+	for _, tt := range tests {
+		if got := formatCountdown(tt.d); got != tt.want {
+			t.Errorf("formatCountdown(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	if got := progressBar(time.Hour, 4*time.Hour, 8); got != "[██░░░░░░]  25%" {
+		t.Errorf("progressBar(25%%) = %q", got)
+	}
+	if got := progressBar(5*time.Hour, 4*time.Hour, 4); got != "[████] 100%" {
+		t.Errorf("progressBar(overflow) = %q", got)
+	}
+	if got := progressBar(-time.Hour, 4*time.Hour, 4); got != "[░░░░]   0%" {
+		t.Errorf("progressBar(negative) = %q", got)
+	}
+}
+
+func TestAdhanAlert_Due(t *testing.T) {
+	s := newTestWatchSource(t)
+	a := &adhanAlert{sound: "adhan.mp3", fajrSound: "none"}
+
+	// Starting mid-window plays nothing, even though Dhuhr is current.
+	f, _ := s.frame(context.Background(), time.Date(2026, 2, 10, 15, 44, 59, 0, s.tzLoc))
+	if got := a.due(f); got != "" {
+		t.Errorf("due() on first frame = %q, want nothing", got)
+	}
+	f, _ = s.frame(context.Background(), time.Date(2026, 2, 10, 15, 45, 0, 0, s.tzLoc))
+	if got := a.due(f); got != "adhan.mp3" {
+		t.Errorf("due() as Asr begins = %q, want adhan.mp3", got)
+	}
+	f, _ = s.frame(context.Background(), time.Date(2026, 2, 10, 15, 45, 1, 0, s.tzLoc))
+	if got := a.due(f); got != "" {
+		t.Errorf("due() a second later = %q, want nothing", got)
+	}
+
+	// Fajr is silent here, and tomorrow's Fajr begins across midnight.
+	a.due(watchFrame{})
+	f, _ = s.frame(context.Background(), time.Date(2026, 2, 10, 23, 0, 0, 0, s.tzLoc))
+	a.due(f)
+	f, _ = s.frame(context.Background(), time.Date(2026, 2, 11, 5, 30, 0, 0, s.tzLoc))
+	if got := a.due(f); got != "" {
+		t.Errorf("due() at a silent Fajr = %q, want nothing", got)
+	}
+}
+
+func TestChimeAlert_Due(t *testing.T) {
+	s := newTestWatchSource(t)
+	a, err := newChimeAlert(&config.Config{ChimeSound: "chime.wav", ChimePrayers: "Asr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(clock string) watchFrame {
+		c, _ := time.Parse("15:04:05", clock)
+		f, _ := s.frame(context.Background(), time.Date(2026, 2, 10, c.Hour(), c.Minute(), c.Second(), 0, s.tzLoc))
+		return f
+	}
+
+	// Starting inside the window plays nothing.
+	if a.due(at("15:40:00")) {
+		t.Error("due() on first frame inside the window, want nothing")
+	}
+	if a.due(at("15:34:59")) {
+		t.Error("due() 10m1s before Asr, want nothing")
+	}
+	if !a.due(at("15:35:00")) {
+		t.Error("due() 10m before Asr = false, want the chime")
+	}
+	if a.due(at("15:35:01")) {
+		t.Error("due() a second later, want nothing")
+	}
+
+	// Dhuhr isn't in chime_prayers.
+	a.due(at("12:00:00"))
+	if a.due(at("12:25:00")) {
+		t.Error("due() before Dhuhr, want nothing")
+	}
+}
+
+func TestScheduledTheme(t *testing.T) {
+	s := newTestWatchSource(t)
+	for _, r := range s.days {
+		r.Timings.Sunrise = "06:50"
+	}
+	steps, err := parseThemeSchedule(defaultThemeSchedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		clock string
+		want  string
+	}{
+		{"03:00", "dim"}, // still the night before
+		{"06:50", "light"},
+		{"14:00", "light"},
+		{"18:30", "dark"},
+		{"23:00", "dim"},
+	}
+	for _, tt := range tests {
+		clock, _ := time.Parse("15:04", tt.clock)
+		now := time.Date(2026, 2, 10, clock.Hour(), clock.Minute(), 0, 0, s.tzLoc)
+		if got := s.theme(context.Background(), now, steps); got != tt.want {
+			t.Errorf("theme at %s = %q, want %q", tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestParseThemeSchedule_Invalid(t *testing.T) {
+	for _, value := range []string{"", "Isha", "Noon:dark", "Isha:blue"} {
+		if _, err := parseThemeSchedule(value); err == nil {
+			t.Errorf("parseThemeSchedule(%q) should error", value)
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	prev := display.Enabled()
+	display.SetEnabled(true)
+	defer display.SetEnabled(prev)
+
+	frame := clearScreen + display.Bold("Fajr") + "\n"
+	got := applyTheme(frame, "dark")
+	if !strings.HasPrefix(got, watchThemes["dark"]+clearScreen) || !strings.Contains(got, resetStyle+watchThemes["dark"]) {
+		t.Errorf("applyTheme(dark) = %q, want the theme set first and after each reset", got)
+	}
+	if got := applyTheme(frame, "dim"); got != watchThemes["dim"]+clearScreen+"Fajr\n" {
+		t.Errorf("applyTheme(dim) = %q, want plain text in the dim theme", got)
+	}
+	if got := applyTheme(frame, "none"); got != frame {
+		t.Errorf("applyTheme(none) = %q, want the frame unchanged", got)
+	}
+}
+
+func TestBlanked(t *testing.T) {
+	s := newTestWatchSource(t)
+	from, err := parseBlankPoint("--blank-from", "Isha+2h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	until, err := parseBlankPoint("--blank-until", "Fajr-1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		clock string
+		want  bool
+	}{
+		{"03:00", true},
+		{"04:30", false}, // Fajr-1h
+		{"14:00", false},
+		{"21:39", false},
+		{"21:40", true}, // Isha+2h
+		{"23:59", true},
+	}
+	for _, tt := range tests {
+		clock, _ := time.Parse("15:04", tt.clock)
+		now := time.Date(2026, 2, 10, clock.Hour(), clock.Minute(), 0, 0, s.tzLoc)
+		if got := s.blanked(context.Background(), now, from, until); got != tt.want {
+			t.Errorf("blanked at %s = %v, want %v", tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestBlanked_PastMidnight(t *testing.T) {
+	s := newTestWatchSource(t)
+	from, _ := parseBlankPoint("--blank-from", "Isha+5h") // 00:40
+	until, _ := parseBlankPoint("--blank-until", "04:00")
+
+	for clock, want := range map[string]bool{"23:30": false, "00:40": true, "03:59": true, "04:00": false} {
+		c, _ := time.Parse("15:04", clock)
+		now := time.Date(2026, 2, 10, c.Hour(), c.Minute(), 0, 0, s.tzLoc)
+		if got := s.blanked(context.Background(), now, from, until); got != want {
+			t.Errorf("blanked at %s = %v, want %v", clock, got, want)
+		}
+	}
+}
+
+func TestParseBlankPoint_Invalid(t *testing.T) {
+	for _, value := range []string{"", "Noon", "Isha+2", "+2h", "25:00"} {
+		if _, err := parseBlankPoint("--blank-from", value); err == nil {
+			t.Errorf("parseBlankPoint(%q) should error", value)
+		}
+	}
+}
+
+func TestShiftFrame(t *testing.T) {
+	if got := shiftFrame("a\n\nb\n", 2, 1); got != "\n  a\n\n  b\n" {
+		t.Errorf("shiftFrame() = %q", got)
+	}
+	seen := make(map[[2]int]bool)
+	for i := range shiftPositions {
+		dx, dy := shiftOffset(time.Unix(int64(i)*int64(shiftEvery/time.Second), 0))
+		seen[[2]int{dx, dy}] = true
+	}
+	if len(seen) != len(shiftPositions) {
+		t.Errorf("shiftOffset visited %d positions, want %d", len(seen), len(shiftPositions))
+	}
+}
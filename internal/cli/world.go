@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/config"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/spf13/cobra"
+)
+
+// defaultWorldCities is the world command's list when neither --cities nor
+// the world_cities config key is set.
+const defaultWorldCities = "Mecca:SA,Cairo:EG,Istanbul:TR,London:UK,New York:US,Lagos:NG,Karachi:PK,Dhaka:BD,Jakarta:ID,Kuala Lumpur:MY"
+
+var flagWorldCities string
+
+func newWorldCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "world",
+		Short: "Show which prayer is in progress around the world",
+		Long: `List a set of world cities with their local time, the prayer currently in
+progress there, and the next one. Cities are fetched concurrently and cached.
+
+The list comes from --cities, the world_cities config key, or a default set of
+major cities, as comma-separated City:Country pairs.`,
+		Example: `  prayer-times world
+  prayer-times world --cities "London:UK,Toronto:CA,Dubai:AE"
+  prayer-times config set world_cities "London:UK,Toronto:CA,Dubai:AE"`,
+		Args: cobra.NoArgs,
+		RunE: runWorld,
+	}
+
+	cmd.Flags().StringVar(&flagWorldCities, "cities", "", "Cities to show as City:Country pairs, e.g. \"London:UK,Cairo:EG\" (overrides config)")
+
+	return cmd
+}
+
+// worldRow is one city of the world command's output.
+type worldRow struct {
+	City, Country string
+	Timezone      string
+	Local         time.Time
+	Current       *prayer.Prayer
+	Next          *prayer.Prayer
+	Err           error
+}
+
+// fetchWorldRow looks up the current and next prayer at a city.
+func fetchWorldRow(ctx context.Context, wc config.WorldCity, now time.Time, method, school int, selected []string, c *cache.Cache) worldRow {
+	row := worldRow{City: wc.City, Country: wc.Country}
+	loc := resolvedLocation{Mode: locationCity, City: wc.City, Country: wc.Country, Timezone: timezoneOverride}
+
+	result, err := fetchTimings(ctx, now, loc, method, school, c)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+
+	tz := loc.timezoneFor(result.Meta)
+	tzLoc, err := time.LoadLocation(tz)
+	if err != nil {
+		row.Err = fmt.Errorf("invalid timezone %q: %w", tz, err)
+		return row
+	}
+
+	// The city's date may differ from ours; fetch its own day if so.
+	local := now.In(tzLoc)
+	if local.Format("2006-01-02") != now.Format("2006-01-02") {
+		if result, err = fetchTimings(ctx, local, loc, method, school, c); err != nil {
+			row.Err = err
+			return row
+		}
+	}
+
+	prayers, err := prayer.ParseTimings(result.Timings, local, tzLoc, selected)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+
+	row.Timezone = tz
+	row.Local = local
+	row.Current = prayer.CurrentPrayer(prayers, local)
+	if row.Current == nil && len(prayers) > 0 {
+		// Before the first prayer, yesterday's last one is still in progress.
+		row.Current = &prayers[len(prayers)-1]
+	}
+	row.Next, row.Err = nextPrayerFrom(ctx, prayers, local, loc, method, school, c, tzLoc, selected)
+	if row.Err == nil && (row.Current == nil || row.Next == nil) {
+		row.Err = fmt.Errorf("could not determine current prayer")
+	}
+	return row
+}
+
+// worldJSONCity is one city of the world command's JSON output.
+type worldJSONCity struct {
+	City      string `json:"city"`
+	Country   string `json:"country"`
+	Timezone  string `json:"timezone,omitempty"`
+	LocalTime string `json:"local_time,omitempty"`
+	Current   string `json:"current,omitempty"`
+	Next      string `json:"next,omitempty"`
+	NextTime  string `json:"next_time,omitempty"`
+	Remaining string `json:"remaining,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func runWorld(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	cfg := effectiveConfig(cmd)
+	selectedPrayers := selectedPrayerNames(cfg)
+	goTimeFmt := goTimeFormat(cfg)
+
+	spec := defaultWorldCities
+	if cmd.Flags().Changed("cities") {
+		spec = flagWorldCities
+	} else if cfg.WorldCities != "" {
+		spec = cfg.WorldCities
+	}
+	cities, err := config.ParseWorldCities(spec)
+	if err != nil {
+		return err
+	}
+	if len(cities) == 0 {
+		return fmt.Errorf("no cities to show: set --cities or world_cities")
+	}
+
+	c := openCache(cfg)
+	method := cfg.MethodOrDefault(-1)
+	school := cfg.SchoolOrDefault(-1)
+	now := currentTime()
+
+	rows := make([]worldRow, len(cities))
+	var wg sync.WaitGroup
+	for i, wc := range cities {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rows[i] = fetchWorldRow(ctx, wc, now, method, school, selectedPrayers, c)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range rows {
+		if r.Err != nil {
+			failed++
+			notices.Warnf("%s, %s: %v", r.City, r.Country, r.Err)
+		}
+	}
+	if failed == len(rows) {
+		return fmt.Errorf("failed to fetch prayer times for any city")
+	}
+
+	if FlagJSON {
+		out := make([]worldJSONCity, 0, len(rows))
+		for _, r := range rows {
+			jc := worldJSONCity{City: r.City, Country: r.Country}
+			if r.Err != nil {
+				jc.Error = r.Err.Error()
+			} else {
+				jc.Timezone = r.Timezone
+				jc.LocalTime = r.Local.Format(goTimeFmt)
+				jc.Current = strings.ToLower(r.Current.Name)
+				jc.Next = strings.ToLower(r.Next.Name)
+				jc.NextTime = r.Next.Time.Format(goTimeFmt)
+				jc.Remaining = prayer.FormatRemaining(prayer.TimeRemaining(*r.Next, r.Local))
+			}
+			out = append(out, jc)
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", display.Bold("Prayer Around the World"))
+	fmt.Fprintln(w)
+
+	tbl := display.NewTable([]string{"City", "Local", "Now", "Next", "In"})
+	for _, r := range rows {
+		name := r.City + ", " + r.Country
+		if r.Err != nil {
+			tbl.AddRow([]string{name, "--:--", display.Dim("unavailable"), "", ""})
+			continue
+		}
+		tbl.AddRow([]string{
+			name,
+			r.Local.Format(goTimeFmt),
+			r.Current.Name,
+			r.Next.Name + " " + r.Next.Time.Format(goTimeFmt),
+			prayer.FormatRemaining(prayer.TimeRemaining(*r.Next, r.Local)),
+		})
+	}
+	fmt.Fprint(w, tbl.Render())
+	fmt.Fprintln(w)
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	backupDirName = "backups"
+	backupPrefix  = "config-"
+	// backupStamp sorts chronologically and keeps quick successive saves apart.
+	backupStamp = "20060102-150405.000000000"
+	// maxBackups is how many backups are kept; older ones are pruned.
+	maxBackups = 20
+)
+
+// ErrNoBackup is returned by Undo when there is no backup to restore.
+var ErrNoBackup = errors.New("no config backup to restore")
+
+// BackupDir returns the directory holding backups of the config file at path.
+func BackupDir(path string) string {
+	return filepath.Join(filepath.Dir(path), backupDirName)
+}
+
+// backup copies the config file at path into BackupDir before it changes.
+// There is nothing to back up when the file doesn't exist, or when it
+// already holds next (pass nil when the file is being deleted).
+func backup(path string, next []byte) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file for backup: %w", err)
+	}
+	if next != nil && bytes.Equal(data, next) {
+		return nil
+	}
+
+	dir := BackupDir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("cannot create backup directory %s: %w", dir, err)
+	}
+	name := backupPrefix + time.Now().Format(backupStamp) + ".json"
+	if err := writePrivate(filepath.Join(dir, name), data); err != nil {
+		return fmt.Errorf("failed to write config backup: %w", err)
+	}
+
+	backups, err := Backups(path)
+	if err != nil {
+		return err
+	}
+	for len(backups) > maxBackups {
+		_ = os.Remove(backups[0])
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Backups returns the backups of the config file at path, oldest first.
+func Backups(path string) ([]string, error) {
+	entries, err := os.ReadDir(BackupDir(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+	var backups []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), backupPrefix) && strings.HasSuffix(e.Name(), ".json") {
+			backups = append(backups, filepath.Join(BackupDir(path), e.Name()))
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Undo restores the most recent config backup.
+func Undo() (time.Time, error) {
+	path, err := Path()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return UndoAt(path)
+}
+
+// UndoAt replaces the config file at path with its most recent backup and
+// removes that backup, so repeated calls step further back. It returns when
+// the backup was taken.
+func UndoAt(path string) (time.Time, error) {
+	backups, err := Backups(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(backups) == 0 {
+		return time.Time{}, ErrNoBackup
+	}
+	last := backups[len(backups)-1]
+
+	data, err := os.ReadFile(last)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read config backup: %w", err)
+	}
+	if err := writePrivate(path, data); err != nil {
+		return time.Time{}, fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Remove(last); err != nil {
+		return time.Time{}, fmt.Errorf("failed to remove restored backup: %w", err)
+	}
+
+	stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(last), backupPrefix), ".json")
+	taken, _ := time.ParseInLocation(backupStamp, stamp, time.Local)
+	return taken, nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveTo_BacksUpPreviousFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	if err := (&Config{City: "London"}).SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := Backups(path); len(backups) != 0 {
+		t.Fatalf("first save made %d backups, want none", len(backups))
+	}
+
+	if err := (&Config{City: "Cairo"}).SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	// Saving the same config again has nothing new to back up.
+	if err := (&Config{City: "Cairo"}).SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := Backups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, want 1", len(backups))
+	}
+	if cfg, _ := LoadFrom(backups[0]); cfg.City != "London" {
+		t.Errorf("backup has city %q, want London", cfg.City)
+	}
+}
+
+func TestSaveTo_Private(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prayer-times", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A config written before it held secrets is made private too.
+	if err := os.WriteFile(path, []byte(`{"city":"London"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&Config{City: "Cairo", AdminToken: "s3cret"}).SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := Backups(path)
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, want 1", len(backups))
+	}
+	for _, p := range []string{path, backups[0]} {
+		if info, err := os.Stat(p); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("%s mode = %v, %v; want 0600", filepath.Base(p), info.Mode().Perm(), err)
+		}
+	}
+	if info, _ := os.Stat(BackupDir(path)); info.Mode().Perm() != 0o700 {
+		t.Errorf("backup directory mode = %v, want 0700", info.Mode().Perm())
+	}
+
+	if _, err := UndoAt(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("restored config mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestUndoAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, city := range []string{"London", "Cairo", "Mecca"} {
+		if err := (&Config{City: city}).SaveTo(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{"Cairo", "London"} {
+		if _, err := UndoAt(path); err != nil {
+			t.Fatal(err)
+		}
+		if cfg, _ := LoadFrom(path); cfg.City != want {
+			t.Errorf("after undo city = %q, want %q", cfg.City, want)
+		}
+	}
+	if _, err := UndoAt(path); !errors.Is(err, ErrNoBackup) {
+		t.Errorf("UndoAt with no backups = %v, want ErrNoBackup", err)
+	}
+}
+
+func TestUndoAt_AfterReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := (&Config{City: "London"}).SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := ResetAt(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("reset should delete the config file")
+	}
+
+	if _, err := UndoAt(path); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _ := LoadFrom(path); cfg.City != "London" {
+		t.Errorf("undo after reset: city = %q, want London", cfg.City)
+	}
+}
+
+func TestBackups_Pruned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for i := 0; i < maxBackups+5; i++ {
+		if err := (&Config{FajrAngle: float64(i + 1)}).SaveTo(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backups, err := Backups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != maxBackups {
+		t.Errorf("got %d backups, want %d", len(backups), maxBackups)
+	}
+}
@@ -10,8 +10,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/adhan"
+	"github.com/smokyabdulrahman/prayer-times/internal/cache"
+	"github.com/smokyabdulrahman/prayer-times/internal/configsync"
+	"github.com/smokyabdulrahman/prayer-times/internal/display"
+	"github.com/smokyabdulrahman/prayer-times/internal/geo"
+	"github.com/smokyabdulrahman/prayer-times/internal/gpio"
+	"github.com/smokyabdulrahman/prayer-times/internal/hijri"
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+	"github.com/smokyabdulrahman/prayer-times/internal/matrix"
+	"github.com/smokyabdulrahman/prayer-times/internal/mqtt"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+	"github.com/smokyabdulrahman/prayer-times/internal/push"
+	"github.com/smokyabdulrahman/prayer-times/internal/webhook"
 )
 
 const (
@@ -23,24 +39,123 @@ const (
 var ValidKeys = []string{
 	"city", "country",
 	"latitude", "longitude",
+	"timezone",
 	"method", "school",
+	"fajr_angle", "maghrib_angle", "isha_angle",
+	"tune",
+	"latitude_adjustment",
+	"midnight_mode",
+	"shafaq",
 	"time_format",
+	"lang",
 	"prayers",
-	"cache_dir",
+	"cache_dir", "cache_max_size",
+	"retries", "retry_backoff",
+	"geo_provider", "geo_api_key",
+	"world_cities",
+	"archive",
+	"reminder",
+	"kids",
+	"transliterate",
+	"events", "hijri_adjustment",
+	"theme",
+	"adhan_sound", "adhan_fajr_sound", "adhan_player",
+	"chime_sound", "chime_before", "chime_prayers",
+	"webhooks", "webhook_before", "webhook_template",
+	"sync_url",
+	"mqtt_broker", "mqtt_topic", "mqtt_username", "mqtt_password",
+	"gpio_pin", "gpio_pattern",
+	"matrix_homeserver", "matrix_token", "matrix_room", "matrix_template",
+	"ntfy_url", "ntfy_token", "ntfy_template", "ntfy_title",
+	"gotify_url", "gotify_token", "gotify_template", "gotify_title",
+	"pushover_user", "pushover_token", "pushover_template", "pushover_title",
+	"notify_targets",
+	"admin_token",
+	"heartbeat_url", "heartbeat_interval",
 }
 
 // Config holds all user-configurable settings.
 // Zero values mean "not set" (use defaults or auto-detect).
 type Config struct {
-	City       string  `json:"city,omitempty"`
-	Country    string  `json:"country,omitempty"`
-	Latitude   float64 `json:"latitude,omitempty"`
-	Longitude  float64 `json:"longitude,omitempty"`
-	Method     *int    `json:"method,omitempty"`      // pointer so we can distinguish "not set" from 0
-	School     *int    `json:"school,omitempty"`      // pointer so we can distinguish "not set" from 0
-	TimeFormat string  `json:"time_format,omitempty"` // "12h" or "24h"
-	Prayers    string  `json:"prayers,omitempty"`     // comma-separated list
-	CacheDir   string  `json:"cache_dir,omitempty"`
+	City               string   `json:"city,omitempty"`
+	Country            string   `json:"country,omitempty"`
+	Latitude           *float64 `json:"latitude,omitempty"`   // pointer so 0 (equator) is distinct from "not set"
+	Longitude          *float64 `json:"longitude,omitempty"`  // pointer so 0 (prime meridian) is distinct from "not set"
+	Timezone           string   `json:"timezone,omitempty"`   // IANA name; overrides the location's own timezone
+	Method             *int     `json:"method,omitempty"`     // pointer so we can distinguish "not set" from 0
+	School             *int     `json:"school,omitempty"`     // pointer so we can distinguish "not set" from 0
+	FajrAngle          float64  `json:"fajr_angle,omitempty"` // custom method angles; 0 means not set
+	MaghribAngle       float64  `json:"maghrib_angle,omitempty"`
+	IshaAngle          float64  `json:"isha_angle,omitempty"`
+	Tune               string   `json:"tune,omitempty"`                // per-prayer minute offsets, e.g. "Fajr:+3,Asr:-2"
+	LatitudeAdjustment string   `json:"latitude_adjustment,omitempty"` // high-latitude rule, see LatitudeAdjustments
+	MidnightMode       string   `json:"midnight_mode,omitempty"`       // "standard" or "jafari"
+	Shafaq             string   `json:"shafaq,omitempty"`              // "general", "ahmer" or "abyad"; method 15 only
+	TimeFormat         string   `json:"time_format,omitempty"`         // "12h" or "24h"
+	Lang               string   `json:"lang,omitempty"`                // language of the schedule and notifications, e.g. "ar"; "auto" (the default) follows the locale, then the country
+	Prayers            string   `json:"prayers,omitempty"`             // comma-separated list
+	CacheDir           string   `json:"cache_dir,omitempty"`
+	CacheMaxSize       string   `json:"cache_max_size,omitempty"`   // entries, size or both, e.g. "500", "20MB", "500,20MB"
+	Retries            *int     `json:"retries,omitempty"`          // pointer so 0 (never retry) is distinct from "not set"
+	RetryBackoff       string   `json:"retry_backoff,omitempty"`    // delay before the first API retry, e.g. "500ms"
+	GeoProvider        string   `json:"geo_provider,omitempty"`     // IP geolocation services to try in order, e.g. "ipinfo,ip-api"
+	GeoAPIKey          string   `json:"geo_api_key,omitempty"`      // API key or token for the first geolocation service
+	WorldCities        string   `json:"world_cities,omitempty"`     // cities for the world command, e.g. "London:UK,Cairo:EG"
+	Archive            bool     `json:"archive,omitempty"`          // keep a permanent history of fetched timings
+	Reminder           bool     `json:"reminder,omitempty"`         // show a daily verse/hadith under today's schedule
+	Kids               bool     `json:"kids,omitempty"`             // simplified, kid-friendly display
+	Transliterate      bool     `json:"transliterate,omitempty"`    // show Arabic prayer names with pronunciation
+	Events             bool     `json:"events,omitempty"`           // show Hijri events such as the days of Hajj under today's schedule
+	HijriAdjustment    int      `json:"hijri_adjustment,omitempty"` // days added to Hijri dates, to follow local moon sighting
+	Theme              string   `json:"theme,omitempty"`            // colors of the accent, dim and highlight styles, e.g. "accent=214+bold"
+	AdhanSound         string   `json:"adhan_sound,omitempty"`      // audio file watch plays at each prayer
+	AdhanFajrSound     string   `json:"adhan_fajr_sound,omitempty"` // audio file for Fajr instead, or "none" for silence
+	AdhanPlayer        string   `json:"adhan_player,omitempty"`     // "auto", "mpv", "afplay" or "paplay"
+	ChimeSound         string   `json:"chime_sound,omitempty"`      // short audio file watch plays before each prayer
+	ChimeBefore        string   `json:"chime_before,omitempty"`     // how long before, e.g. "5m"; 10m when not set
+	ChimePrayers       string   `json:"chime_prayers,omitempty"`    // comma-separated prayers to chime for; all five when not set
+	Webhooks           string   `json:"webhooks,omitempty"`         // comma-separated URLs serve posts prayer events to
+	WebhookBefore      string   `json:"webhook_before,omitempty"`   // also post a reminder this long before each prayer, e.g. "10m"
+	WebhookTemplate    string   `json:"webhook_template,omitempty"` // "auto", "json", "slack", "discord" or a body template
+	SyncURL            string   `json:"sync_url,omitempty"`         // remote for config sync: a WebDAV URL or Git repository
+	MQTTBroker         string   `json:"mqtt_broker,omitempty"`      // broker serve publishes prayer events to, e.g. "mqtt://homeassistant.local"
+	MQTTTopic          string   `json:"mqtt_topic,omitempty"`       // topic prefix; "prayer-times" when not set
+	MQTTUsername       string   `json:"mqtt_username,omitempty"`
+	MQTTPassword       string   `json:"mqtt_password,omitempty"`
+	GPIOPin            string   `json:"gpio_pin,omitempty"`           // GPIO serve pulses at each prayer, e.g. "17" for an LED or buzzer
+	GPIOPattern        string   `json:"gpio_pattern,omitempty"`       // pulses as COUNTxDURATION, e.g. "1x3s"; "5x500ms" when not set
+	MatrixHomeserver   string   `json:"matrix_homeserver,omitempty"`  // Matrix homeserver serve posts prayer events through, e.g. "https://matrix.org"
+	MatrixToken        string   `json:"matrix_token,omitempty"`       // access token of the posting account
+	MatrixRoom         string   `json:"matrix_room,omitempty"`        // room ID, e.g. "!abcdef:matrix.org"
+	MatrixTemplate     string   `json:"matrix_template,omitempty"`    // message template; the event's own message when not set
+	NtfyURL            string   `json:"ntfy_url,omitempty"`           // ntfy topic serve pushes prayer events to, e.g. "https://ntfy.sh/my-prayers"
+	NtfyToken          string   `json:"ntfy_token,omitempty"`         // access token for a protected topic
+	NtfyTemplate       string   `json:"ntfy_template,omitempty"`      // message template, as matrix_template
+	NtfyTitle          string   `json:"ntfy_title,omitempty"`         // title template; the prayer's name when not set
+	GotifyURL          string   `json:"gotify_url,omitempty"`         // Gotify server serve pushes prayer events to
+	GotifyToken        string   `json:"gotify_token,omitempty"`       // Gotify application token
+	GotifyTemplate     string   `json:"gotify_template,omitempty"`    // message template, as matrix_template
+	GotifyTitle        string   `json:"gotify_title,omitempty"`       // title template, as ntfy_title
+	PushoverUser       string   `json:"pushover_user,omitempty"`      // Pushover user or group key serve pushes prayer events to
+	PushoverToken      string   `json:"pushover_token,omitempty"`     // Pushover application token
+	PushoverTemplate   string   `json:"pushover_template,omitempty"`  // message template, as matrix_template
+	PushoverTitle      string   `json:"pushover_title,omitempty"`     // title template, as ntfy_title
+	NotifyTargets      string   `json:"notify_targets,omitempty"`     // comma-separated targets serve notifies, e.g. "ntfy,matrix"; all configured when not set
+	AdminToken         string   `json:"admin_token,omitempty"`        // bearer token for serve's /admin API; unset disables it
+	HeartbeatURL       string   `json:"heartbeat_url,omitempty"`      // URL serve POSTs a status document to, for monitoring
+	HeartbeatInterval  string   `json:"heartbeat_interval,omitempty"` // how often, e.g. "5m"
+
+	// Commands holds per-command flag defaults: command path ("next",
+	// "export ical", or "today" for the root command) -> flag -> value.
+	Commands map[string]map[string]string `json:"commands,omitempty"`
+
+	// Aliases maps a custom command name to the command line it runs, e.g.
+	// "iftar" -> "query Maghrib --format time-remaining".
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// Profiles holds named locations applied with --profile: profile name ->
+	// key (one of ProfileKeys) -> value.
+	Profiles map[string]map[string]string `json:"profiles,omitempty"`
 }
 
 // Defaults returns a Config with all default values applied.
@@ -118,10 +233,11 @@ func (c *Config) Save() error {
 	return c.SaveTo(path)
 }
 
-// SaveTo writes the config to a specific file path.
+// SaveTo writes the config to a specific file path. The previous file is
+// backed up first (see UndoAt).
 func (c *Config) SaveTo(path string) error {
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return fmt.Errorf("cannot create config directory %s: %w", dir, err)
 	}
 
@@ -131,13 +247,26 @@ func (c *Config) SaveTo(path string) error {
 	}
 	data = append(data, '\n')
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	if err := backup(path, data); err != nil {
+		return err
+	}
+	if err := writePrivate(path, data); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	return nil
 }
 
+// writePrivate writes data to the file at path readable only by its owner,
+// as the config holds tokens and passwords. A file written before that was
+// the case is made private too.
+func writePrivate(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
 // Reset deletes the config file.
 func Reset() error {
 	path, err := Path()
@@ -148,8 +277,11 @@ func Reset() error {
 	return ResetAt(path)
 }
 
-// ResetAt deletes the config file at a specific path.
+// ResetAt deletes the config file at a specific path, backing it up first.
 func ResetAt(path string) error {
+	if err := backup(path, nil); err != nil {
+		return err
+	}
 	err := os.Remove(path)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("failed to delete config file: %w", err)
@@ -159,30 +291,47 @@ func ResetAt(path string) error {
 
 // Set sets a config key to the given value.
 // It validates the key name and parses the value into the correct type.
+// Keys of the form "commands.<command>.<flag>" set a command default and
+// "aliases.<name>" an alias; an empty value removes either.
 func (c *Config) Set(key, value string) error {
+	if strings.HasPrefix(key, AliasKeyPrefix) {
+		name, err := ParseAliasKey(key)
+		if err != nil {
+			return err
+		}
+		return c.setAlias(name, value)
+	}
+	if strings.HasPrefix(key, CommandKeyPrefix) {
+		command, flag, err := ParseCommandKey(key)
+		if err != nil {
+			return err
+		}
+		c.setCommandDefault(command, flag, value)
+		return nil
+	}
+
 	switch key {
 	case "city":
 		c.City = value
 	case "country":
 		c.Country = value
 	case "latitude":
-		v, err := strconv.ParseFloat(value, 64)
+		v, err := parseCoordinate(key, value, 90)
 		if err != nil {
-			return fmt.Errorf("invalid latitude %q: must be a number", value)
-		}
-		if v < -90 || v > 90 {
-			return fmt.Errorf("invalid latitude %q: must be between -90 and 90", value)
+			return err
 		}
 		c.Latitude = v
 	case "longitude":
-		v, err := strconv.ParseFloat(value, 64)
+		v, err := parseCoordinate(key, value, 180)
 		if err != nil {
-			return fmt.Errorf("invalid longitude %q: must be a number", value)
-		}
-		if v < -180 || v > 180 {
-			return fmt.Errorf("invalid longitude %q: must be between -180 and 180", value)
+			return err
 		}
 		c.Longitude = v
+	case "timezone":
+		if err := ValidateTimezone(value); err != nil {
+			return err
+		}
+		c.Timezone = value
 	case "method":
 		v, err := strconv.Atoi(value)
 		if err != nil {
@@ -201,23 +350,256 @@ func (c *Config) Set(key, value string) error {
 			return fmt.Errorf("invalid school %q: must be 0 (Shafi) or 1 (Hanafi)", value)
 		}
 		c.School = &v
+	case "fajr_angle", "maghrib_angle", "isha_angle":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v < 0 || v > 30 {
+			return fmt.Errorf("invalid %s %q: must be a number between 0 and 30", key, value)
+		}
+		*c.angle(key) = v
+	case "tune":
+		if _, err := ParseTune(value); err != nil {
+			return err
+		}
+		c.Tune = value
+	case "latitude_adjustment":
+		if _, err := ParseLatitudeAdjustment(value); err != nil {
+			return err
+		}
+		c.LatitudeAdjustment = value
+	case "midnight_mode":
+		if _, err := ParseMidnightMode(value); err != nil {
+			return err
+		}
+		c.MidnightMode = value
+	case "shafaq":
+		if value != "" && !ValidShafaq(value) {
+			return fmt.Errorf("invalid shafaq %q: must be one of %s", value, strings.Join(ShafaqOptions, ", "))
+		}
+		c.Shafaq = value
 	case "time_format":
 		if value != "12h" && value != "24h" {
 			return fmt.Errorf("invalid time_format %q: must be \"12h\" or \"24h\"", value)
 		}
 		c.TimeFormat = value
+	case "lang":
+		if _, err := i18n.Parse(value); err != nil {
+			return err
+		}
+		c.Lang = strings.ToLower(strings.TrimSpace(value))
 	case "prayers":
-		// Validate each prayer name.
-		names := strings.Split(value, ",")
-		for _, n := range names {
-			n = strings.TrimSpace(n)
-			if !isValidPrayerName(n) {
-				return fmt.Errorf("invalid prayer name %q in prayers list", n)
-			}
+		names, err := canonicalPrayers(value, "prayers")
+		if err != nil {
+			return err
 		}
-		c.Prayers = value
+		c.Prayers = names
 	case "cache_dir":
 		c.CacheDir = value
+	case "cache_max_size":
+		if _, err := cache.ParseLimit(value); err != nil {
+			return err
+		}
+		c.CacheMaxSize = strings.ReplaceAll(value, " ", "")
+	case "retries":
+		v, err := ParseRetries(value)
+		if err != nil {
+			return err
+		}
+		c.Retries = &v
+	case "retry_backoff":
+		if _, err := ParseRetryBackoff(value); err != nil {
+			return err
+		}
+		c.RetryBackoff = value
+	case "geo_provider":
+		if _, err := geo.ParseProviders(value, "", true); err != nil {
+			return err
+		}
+		c.GeoProvider = value
+	case "geo_api_key":
+		c.GeoAPIKey = value
+	case "world_cities":
+		if _, err := ParseWorldCities(value); err != nil {
+			return err
+		}
+		c.WorldCities = value
+	case "archive":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid archive %q: must be true or false", value)
+		}
+		c.Archive = v
+	case "reminder":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid reminder %q: must be true or false", value)
+		}
+		c.Reminder = v
+	case "kids":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid kids %q: must be true or false", value)
+		}
+		c.Kids = v
+	case "transliterate":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid transliterate %q: must be true or false", value)
+		}
+		c.Transliterate = v
+	case "events":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid events %q: must be true or false", value)
+		}
+		c.Events = v
+	case "hijri_adjustment":
+		v, err := ParseHijriAdjustment(value)
+		if err != nil {
+			return err
+		}
+		c.HijriAdjustment = v
+	case "theme":
+		if _, err := display.ParseTheme(value); err != nil {
+			return err
+		}
+		c.Theme = value
+	case "adhan_sound":
+		c.AdhanSound = value
+	case "adhan_fajr_sound":
+		c.AdhanFajrSound = value
+	case "adhan_player":
+		if !adhan.ValidPlayer(value) {
+			return fmt.Errorf("invalid adhan_player %q: must be auto, %s", value, strings.Join(adhan.Players, ", "))
+		}
+		c.AdhanPlayer = value
+	case "chime_sound":
+		c.ChimeSound = value
+	case "chime_before":
+		if _, err := ParseChimeBefore(value); err != nil {
+			return err
+		}
+		c.ChimeBefore = value
+	case "chime_prayers":
+		names, err := canonicalPrayers(value, "chime_prayers")
+		if err != nil {
+			return err
+		}
+		c.ChimePrayers = names
+	case "webhooks":
+		if _, err := webhook.ParseURLs(value); err != nil {
+			return err
+		}
+		c.Webhooks = value
+	case "webhook_before":
+		if _, err := ParseWebhookBefore(value); err != nil {
+			return err
+		}
+		c.WebhookBefore = value
+	case "webhook_template":
+		if err := webhook.ParseTemplate(value); err != nil {
+			return err
+		}
+		c.WebhookTemplate = value
+	case "sync_url":
+		if value != "" {
+			if _, err := configsync.NewRemote(value); err != nil {
+				return err
+			}
+		}
+		c.SyncURL = value
+	case "mqtt_broker":
+		if value != "" {
+			if _, err := mqtt.ParseBroker(value); err != nil {
+				return err
+			}
+		}
+		c.MQTTBroker = value
+	case "mqtt_topic":
+		if strings.ContainsAny(value, "#+") || strings.HasSuffix(value, "/") {
+			return fmt.Errorf("invalid mqtt_topic %q: must not contain wildcards or end in /", value)
+		}
+		c.MQTTTopic = value
+	case "mqtt_username":
+		c.MQTTUsername = value
+	case "mqtt_password":
+		c.MQTTPassword = value
+	case "gpio_pin":
+		if value != "" {
+			if _, err := gpio.ParsePin(value); err != nil {
+				return err
+			}
+		}
+		c.GPIOPin = value
+	case "gpio_pattern":
+		if _, err := gpio.ParsePattern(value); err != nil {
+			return err
+		}
+		c.GPIOPattern = value
+	case "matrix_homeserver":
+		if value != "" {
+			if _, err := matrix.ParseHomeserver(value); err != nil {
+				return err
+			}
+		}
+		c.MatrixHomeserver = value
+	case "matrix_token":
+		c.MatrixToken = value
+	case "matrix_room":
+		if value != "" {
+			if _, err := matrix.ParseRoom(value); err != nil {
+				return err
+			}
+		}
+		c.MatrixRoom = value
+	case "matrix_template", "ntfy_template", "gotify_template", "pushover_template",
+		"ntfy_title", "gotify_title", "pushover_title":
+		if err := webhook.ParseMessageTemplate(key, value); err != nil {
+			return err
+		}
+		*c.messageTemplate(key) = value
+	case "ntfy_url":
+		if value != "" {
+			if _, err := push.ParseNtfyTopic(value); err != nil {
+				return err
+			}
+		}
+		c.NtfyURL = value
+	case "ntfy_token":
+		c.NtfyToken = value
+	case "gotify_url":
+		if value != "" {
+			if _, err := push.ParseURL(key, value); err != nil {
+				return err
+			}
+		}
+		c.GotifyURL = value
+	case "gotify_token":
+		c.GotifyToken = value
+	case "pushover_user":
+		c.PushoverUser = value
+	case "pushover_token":
+		c.PushoverToken = value
+	case "notify_targets":
+		if _, err := ParseNotifyTargets(value); err != nil {
+			return err
+		}
+		c.NotifyTargets = value
+	case "admin_token":
+		c.AdminToken = value
+	case "heartbeat_url":
+		urls, err := webhook.ParseURLs(value)
+		if err != nil {
+			return fmt.Errorf("invalid heartbeat_url %q: must be an http or https URL", value)
+		}
+		if len(urls) > 1 {
+			return fmt.Errorf("invalid heartbeat_url %q: must be a single URL", value)
+		}
+		c.HeartbeatURL = value
+	case "heartbeat_interval":
+		if _, err := ParseHeartbeatInterval(value); err != nil {
+			return err
+		}
+		c.HeartbeatInterval = value
 	default:
 		return fmt.Errorf("unknown config key %q; valid keys: %s", key, strings.Join(ValidKeys, ", "))
 	}
@@ -227,21 +609,38 @@ func (c *Config) Set(key, value string) error {
 
 // Get returns the string value of a config key.
 func (c *Config) Get(key string) (string, error) {
+	if strings.HasPrefix(key, AliasKeyPrefix) {
+		name, err := ParseAliasKey(key)
+		if err != nil {
+			return "", err
+		}
+		return c.Aliases[name], nil
+	}
+	if strings.HasPrefix(key, CommandKeyPrefix) {
+		command, flag, err := ParseCommandKey(key)
+		if err != nil {
+			return "", err
+		}
+		return c.Commands[command][flag], nil
+	}
+
 	switch key {
 	case "city":
 		return c.City, nil
 	case "country":
 		return c.Country, nil
 	case "latitude":
-		if c.Latitude == 0 {
+		if c.Latitude == nil {
 			return "", nil
 		}
-		return strconv.FormatFloat(c.Latitude, 'f', -1, 64), nil
+		return strconv.FormatFloat(*c.Latitude, 'f', -1, 64), nil
 	case "longitude":
-		if c.Longitude == 0 {
+		if c.Longitude == nil {
 			return "", nil
 		}
-		return strconv.FormatFloat(c.Longitude, 'f', -1, 64), nil
+		return strconv.FormatFloat(*c.Longitude, 'f', -1, 64), nil
+	case "timezone":
+		return c.Timezone, nil
 	case "method":
 		if c.Method == nil {
 			return "", nil
@@ -252,26 +651,732 @@ func (c *Config) Get(key string) (string, error) {
 			return "", nil
 		}
 		return strconv.Itoa(*c.School), nil
+	case "fajr_angle", "maghrib_angle", "isha_angle":
+		v := *c.angle(key)
+		if v == 0 {
+			return "", nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case "tune":
+		return c.Tune, nil
+	case "latitude_adjustment":
+		return c.LatitudeAdjustment, nil
+	case "midnight_mode":
+		return c.MidnightMode, nil
+	case "shafaq":
+		return c.Shafaq, nil
 	case "time_format":
 		return c.TimeFormat, nil
+	case "lang":
+		return c.Lang, nil
 	case "prayers":
 		return c.Prayers, nil
 	case "cache_dir":
 		return c.CacheDir, nil
+	case "cache_max_size":
+		return c.CacheMaxSize, nil
+	case "retries":
+		if c.Retries == nil {
+			return "", nil
+		}
+		return strconv.Itoa(*c.Retries), nil
+	case "retry_backoff":
+		return c.RetryBackoff, nil
+	case "geo_provider":
+		return c.GeoProvider, nil
+	case "geo_api_key":
+		return c.GeoAPIKey, nil
+	case "world_cities":
+		return c.WorldCities, nil
+	case "archive":
+		if !c.Archive {
+			return "", nil
+		}
+		return "true", nil
+	case "reminder":
+		if !c.Reminder {
+			return "", nil
+		}
+		return "true", nil
+	case "kids":
+		if !c.Kids {
+			return "", nil
+		}
+		return "true", nil
+	case "transliterate":
+		if !c.Transliterate {
+			return "", nil
+		}
+		return "true", nil
+	case "events":
+		if !c.Events {
+			return "", nil
+		}
+		return "true", nil
+	case "hijri_adjustment":
+		if c.HijriAdjustment == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(c.HijriAdjustment), nil
+	case "theme":
+		return c.Theme, nil
+	case "adhan_sound":
+		return c.AdhanSound, nil
+	case "adhan_fajr_sound":
+		return c.AdhanFajrSound, nil
+	case "adhan_player":
+		return c.AdhanPlayer, nil
+	case "chime_sound":
+		return c.ChimeSound, nil
+	case "chime_before":
+		return c.ChimeBefore, nil
+	case "chime_prayers":
+		return c.ChimePrayers, nil
+	case "webhooks":
+		return c.Webhooks, nil
+	case "webhook_before":
+		return c.WebhookBefore, nil
+	case "webhook_template":
+		return c.WebhookTemplate, nil
+	case "sync_url":
+		return c.SyncURL, nil
+	case "mqtt_broker":
+		return c.MQTTBroker, nil
+	case "mqtt_topic":
+		return c.MQTTTopic, nil
+	case "mqtt_username":
+		return c.MQTTUsername, nil
+	case "mqtt_password":
+		return c.MQTTPassword, nil
+	case "gpio_pin":
+		return c.GPIOPin, nil
+	case "gpio_pattern":
+		return c.GPIOPattern, nil
+	case "matrix_homeserver":
+		return c.MatrixHomeserver, nil
+	case "matrix_token":
+		return c.MatrixToken, nil
+	case "matrix_room":
+		return c.MatrixRoom, nil
+	case "matrix_template", "ntfy_template", "gotify_template", "pushover_template",
+		"ntfy_title", "gotify_title", "pushover_title":
+		return *c.messageTemplate(key), nil
+	case "ntfy_url":
+		return c.NtfyURL, nil
+	case "ntfy_token":
+		return c.NtfyToken, nil
+	case "gotify_url":
+		return c.GotifyURL, nil
+	case "gotify_token":
+		return c.GotifyToken, nil
+	case "pushover_user":
+		return c.PushoverUser, nil
+	case "pushover_token":
+		return c.PushoverToken, nil
+	case "notify_targets":
+		return c.NotifyTargets, nil
+	case "admin_token":
+		return c.AdminToken, nil
+	case "heartbeat_url":
+		return c.HeartbeatURL, nil
+	case "heartbeat_interval":
+		return c.HeartbeatInterval, nil
 	default:
 		return "", fmt.Errorf("unknown config key %q", key)
 	}
 }
 
-// validPrayerNames are the prayer names the API supports.
-var validPrayerNames = map[string]bool{
-	"Fajr": true, "Sunrise": true, "Dhuhr": true, "Asr": true,
-	"Sunset": true, "Maghrib": true, "Isha": true,
-	"Imsak": true, "Midnight": true, "Firstthird": true, "Lastthird": true,
+// CommandKeyPrefix starts the config keys of command defaults.
+const CommandKeyPrefix = "commands."
+
+// ParseCommandKey splits a "commands.<command>.<flag>" key into its command
+// path and flag name. Subcommands are dot-separated too, so
+// "commands.export.ical.alarm" is the --alarm flag of "export ical".
+func ParseCommandKey(key string) (command, flag string, err error) {
+	parts := strings.Split(strings.TrimPrefix(key, CommandKeyPrefix), ".")
+	if !strings.HasPrefix(key, CommandKeyPrefix) || len(parts) < 2 {
+		return "", "", fmt.Errorf("invalid command key %q: want commands.<command>.<flag>", key)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return "", "", fmt.Errorf("invalid command key %q: want commands.<command>.<flag>", key)
+		}
+	}
+	return strings.Join(parts[:len(parts)-1], " "), parts[len(parts)-1], nil
+}
+
+// Keys returns ValidKeys followed by the keys of any command defaults and
+// aliases.
+func (c *Config) Keys() []string {
+	keys := make([]string, 0, len(ValidKeys))
+	keys = append(keys, ValidKeys...)
+	keys = append(keys, c.CommandKeys()...)
+	return append(keys, c.AliasKeys()...)
+}
+
+// CommandKeys returns the keys of all command defaults, sorted.
+func (c *Config) CommandKeys() []string {
+	var keys []string
+	for command, flags := range c.Commands {
+		for flag := range flags {
+			keys = append(keys, CommandKeyPrefix+strings.ReplaceAll(command, " ", ".")+"."+flag)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setCommandDefault stores value as the default of a command's flag, or
+// removes it when value is empty.
+func (c *Config) setCommandDefault(command, flag, value string) {
+	if value == "" {
+		delete(c.Commands[command], flag)
+		if len(c.Commands[command]) == 0 {
+			delete(c.Commands, command)
+		}
+		return
+	}
+	if c.Commands == nil {
+		c.Commands = make(map[string]map[string]string)
+	}
+	if c.Commands[command] == nil {
+		c.Commands[command] = make(map[string]string)
+	}
+	c.Commands[command][flag] = value
+}
+
+// AliasKeyPrefix starts the config keys of aliases.
+const AliasKeyPrefix = "aliases."
+
+// ParseAliasKey returns the alias name of an "aliases.<name>" key.
+func ParseAliasKey(key string) (string, error) {
+	name := strings.TrimPrefix(key, AliasKeyPrefix)
+	if !strings.HasPrefix(key, AliasKeyPrefix) || name == "" || strings.HasPrefix(name, "-") ||
+		strings.ContainsAny(name, ". \t") {
+		return "", fmt.Errorf("invalid alias key %q: want aliases.<name>", key)
+	}
+	return name, nil
+}
+
+// AliasKeys returns the keys of all aliases, sorted.
+func (c *Config) AliasKeys() []string {
+	var keys []string
+	for name := range c.Aliases {
+		keys = append(keys, AliasKeyPrefix+name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setAlias stores the command line an alias runs, or removes the alias when
+// value is empty.
+func (c *Config) setAlias(name, value string) error {
+	if value == "" {
+		delete(c.Aliases, name)
+		return nil
+	}
+	if _, err := SplitAlias(value); err != nil {
+		return fmt.Errorf("invalid alias %s: %w", name, err)
+	}
+	if c.Aliases == nil {
+		c.Aliases = make(map[string]string)
+	}
+	c.Aliases[name] = value
+	return nil
+}
+
+// SplitAlias splits an alias's command line into arguments the way a shell
+// would: on whitespace, with single quotes, double quotes and backslashes
+// to keep spaces in an argument.
+func SplitAlias(value string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range value {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", value)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
+
+// parseCoordinate parses a latitude or longitude within ±limit degrees.
+// An empty value clears the coordinate.
+func parseCoordinate(key, value string, limit float64) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: must be a number", key, value)
+	}
+	if v < -limit || v > limit {
+		return nil, fmt.Errorf("invalid %s %q: must be between -%v and %v", key, value, limit, limit)
+	}
+	return &v, nil
+}
+
+// HasCoordinates reports whether a latitude or longitude is set. A missing
+// half is taken as 0, so a location on the equator or prime meridian only
+// needs the other coordinate.
+func (c *Config) HasCoordinates() bool {
+	return c.Latitude != nil || c.Longitude != nil
 }
 
-func isValidPrayerName(name string) bool {
-	return validPrayerNames[name]
+// Coordinates returns the configured latitude and longitude, 0 for either
+// that is not set. See HasCoordinates.
+func (c *Config) Coordinates() (lat, lon float64) {
+	if c.Latitude != nil {
+		lat = *c.Latitude
+	}
+	if c.Longitude != nil {
+		lon = *c.Longitude
+	}
+	return lat, lon
+}
+
+// angle returns the field backing one of the custom angle keys.
+func (c *Config) angle(key string) *float64 {
+	switch key {
+	case "fajr_angle":
+		return &c.FajrAngle
+	case "maghrib_angle":
+		return &c.MaghribAngle
+	}
+	return &c.IshaAngle
+}
+
+// messageTemplate returns the field backing one of the message and title
+// template keys.
+func (c *Config) messageTemplate(key string) *string {
+	switch key {
+	case "matrix_template":
+		return &c.MatrixTemplate
+	case "ntfy_template":
+		return &c.NtfyTemplate
+	case "ntfy_title":
+		return &c.NtfyTitle
+	case "gotify_template":
+		return &c.GotifyTemplate
+	case "gotify_title":
+		return &c.GotifyTitle
+	case "pushover_title":
+		return &c.PushoverTitle
+	}
+	return &c.PushoverTemplate
+}
+
+// canonicalPrayers checks a comma-separated list of prayer names for key,
+// accepting the spellings prayer.Lookup does, and returns it with the API's
+// names, e.g. "Zuhr, maghreb" as "Dhuhr,Maghrib".
+func canonicalPrayers(value, key string) (string, error) {
+	names := strings.Split(value, ",")
+	for i, n := range names {
+		name, ok := prayer.Lookup(n)
+		if !ok {
+			return "", fmt.Errorf("invalid prayer name %q in %s list", strings.TrimSpace(n), key)
+		}
+		names[i] = name
+	}
+	return strings.Join(names, ","), nil
+}
+
+// WorldCity is one city of the world command's list.
+type WorldCity struct {
+	City    string
+	Country string
+}
+
+// ParseWorldCities parses a list of cities such as "London:UK,New York:US".
+// An empty string yields no cities.
+func ParseWorldCities(value string) ([]WorldCity, error) {
+	var cities []WorldCity
+	if strings.TrimSpace(value) == "" {
+		return cities, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		city, country, ok := strings.Cut(strings.TrimSpace(part), ":")
+		city, country = strings.TrimSpace(city), strings.TrimSpace(country)
+		if !ok || city == "" || country == "" {
+			return nil, fmt.Errorf("invalid world_cities entry %q: want City:Country, e.g. London:UK", part)
+		}
+		cities = append(cities, WorldCity{City: city, Country: country})
+	}
+	return cities, nil
+}
+
+// maxTune is the largest per-prayer adjustment, in minutes, either way.
+const maxTune = 60
+
+// ParseTune parses per-prayer minute offsets such as "Fajr:+3,Asr:-2".
+// Firstthird and Lastthird are derived from other times and cannot be tuned.
+// An empty string yields no offsets.
+func ParseTune(value string) (map[string]int, error) {
+	offsets := make(map[string]int)
+	if strings.TrimSpace(value) == "" {
+		return offsets, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		name, mins, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid tune %q: want Prayer:minutes, e.g. Fajr:+3", part)
+		}
+		canonical, ok := prayer.Lookup(name)
+		if !ok || canonical == "Firstthird" || canonical == "Lastthird" {
+			return nil, fmt.Errorf("invalid tune %q: %q cannot be tuned", part, strings.TrimSpace(name))
+		}
+		name = canonical
+		v, err := strconv.Atoi(strings.TrimSpace(mins))
+		if err != nil || v < -maxTune || v > maxTune {
+			return nil, fmt.Errorf("invalid tune %q: minutes must be an integer between -%d and %d", part, maxTune, maxTune)
+		}
+		offsets[name] = v
+	}
+	return offsets, nil
+}
+
+// LatitudeAdjustments maps the accepted latitude_adjustment values to the
+// Al Adhan latitudeAdjustmentMethod IDs. They decide how Fajr and Isha are
+// placed where twilight lasts all night, e.g. in Scandinavia and Canada:
+//
+//   - middle-of-night: no later than the middle of the night
+//   - one-seventh: within the first/last seventh of the night
+//   - angle-based: a fraction of the night set by the twilight angle
+var LatitudeAdjustments = map[string]int{
+	"middle-of-night": 1,
+	"one-seventh":     2,
+	"angle-based":     3,
+}
+
+// ParseLatitudeAdjustment returns the latitudeAdjustmentMethod ID for value,
+// or 0 when value is empty.
+func ParseLatitudeAdjustment(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, ok := LatitudeAdjustments[value]
+	if !ok {
+		return 0, fmt.Errorf("invalid latitude_adjustment %q: must be middle-of-night, one-seventh or angle-based", value)
+	}
+	return id, nil
+}
+
+// MidnightModes maps the accepted midnight_mode values to the Al Adhan
+// midnightMode values. Standard puts midnight halfway from sunset to sunrise;
+// Jafari halfway from sunset to Fajr. Midnight, Firstthird and Lastthird
+// all follow it.
+var MidnightModes = map[string]string{
+	"standard": "0",
+	"jafari":   "1",
+}
+
+// ParseMidnightMode returns the midnightMode value for value, or "" when
+// value is empty.
+func ParseMidnightMode(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	mode, ok := MidnightModes[value]
+	if !ok {
+		return "", fmt.Errorf("invalid midnight_mode %q: must be standard or jafari", value)
+	}
+	return mode, nil
+}
+
+// ParseWebhookBefore parses how long before each prayer a reminder webhook is
+// posted. An empty value means no reminders.
+func ParseWebhookBefore(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 || d >= 24*time.Hour {
+		return 0, fmt.Errorf("invalid webhook_before %q: must be a duration such as 10m, under 24h", value)
+	}
+	return d, nil
+}
+
+// NotifyTargets are the names of the targets serve can send prayer events
+// to, for notify_targets.
+var NotifyTargets = []string{"webhooks", "mqtt", "matrix", "ntfy", "gotify", "pushover", "gpio"}
+
+// ParseNotifyTargets splits a comma-separated list of NotifyTargets names.
+// An empty value means every configured target.
+func ParseNotifyTargets(value string) ([]string, error) {
+	var targets []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, t := range NotifyTargets {
+			known = known || t == name
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid notify_targets %q: unknown target %q, must be from %s", value, name, strings.Join(NotifyTargets, ", "))
+		}
+		targets = append(targets, name)
+	}
+	return targets, nil
+}
+
+// DefaultChimeBefore is how long before a prayer the chime plays when
+// chime_before isn't set.
+const DefaultChimeBefore = 10 * time.Minute
+
+// ParseChimeBefore parses how long before each prayer the chime plays. An
+// empty value means DefaultChimeBefore.
+func ParseChimeBefore(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultChimeBefore, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 || d >= 24*time.Hour {
+		return 0, fmt.Errorf("invalid chime_before %q: must be a duration such as 10m, under 24h", value)
+	}
+	return d, nil
+}
+
+// MaxRetries bounds the retries key and the --retries flag.
+const MaxRetries = 10
+
+// ParseRetries parses how many times a failed API request is retried.
+func ParseRetries(value string) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 0 || v > MaxRetries {
+		return 0, fmt.Errorf("invalid retries %q: must be an integer between 0 and %d", value, MaxRetries)
+	}
+	return v, nil
+}
+
+// ParseHijriAdjustment parses the number of days added to Hijri dates, from
+// -hijri.MaxAdjustment to hijri.MaxAdjustment.
+func ParseHijriAdjustment(value string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(value), "+"))
+	if err != nil || v < -hijri.MaxAdjustment || v > hijri.MaxAdjustment {
+		return 0, fmt.Errorf("invalid hijri_adjustment %q: must be a whole number of days between -%d and %d", value, hijri.MaxAdjustment, hijri.MaxAdjustment)
+	}
+	return v, nil
+}
+
+// ParseRetryBackoff parses the delay before the first retry of a failed API
+// request. An empty value means the default.
+func ParseRetryBackoff(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 || d > time.Minute {
+		return 0, fmt.Errorf("invalid retry_backoff %q: must be a duration such as 500ms, up to 1m", value)
+	}
+	return d, nil
+}
+
+// DefaultHeartbeatInterval is how often serve posts to heartbeat_url when
+// heartbeat_interval isn't set.
+const DefaultHeartbeatInterval = 5 * time.Minute
+
+// ParseHeartbeatInterval parses how often serve posts its status to
+// heartbeat_url. An empty value means DefaultHeartbeatInterval.
+func ParseHeartbeatInterval(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultHeartbeatInterval, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 10*time.Second || d > 24*time.Hour {
+		return 0, fmt.Errorf("invalid heartbeat_interval %q: must be a duration such as 5m, from 10s to 24h", value)
+	}
+	return d, nil
+}
+
+// ValidateTimezone checks that value is an IANA timezone name such as
+// "Europe/London". An empty value is valid and means no override.
+func ValidateTimezone(value string) error {
+	if value == "" {
+		return nil
+	}
+	// "Local" loads but means nothing to the API.
+	if _, err := time.LoadLocation(value); err != nil || value == "Local" {
+		return fmt.Errorf("invalid timezone %q: must be an IANA name such as Europe/London", value)
+	}
+	return nil
+}
+
+// Presets are named bundles of config values for common situations, applied
+// over the config file with --preset. Hajj and Umrah locate at Makkah with the
+// Umm Al-Qura method and Arabic names; Hajj adds the Dhul-Hijjah events.
+var Presets = map[string]map[string]string{
+	"hajj": {
+		"latitude": "21.4225", "longitude": "39.8262",
+		"method":        "4",
+		"transliterate": "true",
+		"events":        "true",
+	},
+	"umrah": {
+		"latitude": "21.4225", "longitude": "39.8262",
+		"method":        "4",
+		"transliterate": "true",
+	},
+}
+
+// PresetNames returns the preset names in sorted order.
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyPreset sets every value of the named preset on c. A preset that sets
+// coordinates clears any city and country so the coordinates take effect.
+func (c *Config) ApplyPreset(name string) error {
+	values, ok := Presets[name]
+	if !ok {
+		return fmt.Errorf("unknown preset %q: must be one of %s", name, strings.Join(PresetNames(), ", "))
+	}
+	if err := c.applyValues(values); err != nil {
+		return fmt.Errorf("preset %s: %w", name, err)
+	}
+	return nil
+}
+
+// applyValues sets every key of values on c. Values that locate by
+// coordinates clear any city and country, and values that locate by city
+// clear any coordinates, so the new location takes effect.
+func (c *Config) applyValues(values map[string]string) error {
+	_, hasLat := values["latitude"]
+	_, hasCity := values["city"]
+	switch {
+	case hasLat:
+		c.City, c.Country = "", ""
+	case hasCity:
+		c.Latitude, c.Longitude = nil, nil
+	}
+	for key, value := range values {
+		if err := c.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProfileKeys are the config keys a location profile may set.
+var ProfileKeys = []string{"city", "country", "latitude", "longitude", "method", "school"}
+
+// ProfileNames returns the names of c's profiles in sorted order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddProfile stores values as the named profile, replacing any profile of
+// that name. Values may only use ProfileKeys and must give a city and
+// country or a latitude and longitude.
+func (c *Config) AddProfile(name string, values map[string]string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, ", \t") {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	profile := make(map[string]string, len(values))
+	for key, value := range values {
+		valid := false
+		for _, k := range ProfileKeys {
+			valid = valid || k == key
+		}
+		if !valid {
+			return fmt.Errorf("invalid profile key %q: must be one of %s", key, strings.Join(ProfileKeys, ", "))
+		}
+		profile[key] = value
+	}
+	var check Config
+	if err := check.applyValues(values); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	if (check.City == "" || check.Country == "") && (check.Latitude == nil || check.Longitude == nil) {
+		return fmt.Errorf("profile %s: needs a city and country or a latitude and longitude", name)
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]map[string]string)
+	}
+	c.Profiles[name] = profile
+	return nil
+}
+
+// RemoveProfile deletes the named profile.
+func (c *Config) RemoveProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	delete(c.Profiles, name)
+	return nil
+}
+
+// ApplyProfile sets every value of the named profile on c, like ApplyPreset.
+func (c *Config) ApplyProfile(name string) error {
+	values, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	if err := c.applyValues(values); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	return nil
+}
+
+// ShafaqOptions are the twilight colours the Moonsighting Committee method
+// (15) can time Isha by: general blends red and white twilight seasonally,
+// ahmer uses the red twilight and abyad the later white twilight.
+var ShafaqOptions = []string{"general", "ahmer", "abyad"}
+
+// ValidShafaq reports whether value is one of ShafaqOptions.
+func ValidShafaq(value string) bool {
+	for _, s := range ShafaqOptions {
+		if s == value {
+			return true
+		}
+	}
+	return false
 }
 
 // MethodOrDefault returns the method value, falling back to the given default.
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // tempConfigPath returns a path to a config file inside a temp directory.
@@ -43,11 +46,11 @@ func TestDefaults(t *testing.T) {
 	if d.Country != "" {
 		t.Errorf("Defaults().Country = %q, want empty", d.Country)
 	}
-	if d.Latitude != 0 {
-		t.Errorf("Defaults().Latitude = %f, want 0", d.Latitude)
+	if d.Latitude != nil {
+		t.Errorf("Defaults().Latitude = %v, want nil", *d.Latitude)
 	}
-	if d.Longitude != 0 {
-		t.Errorf("Defaults().Longitude = %f, want 0", d.Longitude)
+	if d.Longitude != nil {
+		t.Errorf("Defaults().Longitude = %v, want nil", *d.Longitude)
 	}
 	if d.Prayers != "" {
 		t.Errorf("Defaults().Prayers = %q, want empty", d.Prayers)
@@ -255,11 +258,13 @@ func TestSaveAndLoad_RoundTrip(t *testing.T) {
 
 	method := 0 // Jafari -- tests zero value round-trip.
 	school := 1
+	lat := 24.7136
+	lon := 0.0 // prime meridian -- must not read back as "not set".
 	original := &Config{
 		City:       "Riyadh",
 		Country:    "Saudi Arabia",
-		Latitude:   24.7136,
-		Longitude:  46.6753,
+		Latitude:   &lat,
+		Longitude:  &lon,
 		Method:     &method,
 		School:     &school,
 		TimeFormat: "12h",
@@ -283,11 +288,11 @@ func TestSaveAndLoad_RoundTrip(t *testing.T) {
 	if loaded.Country != original.Country {
 		t.Errorf("Country = %q, want %q", loaded.Country, original.Country)
 	}
-	if loaded.Latitude != original.Latitude {
-		t.Errorf("Latitude = %f, want %f", loaded.Latitude, original.Latitude)
+	if loaded.Latitude == nil || *loaded.Latitude != *original.Latitude {
+		t.Errorf("Latitude = %v, want %f", loaded.Latitude, *original.Latitude)
 	}
-	if loaded.Longitude != original.Longitude {
-		t.Errorf("Longitude = %f, want %f", loaded.Longitude, original.Longitude)
+	if loaded.Longitude == nil || *loaded.Longitude != *original.Longitude {
+		t.Errorf("Longitude = %v, want %f", loaded.Longitude, *original.Longitude)
 	}
 	if loaded.Method == nil || *loaded.Method != *original.Method {
 		t.Errorf("Method = %v, want %d", loaded.Method, *original.Method)
@@ -380,13 +385,31 @@ func TestSet_Latitude(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Set(latitude, %q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
 			}
-			if !tt.wantErr && cfg.Latitude != tt.want {
-				t.Errorf("Latitude = %f, want %f", cfg.Latitude, tt.want)
+			if !tt.wantErr && (cfg.Latitude == nil || *cfg.Latitude != tt.want) {
+				t.Errorf("Latitude = %v, want %f", cfg.Latitude, tt.want)
 			}
 		})
 	}
 }
 
+func TestSet_CoordinateClear(t *testing.T) {
+	cfg := &Config{}
+	_ = cfg.Set("latitude", "0")
+	if got, _ := cfg.Get("latitude"); got != "0" {
+		t.Errorf("Get(latitude) = %q, want \"0\"", got)
+	}
+	if !cfg.HasCoordinates() {
+		t.Error("HasCoordinates() = false after setting latitude 0")
+	}
+
+	if err := cfg.Set("latitude", ""); err != nil {
+		t.Fatalf("Set(latitude, \"\") error: %v", err)
+	}
+	if cfg.Latitude != nil || cfg.HasCoordinates() {
+		t.Errorf("Latitude = %v, want cleared", cfg.Latitude)
+	}
+}
+
 func TestSet_Longitude(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -411,8 +434,34 @@ func TestSet_Longitude(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Set(longitude, %q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
 			}
-			if !tt.wantErr && cfg.Longitude != tt.want {
-				t.Errorf("Longitude = %f, want %f", cfg.Longitude, tt.want)
+			if !tt.wantErr && (cfg.Longitude == nil || *cfg.Longitude != tt.want) {
+				t.Errorf("Longitude = %v, want %f", cfg.Longitude, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet_Timezone(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"Europe/London", false},
+		{"UTC", false},
+		{"", false},
+		{"Local", true},
+		{"Mars/Olympus", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.Set("timezone", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set(timezone, %q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.Timezone != tt.value {
+				t.Errorf("Timezone = %q, want %q", cfg.Timezone, tt.value)
 			}
 		})
 	}
@@ -485,6 +534,181 @@ func TestSet_School(t *testing.T) {
 	}
 }
 
+func TestSet_Angles(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"fajr_angle", "18.5", false},
+		{"maghrib_angle", "4", false},
+		{"isha_angle", "17", false},
+		{"isha_angle", "0", false},
+		{"fajr_angle", "31", true},
+		{"fajr_angle", "-1", true},
+		{"isha_angle", "abc", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.Set(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set(%s, %q) error = %v, wantErr = %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := &Config{}
+	_ = cfg.Set("fajr_angle", "18.5")
+	_ = cfg.Set("maghrib_angle", "4")
+	_ = cfg.Set("isha_angle", "17")
+	if cfg.FajrAngle != 18.5 || cfg.MaghribAngle != 4 || cfg.IshaAngle != 17 {
+		t.Errorf("angles = %v/%v/%v, want 18.5/4/17", cfg.FajrAngle, cfg.MaghribAngle, cfg.IshaAngle)
+	}
+}
+
+func TestParseTune(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]int
+		wantErr bool
+	}{
+		{"", map[string]int{}, false},
+		{"Fajr:+3,Asr:-2", map[string]int{"Fajr": 3, "Asr": -2}, false},
+		{" Isha : 5 , Imsak:0", map[string]int{"Isha": 5, "Imsak": 0}, false},
+		{"Fajr", nil, true},
+		{"Fajr:abc", nil, true},
+		{"Fajr:61", nil, true},
+		{"Lastthird:2", nil, true},
+		{"Tahajjud:2", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTune(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTune(%q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseTune(%q) = %v, want %v", tt.value, got, tt.want)
+			continue
+		}
+		for name, v := range tt.want {
+			if got[name] != v {
+				t.Errorf("ParseTune(%q)[%s] = %d, want %d", tt.value, name, got[name], v)
+			}
+		}
+	}
+}
+
+func TestParseLatitudeAdjustment(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"middle-of-night", 1, false},
+		{"one-seventh", 2, false},
+		{"angle-based", 3, false},
+		{"1", 0, true},
+		{"Angle-Based", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLatitudeAdjustment(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLatitudeAdjustment(%q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLatitudeAdjustment(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+
+	cfg := &Config{}
+	if err := cfg.Set("latitude_adjustment", "polar"); err == nil {
+		t.Error("Set(latitude_adjustment, polar) expected error")
+	}
+}
+
+func TestParseMidnightMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"standard", "0", false},
+		{"jafari", "1", false},
+		{"Jafari", "", true},
+		{"1", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMidnightMode(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMidnightMode(%q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMidnightMode(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSet_Shafaq(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"general", false},
+		{"ahmer", false},
+		{"abyad", false},
+		{"", false},
+		{"Ahmer", true},
+		{"red", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.Set("shafaq", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set(shafaq, %q) error = %v, wantErr = %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.Shafaq != tt.value {
+				t.Errorf("Shafaq = %q, want %q", cfg.Shafaq, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseWorldCities(t *testing.T) {
+	got, err := ParseWorldCities("London:UK, New York : US")
+	if err != nil {
+		t.Fatalf("ParseWorldCities() error: %v", err)
+	}
+	want := []WorldCity{{City: "London", Country: "UK"}, {City: "New York", Country: "US"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWorldCities() = %v, want %v", got, want)
+	}
+
+	if got, err := ParseWorldCities(""); err != nil || len(got) != 0 {
+		t.Errorf("ParseWorldCities(\"\") = %v, %v; want no cities", got, err)
+	}
+
+	for _, value := range []string{"London", "London:", ":UK", "London:UK,,Cairo:EG"} {
+		if _, err := ParseWorldCities(value); err == nil {
+			t.Errorf("ParseWorldCities(%q) expected error", value)
+		}
+	}
+}
+
 func TestSet_TimeFormat(t *testing.T) {
 	tests := []struct {
 		value   string
@@ -510,6 +734,22 @@ func TestSet_TimeFormat(t *testing.T) {
 	}
 }
 
+func TestSet_Lang(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("lang", " AR "); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Lang != "ar" {
+		t.Errorf("Lang = %q, want %q", cfg.Lang, "ar")
+	}
+	if err := cfg.Set("lang", "auto"); err != nil || cfg.Lang != "auto" {
+		t.Errorf("Set(lang, auto) = %v, Lang = %q", err, cfg.Lang)
+	}
+	if err := cfg.Set("lang", "xx"); err == nil {
+		t.Error("Set(lang, xx) expected error")
+	}
+}
+
 func TestSet_Prayers(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -548,6 +788,533 @@ func TestSet_CacheDir(t *testing.T) {
 	}
 }
 
+func TestSet_CacheMaxSize(t *testing.T) {
+	cfg := &Config{}
+	for _, v := range []string{"500", "20MB", "500, 1.5GB", ""} {
+		if err := cfg.Set("cache_max_size", v); err != nil {
+			t.Errorf("Set(cache_max_size, %q) error: %v", v, err)
+		}
+	}
+	if err := cfg.Set("cache_max_size", "200, 10MB"); err != nil || cfg.CacheMaxSize != "200,10MB" {
+		t.Errorf("CacheMaxSize = %q, %v; want 200,10MB", cfg.CacheMaxSize, err)
+	}
+	for _, v := range []string{"0", "-5", "lots", "20TB", "1,2"} {
+		if err := cfg.Set("cache_max_size", v); err == nil {
+			t.Errorf("Set(cache_max_size, %q) succeeded, want an error", v)
+		}
+	}
+}
+
+func TestSet_Archive(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("archive", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Archive {
+		t.Error("Archive = false, want true")
+	}
+
+	if err := cfg.Set("archive", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Archive {
+		t.Error("Archive = true, want false")
+	}
+
+	if err := cfg.Set("archive", "sometimes"); err == nil {
+		t.Error("Set(archive, sometimes) should error")
+	}
+}
+
+func TestSet_Reminder(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("reminder", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Reminder {
+		t.Error("Reminder = false, want true")
+	}
+	if err := cfg.Set("reminder", "maybe"); err == nil {
+		t.Error("Set(reminder, maybe) should error")
+	}
+}
+
+func TestSet_Kids(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("kids", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Kids {
+		t.Error("Kids = false, want true")
+	}
+	if err := cfg.Set("kids", "maybe"); err == nil {
+		t.Error("Set(kids, maybe) should error")
+	}
+}
+
+func TestSet_Transliterate(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("transliterate", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Transliterate {
+		t.Error("Transliterate = false, want true")
+	}
+	if err := cfg.Set("transliterate", "maybe"); err == nil {
+		t.Error("Set(transliterate, maybe) should error")
+	}
+}
+
+func TestSet_Events(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("events", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Events {
+		t.Error("Events = false, want true")
+	}
+	if err := cfg.Set("events", "maybe"); err == nil {
+		t.Error("Set(events, maybe) should error")
+	}
+}
+
+func TestSet_HijriAdjustment(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("hijri_adjustment", "+1"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.HijriAdjustment != 1 {
+		t.Errorf("HijriAdjustment = %d, want 1", cfg.HijriAdjustment)
+	}
+	for _, bad := range []string{"3", "-3", "1.5", "one"} {
+		if err := cfg.Set("hijri_adjustment", bad); err == nil {
+			t.Errorf("Set(hijri_adjustment, %q) should error", bad)
+		}
+	}
+}
+
+func TestSet_Theme(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("theme", "accent=#ffaf00+bold,dim=244"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != "accent=#ffaf00+bold,dim=244" {
+		t.Errorf("Theme = %q", cfg.Theme)
+	}
+	for _, bad := range []string{"accent", "accent=pink", "border=red", "dim=256"} {
+		if err := cfg.Set("theme", bad); err == nil {
+			t.Errorf("Set(theme, %q) should error", bad)
+		}
+	}
+}
+
+func TestSet_AdhanPlayer(t *testing.T) {
+	cfg := &Config{}
+	for _, v := range []string{"auto", "mpv", "afplay", "paplay", ""} {
+		if err := cfg.Set("adhan_player", v); err != nil {
+			t.Errorf("Set(adhan_player, %q) error: %v", v, err)
+		}
+	}
+	if err := cfg.Set("adhan_player", "vlc"); err == nil {
+		t.Error("Set(adhan_player, vlc) should error")
+	}
+}
+
+func TestSet_Chime(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("chime_before", "5m"); err != nil {
+		t.Errorf("Set(chime_before, 5m) error: %v", err)
+	}
+	for _, v := range []string{"0", "-5m", "24h", "soon"} {
+		if err := cfg.Set("chime_before", v); err == nil {
+			t.Errorf("Set(chime_before, %q) should error", v)
+		}
+	}
+	if d, _ := ParseChimeBefore(""); d != DefaultChimeBefore {
+		t.Errorf("ParseChimeBefore(\"\") = %s, want %s", d, DefaultChimeBefore)
+	}
+
+	if err := cfg.Set("chime_prayers", "Fajr, Isha"); err != nil {
+		t.Errorf("Set(chime_prayers) error: %v", err)
+	}
+	if err := cfg.Set("chime_prayers", "Fajr,Noon"); err == nil {
+		t.Error("Set(chime_prayers, Fajr,Noon) should error")
+	}
+}
+
+func TestSet_Webhooks(t *testing.T) {
+	cfg := &Config{}
+	for key, value := range map[string]string{
+		"webhooks":         "https://hooks.slack.com/services/x,http://ha.local:8123/api/webhook/prayer",
+		"webhook_before":   "15m",
+		"webhook_template": `{"text": {{json .Message}}}`,
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Errorf("Set(%s, %q) error: %v", key, value, err)
+		}
+	}
+	for key, value := range map[string]string{
+		"webhooks":         "example.com/hook",
+		"webhook_before":   "-5m",
+		"webhook_template": "teams",
+	} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+	if d, _ := ParseWebhookBefore(cfg.WebhookBefore); d != 15*time.Minute {
+		t.Errorf("ParseWebhookBefore(%q) = %v, want 15m", cfg.WebhookBefore, d)
+	}
+}
+
+func TestSet_SyncURL(t *testing.T) {
+	cfg := &Config{}
+	for _, value := range []string{"git+https://github.com/user/dotfiles", "https://dav.example.com/prayer-times.sync", ""} {
+		if err := cfg.Set("sync_url", value); err != nil {
+			t.Errorf("Set(sync_url, %q) error: %v", value, err)
+		}
+	}
+	for _, value := range []string{"git+--upload-pack=touch /tmp/x", "ftp://example.com/x"} {
+		if err := cfg.Set("sync_url", value); err == nil {
+			t.Errorf("Set(sync_url, %q) should error", value)
+		}
+	}
+}
+
+func TestSet_Retries(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("retries", "0"); err != nil || cfg.Retries == nil || *cfg.Retries != 0 {
+		t.Errorf("Set(retries, 0) = %v, Retries = %v; want 0 kept distinct from not set", err, cfg.Retries)
+	}
+	if err := cfg.Set("retry_backoff", "1s"); err != nil {
+		t.Errorf("Set(retry_backoff, 1s) error: %v", err)
+	}
+	for key, value := range map[string]string{
+		"retries":       "11",
+		"retry_backoff": "0s",
+	} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+	if d, _ := ParseRetryBackoff(cfg.RetryBackoff); d != time.Second {
+		t.Errorf("ParseRetryBackoff(%q) = %v, want 1s", cfg.RetryBackoff, d)
+	}
+}
+
+func TestSet_GeoProvider(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("geo_provider", "ipapi,ipinfo"); err != nil {
+		t.Errorf("Set(geo_provider) error: %v", err)
+	}
+	// Whether plain HTTP is allowed is decided when detecting.
+	if err := cfg.Set("geo_provider", "ip-api"); err != nil {
+		t.Errorf("Set(geo_provider, ip-api) error: %v", err)
+	}
+	if err := cfg.Set("geo_provider", "freegeoip"); err == nil {
+		t.Error("Set(geo_provider, freegeoip) should error")
+	}
+}
+
+func TestSet_GPIO(t *testing.T) {
+	cfg := &Config{}
+	for key, value := range map[string]string{"gpio_pin": "17", "gpio_pattern": "3x200ms"} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Errorf("Set(%s, %q) error: %v", key, value, err)
+		}
+	}
+	if err := cfg.Set("gpio_pin", ""); err != nil || cfg.GPIOPin != "" {
+		t.Errorf("Set(gpio_pin, \"\") = %v, want it cleared", err)
+	}
+	for key, value := range map[string]string{"gpio_pin": "GPIO17", "gpio_pattern": "blink"} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+}
+
+func TestSet_Matrix(t *testing.T) {
+	cfg := &Config{}
+	for key, value := range map[string]string{"matrix_homeserver": "https://matrix.org", "matrix_room": "!abcdef:matrix.org"} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Errorf("Set(%s, %q) error: %v", key, value, err)
+		}
+	}
+	for key, value := range map[string]string{"matrix_homeserver": "matrix.org", "matrix_room": "#prayer:matrix.org"} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+}
+
+func TestSet_Push(t *testing.T) {
+	cfg := &Config{}
+	for key, value := range map[string]string{"ntfy_url": "https://ntfy.sh/my-prayers", "gotify_url": "http://gotify.local:8080"} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Errorf("Set(%s, %q) error: %v", key, value, err)
+		}
+	}
+	for key, value := range map[string]string{"ntfy_url": "https://ntfy.sh", "gotify_url": "gotify.local"} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+}
+
+func TestSet_NotifyTargets(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("notify_targets", "ntfy, Pushover,matrix"); err != nil {
+		t.Errorf("Set(notify_targets) error: %v", err)
+	}
+	if got, _ := ParseNotifyTargets(cfg.NotifyTargets); len(got) != 3 || got[1] != "pushover" {
+		t.Errorf("ParseNotifyTargets() = %v, want [ntfy pushover matrix]", got)
+	}
+	if err := cfg.Set("notify_targets", "ntfy,telegram"); err == nil || !strings.Contains(err.Error(), "telegram") {
+		t.Errorf("Set(notify_targets) with an unknown target error = %v, want one naming it", err)
+	}
+	if err := cfg.Set("ntfy_template", "{{.Prayer"); err == nil {
+		t.Error("Set(ntfy_template) with a bad template should error")
+	}
+}
+
+func TestSet_Heartbeat(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("heartbeat_url", "https://hc-ping.com/abc"); err != nil {
+		t.Errorf("Set(heartbeat_url) error: %v", err)
+	}
+	for key, value := range map[string]string{
+		"heartbeat_url":      "hc-ping.com/abc",
+		"heartbeat_interval": "5s",
+	} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+	if err := cfg.Set("heartbeat_url", "https://a.example.com,https://b.example.com"); err == nil {
+		t.Error("Set(heartbeat_url) with two URLs should error")
+	}
+	if d, _ := ParseHeartbeatInterval(""); d != DefaultHeartbeatInterval {
+		t.Errorf("ParseHeartbeatInterval(\"\") = %v, want %v", d, DefaultHeartbeatInterval)
+	}
+}
+
+func TestSet_MQTT(t *testing.T) {
+	cfg := &Config{}
+	for key, value := range map[string]string{
+		"mqtt_broker": "mqtts://broker.example.com:8883",
+		"mqtt_topic":  "home/prayer",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Errorf("Set(%s, %q) error: %v", key, value, err)
+		}
+	}
+	for key, value := range map[string]string{
+		"mqtt_broker": "broker.example.com:1883",
+		"mqtt_topic":  "home/#",
+	} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%s, %q) should error", key, value)
+		}
+	}
+}
+
+func TestApplyPreset(t *testing.T) {
+	cfg := &Config{City: "London", Country: "UK", Kids: true}
+	if err := cfg.ApplyPreset("hajj"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.City != "" || cfg.Country != "" {
+		t.Errorf("city/country = %q/%q, want cleared", cfg.City, cfg.Country)
+	}
+	if lat, lon := cfg.Coordinates(); lat != 21.4225 || lon != 39.8262 {
+		t.Errorf("coordinates = %v, %v, want Makkah", lat, lon)
+	}
+	if cfg.MethodOrDefault(-1) != 4 || !cfg.Transliterate || !cfg.Events {
+		t.Errorf("preset hajj = %+v, want method 4 with transliterate and events", cfg)
+	}
+	if !cfg.Kids {
+		t.Error("ApplyPreset() cleared an unrelated setting")
+	}
+
+	if err := (&Config{}).ApplyPreset("picnic"); err == nil {
+		t.Error("ApplyPreset(picnic) should error")
+	}
+}
+
+func TestAddProfile(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.AddProfile("makkah", map[string]string{"city": "Makkah", "country": "SA", "method": "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.AddProfile("home", map[string]string{"latitude": "51.5", "longitude": "-0.12"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(cfg.ProfileNames(), ","); got != "home,makkah" {
+		t.Errorf("ProfileNames() = %q, want home,makkah", got)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		values map[string]string
+	}{
+		{"work", map[string]string{"city": "Leeds"}},
+		{"work", map[string]string{"latitude": "53.8"}},
+		{"work", map[string]string{"city": "Leeds", "country": "UK", "kids": "true"}},
+		{"work", map[string]string{"city": "Leeds", "country": "UK", "method": "99"}},
+		{"my work", map[string]string{"city": "Leeds", "country": "UK"}},
+		{"", map[string]string{"city": "Leeds", "country": "UK"}},
+	} {
+		if err := cfg.AddProfile(tc.name, tc.values); err == nil {
+			t.Errorf("AddProfile(%q, %v) should error", tc.name, tc.values)
+		}
+	}
+
+	if err := cfg.RemoveProfile("home"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.RemoveProfile("home"); err == nil {
+		t.Error("RemoveProfile() of a missing profile should error")
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	lat, lon := 51.5, -0.12
+	cfg := &Config{Latitude: &lat, Longitude: &lon, Kids: true}
+	if err := cfg.AddProfile("makkah", map[string]string{"city": "Makkah", "country": "SA", "method": "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.ApplyProfile("makkah"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.HasCoordinates() {
+		t.Error("ApplyProfile() kept the coordinates, which would override the city")
+	}
+	if cfg.City != "Makkah" || cfg.Country != "SA" || cfg.MethodOrDefault(-1) != 4 || !cfg.Kids {
+		t.Errorf("after ApplyProfile(makkah) = %+v", cfg)
+	}
+
+	if err := cfg.ApplyProfile("work"); err == nil {
+		t.Error("ApplyProfile(work) should error")
+	}
+}
+
+func TestProfiles_RoundTrip(t *testing.T) {
+	path := tempConfigPath(t)
+	cfg := &Config{}
+	if err := cfg.AddProfile("makkah", map[string]string{"city": "Makkah", "country": "SA"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Profiles["makkah"]["city"]; got != "Makkah" {
+		t.Errorf("loaded profile city = %q, want Makkah", got)
+	}
+}
+
+func TestSet_CommandDefaults(t *testing.T) {
+	cfg := &Config{}
+	for key, value := range map[string]string{
+		"commands.next.format":       "name-and-time",
+		"commands.list.days":         "14",
+		"commands.export.ical.alarm": "10m",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%s) error: %v", key, err)
+		}
+		if got, _ := cfg.Get(key); got != value {
+			t.Errorf("Get(%s) = %q, want %q", key, got, value)
+		}
+	}
+	if got := cfg.Commands["export ical"]["alarm"]; got != "10m" {
+		t.Errorf("Commands[export ical][alarm] = %q, want 10m", got)
+	}
+
+	want := []string{"commands.export.ical.alarm", "commands.list.days", "commands.next.format"}
+	if got := cfg.CommandKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CommandKeys() = %v, want %v", got, want)
+	}
+
+	if err := cfg.Set("commands.list.days", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Commands["list"]; ok {
+		t.Error("clearing the last default should remove the command")
+	}
+
+	for _, key := range []string{"commands.next", "commands..format", "commands.next."} {
+		if err := cfg.Set(key, "x"); err == nil {
+			t.Errorf("Set(%s) should error", key)
+		}
+	}
+}
+
+func TestSet_Aliases(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("aliases.iftar", "query Maghrib --format time-remaining"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cfg.Get("aliases.iftar"); got != "query Maghrib --format time-remaining" {
+		t.Errorf("Get(aliases.iftar) = %q", got)
+	}
+	if got := cfg.Keys(); got[len(got)-1] != "aliases.iftar" {
+		t.Errorf("Keys() = %v, want aliases.iftar last", got)
+	}
+
+	if err := cfg.Set("aliases.iftar", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Aliases["iftar"]; ok {
+		t.Error("an empty value should remove the alias")
+	}
+
+	for key, value := range map[string]string{
+		"aliases.":        "next",
+		"aliases.a.b":     "next",
+		"aliases.-x":      "next",
+		"aliases.bad":     "query 'Maghrib",
+		"aliases.spaces ": "next",
+	} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("Set(%q, %q) should error", key, value)
+		}
+	}
+}
+
+func TestSplitAlias(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"query Maghrib --format time-remaining", []string{"query", "Maghrib", "--format", "time-remaining"}},
+		{"  next   ", []string{"next"}},
+		{`--city "New York" next`, []string{"--city", "New York", "next"}},
+		{`--country 'Saudi Arabia'`, []string{"--country", "Saudi Arabia"}},
+		{`--city New\ York`, []string{"--city", "New York"}},
+		{`query ""`, []string{"query", ""}},
+	}
+	for _, tt := range tests {
+		got, err := SplitAlias(tt.in)
+		if err != nil {
+			t.Errorf("SplitAlias(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitAlias(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "   ", `query "Maghrib`, `next \`} {
+		if _, err := SplitAlias(in); err == nil {
+			t.Errorf("SplitAlias(%q) should error", in)
+		}
+	}
+}
+
 func TestSet_UnknownKey(t *testing.T) {
 	cfg := &Config{}
 	err := cfg.Set("unknown_key", "value")
@@ -561,16 +1328,76 @@ func TestSet_UnknownKey(t *testing.T) {
 func TestGet_AllKeys(t *testing.T) {
 	method := 4
 	school := 1
+	retries := 3
+	lat, lon := 24.7136, 46.6753
 	cfg := &Config{
-		City:       "Riyadh",
-		Country:    "Saudi Arabia",
-		Latitude:   24.7136,
-		Longitude:  46.6753,
-		Method:     &method,
-		School:     &school,
-		TimeFormat: "12h",
-		Prayers:    "Fajr,Dhuhr,Asr,Maghrib,Isha",
-		CacheDir:   "/tmp/cache",
+		City:               "Riyadh",
+		Country:            "Saudi Arabia",
+		Latitude:           &lat,
+		Longitude:          &lon,
+		Timezone:           "Asia/Riyadh",
+		Method:             &method,
+		School:             &school,
+		FajrAngle:          18.5,
+		MaghribAngle:       4,
+		IshaAngle:          17,
+		Tune:               "Fajr:+3,Asr:-2",
+		LatitudeAdjustment: "angle-based",
+		MidnightMode:       "jafari",
+		Shafaq:             "ahmer",
+		TimeFormat:         "12h",
+		Lang:               "ar",
+		Prayers:            "Fajr,Dhuhr,Asr,Maghrib,Isha",
+		CacheDir:           "/tmp/cache",
+		CacheMaxSize:       "500,20MB",
+		Retries:            &retries,
+		RetryBackoff:       "250ms",
+		GeoProvider:        "ipinfo,ip-api",
+		GeoAPIKey:          "0123abcd",
+		WorldCities:        "London:UK,Cairo:EG",
+		Archive:            true,
+		Reminder:           true,
+		Kids:               true,
+		Transliterate:      true,
+		Events:             true,
+		HijriAdjustment:    -1,
+		Theme:              "accent=214+bold",
+		AdhanSound:         "/tmp/adhan.mp3",
+		AdhanFajrSound:     "none",
+		AdhanPlayer:        "mpv",
+		ChimeSound:         "/tmp/chime.wav",
+		ChimeBefore:        "5m",
+		ChimePrayers:       "Fajr,Maghrib",
+		Webhooks:           "https://example.com/hook",
+		WebhookBefore:      "10m",
+		WebhookTemplate:    "slack",
+		SyncURL:            "git@github.com:user/sync.git",
+		MQTTBroker:         "mqtt://homeassistant.local:1883",
+		MQTTTopic:          "home/prayer",
+		MQTTUsername:       "prayer",
+		MQTTPassword:       "secret",
+		GPIOPin:            "17",
+		GPIOPattern:        "1x3s",
+		MatrixHomeserver:   "https://matrix.org",
+		MatrixToken:        "syt_token",
+		MatrixRoom:         "!abcdef:matrix.org",
+		MatrixTemplate:     "{{.Prayer}} now",
+		NtfyURL:            "https://ntfy.sh/my-prayers",
+		NtfyToken:          "tk_abc",
+		NtfyTemplate:       "🕌 {{.Prayer}}",
+		NtfyTitle:          "{{.Name}} {{.Clock}}",
+		GotifyURL:          "https://gotify.example.com",
+		GotifyToken:        "AbCdEf",
+		GotifyTemplate:     "{{.Message}}",
+		GotifyTitle:        "Salah",
+		PushoverUser:       "uQiRzpo4DXghDmr9QzzfQu",
+		PushoverToken:      "azGDORePK8gMaC0QOYAMyEEuzJnyUi",
+		PushoverTemplate:   "{{.Prayer}} in {{.Location}}",
+		PushoverTitle:      "{{if .Reminder}}Soon{{else}}Now{{end}}",
+		NotifyTargets:      "ntfy,matrix",
+		AdminToken:         "s3cret-token",
+		HeartbeatURL:       "https://hc-ping.com/abc",
+		HeartbeatInterval:  "10m",
 	}
 
 	tests := []struct {
@@ -581,11 +1408,69 @@ func TestGet_AllKeys(t *testing.T) {
 		{"country", "Saudi Arabia"},
 		{"latitude", "24.7136"},
 		{"longitude", "46.6753"},
+		{"timezone", "Asia/Riyadh"},
 		{"method", "4"},
 		{"school", "1"},
+		{"fajr_angle", "18.5"},
+		{"maghrib_angle", "4"},
+		{"isha_angle", "17"},
+		{"tune", "Fajr:+3,Asr:-2"},
+		{"latitude_adjustment", "angle-based"},
+		{"midnight_mode", "jafari"},
+		{"shafaq", "ahmer"},
 		{"time_format", "12h"},
+		{"lang", "ar"},
 		{"prayers", "Fajr,Dhuhr,Asr,Maghrib,Isha"},
 		{"cache_dir", "/tmp/cache"},
+		{"cache_max_size", "500,20MB"},
+		{"retries", "3"},
+		{"retry_backoff", "250ms"},
+		{"geo_provider", "ipinfo,ip-api"},
+		{"geo_api_key", "0123abcd"},
+		{"world_cities", "London:UK,Cairo:EG"},
+		{"archive", "true"},
+		{"reminder", "true"},
+		{"kids", "true"},
+		{"transliterate", "true"},
+		{"events", "true"},
+		{"hijri_adjustment", "-1"},
+		{"theme", "accent=214+bold"},
+		{"adhan_sound", "/tmp/adhan.mp3"},
+		{"adhan_fajr_sound", "none"},
+		{"adhan_player", "mpv"},
+		{"chime_sound", "/tmp/chime.wav"},
+		{"chime_before", "5m"},
+		{"chime_prayers", "Fajr,Maghrib"},
+		{"webhooks", "https://example.com/hook"},
+		{"webhook_before", "10m"},
+		{"webhook_template", "slack"},
+		{"sync_url", "git@github.com:user/sync.git"},
+		{"mqtt_broker", "mqtt://homeassistant.local:1883"},
+		{"mqtt_topic", "home/prayer"},
+		{"mqtt_username", "prayer"},
+		{"mqtt_password", "secret"},
+		{"gpio_pin", "17"},
+		{"gpio_pattern", "1x3s"},
+		{"matrix_homeserver", "https://matrix.org"},
+		{"matrix_token", "syt_token"},
+		{"matrix_room", "!abcdef:matrix.org"},
+		{"matrix_template", "{{.Prayer}} now"},
+		{"ntfy_url", "https://ntfy.sh/my-prayers"},
+		{"ntfy_token", "tk_abc"},
+		{"ntfy_template", "🕌 {{.Prayer}}"},
+		{"ntfy_title", "{{.Name}} {{.Clock}}"},
+		{"gotify_url", "https://gotify.example.com"},
+		{"gotify_token", "AbCdEf"},
+		{"gotify_template", "{{.Message}}"},
+		{"gotify_title", "Salah"},
+		{"pushover_user", "uQiRzpo4DXghDmr9QzzfQu"},
+		{"pushover_token", "azGDORePK8gMaC0QOYAMyEEuzJnyUi"},
+		{"pushover_template", "{{.Prayer}} in {{.Location}}"},
+		{"pushover_title", "{{if .Reminder}}Soon{{else}}Now{{end}}"},
+		{"notify_targets", "ntfy,matrix"},
+		{"admin_token", "s3cret-token"},
+		{"heartbeat_url", "https://hc-ping.com/abc"},
+		{"heartbeat_interval", "10m"},
 	}
 
 	for _, tt := range tests {
@@ -689,8 +1574,33 @@ func TestSchoolOrDefault_Zero(t *testing.T) {
 
 func TestValidKeys_ContainsExpected(t *testing.T) {
 	expected := []string{
-		"city", "country", "latitude", "longitude",
-		"method", "school", "time_format", "prayers", "cache_dir",
+		"city", "country", "latitude", "longitude", "timezone",
+		"method", "school",
+		"fajr_angle", "maghrib_angle", "isha_angle",
+		"tune",
+		"latitude_adjustment", "midnight_mode", "shafaq",
+		"time_format", "lang", "prayers", "cache_dir", "cache_max_size",
+		"retries", "retry_backoff",
+		"geo_provider", "geo_api_key",
+		"world_cities",
+		"archive", "reminder",
+		"kids",
+		"transliterate",
+		"events", "hijri_adjustment",
+		"theme",
+		"adhan_sound", "adhan_fajr_sound", "adhan_player",
+		"chime_sound", "chime_before", "chime_prayers",
+		"webhooks", "webhook_before", "webhook_template",
+		"sync_url",
+		"mqtt_broker", "mqtt_topic", "mqtt_username", "mqtt_password",
+		"gpio_pin", "gpio_pattern",
+		"matrix_homeserver", "matrix_token", "matrix_room", "matrix_template",
+		"ntfy_url", "ntfy_token", "ntfy_template", "ntfy_title",
+		"gotify_url", "gotify_token", "gotify_template", "gotify_title",
+		"pushover_user", "pushover_token", "pushover_template", "pushover_title",
+		"notify_targets",
+		"admin_token",
+		"heartbeat_url", "heartbeat_interval",
 	}
 
 	if len(ValidKeys) != len(expected) {
@@ -708,24 +1618,34 @@ func TestValidKeys_ContainsExpected(t *testing.T) {
 	}
 }
 
-// --- isValidPrayerName ---
+// --- canonicalPrayers ---
 
-func TestIsValidPrayerName(t *testing.T) {
-	valid := []string{"Fajr", "Sunrise", "Dhuhr", "Asr", "Sunset", "Maghrib", "Isha", "Imsak", "Midnight", "Firstthird", "Lastthird"}
-	for _, name := range valid {
-		if !isValidPrayerName(name) {
-			t.Errorf("isValidPrayerName(%q) = false, want true", name)
-		}
+func TestCanonicalPrayers(t *testing.T) {
+	got, err := canonicalPrayers("Fajr, zuhr,Maghreb , ISHA", "prayers")
+	if err != nil {
+		t.Fatalf("canonicalPrayers() error: %v", err)
+	}
+	if got != "Fajr,Dhuhr,Maghrib,Isha" {
+		t.Errorf("canonicalPrayers() = %q, want %q", got, "Fajr,Dhuhr,Maghrib,Isha")
 	}
 
-	invalid := []string{"fajr", "FAJR", "Prayer", "", "Invalid"}
-	for _, name := range invalid {
-		if isValidPrayerName(name) {
-			t.Errorf("isValidPrayerName(%q) = true, want false", name)
+	for _, bad := range []string{"Prayer", "", "Fajr,,Asr", "Fajr,Invalid"} {
+		if _, err := canonicalPrayers(bad, "prayers"); err == nil {
+			t.Errorf("canonicalPrayers(%q) should error", bad)
 		}
 	}
 }
 
+func TestSet_PrayersAliases(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("chime_prayers", "Subuh,Ashar"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ChimePrayers != "Fajr,Asr" {
+		t.Errorf("ChimePrayers = %q, want Fajr,Asr", cfg.ChimePrayers)
+	}
+}
+
 // --- OmitEmpty JSON behavior ---
 
 func TestConfig_OmitEmpty_JSON(t *testing.T) {
@@ -771,11 +1691,69 @@ func TestSetThenGet_RoundTrip(t *testing.T) {
 		{"country", "Saudi Arabia"},
 		{"latitude", "24.7136"},
 		{"longitude", "46.6753"},
+		{"timezone", "Asia/Riyadh"},
 		{"method", "4"},
 		{"school", "1"},
+		{"fajr_angle", "18.5"},
+		{"maghrib_angle", "4"},
+		{"isha_angle", "17"},
+		{"tune", "Fajr:+3,Asr:-2"},
+		{"latitude_adjustment", "angle-based"},
+		{"midnight_mode", "jafari"},
+		{"shafaq", "ahmer"},
 		{"time_format", "12h"},
+		{"lang", "ar"},
 		{"prayers", "Fajr,Dhuhr,Asr,Maghrib,Isha"},
 		{"cache_dir", "/tmp/cache"},
+		{"cache_max_size", "500,20MB"},
+		{"retries", "3"},
+		{"retry_backoff", "250ms"},
+		{"geo_provider", "ipinfo,ip-api"},
+		{"geo_api_key", "0123abcd"},
+		{"world_cities", "London:UK,Cairo:EG"},
+		{"archive", "true"},
+		{"reminder", "true"},
+		{"kids", "true"},
+		{"transliterate", "true"},
+		{"events", "true"},
+		{"hijri_adjustment", "-1"},
+		{"theme", "accent=214+bold"},
+		{"adhan_sound", "/tmp/adhan.mp3"},
+		{"adhan_fajr_sound", "none"},
+		{"adhan_player", "mpv"},
+		{"chime_sound", "/tmp/chime.wav"},
+		{"chime_before", "5m"},
+		{"chime_prayers", "Fajr,Maghrib"},
+		{"webhooks", "https://example.com/hook"},
+		{"webhook_before", "10m"},
+		{"webhook_template", "slack"},
+		{"sync_url", "git@github.com:user/sync.git"},
+		{"mqtt_broker", "mqtt://homeassistant.local:1883"},
+		{"mqtt_topic", "home/prayer"},
+		{"mqtt_username", "prayer"},
+		{"mqtt_password", "secret"},
+		{"gpio_pin", "17"},
+		{"gpio_pattern", "1x3s"},
+		{"matrix_homeserver", "https://matrix.org"},
+		{"matrix_token", "syt_token"},
+		{"matrix_room", "!abcdef:matrix.org"},
+		{"matrix_template", "{{.Prayer}} now"},
+		{"ntfy_url", "https://ntfy.sh/my-prayers"},
+		{"ntfy_token", "tk_abc"},
+		{"ntfy_template", "🕌 {{.Prayer}}"},
+		{"ntfy_title", "{{.Name}} {{.Clock}}"},
+		{"gotify_url", "https://gotify.example.com"},
+		{"gotify_token", "AbCdEf"},
+		{"gotify_template", "{{.Message}}"},
+		{"gotify_title", "Salah"},
+		{"pushover_user", "uQiRzpo4DXghDmr9QzzfQu"},
+		{"pushover_token", "azGDORePK8gMaC0QOYAMyEEuzJnyUi"},
+		{"pushover_template", "{{.Prayer}} in {{.Location}}"},
+		{"pushover_title", "{{if .Reminder}}Soon{{else}}Now{{end}}"},
+		{"notify_targets", "ntfy,matrix"},
+		{"admin_token", "s3cret-token"},
+		{"heartbeat_url", "https://hc-ping.com/abc"},
+		{"heartbeat_interval", "10m"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,153 @@
+package configsync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func init() {
+	// Keep tests fast; the work factor is read back from each file.
+	iterations = 1000
+}
+
+func TestPBKDF2_RFC7914(t *testing.T) {
+	got := hex.EncodeToString(pbkdf2([]byte("passwd"), []byte("salt"), 1, 64, sha256.New))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Errorf("pbkdf2 = %s, want %s", got, want)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	plain := []byte(`{"city": "Riyadh"}`)
+	sealed, err := Seal(plain, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, []byte("Riyadh")) {
+		t.Error("sealed data contains the plaintext")
+	}
+
+	got, err := Open(sealed, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Open() = %s, want %s", got, plain)
+	}
+
+	if _, err := Open(sealed, "wrong"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("Open with a wrong passphrase = %v, want ErrPassphrase", err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := Open(sealed, "correct horse"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("Open of tampered data = %v, want ErrPassphrase", err)
+	}
+	if _, err := Open([]byte(`{"city": "Riyadh"}`), "correct horse"); err == nil {
+		t.Error("Open of a plain file should error")
+	}
+	if _, err := Seal(plain, ""); err == nil {
+		t.Error("Seal with an empty passphrase should error")
+	}
+}
+
+func TestNewRemote(t *testing.T) {
+	tests := []struct {
+		url  string
+		git  bool
+		fail bool
+	}{
+		{"https://dav.example.com/prayer-times.sync", false, false},
+		{"git+https://github.com/user/dotfiles", true, false},
+		{"git@github.com:user/dotfiles.git", true, false},
+		{"/srv/git/prayer.git", true, false},
+		{"git+--upload-pack=touch /tmp/pwned", false, true},
+		{"--upload-pack=touch /tmp/pwned;x.git", false, true},
+		{"s3://bucket/key", false, true},
+		{"ftp://example.com/x", false, true},
+		{"", false, true},
+	}
+	for _, tt := range tests {
+		r, err := NewRemote(tt.url)
+		if (err != nil) != tt.fail {
+			t.Errorf("NewRemote(%q) error = %v, want failure %v", tt.url, err, tt.fail)
+			continue
+		}
+		if _, isGit := r.(*gitRemote); err == nil && isGit != tt.git {
+			t.Errorf("NewRemote(%q) = %T, want git %v", tt.url, r, tt.git)
+		}
+	}
+}
+
+func TestWebDAVRemote(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		stored []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			if stored == nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(stored)
+		}
+	}))
+	defer srv.Close()
+
+	r, err := NewRemote(srv.URL + "/prayer-times.sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Pull(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Pull before Push = %v, want ErrNotFound", err)
+	}
+	if err := r.Push([]byte("sealed")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r.Pull(); err != nil || string(got) != "sealed" {
+		t.Errorf("Pull() = %q, %v", got, err)
+	}
+}
+
+func TestGitRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := filepath.Join(t.TempDir(), "sync.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+
+	r, err := NewRemote(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Pull(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Pull before Push = %v, want ErrNotFound", err)
+	}
+	for _, data := range []string{"first", "second"} {
+		if err := r.Push([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := r.Pull(); err != nil || string(got) != data {
+			t.Errorf("Pull() = %q, %v, want %q", got, err, data)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package configsync
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// magic starts every sealed file, so a wrong file is told apart from a
+// wrong passphrase.
+const magic = "prayer-times sync v1\n"
+
+const (
+	saltSize = 16
+	keySize  = 32 // AES-256
+)
+
+// iterations is the PBKDF2 work factor for new files. It is stored in each
+// file, so raising it doesn't break older ones. Tests lower it.
+var iterations uint32 = 600_000
+
+// ErrPassphrase is returned by Open when the passphrase is wrong or the data
+// was tampered with.
+var ErrPassphrase = errors.New("wrong passphrase or corrupted sync data")
+
+// Seal encrypts plaintext with a key derived from passphrase. The result
+// holds the salt and work factor needed to open it.
+func Seal(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	binary.Write(&buf, binary.BigEndian, iterations)
+	buf.Write(salt)
+	buf.Write(nonce)
+	header := buf.Bytes()
+	// The header is authenticated too, so the work factor can't be swapped.
+	return gcm.Seal(header, nonce, plaintext, header), nil
+}
+
+// Open decrypts data sealed by Seal.
+func Open(data []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return nil, errors.New("not a prayer-times sync file")
+	}
+	rest := data[len(magic):]
+	if len(rest) < 4+saltSize {
+		return nil, ErrPassphrase
+	}
+	iter := binary.BigEndian.Uint32(rest)
+	salt := rest[4 : 4+saltSize]
+	if iter == 0 || iter > 100*iterations {
+		return nil, fmt.Errorf("unsupported sync file work factor %d", iter)
+	}
+
+	gcm, err := newGCM(passphrase, salt, iter)
+	if err != nil {
+		return nil, err
+	}
+	headerLen := len(magic) + 4 + saltSize + gcm.NonceSize()
+	if len(data) < headerLen {
+		return nil, ErrPassphrase
+	}
+	nonce := data[headerLen-gcm.NonceSize() : headerLen]
+	plaintext, err := gcm.Open(nil, nonce, data[headerLen:], data[:headerLen])
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(passphrase string, salt []byte, iter uint32) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2([]byte(passphrase), salt, int(iter), keySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2 derives a key from password as in RFC 8018.
+func pbkdf2(password, salt []byte, iter, keyLen int, h func() hash.Hash) []byte {
+	prf := hmac.New(h, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	key := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		t := prf.Sum(nil)
+		copy(u, t)
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
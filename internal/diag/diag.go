@@ -0,0 +1,80 @@
+// Package diag routes warnings and status messages away from a command's
+// primary output.
+//
+// Commands write results to stdout and report everything else through a
+// Channel. Where those messages end up depends on how the binary is being
+// used: stderr for an interactive terminal, a timestamped log file for the
+// long-running server, or nowhere for a status bar that captures all output.
+package diag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Channel is a destination for warnings and informational messages.
+// A nil writer discards that kind of message. It is safe for concurrent use.
+type Channel struct {
+	mu         sync.Mutex
+	warn       io.Writer
+	info       io.Writer
+	timestamps bool
+	now        func() time.Time
+}
+
+// New returns a Channel writing warnings to warn and status messages to info.
+// Either may be nil to discard those messages.
+func New(warn, info io.Writer) *Channel {
+	return &Channel{warn: warn, info: info, now: time.Now}
+}
+
+// Stderr returns a Channel sending everything to stderr, for interactive use.
+func Stderr() *Channel {
+	return New(os.Stderr, os.Stderr)
+}
+
+// Discard returns a Channel that drops every message.
+func Discard() *Channel {
+	return New(nil, nil)
+}
+
+// Log returns a Channel sending everything to w with an RFC 3339 timestamp
+// on each line, for log files written by long-running processes.
+func Log(w io.Writer) *Channel {
+	c := New(w, w)
+	c.timestamps = true
+	return c
+}
+
+// SuppressWarnings stops warnings from being written. Status messages are kept.
+func (c *Channel) SuppressWarnings() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warn = nil
+}
+
+// Warnf reports a non-fatal problem, prefixed with "warning: ".
+func (c *Channel) Warnf(format string, a ...any) {
+	c.write(c.warn, "warning: "+format, a...)
+}
+
+// Infof reports a status message such as a listening address or a written file.
+func (c *Channel) Infof(format string, a ...any) {
+	c.write(c.info, format, a...)
+}
+
+func (c *Channel) write(w io.Writer, format string, a ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if w == nil {
+		return
+	}
+	msg := fmt.Sprintf(format, a...)
+	if c.timestamps {
+		msg = c.now().Format(time.RFC3339) + " " + msg
+	}
+	fmt.Fprintln(w, msg)
+}
@@ -0,0 +1,60 @@
+package diag
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestChannel(t *testing.T) {
+	var warn, info bytes.Buffer
+	c := New(&warn, &info)
+
+	c.Warnf("cache disabled: %v", "read-only")
+	c.Infof("Listening on %s", ":8080")
+
+	if got, want := warn.String(), "warning: cache disabled: read-only\n"; got != want {
+		t.Errorf("warn = %q, want %q", got, want)
+	}
+	if got, want := info.String(), "Listening on :8080\n"; got != want {
+		t.Errorf("info = %q, want %q", got, want)
+	}
+}
+
+func TestChannel_NilWriterDiscards(t *testing.T) {
+	var info bytes.Buffer
+	c := New(nil, &info)
+
+	c.Warnf("dropped")
+	c.Infof("kept")
+
+	if got := info.String(); got != "kept\n" {
+		t.Errorf("info = %q, want %q", got, "kept\n")
+	}
+	Discard().Warnf("no panic")
+}
+
+func TestChannel_SuppressWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	c := New(&buf, &buf)
+	c.SuppressWarnings()
+
+	c.Warnf("dropped")
+	c.Infof("kept")
+
+	if got := buf.String(); got != "kept\n" {
+		t.Errorf("output = %q, want %q", got, "kept\n")
+	}
+}
+
+func TestLog_Timestamps(t *testing.T) {
+	var buf bytes.Buffer
+	c := Log(&buf)
+	c.now = func() time.Time { return time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC) }
+
+	c.Warnf("retrying")
+
+	if got, want := buf.String(), "2026-02-28T12:00:00Z warning: retrying\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -2,12 +2,16 @@
 //
 // It respects the NO_COLOR environment variable (https://no-color.org/) and
 // detects whether stdout is a terminal. Colors are automatically disabled when
-// output is piped or redirected, or when NO_COLOR is set.
+// output is piped or redirected, or when NO_COLOR is set. The styles that carry
+// meaning, such as the accent of the next prayer, come from a Theme.
 package display
 
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // ANSI escape codes for styling.
@@ -77,9 +81,10 @@ func Bold(text string) string {
 	return wrap(bold, text)
 }
 
-// Dim returns text rendered in dim/faint.
+// Dim returns text rendered in the theme's dim style, faint by default.
+// Used for past and current prayers.
 func Dim(text string) string {
-	return wrap(dim, text)
+	return Styled(theme.Dim, text)
 }
 
 // Green returns text rendered in green.
@@ -102,13 +107,72 @@ func Gray(text string) string {
 	return wrap(fgGray, text)
 }
 
-// Accent returns text rendered in the accent color (cyan + bold).
-// Used for the "next prayer" highlight.
+// Accent returns text rendered in the theme's accent style, cyan and bold by
+// default. Used for the "next prayer" highlight.
 func Accent(text string) string {
-	if !enabled {
+	return Styled(theme.Accent, text)
+}
+
+// styleCodes are the names ParseStyle accepts, by their ANSI codes.
+var styleCodes = map[string]string{
+	"bold":    bold,
+	"dim":     dim,
+	"red":     "\033[31m",
+	"green":   green,
+	"yellow":  yellow,
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    cyan,
+	"gray":    fgGray,
+}
+
+// ParseStyle checks spec, a comma-separated list of style names such as
+// "red,bold", 256-color palette numbers such as "214" and truecolor values
+// such as "#ffaf00", and returns its ANSI codes.
+func ParseStyle(spec string) (string, error) {
+	var codes strings.Builder
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		code, ok := styleCodes[name]
+		if !ok {
+			code, ok = colorCode(name)
+		}
+		if !ok {
+			names := make([]string, 0, len(styleCodes))
+			for n := range styleCodes {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return "", fmt.Errorf("invalid style %q: must be a comma-separated list of %s, 0-255 or #rrggbb", spec, strings.Join(names, ", "))
+		}
+		codes.WriteString(code)
+	}
+	return codes.String(), nil
+}
+
+// colorCode returns the ANSI code for text in a color of the 256-color
+// palette, "0" to "255", or a truecolor "#rrggbb".
+func colorCode(name string) (string, bool) {
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 && n <= 255 {
+		return fmt.Sprintf("\033[38;5;%dm", n), true
+	}
+	if len(name) == 7 && name[0] == '#' {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return fmt.Sprintf("\033[38;2;%d;%d;%dm", v>>16, v>>8&0xff, v&0xff), true
+		}
+	}
+	return "", false
+}
+
+// Styled returns text rendered in code, as returned by ParseStyle.
+func Styled(code, text string) string {
+	if code == "" {
 		return text
 	}
-	return bold + cyan + text + reset
+	return wrap(code, text)
 }
 
 // Boldf formats and bolds a string.
@@ -142,3 +142,38 @@ func TestAllColors_Disabled_ReturnPlainText(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStyle(t *testing.T) {
+	code, err := ParseStyle("red, Bold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "\033[31m\033[1m" {
+		t.Errorf("ParseStyle(\"red, Bold\") = %q", code)
+	}
+	if _, err := ParseStyle("red,blink"); err == nil {
+		t.Error("ParseStyle(\"red,blink\") expected error")
+	}
+	for spec, want := range map[string]string{
+		"214":          "\033[38;5;214m",
+		"#FFaf00,bold": "\033[38;2;255;175;0m\033[1m",
+	} {
+		if got, err := ParseStyle(spec); err != nil || got != want {
+			t.Errorf("ParseStyle(%q) = %q, %v; want %q", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"256", "-1", "#ffaf0", "#gggggg"} {
+		if _, err := ParseStyle(spec); err == nil {
+			t.Errorf("ParseStyle(%q) expected error", spec)
+		}
+	}
+
+	SetEnabled(true)
+	defer SetEnabled(false)
+	if got := Styled(code, "Asr"); got != "\033[31m\033[1mAsr\033[0m" {
+		t.Errorf("Styled() = %q", got)
+	}
+	if got := Styled("", "Asr"); got != "Asr" {
+		t.Errorf("Styled(\"\") = %q, want the text unstyled", got)
+	}
+}
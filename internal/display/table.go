@@ -1,8 +1,8 @@
 package display
 
 import (
-	"fmt"
 	"strings"
+	"unicode"
 )
 
 // Table renders an aligned text table with optional color support.
@@ -40,12 +40,12 @@ func (t *Table) Render() string {
 	// Calculate column widths.
 	widths := make([]int, len(t.headers))
 	for i, h := range t.headers {
-		widths[i] = len(h)
+		widths[i] = Width(h)
 	}
 	for _, row := range t.rows {
 		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < len(widths) && Width(cell) > widths[i] {
+				widths[i] = Width(cell)
 			}
 		}
 	}
@@ -68,7 +68,11 @@ func (t *Table) Render() string {
 	for i, row := range t.rows {
 		line := formatRow(row, widths)
 		if i == t.highlightRow {
-			sb.WriteString("  " + Accent(line) + "\n")
+			// Resume the highlight after any colored cell's reset.
+			if enabled {
+				line = strings.ReplaceAll(line, reset, reset+theme.Highlight)
+			}
+			sb.WriteString("  " + Styled(theme.Highlight, line) + "\n")
 		} else {
 			sb.WriteString("  " + line + "\n")
 		}
@@ -85,7 +89,123 @@ func formatRow(cells []string, widths []int) string {
 		if i < len(cells) {
 			cell = cells[i]
 		}
-		parts[i] = fmt.Sprintf("%-*s", w, cell)
+		parts[i] = PadRight(cell, w)
 	}
 	return strings.Join(parts, "  ")
 }
+
+// Width returns the number of terminal columns s takes up: one for most
+// characters, two for East Asian wide characters and emoji, and none for the
+// ones drawn with no width of their own, such as Arabic vowel marks, combining
+// accents and the directional isolates around right-to-left text. An emoji
+// sequence joined with zero-width joiners counts as the one emoji it draws,
+// and a flag as its two regional indicator letters. ANSI escape codes, as in
+// cells colored with this package, take no columns either.
+func Width(s string) int {
+	n := 0
+	joined, escape := false, false
+	for i, r := range s {
+		switch {
+		case escape:
+			// Skip the "[" and the parameters, up to the final byte from @ to ~.
+			escape = r < 0x40 || r > 0x7e || s[i-1] == '\033'
+		case r == '\033' && strings.HasPrefix(s[i:], "\033["):
+			escape = true
+		case r == zeroWidthJoiner:
+			joined = true
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, zeroWidth):
+		case joined:
+			joined = false
+		case unicode.Is(wide, r):
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// zeroWidthJoiner joins emoji into one, e.g. a family from its members.
+const zeroWidthJoiner = '\u200d'
+
+// zeroWidth are the characters outside Mn, Me and Cf that take no column of
+// their own: Hangul medial vowels and final consonants, which join the
+// syllable before them, and emoji skin tone modifiers.
+var zeroWidth = &unicode.RangeTable{
+	R16: []unicode.Range16{{Lo: 0x1160, Hi: 0x11ff, Stride: 1}},
+	R32: []unicode.Range32{{Lo: 0x1f3fb, Hi: 0x1f3ff, Stride: 1}},
+}
+
+// wide are the East Asian wide and fullwidth characters and the emoji
+// terminals draw two columns wide.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1}, // Hangul initial consonants
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1}, // watch, hourglass
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 1},
+		{Lo: 0x23f0, Hi: 0x23f3, Stride: 3}, // alarm clock, hourglass with sand
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1}, // zodiac
+		{Lo: 0x267f, Hi: 0x267f, Stride: 1},
+		{Lo: 0x2693, Hi: 0x2693, Stride: 1},
+		{Lo: 0x26a1, Hi: 0x26a1, Stride: 1},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 1},
+		{Lo: 0x26ce, Hi: 0x26ce, Stride: 1},
+		{Lo: 0x26d4, Hi: 0x26d4, Stride: 1},
+		{Lo: 0x26ea, Hi: 0x26ea, Stride: 1},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 1},
+		{Lo: 0x26f5, Hi: 0x26f5, Stride: 1},
+		{Lo: 0x26fa, Hi: 0x26fa, Stride: 1},
+		{Lo: 0x26fd, Hi: 0x26fd, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274e, Stride: 2},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27b0, Hi: 0x27bf, Stride: 15},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 5},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1}, // CJK radicals and punctuation
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1}, // kana, CJK compatibility
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1}, // CJK extension A
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1}, // CJK unified ideographs
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1}, // Yi
+		{Lo: 0xa960, Hi: 0xa97f, Stride: 1},
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1}, // Hangul syllables
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1},
+		{Lo: 0xfe10, Hi: 0xfe19, Stride: 1},
+		{Lo: 0xfe30, Hi: 0xfe6f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1}, // fullwidth forms
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x16fe0, Hi: 0x16fe4, Stride: 1},
+		{Lo: 0x17000, Hi: 0x18aff, Stride: 1},
+		{Lo: 0x1b000, Hi: 0x1b2ff, Stride: 1},
+		{Lo: 0x1f004, Hi: 0x1f004, Stride: 1},
+		{Lo: 0x1f0cf, Hi: 0x1f0cf, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f200, Hi: 0x1f251, Stride: 1},
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1}, // pictographs and emoticons
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1}, // transport and map symbols
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x1fa70, Hi: 0x1faff, Stride: 1},
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1},
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1},
+	},
+}
+
+// PadRight pads s with spaces to width columns, as measured by Width.
+func PadRight(s string, width int) string {
+	if pad := width - Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
@@ -109,3 +109,81 @@ func TestFormatRow_MissingCells(t *testing.T) {
 		t.Errorf("formatRow = %q, want %q", got, want)
 	}
 }
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"Fajr", 4},
+		{"İkindi", 6},
+		{"العصر", 5},
+		{"صَلَاة", 4},            // vowel marks take no column
+		{"\u2068العصر\u2069", 5}, // nor do directional isolates
+		{"Re\u0301union", 7},     // nor combining accents
+		{"東京", 4},                // CJK takes two columns
+		{"서울", 4},
+		{"🕌 Mosque", 9},
+		{"👍🏽", 2},                          // skin tone modifier
+		{"👨\u200d👩\u200d👧", 2},             // one family emoji
+		{"\U0001F1F8\U0001F1E6 Riyadh", 9}, // flag
+		{"\033[2m05:30\033[0m", 5},         // ANSI codes take no column
+		{"\033[1;36mAsr\033[0m", 3},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTable_RTLAlignment(t *testing.T) {
+	SetEnabled(false)
+
+	tbl := NewTable([]string{"التاريخ", "\u2068الفجر\u2069"})
+	tbl.AddRow([]string{"الجمعة", "٠٥:٠٦"})
+	lines := strings.Split(strings.TrimRight(tbl.Render(), "\n"), "\n")
+	for _, line := range lines {
+		if got := Width(line); got != Width(lines[0]) {
+			t.Errorf("line %q is %d columns, want %d like the header", line, got, Width(lines[0]))
+		}
+	}
+}
+
+func TestTable_WideAlignment(t *testing.T) {
+	SetEnabled(false)
+
+	tbl := NewTable([]string{"City", "Fajr"})
+	tbl.AddRow([]string{"東京", "04:51"})
+	tbl.AddRow([]string{"Réunion", "04:30"})
+	tbl.AddRow([]string{"🕌 Mecca", "05:30"})
+	lines := strings.Split(strings.TrimRight(tbl.Render(), "\n"), "\n")
+	for _, line := range lines {
+		if got := Width(line); got != Width(lines[0]) {
+			t.Errorf("line %q is %d columns, want %d like the header", line, got, Width(lines[0]))
+		}
+		if i := strings.Index(line, "0"); i >= 0 && Width(line[:i]) != Width(lines[0][:strings.Index(lines[0], "Fajr")]) {
+			t.Errorf("line %q: times don't line up under Fajr", line)
+		}
+	}
+}
+
+func TestTable_ColoredCells(t *testing.T) {
+	SetEnabled(true)
+	defer SetEnabled(false)
+
+	tbl := NewTable([]string{"Date", "Fajr", "Dhuhr"})
+	tbl.AddRow([]string{"Mon 02 Mar", Dim("05:30"), "12:30"})
+	tbl.AddRow([]string{"Tue 03 Mar", "05:29", "12:30"})
+	tbl.SetHighlightRow(0)
+	lines := strings.Split(strings.TrimRight(tbl.Render(), "\n"), "\n")
+	for _, line := range lines {
+		if got := Width(line); got != Width(lines[0]) {
+			t.Errorf("line %q is %d columns, want %d like the header", line, got, Width(lines[0]))
+		}
+	}
+	// The highlight picks up again after the dimmed cell.
+	if !strings.Contains(lines[2], reset+bold+cyan+"  12:30") {
+		t.Errorf("highlighted row %q doesn't resume the accent after a colored cell", lines[2])
+	}
+}
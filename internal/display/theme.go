@@ -0,0 +1,63 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Theme is the styles, as ANSI codes from ParseStyle, of the text that
+// carries meaning: the accent of the next prayer, dimmed past and current
+// prayers, and the highlighted row of a table (today's, in a list).
+type Theme struct {
+	Accent    string
+	Dim       string
+	Highlight string
+}
+
+// DefaultTheme is the theme used unless SetTheme picks another.
+var DefaultTheme = Theme{Accent: bold + cyan, Dim: dim, Highlight: bold + cyan}
+
+// theme is the current theme.
+var theme = DefaultTheme
+
+// SetTheme sets the theme of all further output.
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// CurrentTheme returns the theme set by SetTheme.
+func CurrentTheme() Theme {
+	return theme
+}
+
+// ParseTheme parses a theme such as "accent=214+bold,dim=gray,highlight=#ffaf00":
+// comma-separated role=style pairs, where the roles are accent, dim and
+// highlight and a style is a "+"-separated list as ParseStyle takes. Roles
+// left out keep DefaultTheme's style; an empty spec is DefaultTheme.
+func ParseTheme(spec string) (Theme, error) {
+	t := DefaultTheme
+	if strings.TrimSpace(spec) == "" {
+		return t, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		role, style, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Theme{}, fmt.Errorf("invalid theme %q: want role=style pairs, e.g. accent=214+bold", spec)
+		}
+		code, err := ParseStyle(strings.ReplaceAll(style, "+", ","))
+		if err != nil {
+			return Theme{}, fmt.Errorf("invalid theme %q: %w", spec, err)
+		}
+		switch strings.ToLower(strings.TrimSpace(role)) {
+		case "accent":
+			t.Accent = code
+		case "dim":
+			t.Dim = code
+		case "highlight":
+			t.Highlight = code
+		default:
+			return Theme{}, fmt.Errorf("invalid theme %q: unknown role %q, must be accent, dim or highlight", spec, role)
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,47 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTheme(t *testing.T) {
+	got, err := ParseTheme("accent=214+bold, highlight=#00ff00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Theme{Accent: "\033[38;5;214m" + bold, Dim: dim, Highlight: "\033[38;2;0;255;0m"}
+	if got != want {
+		t.Errorf("ParseTheme() = %q, want %q", got, want)
+	}
+	if got, err := ParseTheme(""); err != nil || got != DefaultTheme {
+		t.Errorf("ParseTheme(\"\") = %q, %v; want DefaultTheme", got, err)
+	}
+	for _, bad := range []string{"accent", "accent=pink", "border=red"} {
+		if _, err := ParseTheme(bad); err == nil {
+			t.Errorf("ParseTheme(%q) expected error", bad)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	SetEnabled(true)
+	defer SetEnabled(false)
+	defer SetTheme(DefaultTheme)
+
+	SetTheme(Theme{Accent: green, Dim: fgGray, Highlight: yellow})
+	if got := Accent("Asr"); got != green+"Asr"+reset {
+		t.Errorf("Accent() = %q", got)
+	}
+	if got := Dim("Dhuhr"); got != fgGray+"Dhuhr"+reset {
+		t.Errorf("Dim() = %q", got)
+	}
+
+	tbl := NewTable([]string{"Prayer", "Time"})
+	tbl.AddRow([]string{"Asr", Green("15:45")})
+	tbl.SetHighlightRow(0)
+	out := tbl.Render()
+	if !strings.Contains(out, "  "+yellow+"Asr") || !strings.Contains(out, green+"15:45"+reset+yellow) {
+		t.Errorf("Render() = %q, want the row highlighted in yellow", out)
+	}
+}
@@ -1,9 +1,12 @@
 package geo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -16,50 +19,52 @@ type Location struct {
 	Timezone  string  `json:"timezone"`
 }
 
-// ipAPIResponse maps the response from ip-api.com.
-type ipAPIResponse struct {
-	Status   string  `json:"status"`
-	Message  string  `json:"message"`
-	Lat      float64 `json:"lat"`
-	Lon      float64 `json:"lon"`
-	City     string  `json:"city"`
-	Country  string  `json:"country"`
-	Timezone string  `json:"timezone"`
-}
-
-// geoAPIURL is the geolocation API endpoint. It is a variable (not a constant)
-// so that tests can override it with an httptest server URL.
-var geoAPIURL = "http://ip-api.com/json/?fields=status,message,lat,lon,city,country,timezone"
-
-// DetectLocation uses ip-api.com to determine the user's location from their
-// public IP address. This is a free service that requires no API key.
-func DetectLocation() (*Location, error) {
+// DetectLocation determines the user's location from their public IP
+// address, asking each provider in turn until one answers. With no providers
+// it uses DefaultProviders. The requests are abandoned when ctx is done.
+func DetectLocation(ctx context.Context, providers ...Provider) (*Location, error) {
+	if len(providers) == 0 {
+		providers = DefaultProviders()
+	}
 	client := &http.Client{Timeout: 5 * time.Second}
 
-	resp, err := client.Get(geoAPIURL)
+	var errs []error
+	for _, p := range providers {
+		loc, err := p.Detect(ctx, client)
+		if err == nil {
+			return loc, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// getJSON fetches endpoint and decodes its JSON body into v.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("geolocation request failed: %w", err)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("geolocation request failed: %w", err)
+		// The URL may carry an API key, so leave it out of the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("geolocation request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("geolocation API returned status %d", resp.StatusCode)
+		return fmt.Errorf("geolocation API returned status %d", resp.StatusCode)
 	}
 
-	var result ipAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode geolocation response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode geolocation response: %w", err)
 	}
-
-	if result.Status != "success" {
-		return nil, fmt.Errorf("geolocation failed: %s", result.Message)
-	}
-
-	return &Location{
-		Latitude:  result.Lat,
-		Longitude: result.Lon,
-		City:      result.City,
-		Country:   result.Country,
-		Timezone:  result.Timezone,
-	}, nil
+	return nil
 }
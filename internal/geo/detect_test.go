@@ -1,6 +1,7 @@
 package geo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -28,7 +29,7 @@ func TestDetectLocation_Success(t *testing.T) {
 	geoAPIURL = server.URL
 	defer func() { geoAPIURL = origURL }()
 
-	loc, err := DetectLocation()
+	loc, err := DetectLocation(context.Background(), ipAPI{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -64,7 +65,7 @@ func TestDetectLocation_APIFailureStatus(t *testing.T) {
 	geoAPIURL = server.URL
 	defer func() { geoAPIURL = origURL }()
 
-	_, err := DetectLocation()
+	_, err := DetectLocation(context.Background(), ipAPI{})
 	if err == nil {
 		t.Fatal("expected error for failed status, got nil")
 	}
@@ -83,7 +84,7 @@ func TestDetectLocation_HTTPError(t *testing.T) {
 	geoAPIURL = server.URL
 	defer func() { geoAPIURL = origURL }()
 
-	_, err := DetectLocation()
+	_, err := DetectLocation(context.Background(), ipAPI{})
 	if err == nil {
 		t.Fatal("expected error for HTTP 500, got nil")
 	}
@@ -103,7 +104,7 @@ func TestDetectLocation_InvalidJSON(t *testing.T) {
 	geoAPIURL = server.URL
 	defer func() { geoAPIURL = origURL }()
 
-	_, err := DetectLocation()
+	_, err := DetectLocation(context.Background(), ipAPI{})
 	if err == nil {
 		t.Fatal("expected error for invalid JSON, got nil")
 	}
@@ -117,8 +118,155 @@ func TestDetectLocation_ConnectionRefused(t *testing.T) {
 	geoAPIURL = "http://127.0.0.1:1" // nothing listening
 	defer func() { geoAPIURL = origURL }()
 
-	_, err := DetectLocation()
+	_, err := DetectLocation(context.Background(), ipAPI{})
 	if err == nil {
 		t.Fatal("expected error for connection refused, got nil")
 	}
 }
+
+func TestDetectLocation_FallsBack(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer down.Close()
+	info := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"city":"London","country":"GB","loc":"51.5085,-0.1257","timezone":"Europe/London"}`))
+	}))
+	defer info.Close()
+
+	origAPI, origInfo := geoAPIURL, ipInfoURL
+	geoAPIURL, ipInfoURL = down.URL, info.URL
+	defer func() { geoAPIURL, ipInfoURL = origAPI, origInfo }()
+
+	loc, err := DetectLocation(context.Background(), ipAPI{}, ipInfo{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc.Latitude != 51.5085 || loc.Longitude != -0.1257 || loc.Country != "GB" {
+		t.Errorf("location = %+v, want ipinfo.io's answer", loc)
+	}
+
+	// When every provider fails, each one's error is reported.
+	ipInfoURL = down.URL
+	_, err = DetectLocation(context.Background(), ipAPI{}, ipInfo{})
+	if err == nil || !strings.Contains(err.Error(), "ip-api: ") || !strings.Contains(err.Error(), "ipinfo: ") {
+		t.Errorf("error = %v, want both providers' failures", err)
+	}
+}
+
+func TestIPAPICo(t *testing.T) {
+	tests := []struct {
+		body    string
+		wantErr string
+	}{
+		{`{"city":"Cairo","country_name":"Egypt","latitude":30.0444,"longitude":31.2357,"timezone":"Africa/Cairo"}`, ""},
+		{`{"error":true,"reason":"RateLimited"}`, "RateLimited"},
+		{`{"city":"Cairo"}`, "no coordinates"},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.body))
+		}))
+		orig := ipapiCoURL
+		ipapiCoURL = server.URL
+
+		loc, err := DetectLocation(context.Background(), ipapiCo{})
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.body, err)
+		case tt.wantErr == "" && (loc.Latitude != 30.0444 || loc.Country != "Egypt"):
+			t.Errorf("%s: location = %+v", tt.body, loc)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: error = %v, want %q", tt.body, err, tt.wantErr)
+		}
+
+		ipapiCoURL = orig
+		server.Close()
+	}
+}
+
+func TestFixedProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/empty" {
+			w.Write([]byte(`{"city":"Nowhere"}`))
+			return
+		}
+		w.Write([]byte(`{"lat":21.4225,"lon":39.8262,"city":"Mecca","country":"SA","timezone":"Asia/Riyadh"}`))
+	}))
+	defer server.Close()
+
+	loc, err := DetectLocation(context.Background(), NewFixedProvider(server.URL+"/here"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Latitude != 21.4225 || loc.City != "Mecca" || loc.Timezone != "Asia/Riyadh" {
+		t.Errorf("location = %+v", loc)
+	}
+	if _, err := DetectLocation(context.Background(), NewFixedProvider(server.URL+"/empty")); err == nil || !strings.Contains(err.Error(), "no coordinates") {
+		t.Errorf("error = %v, want no coordinates", err)
+	}
+}
+
+func TestParseProviders(t *testing.T) {
+	providers, err := ParseProviders(" ipinfo, IP-API ", "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(providers) != 2 || providers[0].Name() != "ipinfo" || providers[1].Name() != "ip-api" {
+		t.Errorf("ParseProviders = %v, want ipinfo then ip-api", providers)
+	}
+	if all, _ := ParseProviders("", "", true); len(all) != len(ProviderNames) {
+		t.Errorf("ParseProviders(\"\") = %d providers, want all %d", len(all), len(ProviderNames))
+	}
+	if _, err := ParseProviders("freegeoip", "", true); err == nil {
+		t.Error("ParseProviders(freegeoip) should error")
+	}
+}
+
+func TestParseProviders_Insecure(t *testing.T) {
+	// The default list drops plain HTTP; naming it is an error.
+	for _, p := range DefaultProviders() {
+		if !p.Secure() {
+			t.Errorf("DefaultProviders includes %s, which is plain HTTP", p.Name())
+		}
+	}
+	if _, err := ParseProviders("ipinfo,ip-api", "", false); err == nil || !strings.Contains(err.Error(), "--allow-insecure-geo") {
+		t.Errorf("ParseProviders(ipinfo,ip-api) error = %v, want one naming --allow-insecure-geo", err)
+	}
+
+	// With a key, ip-api.com is reached over HTTPS.
+	providers, err := ParseProviders("ip-api,ipinfo", "k3y", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !providers[0].Secure() || providers[1].(ipInfo).token != "" {
+		t.Errorf("providers = %+v, want the key given only to ip-api", providers)
+	}
+}
+
+func TestDetectLocation_APIKey(t *testing.T) {
+	var gotKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.URL.Query().Get("key")
+		w.Write([]byte(`{"status":"success","lat":21.4225,"lon":39.8262,"city":"Mecca"}`))
+	}))
+	defer server.Close()
+
+	origURL, origPro := geoAPIURL, geoAPIProURL
+	geoAPIURL, geoAPIProURL = "http://127.0.0.1:1", server.URL+"/json/?fields=status"
+	defer func() { geoAPIURL, geoAPIProURL = origURL, origPro }()
+
+	if _, err := DetectLocation(context.Background(), ipAPI{key: "k3y"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotKey != "k3y" {
+		t.Errorf("key = %q, want k3y sent to the HTTPS endpoint", gotKey)
+	}
+
+	// Failed requests don't echo the key.
+	geoAPIProURL = "http://127.0.0.1:1/json/"
+	_, err := DetectLocation(context.Background(), ipAPI{key: "k3y"})
+	if err == nil || strings.Contains(err.Error(), "k3y") {
+		t.Errorf("error = %v, want one without the key", err)
+	}
+}
@@ -0,0 +1,251 @@
+package geo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Provider is an IP geolocation service.
+type Provider interface {
+	// Name is the provider's name in the geo_provider config key.
+	Name() string
+	// Detect looks up the location of the caller's public IP address.
+	Detect(ctx context.Context, client *http.Client) (*Location, error)
+	// Secure reports whether the provider is reached over HTTPS.
+	Secure() bool
+}
+
+// ProviderNames lists the providers in their default order: the HTTPS
+// services first, then ip-api.com, which is plain HTTP without a key.
+var ProviderNames = []string{"ipinfo", "ipapi", "ip-api"}
+
+// The provider endpoints are variables (not constants) so that tests can
+// override them with httptest server URLs.
+var (
+	geoAPIURL    = "http://ip-api.com/json/?fields=status,message,lat,lon,city,country,timezone"
+	geoAPIProURL = "https://pro.ip-api.com/json/?fields=status,message,lat,lon,city,country,timezone"
+	ipInfoURL    = "https://ipinfo.io/json"
+	ipapiCoURL   = "https://ipapi.co/json/"
+)
+
+// DefaultProviders returns the HTTPS providers in the order of
+// ProviderNames, without an API key.
+func DefaultProviders() []Provider {
+	providers, _ := ParseProviders("", "", false)
+	return providers
+}
+
+// ParseProviders parses a comma-separated list of provider names, such as
+// "ipinfo,ip-api", into the providers to try in that order. An empty value
+// means all of them in the default order. apiKey, if set, is given to the
+// first provider. Plain HTTP providers are left out of the default list
+// unless allowInsecure is set, and naming one is an error.
+func ParseProviders(value, apiKey string, allowInsecure bool) ([]Provider, error) {
+	names, explicit := ProviderNames, strings.TrimSpace(value) != ""
+	if explicit {
+		names = strings.Split(value, ",")
+	}
+
+	var providers []Provider
+	for i, name := range names {
+		key := ""
+		if i == 0 {
+			key = apiKey
+		}
+		var p Provider
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "ip-api":
+			p = ipAPI{key: key}
+		case "ipinfo":
+			p = ipInfo{token: key}
+		case "ipapi":
+			p = ipapiCo{key: key}
+		default:
+			return nil, fmt.Errorf("unknown geo provider %q; valid providers: %s", strings.TrimSpace(name), strings.Join(ProviderNames, ", "))
+		}
+		if !p.Secure() && !allowInsecure {
+			if explicit {
+				return nil, fmt.Errorf("geo provider %s uses plain HTTP without an API key; set geo_api_key or pass --allow-insecure-geo", p.Name())
+			}
+			continue
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// withQuery returns rawURL with the query parameter name=value added.
+func withQuery(rawURL, name, value string) string {
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + name + "=" + url.QueryEscape(value)
+}
+
+// ipAPI looks locations up with ip-api.com. It is free without a key, but
+// only over plain HTTP and limited to 45 requests a minute; with a key it
+// uses the HTTPS endpoint of the paid service.
+type ipAPI struct {
+	key string
+}
+
+// ipAPIResponse maps the response from ip-api.com.
+type ipAPIResponse struct {
+	Status   string  `json:"status"`
+	Message  string  `json:"message"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	City     string  `json:"city"`
+	Country  string  `json:"country"`
+	Timezone string  `json:"timezone"`
+}
+
+func (ipAPI) Name() string { return "ip-api" }
+
+func (p ipAPI) Secure() bool { return p.key != "" }
+
+func (p ipAPI) Detect(ctx context.Context, client *http.Client) (*Location, error) {
+	endpoint := geoAPIURL
+	if p.key != "" {
+		endpoint = withQuery(geoAPIProURL, "key", p.key)
+	}
+	var result ipAPIResponse
+	if err := getJSON(ctx, client, endpoint, &result); err != nil {
+		return nil, err
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("geolocation failed: %s", result.Message)
+	}
+	return &Location{
+		Latitude:  result.Lat,
+		Longitude: result.Lon,
+		City:      result.City,
+		Country:   result.Country,
+		Timezone:  result.Timezone,
+	}, nil
+}
+
+// ipInfo looks locations up with ipinfo.io, over HTTPS. A token lifts the
+// free tier's limits.
+type ipInfo struct {
+	token string
+}
+
+// ipInfoResponse maps the response from ipinfo.io. Loc is "lat,lon" and
+// Country a two-letter code.
+type ipInfoResponse struct {
+	City     string `json:"city"`
+	Country  string `json:"country"`
+	Loc      string `json:"loc"`
+	Timezone string `json:"timezone"`
+}
+
+func (ipInfo) Name() string { return "ipinfo" }
+
+func (ipInfo) Secure() bool { return true }
+
+func (p ipInfo) Detect(ctx context.Context, client *http.Client) (*Location, error) {
+	endpoint := ipInfoURL
+	if p.token != "" {
+		endpoint = withQuery(endpoint, "token", p.token)
+	}
+	var result ipInfoResponse
+	if err := getJSON(ctx, client, endpoint, &result); err != nil {
+		return nil, err
+	}
+	latStr, lonStr, ok := strings.Cut(result.Loc, ",")
+	lat, latErr := strconv.ParseFloat(latStr, 64)
+	lon, lonErr := strconv.ParseFloat(lonStr, 64)
+	if !ok || latErr != nil || lonErr != nil {
+		return nil, fmt.Errorf("geolocation failed: no coordinates in response")
+	}
+	return &Location{
+		Latitude:  lat,
+		Longitude: lon,
+		City:      result.City,
+		Country:   result.Country,
+		Timezone:  result.Timezone,
+	}, nil
+}
+
+// ipapiCo looks locations up with ipapi.co, over HTTPS. A key lifts the
+// free tier's limits.
+type ipapiCo struct {
+	key string
+}
+
+// ipapiCoResponse maps the response from ipapi.co, which reports failures
+// in the body with Error set.
+type ipapiCoResponse struct {
+	Error     bool     `json:"error"`
+	Reason    string   `json:"reason"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	City      string   `json:"city"`
+	Country   string   `json:"country_name"`
+	Timezone  string   `json:"timezone"`
+}
+
+func (ipapiCo) Name() string { return "ipapi" }
+
+func (ipapiCo) Secure() bool { return true }
+
+func (p ipapiCo) Detect(ctx context.Context, client *http.Client) (*Location, error) {
+	endpoint := ipapiCoURL
+	if p.key != "" {
+		endpoint = withQuery(endpoint, "key", p.key)
+	}
+	var result ipapiCoResponse
+	if err := getJSON(ctx, client, endpoint, &result); err != nil {
+		return nil, err
+	}
+	if result.Error {
+		return nil, fmt.Errorf("geolocation failed: %s", result.Reason)
+	}
+	if result.Latitude == nil || result.Longitude == nil {
+		return nil, fmt.Errorf("geolocation failed: no coordinates in response")
+	}
+	return &Location{
+		Latitude:  *result.Latitude,
+		Longitude: *result.Longitude,
+		City:      result.City,
+		Country:   result.Country,
+		Timezone:  result.Timezone,
+	}, nil
+}
+
+// fixed answers with the location served at a URL, in Location's own JSON
+// form, e.g. {"lat": 21.42, "lon": 39.83, "city": "Mecca", "country": "SA",
+// "timezone": "Asia/Riyadh"}. It stands in for the real services in
+// integration tests.
+type fixed struct {
+	url string
+}
+
+// NewFixedProvider returns a provider that reads the location from url,
+// which serves it in Location's JSON form, instead of looking up the
+// caller's IP address.
+func NewFixedProvider(url string) Provider {
+	return fixed{url: url}
+}
+
+func (fixed) Name() string { return "fixed" }
+
+// Secure is true: the URL is the user's own choice, not a third party.
+func (fixed) Secure() bool { return true }
+
+func (p fixed) Detect(ctx context.Context, client *http.Client) (*Location, error) {
+	var loc Location
+	if err := getJSON(ctx, client, p.url, &loc); err != nil {
+		return nil, err
+	}
+	if loc.Latitude == 0 && loc.Longitude == 0 {
+		return nil, fmt.Errorf("geolocation failed: no coordinates in response")
+	}
+	return &loc, nil
+}
@@ -0,0 +1,98 @@
+// Package gpio drives an output pin through the Linux sysfs GPIO interface,
+// to blink an LED or sound a buzzer on a Raspberry Pi or similar board.
+package gpio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sysfsRoot is the sysfs GPIO directory. Tests replace it.
+var sysfsRoot = "/sys/class/gpio"
+
+// exportWait bounds how long Pulse waits for a newly exported pin to appear
+// and become writable, which udev may take a moment over.
+const exportWait = time.Second
+
+// DefaultPattern is the pattern when gpio_pattern isn't set: five half-second
+// blinks.
+const DefaultPattern = "5x500ms"
+
+// Pattern is a number of pulses, each on for Pulse and then off for as long.
+type Pattern struct {
+	Count int
+	Pulse time.Duration
+}
+
+// ParsePattern parses a pattern such as "5x500ms" (five blinks) or "1x3s"
+// (one long buzz). An empty value means DefaultPattern.
+func ParsePattern(value string) (Pattern, error) {
+	if value == "" {
+		value = DefaultPattern
+	}
+	count, pulse, ok := strings.Cut(strings.TrimSpace(value), "x")
+	n, err := strconv.Atoi(count)
+	d, derr := time.ParseDuration(pulse)
+	if !ok || err != nil || derr != nil || n < 1 || n > 100 || d < 10*time.Millisecond || d > time.Minute {
+		return Pattern{}, fmt.Errorf("invalid gpio_pattern %q: must be COUNTxDURATION, such as 5x500ms or 1x3s", value)
+	}
+	return Pattern{Count: n, Pulse: d}, nil
+}
+
+// ParsePin parses a GPIO pin number as the kernel numbers it.
+func ParsePin(value string) (int, error) {
+	pin, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || pin < 0 {
+		return 0, fmt.Errorf("invalid gpio_pin %q: must be a GPIO number such as 17", value)
+	}
+	return pin, nil
+}
+
+// Pulse exports pin as an output if it isn't already and plays p on it,
+// leaving it off. It returns once the pattern has finished.
+func Pulse(pin int, p Pattern) error {
+	dir := filepath.Join(sysfsRoot, "gpio"+strconv.Itoa(pin))
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if err := write(filepath.Join(sysfsRoot, "export"), strconv.Itoa(pin)); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	for deadline := time.Now().Add(exportWait); ; time.Sleep(50 * time.Millisecond) {
+		if err = write(filepath.Join(dir, "direction"), "out"); err == nil || time.Now().After(deadline) {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	value := filepath.Join(dir, "value")
+	for i := 0; i < p.Count; i++ {
+		if err := write(value, "1"); err != nil {
+			return err
+		}
+		time.Sleep(p.Pulse)
+		if err := write(value, "0"); err != nil {
+			return err
+		}
+		if i < p.Count-1 {
+			time.Sleep(p.Pulse)
+		}
+	}
+	return nil
+}
+
+// write writes s to the sysfs file at path.
+func write(path, s string) error {
+	if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+		return fmt.Errorf("gpio: %w", err)
+	}
+	return nil
+}
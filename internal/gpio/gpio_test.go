@@ -0,0 +1,68 @@
+package gpio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Pattern
+	}{
+		{"", Pattern{Count: 5, Pulse: 500 * time.Millisecond}},
+		{"1x3s", Pattern{Count: 1, Pulse: 3 * time.Second}},
+		{" 3x200ms ", Pattern{Count: 3, Pulse: 200 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePattern(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePattern(%q) = %+v, %v; want %+v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"5", "x500ms", "0x1s", "5x", "5x1ms", "5x2m", "fivex1s"} {
+		if _, err := ParsePattern(value); err == nil {
+			t.Errorf("ParsePattern(%q) should error", value)
+		}
+	}
+}
+
+func TestParsePin(t *testing.T) {
+	if pin, err := ParsePin("17"); err != nil || pin != 17 {
+		t.Errorf("ParsePin(17) = %d, %v", pin, err)
+	}
+	for _, value := range []string{"", "-1", "GPIO17"} {
+		if _, err := ParsePin(value); err == nil {
+			t.Errorf("ParsePin(%q) should error", value)
+		}
+	}
+}
+
+func TestPulse(t *testing.T) {
+	root := t.TempDir()
+	orig := sysfsRoot
+	sysfsRoot = root
+	defer func() { sysfsRoot = orig }()
+
+	// The kernel creates the pin's directory when it is exported.
+	dir := filepath.Join(root, "gpio17")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Pulse(17, Pattern{Count: 2, Pulse: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("Pulse() error: %v", err)
+	}
+	for file, want := range map[string]string{"direction": "out", "value": "0"} {
+		got, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", file, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "export")); err == nil {
+		t.Error("Pulse() exported a pin that was already exported")
+	}
+}
@@ -0,0 +1,228 @@
+// Package hijri converts dates between the Gregorian and Hijri calendars
+// without the API. From 1423 to 1500 AH it follows Umm al-Qura, the calendar
+// the API uses, working out from the Sun and Moon where each month starts;
+// before and after, it uses the tabular (arithmetical) Islamic calendar:
+// months alternate between 30 and 29 days, and 11 years in each 30-year
+// cycle add a 30th day to Dhul-Hijjah. An adjustment of whole days matches
+// local moon sighting.
+package hijri
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxAdjustment bounds the adjustment, in days, accepted by FromGregorian and
+// ToGregorian.
+const MaxAdjustment = 2
+
+// Date is a day of the Hijri calendar.
+type Date struct {
+	Year  int
+	Month int // 1 (Muharram) to 12 (Dhul-Hijjah)
+	Day   int
+}
+
+// epoch is the day number (see dayNumber) of 1 Muharram 1 AH, Friday 16 July
+// 622 in the Julian calendar.
+var epoch = dayNumber(622, time.July, 19)
+
+// cycleDays is the length of a 30-year cycle.
+const cycleDays = 30*354 + 11
+
+// Leap reports whether year has 355 days rather than 354 in the tabular
+// calendar.
+func Leap(year int) bool {
+	return (14+11*year)%30 < 11
+}
+
+// tabularDays returns the number of days in month of year in the tabular
+// calendar: 30 in odd months, 29 in even ones except Dhul-Hijjah in a leap
+// year.
+func tabularDays(year, month int) int {
+	if month%2 == 1 || (month == 12 && Leap(year)) {
+		return 30
+	}
+	return 29
+}
+
+// tabularStart returns the day number of the first day of month of year in
+// the tabular calendar.
+func tabularStart(year, month int) int {
+	days := cycleDays * ((year - 1) / 30)
+	for y := year - (year-1)%30; y < year; y++ {
+		days += 354
+		if Leap(y) {
+			days++
+		}
+	}
+	for m := 1; m < month; m++ {
+		days += tabularDays(year, m)
+	}
+	return epoch + days
+}
+
+// tabularDate returns the tabular date of the day number day.
+func tabularDate(day int) Date {
+	days := day - epoch
+	year := 30*(days/cycleDays) + 1
+	days %= cycleDays
+	for {
+		n := 354
+		if Leap(year) {
+			n = 355
+		}
+		if days < n {
+			break
+		}
+		days -= n
+		year++
+	}
+	month := 1
+	for days >= tabularDays(year, month) {
+		days -= tabularDays(year, month)
+		month++
+	}
+	return Date{Year: year, Month: month, Day: days + 1}
+}
+
+// monthStart returns the day number of the first day of the nth month after
+// 1 Muharram 1 AH: Umm al-Qura's from Muharram 1423 to Muharram 1501, the
+// tabular calendar's outside them.
+func monthStart(n int) int {
+	first := (firstUmmAlQura - 1) * 12
+	if starts := ummAlQuraStarts(); n >= first && n < first+len(starts) {
+		return starts[n-first]
+	}
+	return tabularStart(n/12+1, n%12+1)
+}
+
+// DaysInMonth returns the number of days in month of year, 29 or 30.
+func DaysInMonth(year, month int) int {
+	n := (year-1)*12 + month - 1
+	return monthStart(n+1) - monthStart(n)
+}
+
+// Valid reports whether d is a date of the calendar, on or after 1 Muharram 1.
+func (d Date) Valid() bool {
+	return d.Year >= 1 && d.Month >= 1 && d.Month <= 12 && d.Day >= 1 && d.Day <= DaysInMonth(d.Year, d.Month)
+}
+
+// FromGregorian returns the Hijri date of t's calendar day, moved by adjust
+// days: 1 gives the day after the calendar's date, -1 the day before.
+func FromGregorian(t time.Time, adjust int) Date {
+	y, m, d := t.AddDate(0, 0, adjust).Date()
+	day := dayNumber(y, m, d)
+
+	// The tabular date is within a day or two; step to the month holding day.
+	h := tabularDate(day)
+	n := (h.Year-1)*12 + h.Month - 1
+	for n > 0 && monthStart(n) > day {
+		n--
+	}
+	for monthStart(n+1) <= day {
+		n++
+	}
+	return Date{Year: n/12 + 1, Month: n%12 + 1, Day: day - monthStart(n) + 1}
+}
+
+// ToGregorian returns the Gregorian day, at midnight UTC, of d in a calendar
+// moved by adjust days, the inverse of FromGregorian.
+func ToGregorian(d Date, adjust int) (time.Time, error) {
+	if !d.Valid() {
+		return time.Time{}, fmt.Errorf("invalid Hijri date %s", d)
+	}
+	days := monthStart((d.Year-1)*12+d.Month-1) + d.Day - 1 - epoch
+	return time.Date(622, time.July, 19+days-adjust, 0, 0, 0, 0, time.UTC), nil
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	g, err := ToGregorian(d, 0)
+	if err != nil {
+		return d
+	}
+	return FromGregorian(g, n)
+}
+
+// String returns d as YYYY-MM-DD.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// Format returns d as "DD MonthName YYYY AH", like the API's dates.
+func (d Date) Format() string {
+	return fmt.Sprintf("%d %s %d AH", d.Day, MonthName(d.Month), d.Year)
+}
+
+// monthNames are the months in English and Arabic, spelled as the API
+// spells them.
+var monthNames = [12][2]string{
+	{"Muḥarram", "مُحَرَّم"},
+	{"Ṣafar", "صَفَر"},
+	{"Rabīʿ al-awwal", "رَبيع الأوَّل"},
+	{"Rabīʿ al-thānī", "رَبيع الثاني"},
+	{"Jumādá al-ūlá", "جُمادى الأولى"},
+	{"Jumādá al-ākhirah", "جُمادى الآخرة"},
+	{"Rajab", "رَجَب"},
+	{"Shaʿbān", "شَعْبان"},
+	{"Ramaḍān", "رَمَضان"},
+	{"Shawwāl", "شَوّال"},
+	{"Dhū al-Qaʿdah", "ذوالقعدة"},
+	{"Dhū al-Ḥijjah", "ذوالحجة"},
+}
+
+// MonthName returns the English name of month, 1 to 12, or "" outside it.
+func MonthName(month int) string {
+	if month < 1 || month > 12 {
+		return ""
+	}
+	return monthNames[month-1][0]
+}
+
+// MonthNameAr returns the Arabic name of month, 1 to 12, or "" outside it.
+func MonthNameAr(month int) string {
+	if month < 1 || month > 12 {
+		return ""
+	}
+	return monthNames[month-1][1]
+}
+
+// holidays are the holidays fixed to a day of the year, keyed by month and day.
+var holidays = map[[2]int]string{
+	{1, 1}:   "Islamic New Year",
+	{1, 10}:  "Ashura",
+	{3, 12}:  "Mawlid al-Nabi",
+	{7, 27}:  "Lailat-ul-Miraj",
+	{8, 15}:  "Lailat-ul-Bara'at",
+	{9, 1}:   "1st Day of Ramadan",
+	{9, 27}:  "Lailat-ul-Qadr",
+	{10, 1}:  "Eid-ul-Fitr",
+	{12, 9}:  "Arafa",
+	{12, 10}: "Eid-ul-Adha",
+}
+
+// Holidays returns the holidays on d, if any.
+func Holidays(d Date) []string {
+	if name, ok := holidays[[2]int{d.Month, d.Day}]; ok {
+		return []string{name}
+	}
+	return nil
+}
+
+// dayNumber counts the days from 1 March of year 0 in the proleptic
+// Gregorian calendar to y-m-d, without time.Duration's 292-year limit.
+func dayNumber(y int, m time.Month, d int) int {
+	if m <= time.February {
+		y--
+	}
+	era := y / 400
+	if y < 0 {
+		era = (y - 399) / 400
+	}
+	yoe := y - era*400
+	mp := (int(m) + 9) % 12
+	doy := (153*mp+2)/5 + d - 1
+	doe := yoe*365 + yoe/4 - yoe/100 + doy
+	return era*146097 + doe
+}
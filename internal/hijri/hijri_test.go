@@ -0,0 +1,142 @@
+package hijri
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromGregorian(t *testing.T) {
+	tests := []struct {
+		date string
+		want Date
+	}{
+		{"2026-02-18", Date{1447, 9, 1}},  // 1 Ramadan
+		{"2026-03-20", Date{1447, 10, 1}}, // Eid al-Fitr
+		{"2025-03-01", Date{1446, 9, 1}},
+		{"2000-01-01", Date{1420, 9, 24}}, // tabular, before Umm al-Qura
+	}
+	for _, tt := range tests {
+		g, _ := time.Parse("2006-01-02", tt.date)
+		if got := FromGregorian(g, 0); got != tt.want {
+			t.Errorf("FromGregorian(%s) = %s, want %s", tt.date, got, tt.want)
+		}
+	}
+
+	g := time.Date(2026, 2, 18, 23, 30, 0, 0, time.FixedZone("+03", 3*3600))
+	if got := FromGregorian(g, 1); got != (Date{1447, 9, 2}) {
+		t.Errorf("FromGregorian(18 Feb, +1) = %s, want 1447-09-02", got)
+	}
+	if got := FromGregorian(g, -1); got != (Date{1447, 8, 29}) {
+		t.Errorf("FromGregorian(18 Feb, -1) = %s, want 1447-08-29", got)
+	}
+}
+
+func TestToGregorian_RoundTrip(t *testing.T) {
+	start := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200*365; i += 7 {
+		g := start.AddDate(0, 0, i)
+		for _, adjust := range []int{-1, 0, 2} {
+			h := FromGregorian(g, adjust)
+			back, err := ToGregorian(h, adjust)
+			if err != nil {
+				t.Fatalf("ToGregorian(%s) error: %v", h, err)
+			}
+			if !back.Equal(g) {
+				t.Fatalf("ToGregorian(FromGregorian(%s, %d)) = %s", g.Format("2006-01-02"), adjust, back.Format("2006-01-02"))
+			}
+		}
+	}
+}
+
+func TestToGregorian_Invalid(t *testing.T) {
+	for _, d := range []Date{{1447, 8, 30}, {1447, 13, 1}, {0, 1, 1}, {1447, 1, 0}} {
+		if _, err := ToGregorian(d, 0); err == nil {
+			t.Errorf("ToGregorian(%s) should error", d)
+		}
+	}
+}
+
+func TestDaysInMonth(t *testing.T) {
+	if DaysInMonth(1447, 9) != 30 || DaysInMonth(1447, 8) != 29 {
+		t.Error("Ramadan 1447 should have 30 days and Sha'ban 29")
+	}
+	for y := firstUmmAlQura - 1; y <= lastUmmAlQura+1; y++ {
+		for m := 1; m <= 12; m++ {
+			if n := DaysInMonth(y, m); n != 29 && n != 30 {
+				t.Errorf("DaysInMonth(%d, %d) = %d", y, m, n)
+			}
+		}
+	}
+}
+
+func TestTabularDays(t *testing.T) {
+	if tabularDays(1447, 9) != 30 || tabularDays(1447, 8) != 29 {
+		t.Error("odd months should have 30 days and even months 29")
+	}
+	leap, common := 0, 0
+	for y := 1441; y <= 1470; y++ {
+		if Leap(y) {
+			leap++
+			if tabularDays(y, 12) != 30 {
+				t.Errorf("tabularDays(%d, 12) = 29 in a leap year", y)
+			}
+		} else {
+			common++
+		}
+	}
+	if leap != 11 || common != 19 {
+		t.Errorf("got %d leap years in a 30-year cycle, want 11", leap)
+	}
+}
+
+// TestUmmAlQura checks month starts against the published Umm al-Qura
+// calendar, including months where the tabular calendar is a day off.
+func TestUmmAlQura(t *testing.T) {
+	tests := []struct {
+		date string
+		want Date
+	}{
+		{"2002-03-15", Date{1423, 1, 1}},
+		{"2005-10-04", Date{1426, 9, 1}},
+		{"2010-08-11", Date{1431, 9, 1}},
+		{"2015-06-18", Date{1436, 9, 1}},
+		{"2019-05-06", Date{1440, 9, 1}},
+		{"2023-03-23", Date{1444, 9, 1}},
+		{"2023-04-21", Date{1444, 10, 1}},
+		{"2024-03-11", Date{1445, 9, 1}},
+		{"2024-04-10", Date{1445, 10, 1}},
+		{"2024-06-07", Date{1445, 12, 1}},
+		{"2024-07-07", Date{1446, 1, 1}},
+		{"2025-03-30", Date{1446, 10, 1}},
+		{"2025-05-28", Date{1446, 12, 1}},
+		{"2025-06-26", Date{1447, 1, 1}},
+	}
+	for _, tt := range tests {
+		g, _ := time.Parse("2006-01-02", tt.date)
+		if got := FromGregorian(g, 0); got != tt.want {
+			t.Errorf("FromGregorian(%s) = %s, want %s", tt.date, got, tt.want)
+		}
+		if back, err := ToGregorian(tt.want, 0); err != nil || !back.Equal(g) {
+			t.Errorf("ToGregorian(%s) = %s, %v, want %s", tt.want, back.Format("2006-01-02"), err, tt.date)
+		}
+	}
+	if !UmmAlQura(1447) || UmmAlQura(1422) || UmmAlQura(1501) {
+		t.Error("UmmAlQura should cover 1423 to 1500")
+	}
+}
+
+func TestFormatAndHolidays(t *testing.T) {
+	d := Date{1447, 10, 1}
+	if got := d.Format(); got != "1 Shawwāl 1447 AH" {
+		t.Errorf("Format() = %q, want %q", got, "1 Shawwāl 1447 AH")
+	}
+	if got := Holidays(d); len(got) != 1 || got[0] != "Eid-ul-Fitr" {
+		t.Errorf("Holidays(1 Shawwal) = %q, want Eid-ul-Fitr", got)
+	}
+	if got := Holidays(Date{1447, 10, 2}); got != nil {
+		t.Errorf("Holidays(2 Shawwal) = %q, want none", got)
+	}
+	if MonthName(13) != "" || MonthNameAr(9) != "رَمَضان" {
+		t.Error("MonthName/MonthNameAr out of step with the months")
+	}
+}
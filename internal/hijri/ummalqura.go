@@ -0,0 +1,280 @@
+package hijri
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Umm al-Qura, Saudi Arabia's calendar and the one the API follows, starts a
+// month on the day after the 29th when, seen from Mecca, the conjunction of
+// the new moon comes before sunset and the moon sets after the sun; otherwise
+// the month has 30 days. The rule has been in force since 1423 AH, and the
+// calendar is published up to 1500 AH; outside those years the tabular
+// calendar stands in.
+const (
+	firstUmmAlQura = 1423
+	lastUmmAlQura  = 1500
+)
+
+// UmmAlQura reports whether year's dates follow Umm al-Qura rather than the
+// tabular calendar.
+func UmmAlQura(year int) bool {
+	return year >= firstUmmAlQura && year <= lastUmmAlQura
+}
+
+// Mecca's coordinates, in degrees, and its offset from UTC, in days.
+const (
+	meccaLat    = 21.4225
+	meccaLon    = 39.8262
+	meccaOffset = 3.0 / 24
+)
+
+// deltaT is the difference between Terrestrial Time, in which the positions
+// of the Sun and Moon are computed, and UT, in days. It grew from 64 to 69
+// seconds over 2002-2025; the drift to 2077 is well under the margin of the
+// rule.
+const deltaT = 69.0 / 86400
+
+// shawwal1420 counts the months from 1 Muharram 1 AH to Shawwal 1420, which
+// began after lunation 0, the new moon of 6 January 2000.
+const shawwal1420 = 1419*12 + 9
+
+// jdnOffset converts a day number (see dayNumber) to a Julian day number.
+var jdnOffset = 2451545 - dayNumber(2000, time.January, 1)
+
+// ummAlQuraStarts holds the day numbers of the first days of the months
+// from Muharram 1423 to Muharram 1501, the end of the last month, computed
+// on first use.
+var ummAlQuraStarts = sync.OnceValue(func() []int {
+	starts := make([]int, (lastUmmAlQura-firstUmmAlQura+1)*12+1)
+	for i := range starts {
+		starts[i] = ummAlQuraStart((firstUmmAlQura-1)*12 + i)
+	}
+	return starts
+})
+
+// ummAlQuraStart returns the day number of the first day of the nth month
+// after 1 Muharram 1 AH by the Umm al-Qura rule.
+func ummAlQuraStart(n int) int {
+	conjunction := newMoon(float64(n-shawwal1420)) - deltaT
+	day := int(math.Floor(conjunction + 0.5 + meccaOffset))
+	set := sunset(float64(day) - meccaOffset)
+	start := day + 2
+	if conjunction < set && moonAboveHorizon(set) {
+		start = day + 1
+	}
+	return start - jdnOffset
+}
+
+// newMoon returns the Julian Ephemeris Day of the kth new moon after that of
+// 6 January 2000, to within a minute (Meeus, Astronomical Algorithms, ch. 49).
+func newMoon(k float64) float64 {
+	t := k / 1236.85
+	jde := 2451550.09766 + 29.530588861*k + t*t*(0.00015437+t*(-0.000000150+t*0.00000000073))
+	e := 1 - t*(0.002516+t*0.0000074)
+	m := 2.5534 + 29.10535670*k - t*t*(0.0000014+t*0.00000011)
+	mp := 201.5643 + 385.81693528*k + t*t*(0.0107582+t*(0.00001238-t*0.000000058))
+	f := 160.7108 + 390.67050284*k - t*t*(0.0016118+t*(0.00000227-t*0.000000011))
+	om := 124.7746 - 1.56375588*k + t*t*(0.0020672+t*0.00000215)
+
+	return jde -
+		0.40720*sin(mp) +
+		0.17241*e*sin(m) +
+		0.01608*sin(2*mp) +
+		0.01039*sin(2*f) +
+		0.00739*e*sin(mp-m) -
+		0.00514*e*sin(mp+m) +
+		0.00208*e*e*sin(2*m) -
+		0.00111*sin(mp-2*f) -
+		0.00057*sin(mp+2*f) +
+		0.00056*e*sin(2*mp+m) -
+		0.00042*sin(3*mp) +
+		0.00042*e*sin(m+2*f) +
+		0.00038*e*sin(m-2*f) -
+		0.00024*e*sin(2*mp-m) -
+		0.00017*sin(om) -
+		0.00007*sin(mp+2*m) +
+		0.00004*sin(2*mp-2*f) +
+		0.00004*sin(3*m) +
+		0.00003*sin(mp+m-2*f) +
+		0.00003*sin(2*mp+2*f) -
+		0.00003*sin(mp+m+2*f) +
+		0.00003*sin(mp-m+2*f) -
+		0.00002*sin(mp-m-2*f) -
+		0.00002*sin(3*mp+m) +
+		0.00002*sin(4*mp)
+}
+
+// sunset returns the Julian Day, in UT, of sunset in Mecca on the day whose
+// noon in UTC is noon.
+func sunset(noon float64) float64 {
+	jd := noon + 0.25
+	for range 3 {
+		ra, dec := sunPosition(jd)
+		h0 := acos((sin(-0.8333) - sin(meccaLat)*sin(dec)) / (cos(meccaLat) * cos(dec)))
+		h := math.Remainder(siderealTime(jd)+meccaLon-ra, 360)
+		jd += (h0 - h) / 360.985647
+	}
+	return jd
+}
+
+// moonAboveHorizon reports whether the Moon has yet to set in Mecca at the
+// Julian Day jd, in UT.
+func moonAboveHorizon(jd float64) bool {
+	ra, dec, parallax := moonPosition(jd + deltaT)
+	h := siderealTime(jd) + meccaLon - ra
+	alt := asin(sin(meccaLat)*sin(dec) + cos(meccaLat)*cos(dec)*cos(h))
+	// The Moon's centre sets at this altitude, allowing for its parallax,
+	// semidiameter and refraction (Meeus, ch. 15).
+	return alt > 0.7275*parallax-0.5667
+}
+
+// julianCenturies returns the time from J2000.0 to jd in Julian centuries.
+func julianCenturies(jd float64) float64 {
+	return (jd - 2451545) / 36525
+}
+
+// siderealTime returns the mean sidereal time at Greenwich, in degrees, at
+// the Julian Day jd, in UT (Meeus, ch. 12).
+func siderealTime(jd float64) float64 {
+	t := julianCenturies(jd)
+	return 280.46061837 + 360.98564736629*(jd-2451545) + t*t*(0.000387933-t/38710000)
+}
+
+// obliquity returns the obliquity of the ecliptic, in degrees, t Julian
+// centuries from J2000.0.
+func obliquity(t float64) float64 {
+	return 23.439291 - 0.0130042*t
+}
+
+// sunPosition returns the Sun's apparent right ascension and declination, in
+// degrees, at the Julian Day jd, to within 0.01° (Meeus, ch. 25).
+func sunPosition(jd float64) (ra, dec float64) {
+	t := julianCenturies(jd)
+	l0 := 280.46646 + t*(36000.76983+t*0.0003032)
+	m := 357.52911 + t*(35999.05029-t*0.0001537)
+	c := (1.914602-t*(0.004817+t*0.000014))*sin(m) +
+		(0.019993-t*0.000101)*sin(2*m) +
+		0.000289*sin(3*m)
+	om := 125.04 - 1934.136*t
+	lambda := l0 + c - 0.00569 - 0.00478*sin(om)
+	eps := obliquity(t) + 0.00256*cos(om)
+	return atan2(cos(eps)*sin(lambda), cos(lambda)), asin(sin(eps) * sin(lambda))
+}
+
+// moonTerm is a periodic term of the Moon's longitude and distance, or of its
+// latitude: the multiples of the arguments D, M, M' and F and the amplitude
+// in millionths of a degree or thousandths of a kilometre.
+type moonTerm struct {
+	d, m, mp, f float64
+	l, r        float64
+}
+
+// moonLR are the largest terms of the Moon's longitude and distance, to
+// 0.002° (Meeus, table 47.A).
+var moonLR = []moonTerm{
+	{0, 0, 1, 0, 6288774, -20905355},
+	{2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968},
+	{0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888},
+	{0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158},
+	{2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733},
+	{2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620},
+	{1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755},
+	{2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0},
+	{0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782},
+	{0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636},
+	{2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824},
+	{1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675},
+	{2, -1, 1, 0, 4036, -12831},
+	{2, 0, 2, 0, 3994, -10445},
+	{4, 0, 0, 0, 3861, -11650},
+	{2, 0, -3, 0, 3665, 14403},
+	{0, 1, -2, 0, -2689, -7003},
+	{2, 0, -1, 2, -2602, 0},
+	{2, -1, -2, 0, 2390, 10056},
+	{1, 0, 1, 0, -2348, 6322},
+	{2, -2, 0, 0, 2236, -9884},
+	{0, 1, 2, 0, -2120, 5751},
+	{0, 2, 0, 0, -2069, 0},
+}
+
+// moonB are the largest terms of the Moon's latitude (Meeus, table 47.B),
+// with the amplitude in l.
+var moonB = []moonTerm{
+	{0, 0, 0, 1, 5128122, 0},
+	{0, 0, 1, 1, 280602, 0},
+	{0, 0, 1, -1, 277693, 0},
+	{2, 0, 0, -1, 173237, 0},
+	{2, 0, -1, 1, 55413, 0},
+	{2, 0, -1, -1, 46271, 0},
+	{2, 0, 0, 1, 32573, 0},
+	{0, 0, 2, 1, 17198, 0},
+	{2, 0, 1, -1, 9266, 0},
+	{0, 0, 2, -1, 8822, 0},
+	{2, -1, 0, -1, 8216, 0},
+	{2, 0, -2, -1, 4324, 0},
+	{2, 0, 1, 1, 4200, 0},
+	{2, 1, 0, -1, -3359, 0},
+	{2, -1, -1, 1, 2463, 0},
+}
+
+// moonPosition returns the Moon's geocentric right ascension, declination
+// and horizontal parallax, in degrees, at the Julian Ephemeris Day jde
+// (Meeus, ch. 47).
+func moonPosition(jde float64) (ra, dec, parallax float64) {
+	t := julianCenturies(jde)
+	lp := 218.3164477 + 481267.88123421*t - 0.0015786*t*t
+	d := 297.8501921 + 445267.1114034*t - 0.0018819*t*t
+	m := 357.5291092 + 35999.0502909*t - 0.0001536*t*t
+	mp := 134.9633964 + 477198.8675055*t + 0.0087414*t*t
+	f := 93.2720950 + 483202.0175233*t - 0.0036539*t*t
+	e := 1 - t*(0.002516+t*0.0000074)
+	a1 := 119.75 + 131.849*t
+	a2 := 53.09 + 479264.290*t
+	a3 := 313.45 + 481266.484*t
+
+	// Terms in M shrink with the eccentricity of the Earth's orbit.
+	ecc := func(term moonTerm) float64 {
+		return math.Pow(e, math.Abs(term.m))
+	}
+	sl := 3958*sin(a1) + 1962*sin(lp-f) + 318*sin(a2)
+	sr := 0.0
+	for _, term := range moonLR {
+		arg := term.d*d + term.m*m + term.mp*mp + term.f*f
+		sl += term.l * ecc(term) * sin(arg)
+		sr += term.r * ecc(term) * cos(arg)
+	}
+	sb := -2235*sin(lp) + 382*sin(a3) + 175*sin(a1-f) + 175*sin(a1+f) + 127*sin(lp-mp) - 115*sin(lp+mp)
+	for _, term := range moonB {
+		sb += term.l * ecc(term) * sin(term.d*d+term.m*m+term.mp*mp+term.f*f)
+	}
+
+	lambda := lp + sl/1e6
+	beta := sb / 1e6
+	distance := 385000.56 + sr/1000
+	eps := obliquity(t)
+	ra = atan2(sin(lambda)*cos(eps)-math.Tan(beta*math.Pi/180)*sin(eps), cos(lambda))
+	dec = asin(sin(beta)*cos(eps) + cos(beta)*sin(eps)*sin(lambda))
+	return ra, dec, asin(6378.14 / distance)
+}
+
+// Trigonometry in degrees.
+
+func sin(deg float64) float64 { return math.Sin(deg * math.Pi / 180) }
+func cos(deg float64) float64 { return math.Cos(deg * math.Pi / 180) }
+func asin(x float64) float64  { return math.Asin(x) * 180 / math.Pi }
+func acos(x float64) float64  { return math.Acos(x) * 180 / math.Pi }
+
+func atan2(y, x float64) float64 { return math.Atan2(y, x) * 180 / math.Pi }
@@ -0,0 +1,168 @@
+// Package i18n translates the text prayer-times shows and sends: prayer
+// names, weekday names, labels and fixed sentences, clock times and
+// durations, with native digits and right-to-left isolation where a language
+// needs them. The translations themselves are in strings.go.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Default is the language used when no other can be found.
+const Default = "en"
+
+// Auto picks the language from the environment's locale or the location's
+// country; see Detect.
+const Auto = "auto"
+
+// Langs are the supported languages.
+var Langs = []string{"en", "ar", "tr", "ur", "id", "fr"}
+
+// Parse checks value, Auto or a language code from Langs, and returns it in
+// lower case; an empty value means Auto.
+func Parse(value string) (string, error) {
+	lang := strings.ToLower(strings.TrimSpace(value))
+	if lang == "" || lang == Auto {
+		return Auto, nil
+	}
+	if slices.Contains(Langs, lang) {
+		return lang, nil
+	}
+	return "", fmt.Errorf("invalid lang %q: must be auto or one of %s", value, strings.Join(Langs, ", "))
+}
+
+// Resolve returns the language to use for value, as accepted by Parse, in a
+// location in country: value itself, or when it is Auto or unset, Detect's
+// pick. An invalid value resolves to Default.
+func Resolve(value, country string) string {
+	lang, err := Parse(value)
+	if err != nil {
+		return Default
+	}
+	if lang == Auto {
+		return Detect(country)
+	}
+	return lang
+}
+
+// Detect picks a language from the locale, the first of $LC_ALL,
+// $LC_MESSAGES and $LANG to be set (e.g. "ar_SA.UTF-8" is Arabic), when it
+// is one of Langs. With no locale, the C or POSIX locale, or a language
+// without a translation, it falls back to the language of country, an ISO
+// code or English name, and then to Default.
+func Detect(country string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(locale), ".")
+		lang, _, _ = strings.Cut(lang, "@")
+		lang, _, _ = strings.Cut(lang, "_")
+		if slices.Contains(Langs, lang) {
+			return lang
+		}
+		break
+	}
+	if lang, ok := countryLangs[strings.ToLower(strings.TrimSpace(country))]; ok {
+		return lang
+	}
+	return Default
+}
+
+// RTL reports whether lang is written right to left.
+func RTL(lang string) bool {
+	return lang == "ar" || lang == "ur"
+}
+
+// Isolate wraps s in Unicode directional isolates when lang is written right
+// to left, so embedded text such as a Latin place name keeps its own order
+// and doesn't reorder the sentence around it.
+func Isolate(lang, s string) string {
+	if !RTL(lang) || s == "" {
+		return s
+	}
+	return "\u2068" + s + "\u2069" // FIRST STRONG ISOLATE, POP DIRECTIONAL ISOLATE
+}
+
+// Digits replaces the ASCII digits in s with lang's own.
+func Digits(lang, s string) string {
+	native, ok := digits[lang]
+	if !ok {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return native[r-'0']
+		}
+		return r
+	}, s)
+}
+
+// PrayerName returns name, a prayer as the API names it (e.g. "Asr"), in
+// lang; names without a translation are returned as they are.
+func PrayerName(lang, name string) string {
+	if translated, ok := prayerNames[lang][name]; ok {
+		return translated
+	}
+	return name
+}
+
+// Weekday returns the name of wd in lang, e.g. "Friday" or "الجمعة".
+func Weekday(lang string, wd time.Weekday) string {
+	if names, ok := weekdays[lang]; ok {
+		return names[wd]
+	}
+	return wd.String()
+}
+
+// ShortWeekday returns the abbreviated name of wd in lang, e.g. "Fri", for
+// table columns; languages that don't abbreviate weekdays get the full name.
+func ShortWeekday(lang string, wd time.Weekday) string {
+	if names, ok := shortWeekdays[lang]; ok {
+		return names[wd]
+	}
+	if names, ok := weekdays[lang]; ok {
+		return names[wd]
+	}
+	return wd.String()[:3]
+}
+
+// Sprintf formats the sentence key in lang with args, falling back to
+// Default when lang has no translation of it.
+func Sprintf(lang, key string, args ...any) string {
+	forms := sentences[key]
+	format, ok := forms[lang]
+	if !ok {
+		format = forms[Default]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Clock formats t with layout, "15:04" or "3:04 PM", in lang.
+func Clock(lang string, t time.Time, layout string) string {
+	s := t.Format(layout)
+	if strings.Contains(layout, "PM") {
+		marker := "clock.am"
+		if t.Hour() >= 12 {
+			marker = "clock.pm"
+		}
+		s = strings.TrimSuffix(strings.TrimSuffix(s, "AM"), "PM") + Sprintf(lang, marker)
+	}
+	return Digits(lang, s)
+}
+
+// Duration formats d as "2h 15m" or "45m" in lang, rounding down to the
+// minute; a negative d is "0m".
+func Duration(lang string, d time.Duration) string {
+	d = max(d, 0)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h > 0 {
+		return Digits(lang, Sprintf(lang, "duration.hours", fmt.Sprint(h), fmt.Sprint(m)))
+	}
+	return Digits(lang, Sprintf(lang, "duration.minutes", fmt.Sprint(m)))
+}
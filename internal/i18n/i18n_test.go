@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for value, want := range map[string]string{"": "auto", "Auto": "auto", "en": "en", " AR ": "ar"} {
+		if got, err := Parse(value); err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := Parse("xx"); err == nil {
+		t.Error("Parse(xx) expected error")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		lcAll, lang, country, want string
+	}{
+		{"", "ar_SA.UTF-8", "", "ar"},
+		{"en_US.UTF-8", "ar_SA.UTF-8", "Saudi Arabia", "en"}, // $LC_ALL wins
+		{"", "C.UTF-8", "Saudi Arabia", "ar"},
+		{"", "", "EG", "ar"},
+		{"", "de_DE.UTF-8", "Germany", "en"},
+		{"", "", "", "en"},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.lang)
+		if got := Detect(tt.country); got != tt.want {
+			t.Errorf("Detect(%q) with LC_ALL=%q LANG=%q = %q, want %q", tt.country, tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+
+	t.Setenv("LC_ALL", "ar_EG.UTF-8")
+	if got := Resolve("en", "Egypt"); got != "en" {
+		t.Errorf("Resolve(en) = %q, want the explicit language", got)
+	}
+	if got := Resolve("auto", ""); got != "ar" {
+		t.Errorf("Resolve(auto) = %q, want the locale's language", got)
+	}
+}
+
+func TestClock(t *testing.T) {
+	at := time.Date(2026, 2, 10, 15, 45, 0, 0, time.UTC)
+	tests := []struct {
+		lang, layout, want string
+	}{
+		{"en", "15:04", "15:45"},
+		{"en", "3:04 PM", "3:45 PM"},
+		{"ar", "15:04", "١٥:٤٥"},
+		{"ar", "3:04 PM", "٣:٤٥ م"},
+	}
+	for _, tt := range tests {
+		if got := Clock(tt.lang, at, tt.layout); got != tt.want {
+			t.Errorf("Clock(%s, %q) = %q, want %q", tt.lang, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		lang string
+		d    time.Duration
+		want string
+	}{
+		{"en", 2*time.Hour + 15*time.Minute, "2h 15m"},
+		{"en", 45 * time.Minute, "45m"},
+		{"en", -time.Minute, "0m"},
+		{"ar", 2*time.Hour + 15*time.Minute, "٢ س ١٥ د"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.lang, tt.d); got != tt.want {
+			t.Errorf("Duration(%s, %v) = %q, want %q", tt.lang, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestIsolate(t *testing.T) {
+	if got := Isolate("en", "Mecca"); got != "Mecca" {
+		t.Errorf("Isolate(en) = %q, want it unchanged", got)
+	}
+	if got := Isolate("ar", "Mecca"); got != "\u2068Mecca\u2069" {
+		t.Errorf("Isolate(ar) = %q, want it wrapped in isolates", got)
+	}
+}
+
+func TestPrayerName(t *testing.T) {
+	if got := PrayerName("ar", "Maghrib"); got != "المغرب" {
+		t.Errorf("PrayerName(ar, Maghrib) = %q", got)
+	}
+	if got := PrayerName("en", "Maghrib"); got != "Maghrib" {
+		t.Errorf("PrayerName(en, Maghrib) = %q", got)
+	}
+}
+
+func TestWeekday(t *testing.T) {
+	tests := []struct {
+		lang        string
+		full, short string
+	}{
+		{"en", "Friday", "Fri"},
+		{"ar", "الجمعة", "الجمعة"},
+		{"tr", "Cuma", "Cum"},
+		{"fr", "vendredi", "ven"},
+	}
+	for _, tt := range tests {
+		if got := Weekday(tt.lang, time.Friday); got != tt.full {
+			t.Errorf("Weekday(%s) = %q, want %q", tt.lang, got, tt.full)
+		}
+		if got := ShortWeekday(tt.lang, time.Friday); got != tt.short {
+			t.Errorf("ShortWeekday(%s) = %q, want %q", tt.lang, got, tt.short)
+		}
+	}
+}
+
+// TestTranslationsComplete checks every language has every sentence and,
+// but for French, which keeps most Arabic names, every daily prayer.
+func TestTranslationsComplete(t *testing.T) {
+	for _, lang := range Langs {
+		for key, forms := range sentences {
+			if _, ok := forms[lang]; !ok && lang != Default && key != "clock.am" && key != "clock.pm" {
+				t.Errorf("%s has no %q", lang, key)
+			}
+		}
+		if lang == Default || lang == "fr" {
+			continue
+		}
+		for _, name := range []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"} {
+			if PrayerName(lang, name) == name {
+				t.Errorf("%s has no name for %s", lang, name)
+			}
+		}
+	}
+	if got := Digits("ur", "12:05"); got != "۱۲:۰۵" {
+		t.Errorf("Digits(ur) = %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+package i18n
+
+// countryLangs are the languages of countries, by lower-case ISO code and
+// English name, for the countries whose language isn't Default.
+var countryLangs = map[string]string{
+	"sa": "ar", "saudi arabia": "ar",
+	"ae": "ar", "united arab emirates": "ar",
+	"bh": "ar", "bahrain": "ar",
+	"dz": "ar", "algeria": "ar",
+	"eg": "ar", "egypt": "ar",
+	"iq": "ar", "iraq": "ar",
+	"jo": "ar", "jordan": "ar",
+	"kw": "ar", "kuwait": "ar",
+	"lb": "ar", "lebanon": "ar",
+	"ly": "ar", "libya": "ar",
+	"ma": "ar", "morocco": "ar",
+	"mr": "ar", "mauritania": "ar",
+	"om": "ar", "oman": "ar",
+	"ps": "ar", "palestine": "ar",
+	"qa": "ar", "qatar": "ar",
+	"sd": "ar", "sudan": "ar",
+	"sy": "ar", "syria": "ar",
+	"tn": "ar", "tunisia": "ar",
+	"ye": "ar", "yemen": "ar",
+	"tr": "tr", "turkey": "tr", "türkiye": "tr", "turkiye": "tr",
+	"pk": "ur", "pakistan": "ur",
+	"id": "id", "indonesia": "id",
+	"fr": "fr", "france": "fr",
+}
+
+// digits are each language's digits 0-9, for languages that don't use
+// ASCII ones.
+var digits = map[string][]rune{
+	"ar": []rune("٠١٢٣٤٥٦٧٨٩"),
+	"ur": []rune("۰۱۲۳۴۵۶۷۸۹"), // Extended Arabic-Indic
+}
+
+// prayerNames are the prayer names in each language but English, by the
+// API's names.
+var prayerNames = map[string]map[string]string{
+	"ar": {
+		"Fajr": "الفجر", "Sunrise": "الشروق", "Dhuhr": "الظهر", "Asr": "العصر",
+		"Sunset": "الغروب", "Maghrib": "المغرب", "Isha": "العشاء",
+		"Imsak": "الإمساك", "Midnight": "منتصف الليل",
+		"Firstthird": "الثلث الأول", "Lastthird": "الثلث الأخير",
+	},
+	"tr": {
+		"Fajr": "Sabah", "Sunrise": "Güneş", "Dhuhr": "Öğle", "Asr": "İkindi",
+		"Sunset": "Gün batımı", "Maghrib": "Akşam", "Isha": "Yatsı",
+		"Imsak": "İmsak", "Midnight": "Gece yarısı",
+		"Firstthird": "Gecenin ilk üçte biri", "Lastthird": "Gecenin son üçte biri",
+	},
+	"ur": {
+		"Fajr": "فجر", "Sunrise": "طلوعِ آفتاب", "Dhuhr": "ظہر", "Asr": "عصر",
+		"Sunset": "غروبِ آفتاب", "Maghrib": "مغرب", "Isha": "عشاء",
+		"Imsak": "امساک", "Midnight": "آدھی رات",
+		"Firstthird": "رات کا پہلا تہائی", "Lastthird": "رات کا آخری تہائی",
+	},
+	"id": {
+		"Fajr": "Subuh", "Sunrise": "Terbit", "Dhuhr": "Zuhur", "Asr": "Asar",
+		"Sunset": "Terbenam", "Maghrib": "Magrib", "Isha": "Isya",
+		"Imsak": "Imsak", "Midnight": "Tengah malam",
+		"Firstthird": "Sepertiga awal", "Lastthird": "Sepertiga akhir",
+	},
+	"fr": {
+		"Sunrise": "Lever du soleil", "Sunset": "Coucher du soleil", "Midnight": "Minuit",
+		"Firstthird": "Premier tiers", "Lastthird": "Dernier tiers",
+	},
+}
+
+// weekdays are the weekday names in each language but English, from Sunday.
+var weekdays = map[string][7]string{
+	"ar": {"الأحد", "الإثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"},
+	"tr": {"Pazar", "Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi"},
+	"ur": {"اتوار", "پیر", "منگل", "بدھ", "جمعرات", "جمعہ", "ہفتہ"},
+	"id": {"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+	"fr": {"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+}
+
+// shortWeekdays are the abbreviated weekday names, from Sunday, of the
+// languages in weekdays that abbreviate them.
+var shortWeekdays = map[string][7]string{
+	"tr": {"Paz", "Pzt", "Sal", "Çar", "Per", "Cum", "Cmt"},
+	"id": {"Min", "Sen", "Sel", "Rab", "Kam", "Jum", "Sab"},
+	"fr": {"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+}
+
+// sentences are the fixed sentences and labels, by key and then language.
+// Arguments are passed already in the language, so only word order differs.
+var sentences = map[string]map[string]string{
+	// prayer name, clock, location
+	"notify.prayer": {
+		"en": "It's time for %[1]s (%[2]s) in %[3]s",
+		"ar": "حان الآن وقت صلاة %[1]s (%[2]s) في %[3]s",
+		"tr": "%[3]s için %[1]s vakti (%[2]s)",
+		"ur": "%[3]s میں %[1]s کا وقت ہو گیا (%[2]s)",
+		"id": "Waktunya salat %[1]s (%[2]s) di %[3]s",
+		"fr": "C'est l'heure de %[1]s (%[2]s) à %[3]s",
+	},
+	// prayer name, minutes, clock, location
+	"notify.reminder": {
+		"en": "%[1]s in %[2]s minutes (%[3]s) in %[4]s",
+		"ar": "صلاة %[1]s بعد %[2]s دقيقة (%[3]s) في %[4]s",
+		"tr": "%[4]s için %[1]s vaktine %[2]s dakika (%[3]s)",
+		"ur": "%[4]s میں %[1]s %[2]s منٹ میں (%[3]s)",
+		"id": "%[1]s dalam %[2]s menit (%[3]s) di %[4]s",
+		"fr": "%[1]s dans %[2]s minutes (%[3]s) à %[4]s",
+	},
+	// hours and minutes; minutes alone
+	"duration.hours": {
+		"en": "%[1]sh %[2]sm", "ar": "%[1]s س %[2]s د", "tr": "%[1]s sa %[2]s dk",
+		"ur": "%[1]s گھنٹے %[2]s منٹ", "id": "%[1]s j %[2]s mnt", "fr": "%[1]s h %[2]s min",
+	},
+	"duration.minutes": {
+		"en": "%[1]sm", "ar": "%[1]s د", "tr": "%[1]s dk",
+		"ur": "%[1]s منٹ", "id": "%[1]s mnt", "fr": "%[1]s min",
+	},
+	// the 12-hour clock's markers
+	"clock.am": {"en": "AM", "ar": "ص", "tr": "ÖÖ"},
+	"clock.pm": {"en": "PM", "ar": "م", "tr": "ÖS"},
+
+	// the schedule's title
+	"ui.title": {
+		"en": "Prayer Times", "ar": "مواقيت الصلاة", "tr": "Namaz Vakitleri",
+		"ur": "اوقاتِ نماز", "id": "Jadwal Salat", "fr": "Horaires de prière",
+	},
+	// the date column
+	"ui.date": {
+		"en": "Date", "ar": "التاريخ", "tr": "Tarih",
+		"ur": "تاریخ", "id": "Tanggal", "fr": "Date",
+	},
+	// the marker on the next prayer: time remaining
+	"ui.next": {
+		"en": "<- next in %[1]s", "ar": "<- التالية بعد %[1]s", "tr": "<- sonraki, %[1]s sonra",
+		"ur": "<- اگلی، %[1]s میں", "id": "<- berikutnya dalam %[1]s", "fr": "<- prochaine dans %[1]s",
+	},
+}
@@ -0,0 +1,142 @@
+// Package ical encodes prayer times as an iCalendar (RFC 5545) document.
+//
+// Only the subset needed for prayer schedules is supported: VEVENTs with an
+// optional display VALARM. Times are written in UTC so the output imports
+// correctly into any calendar regardless of its timezone database.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	utcLayout   = "20060102T150405Z"
+	maxLineSize = 75 // octets, per RFC 5545 section 3.1
+)
+
+// Event is a single calendar entry.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	Duration    time.Duration
+	// Alarm, when positive, adds a display reminder this long before Start.
+	Alarm time.Duration
+}
+
+// Calendar is a named collection of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+	// Stamp is written as DTSTAMP on every event. Defaults to the current time.
+	Stamp time.Time
+}
+
+// Encode writes the calendar to w with CRLF line endings and folded long lines.
+func (c *Calendar) Encode(w io.Writer) error {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	var sb strings.Builder
+	line := func(name, value string) {
+		sb.WriteString(fold(name + ":" + value))
+		sb.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//prayer-times//prayer-times CLI//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(e.UID))
+		line("DTSTAMP", stamp.UTC().Format(utcLayout))
+		line("DTSTART", e.Start.UTC().Format(utcLayout))
+		if e.Duration > 0 {
+			line("DTEND", e.Start.Add(e.Duration).UTC().Format(utcLayout))
+		}
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		line("TRANSP", "TRANSPARENT")
+		if e.Alarm > 0 {
+			line("BEGIN", "VALARM")
+			line("ACTION", "DISPLAY")
+			line("DESCRIPTION", escape(e.Summary))
+			line("TRIGGER", "-"+duration(e.Alarm))
+			line("END", "VALARM")
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// escape escapes TEXT values per RFC 5545 section 3.3.11.
+func escape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// fold splits a content line into chunks of at most 75 octets, continuing each
+// with CRLF followed by a single space. Multi-byte runes are never split.
+func fold(s string) string {
+	if len(s) <= maxLineSize {
+		return s
+	}
+
+	var sb strings.Builder
+	lineLen := 0
+	for _, r := range s {
+		n := len(string(r))
+		if lineLen+n > maxLineSize {
+			sb.WriteString("\r\n ")
+			lineLen = 1
+		}
+		sb.WriteRune(r)
+		lineLen += n
+	}
+	return sb.String()
+}
+
+// duration formats d as an RFC 5545 DURATION value (e.g. PT10M, PT1H30M).
+func duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+
+	var sb strings.Builder
+	sb.WriteString("PT")
+	if h > 0 {
+		fmt.Fprintf(&sb, "%dH", h)
+	}
+	if m > 0 || h == 0 {
+		fmt.Fprintf(&sb, "%dM", m)
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleCalendar() *Calendar {
+	riyadh := time.FixedZone("AST", 3*60*60)
+	return &Calendar{
+		Name:  "Prayer Times — Riyadh",
+		Stamp: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Events: []Event{
+			{
+				UID:      "20260301-fajr@prayer-times",
+				Summary:  "Fajr",
+				Location: "Riyadh, Saudi Arabia",
+				Start:    time.Date(2026, 3, 1, 5, 2, 0, 0, riyadh),
+				Duration: 15 * time.Minute,
+				Alarm:    10 * time.Minute,
+			},
+		},
+	}
+}
+
+func TestEncode_Structure(t *testing.T) {
+	var sb strings.Builder
+	if err := sampleCalendar().Encode(&sb); err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	out := sb.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"VERSION:2.0\r\n",
+		"BEGIN:VEVENT\r\n",
+		"UID:20260301-fajr@prayer-times\r\n",
+		"DTSTAMP:20260301T000000Z\r\n",
+		"DTSTART:20260301T020200Z\r\n",
+		"DTEND:20260301T021700Z\r\n",
+		"SUMMARY:Fajr\r\n",
+		"LOCATION:Riyadh\\, Saudi Arabia\r\n",
+		"BEGIN:VALARM\r\n",
+		"TRIGGER:-PT10M\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestEncode_NoAlarm(t *testing.T) {
+	cal := sampleCalendar()
+	cal.Events[0].Alarm = 0
+
+	var sb strings.Builder
+	cal.Encode(&sb)
+	if strings.Contains(sb.String(), "VALARM") {
+		t.Error("event without Alarm should not contain a VALARM")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := escape("a,b;c\\d\ne")
+	want := `a\,b\;c\\d\ne`
+	if got != want {
+		t.Errorf("escape() = %q, want %q", got, want)
+	}
+}
+
+func TestFold(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("ف", 60) // 2-byte runes
+	folded := fold(long)
+
+	for _, line := range strings.Split(folded, "\r\n") {
+		if len(line) > maxLineSize {
+			t.Errorf("folded line is %d octets, want <= %d", len(line), maxLineSize)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Error("unfolding should restore the original line")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{10 * time.Minute, "PT10M"},
+		{time.Hour, "PT1H"},
+		{90 * time.Minute, "PT1H30M"},
+		{0, "PT0M"},
+	}
+	for _, tt := range tests {
+		if got := duration(tt.d); got != tt.want {
+			t.Errorf("duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package khatmah plans a complete reading of the Quran (a khatmah) over a
+// number of days, split into portions read after each daily prayer.
+//
+// Portions are measured in pages of the standard 604-page Madinah Mushaf.
+// The plan and reading progress are stored as a single JSON file under
+// ~/.local/share/prayer-times/ (respects $XDG_DATA_HOME).
+package khatmah
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TotalPages is the page count of the standard Madinah Mushaf.
+const TotalPages = 604
+
+const dateLayout = "2006-01-02"
+
+// DefaultPrayers are the prayers after which portions are read by default.
+var DefaultPrayers = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// Plan is a khatmah schedule together with its reading progress.
+type Plan struct {
+	Start     string   `json:"start"` // YYYY-MM-DD
+	Days      int      `json:"days"`
+	Prayers   []string `json:"prayers"`
+	PagesRead int      `json:"pages_read"`
+}
+
+// Portion is a contiguous range of pages (inclusive) to read after one prayer.
+type Portion struct {
+	Prayer string `json:"prayer"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+}
+
+// Pages returns the number of pages in the portion.
+func (p Portion) Pages() int {
+	return p.To - p.From + 1
+}
+
+// NewPlan creates a plan that finishes the Quran in days days starting on start.
+// If prayers is empty, DefaultPrayers is used.
+func NewPlan(start time.Time, days int, prayers []string) (*Plan, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("invalid number of days %d: must be at least 1", days)
+	}
+	if len(prayers) == 0 {
+		prayers = DefaultPrayers
+	}
+	return &Plan{
+		Start:   start.Format(dateLayout),
+		Days:    days,
+		Prayers: prayers,
+	}, nil
+}
+
+// Day returns the zero-based plan day for date, or -1 if date is outside the plan.
+func (p *Plan) Day(date time.Time) int {
+	start, err := time.Parse(dateLayout, p.Start)
+	if err != nil {
+		return -1
+	}
+	d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	day := int(d.Sub(start).Hours() / 24)
+	if day < 0 || day >= p.Days {
+		return -1
+	}
+	return day
+}
+
+// PortionsFor returns the portions scheduled on date, in prayer order.
+// Dates outside the plan have no portions.
+func (p *Plan) PortionsFor(date time.Time) []Portion {
+	day := p.Day(date)
+	if day < 0 || len(p.Prayers) == 0 {
+		return nil
+	}
+
+	n := p.Days * len(p.Prayers)
+	var portions []Portion
+	for i, name := range p.Prayers {
+		k := day*len(p.Prayers) + i
+		from := k*TotalPages/n + 1
+		to := (k + 1) * TotalPages / n
+		if from > to {
+			// More portions than pages: nothing to read after this prayer.
+			continue
+		}
+		portions = append(portions, Portion{Prayer: name, From: from, To: to})
+	}
+	return portions
+}
+
+// Expected returns how many pages should have been read by the end of date.
+func (p *Plan) Expected(date time.Time) int {
+	start, err := time.Parse(dateLayout, p.Start)
+	if err != nil {
+		return 0
+	}
+	d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(d.Sub(start).Hours()/24) + 1
+	switch {
+	case days <= 0:
+		return 0
+	case days >= p.Days:
+		return TotalPages
+	}
+	return days * TotalPages / p.Days
+}
+
+// NextPortion returns the earliest scheduled portion that has not been fully read.
+// ok is false once the khatmah is complete.
+func (p *Plan) NextPortion() (Portion, bool) {
+	if p.Complete() {
+		return Portion{}, false
+	}
+	start, err := time.Parse(dateLayout, p.Start)
+	if err != nil {
+		return Portion{}, false
+	}
+	for day := 0; day < p.Days; day++ {
+		for _, portion := range p.PortionsFor(start.AddDate(0, 0, day)) {
+			if portion.To > p.PagesRead {
+				return portion, true
+			}
+		}
+	}
+	return Portion{}, false
+}
+
+// MarkRead records that pages 1 through page have been read.
+func (p *Plan) MarkRead(page int) error {
+	if page < 0 || page > TotalPages {
+		return fmt.Errorf("invalid page %d: must be between 0 and %d", page, TotalPages)
+	}
+	p.PagesRead = page
+	return nil
+}
+
+// Complete reports whether every page has been read.
+func (p *Plan) Complete() bool {
+	return p.PagesRead >= TotalPages
+}
+
+// DefaultPath returns the default plan file location.
+// It respects $XDG_DATA_HOME if set, otherwise uses ~/.local/share/.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot determine home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "prayer-times", "khatmah.json"), nil
+}
+
+// Load reads the plan at path. A missing file yields a nil plan and no error.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read khatmah plan: %w", err)
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("corrupt khatmah plan %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Save writes the plan to path, creating parent directories as needed.
+func (p *Plan) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cannot create directory for khatmah plan: %w", err)
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal khatmah plan: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write khatmah plan: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package khatmah
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func samplePlan(t *testing.T) *Plan {
+	t.Helper()
+	p, err := NewPlan(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 30, nil)
+	if err != nil {
+		t.Fatalf("NewPlan() error: %v", err)
+	}
+	return p
+}
+
+func TestNewPlan_InvalidDays(t *testing.T) {
+	if _, err := NewPlan(time.Now(), 0, nil); err == nil {
+		t.Error("NewPlan(days=0) should return an error")
+	}
+}
+
+func TestPortionsFor_CoversEveryPageOnce(t *testing.T) {
+	p := samplePlan(t)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	next := 1
+	for day := 0; day < p.Days; day++ {
+		portions := p.PortionsFor(start.AddDate(0, 0, day))
+		if len(portions) != len(DefaultPrayers) {
+			t.Fatalf("day %d: got %d portions, want %d", day, len(portions), len(DefaultPrayers))
+		}
+		for _, portion := range portions {
+			if portion.From != next {
+				t.Fatalf("day %d %s: starts at page %d, want %d", day, portion.Prayer, portion.From, next)
+			}
+			next = portion.To + 1
+		}
+	}
+	if next != TotalPages+1 {
+		t.Errorf("plan ends at page %d, want %d", next-1, TotalPages)
+	}
+}
+
+func TestPortionsFor_OutsidePlan(t *testing.T) {
+	p := samplePlan(t)
+	if got := p.PortionsFor(time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)); got != nil {
+		t.Errorf("day before plan: got %v, want nil", got)
+	}
+	if got := p.PortionsFor(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)); got != nil {
+		t.Errorf("day after plan: got %v, want nil", got)
+	}
+}
+
+func TestPortionsFor_MorePortionsThanPages(t *testing.T) {
+	p, _ := NewPlan(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 200, nil)
+	total := 0
+	for day := 0; day < p.Days; day++ {
+		for _, portion := range p.PortionsFor(time.Date(2026, 3, 1+day, 0, 0, 0, 0, time.UTC)) {
+			total += portion.Pages()
+		}
+	}
+	if total != TotalPages {
+		t.Errorf("pages scheduled = %d, want %d", total, TotalPages)
+	}
+}
+
+func TestExpected(t *testing.T) {
+	p := samplePlan(t)
+	tests := []struct {
+		date time.Time
+		want int
+	}{
+		{time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 20},
+		{time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC), TotalPages},
+		{time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), TotalPages},
+	}
+	for _, tt := range tests {
+		if got := p.Expected(tt.date); got != tt.want {
+			t.Errorf("Expected(%s) = %d, want %d", tt.date.Format(dateLayout), got, tt.want)
+		}
+	}
+}
+
+func TestNextPortion_And_MarkRead(t *testing.T) {
+	p := samplePlan(t)
+
+	first, ok := p.NextPortion()
+	if !ok || first.From != 1 || first.Prayer != "Fajr" {
+		t.Fatalf("NextPortion() = %+v, %v; want Fajr from page 1", first, ok)
+	}
+
+	if err := p.MarkRead(first.To); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := p.NextPortion()
+	if second.From != first.To+1 || second.Prayer != "Dhuhr" {
+		t.Errorf("after first portion, NextPortion() = %+v", second)
+	}
+
+	if err := p.MarkRead(TotalPages + 1); err == nil {
+		t.Error("MarkRead beyond the last page should return an error")
+	}
+	p.MarkRead(TotalPages)
+	if _, ok := p.NextPortion(); ok || !p.Complete() {
+		t.Error("plan should be complete after reading every page")
+	}
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "khatmah.json")
+	p := samplePlan(t)
+	p.PagesRead = 42
+
+	if err := p.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.Start != p.Start || got.Days != p.Days || got.PagesRead != 42 || len(got.Prayers) != 5 {
+		t.Errorf("Load() = %+v, want %+v", got, p)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	p, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if p != nil || err != nil {
+		t.Errorf("Load(missing) = %v, %v; want nil, nil", p, err)
+	}
+}
+
+func TestDefaultPath_XDG(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg-data")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/tmp/xdg-data/prayer-times/khatmah.json" {
+		t.Errorf("DefaultPath() = %q", path)
+	}
+}
@@ -0,0 +1,83 @@
+// Package matrix sends text messages to a Matrix room through a homeserver's
+// client-server API, with an access token for an existing account.
+package matrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// txnCounter makes transaction IDs unique within the process; the
+// homeserver treats a repeated ID as a retry of the same message.
+var txnCounter atomic.Int64
+
+// ParseHomeserver checks that value is an http or https URL, such as
+// "https://matrix.org", and returns it without a trailing slash.
+func ParseHomeserver(value string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid matrix_homeserver %q: must be an http or https URL such as https://matrix.org", value)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// ParseRoom checks that value is a room ID such as "!abcdef:matrix.org".
+func ParseRoom(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "!") || !strings.Contains(value, ":") {
+		return "", fmt.Errorf("invalid matrix_room %q: must be a room ID such as !abcdef:matrix.org (see the room's settings)", value)
+	}
+	return value, nil
+}
+
+// Client sends messages as the account the token belongs to.
+type Client struct {
+	Homeserver string // base URL, e.g. "https://matrix.org"
+	Token      string // access token
+	HTTP       *http.Client
+}
+
+// Send posts text to room as a plain-text message.
+func (c *Client) Send(room, text string) error {
+	body, err := json.Marshal(map[string]string{"msgtype": "m.text", "body": text})
+	if err != nil {
+		return err
+	}
+	txn := strconv.FormatInt(time.Now().UnixNano(), 36) + "." + strconv.FormatInt(txnCounter.Add(1), 36)
+	endpoint := c.Homeserver + "/_matrix/client/v3/rooms/" + url.PathEscape(room) + "/send/m.room.message/" + txn
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Matrix errors carry a code and a message, e.g. M_FORBIDDEN.
+		var e struct {
+			Code    string `json:"errcode"`
+			Message string `json:"error"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&e) == nil && e.Code != "" {
+			return fmt.Errorf("matrix: %s: %s", e.Code, e.Message)
+		}
+		return fmt.Errorf("matrix: %s", resp.Status)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	return nil
+}
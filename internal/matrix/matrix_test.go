@@ -0,0 +1,66 @@
+package matrix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	var paths []string
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token"}`))
+			return
+		}
+		paths = append(paths, r.URL.EscapedPath())
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"event_id":"$abc"}`))
+	}))
+	defer server.Close()
+
+	c := &Client{Homeserver: server.URL, Token: "tok", HTTP: server.Client()}
+	for i := 0; i < 2; i++ {
+		if err := c.Send("!room:example.org", "It's time for Asr"); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+	if got["msgtype"] != "m.text" || got["body"] != "It's time for Asr" {
+		t.Errorf("body = %v, want an m.text message", got)
+	}
+	prefix := "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/"
+	if len(paths) != 2 || !strings.HasPrefix(paths[0], prefix) || paths[0] == paths[1] {
+		t.Errorf("paths = %v, want two distinct transactions under %s", paths, prefix)
+	}
+
+	c.Token = "bad"
+	if err := c.Send("!room:example.org", "hi"); err == nil || !strings.Contains(err.Error(), "M_UNKNOWN_TOKEN") {
+		t.Errorf("Send() with a bad token error = %v, want M_UNKNOWN_TOKEN", err)
+	}
+}
+
+func TestParseHomeserver(t *testing.T) {
+	if got, err := ParseHomeserver("https://matrix.org/"); err != nil || got != "https://matrix.org" {
+		t.Errorf("ParseHomeserver() = %q, %v", got, err)
+	}
+	for _, value := range []string{"", "matrix.org", "ftp://matrix.org"} {
+		if _, err := ParseHomeserver(value); err == nil {
+			t.Errorf("ParseHomeserver(%q) should error", value)
+		}
+	}
+}
+
+func TestParseRoom(t *testing.T) {
+	if _, err := ParseRoom("!abc:matrix.org"); err != nil {
+		t.Errorf("ParseRoom() error: %v", err)
+	}
+	for _, value := range []string{"", "#prayer:matrix.org", "!abc"} {
+		if _, err := ParseRoom(value); err == nil {
+			t.Errorf("ParseRoom(%q) should error", value)
+		}
+	}
+}
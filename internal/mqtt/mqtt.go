@@ -0,0 +1,194 @@
+// Package mqtt publishes messages to an MQTT 3.1.1 broker. It covers only
+// what prayer-times needs: connect, publish at QoS 0 (optionally retained),
+// disconnect.
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+// Packet types (MQTT 3.1.1 section 2.2.1), shifted into the fixed header.
+const (
+	packetConnect    = 1 << 4
+	packetConnAck    = 2 << 4
+	packetPublish    = 3 << 4
+	packetDisconnect = 14 << 4
+)
+
+const (
+	flagCleanSession = 0x02
+	flagPassword     = 0x40
+	flagUsername     = 0x80
+	flagRetain       = 0x01
+)
+
+// Message is one message to publish.
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// Options are the connection settings.
+type Options struct {
+	ClientID string
+	Username string
+	Password string
+	Timeout  time.Duration // for the whole exchange; default 10s
+}
+
+// ParseBroker checks a broker URL: mqtt:// or tcp:// (port 1883 by default),
+// or mqtts://, ssl:// or tls:// for TLS (port 8883).
+func ParseBroker(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid MQTT broker %q: want mqtt://host:port", raw)
+	}
+	switch u.Scheme {
+	case "mqtt", "tcp", "mqtts", "ssl", "tls":
+		return u, nil
+	default:
+		return nil, fmt.Errorf("invalid MQTT broker %q: scheme must be mqtt, tcp, mqtts, ssl or tls", raw)
+	}
+}
+
+// Publish connects to broker, publishes msgs and disconnects. Connecting
+// for each batch keeps idle hours between prayers free of keepalives.
+func Publish(broker string, opts Options, msgs ...Message) error {
+	u, err := ParseBroker(broker)
+	if err != nil {
+		return err
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	conn, err := dial(u, timeout)
+	if err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	w := bufio.NewWriter(conn)
+	w.Write(connectPacket(opts))
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	if err := readConnAck(conn); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	for _, m := range msgs {
+		w.Write(publishPacket(m))
+	}
+	w.Write([]byte{packetDisconnect, 0})
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	return nil
+}
+
+func dial(u *url.URL, timeout time.Duration) (net.Conn, error) {
+	host := u.Host
+	secure := u.Scheme == "mqtts" || u.Scheme == "ssl" || u.Scheme == "tls"
+	if u.Port() == "" {
+		port := "1883"
+		if secure {
+			port = "8883"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	d := &net.Dialer{Timeout: timeout}
+	if secure {
+		return tls.DialWithDialer(d, "tcp", host, &tls.Config{ServerName: u.Hostname()})
+	}
+	return d.Dial("tcp", host)
+}
+
+func connectPacket(opts Options) []byte {
+	flags := byte(flagCleanSession)
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4) // protocol level 3.1.1
+	if opts.Username != "" {
+		flags |= flagUsername
+		if opts.Password != "" {
+			flags |= flagPassword
+		}
+	}
+	body = append(body, flags, 0, 60) // keepalive: 60s
+	body = appendString(body, opts.ClientID)
+	if opts.Username != "" {
+		body = appendString(body, opts.Username)
+		if opts.Password != "" {
+			body = appendString(body, opts.Password)
+		}
+	}
+	return packet(packetConnect, body)
+}
+
+func publishPacket(m Message) []byte {
+	header := byte(packetPublish)
+	if m.Retain {
+		header |= flagRetain
+	}
+	body := appendString(nil, m.Topic)
+	return packet(header, append(body, m.Payload...))
+}
+
+// connAckErrors are the CONNACK return codes (section 3.2.2.3).
+var connAckErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+func readConnAck(r io.Reader) error {
+	var ack [4]byte
+	if _, err := io.ReadFull(r, ack[:]); err != nil {
+		return fmt.Errorf("no CONNACK from broker: %w", err)
+	}
+	if ack[0] != packetConnAck || ack[1] != 2 {
+		return errors.New("unexpected reply from broker")
+	}
+	if ack[3] != 0 {
+		if msg, ok := connAckErrors[ack[3]]; ok {
+			return fmt.Errorf("connection refused: %s", msg)
+		}
+		return fmt.Errorf("connection refused (code %d)", ack[3])
+	}
+	return nil
+}
+
+// packet prefixes body with the fixed header and remaining length.
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
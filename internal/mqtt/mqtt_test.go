@@ -0,0 +1,150 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// readPacket reads one packet: its fixed header byte and body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, mult := 0, 1
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n += int(b&0x7f) * mult
+		mult *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	return header, body, err
+}
+
+// fakeBroker accepts one connection, answers CONNACK with code and records
+// the packets it receives.
+func fakeBroker(t *testing.T, code byte) (string, <-chan [][]byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	got := make(chan [][]byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var packets [][]byte
+		for {
+			header, body, err := readPacket(r)
+			if err != nil {
+				break
+			}
+			packets = append(packets, append([]byte{header}, body...))
+			if header == packetConnect {
+				conn.Write([]byte{packetConnAck, 2, 0, code})
+			}
+			if header == packetDisconnect {
+				break
+			}
+		}
+		got <- packets
+	}()
+	return "mqtt://" + ln.Addr().String(), got
+}
+
+func TestPublish(t *testing.T) {
+	broker, got := fakeBroker(t, 0)
+
+	err := Publish(broker, Options{ClientID: "prayer-times", Username: "home", Password: "secret"},
+		Message{Topic: "prayer-times/next", Payload: []byte(`{"prayer":"Asr"}`), Retain: true},
+		Message{Topic: "prayer-times/prayer", Payload: []byte(strings.Repeat("x", 200))},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packets := <-got
+	if len(packets) != 4 {
+		t.Fatalf("broker got %d packets, want connect, 2 publishes and disconnect", len(packets))
+	}
+
+	connect := packets[0]
+	if connect[0] != packetConnect || !bytes.Contains(connect, []byte("MQTT\x04")) {
+		t.Errorf("first packet = %q, want CONNECT for MQTT 3.1.1", connect)
+	}
+	if flags := connect[8]; flags != flagCleanSession|flagUsername|flagPassword {
+		t.Errorf("connect flags = %#x", flags)
+	}
+	if !bytes.HasSuffix(connect, []byte("\x00\x0cprayer-times\x00\x04home\x00\x06secret")) {
+		t.Errorf("connect payload = %q", connect)
+	}
+
+	pub := packets[1]
+	if pub[0] != packetPublish|flagRetain || string(pub[1:]) != "\x00\x11prayer-times/next"+`{"prayer":"Asr"}` {
+		t.Errorf("retained publish = %q", pub)
+	}
+	// A 200-byte payload needs a two-byte remaining length.
+	if pub := packets[2]; pub[0] != packetPublish || len(pub) != 1+2+len("prayer-times/prayer")+200 {
+		t.Errorf("second publish = %d bytes, header %#x", len(pub), pub[0])
+	}
+	if packets[3][0] != packetDisconnect {
+		t.Errorf("last packet = %#x, want DISCONNECT", packets[3][0])
+	}
+}
+
+func TestPublish_Refused(t *testing.T) {
+	broker, _ := fakeBroker(t, 4)
+	err := Publish(broker, Options{ClientID: "prayer-times"}, Message{Topic: "t"})
+	if err == nil || !strings.Contains(err.Error(), "bad user name or password") {
+		t.Errorf("Publish() = %v, want a refused connection", err)
+	}
+}
+
+func TestParseBroker(t *testing.T) {
+	for _, ok := range []string{"mqtt://localhost", "tcp://10.0.0.2:1883", "mqtts://broker.example.com:8883"} {
+		if _, err := ParseBroker(ok); err != nil {
+			t.Errorf("ParseBroker(%q) error: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"localhost:1883", "http://broker", "mqtt://"} {
+		if _, err := ParseBroker(bad); err == nil {
+			t.Errorf("ParseBroker(%q) should error", bad)
+		}
+	}
+}
+
+func TestPacket_RemainingLength(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []byte
+	}{
+		{0, []byte{0}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{16383, []byte{0xff, 0x7f}},
+		{16384, []byte{0x80, 0x80, 0x01}},
+	}
+	for _, tt := range tests {
+		got := packet(packetPublish, make([]byte, tt.n))[1 : 1+len(tt.want)]
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("remaining length %d = % x, want % x", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package pdf writes simple PDF documents: pages of text, lines and shaded
+// rectangles, enough for a printable timetable.
+//
+// Text is set in the standard Helvetica fonts every PDF reader has, so
+// nothing is embedded and the output stays small. Those fonts cover Latin-1
+// and a little punctuation; other letters with diacritics, as in
+// transliterated Arabic, are written without them, and other scripts as "?"
+// (see CanPrint).
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page sizes in points (1/72 inch), portrait.
+const (
+	A4Width      = 595.28
+	A4Height     = 841.89
+	LetterWidth  = 612
+	LetterHeight = 792
+)
+
+// Document is a PDF document under construction.
+type Document struct {
+	Title  string
+	Width  float64
+	Height float64
+	pages  []*Page
+}
+
+// New returns an empty document with pages of the given size.
+func New(width, height float64) *Document {
+	return &Document{Width: width, Height: height}
+}
+
+// Page is one page of a Document. Coordinates are in points from the page's
+// bottom-left corner.
+type Page struct {
+	content bytes.Buffer
+}
+
+// AddPage adds a blank page to the end of d and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text writes s with its baseline starting at x, y, in Helvetica, or
+// Helvetica-Bold when bold is set.
+func (p *Page) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, num(size), num(x), num(y), escape(winAnsi(s)))
+}
+
+// TextCenter writes s centered on x.
+func (p *Page) TextCenter(x, y, size float64, bold bool, s string) {
+	p.Text(x-TextWidth(s, size, bold)/2, y, size, bold, s)
+}
+
+// Line draws a line from x1, y1 to x2, y2, width points wide, in gray
+// (0 black to 1 white).
+func (p *Page) Line(x1, y1, x2, y2, width, gray float64) {
+	fmt.Fprintf(&p.content, "%s G %s w %s %s m %s %s l S\n", num(gray), num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Rect fills the rectangle with its bottom-left corner at x, y in gray.
+func (p *Page) Rect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f 0 g\n", num(gray), num(x), num(y), num(w), num(h))
+}
+
+// TextWidth returns the width of s, in points, set at size.
+func TextWidth(s string, size float64, bold bool) float64 {
+	widths := &helvetica
+	if bold {
+		widths = &helveticaBold
+	}
+	total := 0
+	for _, b := range []byte(winAnsi(s)) {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Encode writes d to w as a PDF file.
+func (d *Document) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are the catalog, the page tree, the fonts and the info;
+	// each page is then followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>", strings.Join(kids, " "), len(d.pages), num(d.Width), num(d.Height)))
+	obj("<< /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >> " +
+		"/F2 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >> >>")
+	info := "<< /Producer (prayer-times)"
+	if d.Title != "" {
+		info += " /Title (" + escape(winAnsi(d.Title)) + ")"
+	}
+	obj(info + " >>")
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font 3 0 R >> /Contents %d 0 R >>", 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// num formats a coordinate or size without needless digits.
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// escape escapes the characters special in a PDF string.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
+
+// winAnsiExtra are the characters WinAnsiEncoding places in 0x80-0x9F.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// plainLetters spells the letters of transliterated Arabic, and Turkish,
+// that WinAnsiEncoding lacks without their diacritics.
+var plainLetters = map[rune]string{
+	'ā': "a", 'Ā': "A", 'ī': "i", 'Ī': "I", 'ū': "u", 'Ū': "U",
+	'ḥ': "h", 'Ḥ': "H", 'ḍ': "d", 'Ḍ': "D", 'ṣ': "s", 'Ṣ': "S",
+	'ṭ': "t", 'Ṭ': "T", 'ẓ': "z", 'Ẓ': "Z", 'ʿ': "'", 'ʾ': "'",
+	'ğ': "g", 'Ğ': "G", 'ş': "s", 'Ş': "S", 'ı': "i", 'İ': "I",
+}
+
+// winAnsi encodes s in WinAnsiEncoding, dropping diacritics it lacks and
+// replacing other characters with "?".
+func winAnsi(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if !winAnsiRune(&sb, r) {
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
+
+// CanPrint reports whether the standard fonts can print every character of
+// s, if only without its diacritics. Text in other scripts, such as Arabic,
+// would come out as "?".
+func CanPrint(s string) bool {
+	var sb strings.Builder
+	for _, r := range s {
+		if !winAnsiRune(&sb, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// winAnsiRune writes r to sb in WinAnsiEncoding, reporting false if it has
+// no encoding.
+func winAnsiRune(sb *strings.Builder, r rune) bool {
+	switch {
+	case r < utf8.RuneSelf:
+		sb.WriteRune(r)
+	case r >= 0xA0 && r <= 0xFF:
+		sb.WriteByte(byte(r))
+	case winAnsiExtra[r] != 0:
+		sb.WriteByte(winAnsiExtra[r])
+	case plainLetters[r] != "":
+		sb.WriteString(plainLetters[r])
+	case r == '⁦' || r == '⁧' || r == '⁨' || r == '⁩':
+		// Bidi isolates have no glyph.
+	default:
+		return false
+	}
+	return true
+}
+
+// helvetica and helveticaBold are the widths, in thousandths of the font
+// size, of the printable ASCII characters from the fonts' AFM metrics.
+var helvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBold = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,114 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	doc := New(A4Width, A4Height)
+	doc.Title = "Prayer Times (March)"
+	p := doc.AddPage()
+	p.Text(40, 800, 12, true, "Fajr")
+	p.Rect(40, 700, 100, 20, 0.9)
+	p.Line(40, 700, 140, 700, 0.5, 0)
+	doc.AddPage().Text(40, 800, 12, false, "Page two")
+
+	var buf bytes.Buffer
+	if err := doc.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"%PDF-1.4\n",
+		"/Count 2 /MediaBox [0 0 595.28 841.89]",
+		"/BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding",
+		`/Title (Prayer Times \(March\))`,
+		"BT /F2 12 Tf 40 800 Td (Fajr) Tj ET",
+		"0.9 g 40 700 100 20 re f 0 g",
+		"(Page two) Tj",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PDF missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "%%EOF\n") {
+		t.Errorf("PDF does not end with %%%%EOF: %q", out[len(out)-20:])
+	}
+
+	// Every xref entry must point at its object, and startxref at the table.
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(m[1])
+	if !strings.HasPrefix(out[xref:], "xref\n0 9\n") {
+		t.Fatalf("startxref %d points at %q", xref, out[xref:min(xref+10, len(out))])
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[xref:], -1)
+	if len(entries) != 8 {
+		t.Fatalf("xref has %d objects, want 8", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(e[1])
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !strings.HasPrefix(out[off:], want) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, out[off:off+len(want)], want)
+		}
+	}
+
+	// Stream lengths must match their content.
+	for _, m := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllStringSubmatch(out, -1) {
+		if n, _ := strconv.Atoi(m[1]); n != len(m[2]) {
+			t.Errorf("stream /Length %d, content is %d bytes", n, len(m[2]))
+		}
+	}
+}
+
+func TestWinAnsi(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Fajr 05:30", "Fajr 05:30"},
+		{"Ramaḍān", "Ramadan"},
+		{"Shaʿbān", "Sha'ban"},
+		{"Dhū al-Ḥijjah", "Dhu al-Hijjah"},
+		{"Café — 1447", "Caf\xe9 \x97 1447"},
+		{"مكة", "???"},
+	}
+	for _, tt := range tests {
+		if got := winAnsi(tt.in); got != tt.want {
+			t.Errorf("winAnsi(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCanPrint(t *testing.T) {
+	for _, s := range []string{"Masjid An-Nur", "Ramaḍān", "Café — 1447", ""} {
+		if !CanPrint(s) {
+			t.Errorf("CanPrint(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"مسجد النور", "Masjid مكة"} {
+		if CanPrint(s) {
+			t.Errorf("CanPrint(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	// "Fajr": F 611 + a 556 + j 222 + r 333 in Helvetica.
+	if got := TextWidth("Fajr", 10, false); got != 17.22 {
+		t.Errorf("TextWidth(Fajr) = %v, want 17.22", got)
+	}
+	if TextWidth("Fajr", 10, true) <= TextWidth("Fajr", 10, false) {
+		t.Error("bold text is not wider than regular")
+	}
+	if TextWidth("Ramaḍān", 10, false) != TextWidth("Ramadan", 10, false) {
+		t.Error("folded diacritics change the width")
+	}
+}
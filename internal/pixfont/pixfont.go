@@ -0,0 +1,211 @@
+// Package pixfont draws text in a small built-in bitmap font, so images with
+// text can be rendered with the standard library alone.
+//
+// Each glyph is 5×7 pixels, drawn at an integer scale. The font covers
+// printable ASCII; Latin letters with diacritics are drawn without them and
+// anything else as "?".
+package pixfont
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode"
+)
+
+// Glyph metrics in unscaled pixels: each character advances one column
+// more than it is wide.
+const (
+	GlyphWidth  = 5
+	GlyphHeight = 7
+	Advance     = GlyphWidth + 1
+)
+
+// Width returns the width in pixels of s drawn at scale.
+func Width(s string, scale int) int {
+	n := len(Fold(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*Advance - 1) * scale
+}
+
+// Draw draws s onto dst in c with its top-left corner at x, y, each font
+// pixel a scale×scale square. Bold thickens the strokes by one pixel.
+func Draw(dst draw.Image, x, y int, s string, scale int, c color.Color, bold bool) {
+	src := image.NewUniform(c)
+	thick := scale
+	if bold {
+		thick += max(scale/2, 1)
+	}
+	for i, b := range []byte(Fold(s)) {
+		glyph := &glyphs[b-' ']
+		gx := x + i*Advance*scale
+		for row, bits := range glyph {
+			for col := range GlyphWidth {
+				if bits&(1<<(GlyphWidth-1-col)) == 0 {
+					continue
+				}
+				px, py := gx+col*scale, y+row*scale
+				draw.Draw(dst, image.Rect(px, py, px+thick, py+scale), src, image.Point{}, draw.Over)
+			}
+		}
+	}
+}
+
+// Fold returns s in the characters the font has: accents and other marks
+// are dropped from letters, a few typographic characters are replaced with
+// their ASCII look-alikes, and anything else becomes "?".
+func Fold(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if !foldRune(&sb, r) {
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
+
+// CanDraw reports whether the font can draw every character of s, if only
+// as its ASCII look-alike. Text in other scripts, such as Arabic, would
+// come out as "?".
+func CanDraw(s string) bool {
+	var sb strings.Builder
+	for _, r := range s {
+		if !foldRune(&sb, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// foldRune writes r to sb in the characters the font has, reporting false
+// if it has none for it.
+func foldRune(sb *strings.Builder, r rune) bool {
+	switch {
+	case r >= ' ' && r <= '~':
+		sb.WriteRune(r)
+	case lookalikes[r] != "":
+		sb.WriteString(lookalikes[r])
+	case unicode.Is(unicode.Mn, r) || r == '\u2066' || r == '\u2067' || r == '\u2068' || r == '\u2069':
+		// Combining marks and bidi isolates have no glyph of their own.
+	default:
+		return false
+	}
+	return true
+}
+
+// lookalikes spells characters outside ASCII that timetables use, such as
+// the letters of transliterated Arabic, with the nearest ASCII.
+var lookalikes = map[rune]string{
+	'ā': "a", 'Ā': "A", 'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'Á': "A",
+	'ī': "i", 'Ī': "I", 'í': "i", 'î': "i", 'ı': "i", 'İ': "I",
+	'ū': "u", 'Ū': "U", 'ú': "u", 'ü': "u", 'Ü': "U",
+	'é': "e", 'è': "e", 'ê': "e", 'É': "E", 'ó': "o", 'ö': "o", 'Ö': "O",
+	'ç': "c", 'Ç': "C", 'ñ': "n", 'ğ': "g", 'Ğ': "G", 'ş': "s", 'Ş': "S",
+	'ḥ': "h", 'Ḥ': "H", 'ḍ': "d", 'Ḍ': "D", 'ṣ': "s", 'Ṣ': "S",
+	'ṭ': "t", 'Ṭ': "T", 'ẓ': "z", 'Ẓ': "Z", 'ʿ': "'", 'ʾ': "'",
+	'‘': "'", '’': "'", '“': "\"", '”': "\"", '–': "-", '—': "-",
+	'·': "-", '…': "...", '×': "x", '\u00a0': " ",
+}
+
+// glyphs are the font's printable ASCII characters from ' ' on, one byte
+// per row, top to bottom, with the leftmost pixel in bit 4.
+var glyphs = [95][GlyphHeight]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // space
+	{0x04, 0x04, 0x04, 0x04, 0x04, 0x00, 0x04}, // !
+	{0x0a, 0x0a, 0x00, 0x00, 0x00, 0x00, 0x00}, // "
+	{0x0a, 0x0a, 0x1f, 0x0a, 0x1f, 0x0a, 0x0a}, // #
+	{0x04, 0x0f, 0x14, 0x0e, 0x05, 0x1e, 0x04}, // $
+	{0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03}, // %
+	{0x0c, 0x12, 0x14, 0x08, 0x15, 0x12, 0x0d}, // &
+	{0x04, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00}, // '
+	{0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02}, // (
+	{0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08}, // )
+	{0x00, 0x04, 0x15, 0x0e, 0x15, 0x04, 0x00}, // *
+	{0x00, 0x04, 0x04, 0x1f, 0x04, 0x04, 0x00}, // +
+	{0x00, 0x00, 0x00, 0x00, 0x0c, 0x04, 0x08}, // ,
+	{0x00, 0x00, 0x00, 0x1f, 0x00, 0x00, 0x00}, // -
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x0c}, // .
+	{0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00}, // /
+	{0x0e, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0e}, // 0
+	{0x04, 0x0c, 0x04, 0x04, 0x04, 0x04, 0x0e}, // 1
+	{0x0e, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1f}, // 2
+	{0x1f, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0e}, // 3
+	{0x02, 0x06, 0x0a, 0x12, 0x1f, 0x02, 0x02}, // 4
+	{0x1f, 0x10, 0x1e, 0x01, 0x01, 0x11, 0x0e}, // 5
+	{0x06, 0x08, 0x10, 0x1e, 0x11, 0x11, 0x0e}, // 6
+	{0x1f, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08}, // 7
+	{0x0e, 0x11, 0x11, 0x0e, 0x11, 0x11, 0x0e}, // 8
+	{0x0e, 0x11, 0x11, 0x0f, 0x01, 0x02, 0x0c}, // 9
+	{0x00, 0x0c, 0x0c, 0x00, 0x0c, 0x0c, 0x00}, // :
+	{0x00, 0x0c, 0x0c, 0x00, 0x0c, 0x04, 0x08}, // ;
+	{0x02, 0x04, 0x08, 0x10, 0x08, 0x04, 0x02}, // <
+	{0x00, 0x00, 0x1f, 0x00, 0x1f, 0x00, 0x00}, // =
+	{0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x08}, // >
+	{0x0e, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04}, // ?
+	{0x0e, 0x11, 0x01, 0x0d, 0x15, 0x15, 0x0e}, // @
+	{0x0e, 0x11, 0x11, 0x1f, 0x11, 0x11, 0x11}, // A
+	{0x1e, 0x11, 0x11, 0x1e, 0x11, 0x11, 0x1e}, // B
+	{0x0e, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0e}, // C
+	{0x1c, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1c}, // D
+	{0x1f, 0x10, 0x10, 0x1e, 0x10, 0x10, 0x1f}, // E
+	{0x1f, 0x10, 0x10, 0x1e, 0x10, 0x10, 0x10}, // F
+	{0x0e, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0f}, // G
+	{0x11, 0x11, 0x11, 0x1f, 0x11, 0x11, 0x11}, // H
+	{0x0e, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0e}, // I
+	{0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0c}, // J
+	{0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11}, // K
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1f}, // L
+	{0x11, 0x1b, 0x15, 0x15, 0x11, 0x11, 0x11}, // M
+	{0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11}, // N
+	{0x0e, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0e}, // O
+	{0x1e, 0x11, 0x11, 0x1e, 0x10, 0x10, 0x10}, // P
+	{0x0e, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0d}, // Q
+	{0x1e, 0x11, 0x11, 0x1e, 0x14, 0x12, 0x11}, // R
+	{0x0f, 0x10, 0x10, 0x0e, 0x01, 0x01, 0x1e}, // S
+	{0x1f, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04}, // T
+	{0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0e}, // U
+	{0x11, 0x11, 0x11, 0x11, 0x11, 0x0a, 0x04}, // V
+	{0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0a}, // W
+	{0x11, 0x11, 0x0a, 0x04, 0x0a, 0x11, 0x11}, // X
+	{0x11, 0x11, 0x0a, 0x04, 0x04, 0x04, 0x04}, // Y
+	{0x1f, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1f}, // Z
+	{0x0e, 0x08, 0x08, 0x08, 0x08, 0x08, 0x0e}, // [
+	{0x00, 0x10, 0x08, 0x04, 0x02, 0x01, 0x00}, // \
+	{0x0e, 0x02, 0x02, 0x02, 0x02, 0x02, 0x0e}, // ]
+	{0x04, 0x0a, 0x11, 0x00, 0x00, 0x00, 0x00}, // ^
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1f}, // _
+	{0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00}, // `
+	{0x00, 0x00, 0x0e, 0x01, 0x0f, 0x11, 0x0f}, // a
+	{0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x1e}, // b
+	{0x00, 0x00, 0x0e, 0x10, 0x10, 0x11, 0x0e}, // c
+	{0x01, 0x01, 0x0d, 0x13, 0x11, 0x11, 0x0f}, // d
+	{0x00, 0x00, 0x0e, 0x11, 0x1f, 0x10, 0x0e}, // e
+	{0x06, 0x09, 0x08, 0x1c, 0x08, 0x08, 0x08}, // f
+	{0x00, 0x0f, 0x11, 0x11, 0x0f, 0x01, 0x0e}, // g
+	{0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x11}, // h
+	{0x04, 0x00, 0x0c, 0x04, 0x04, 0x04, 0x0e}, // i
+	{0x02, 0x00, 0x06, 0x02, 0x02, 0x12, 0x0c}, // j
+	{0x10, 0x10, 0x12, 0x14, 0x18, 0x14, 0x12}, // k
+	{0x0c, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0e}, // l
+	{0x00, 0x00, 0x1a, 0x15, 0x15, 0x11, 0x11}, // m
+	{0x00, 0x00, 0x16, 0x19, 0x11, 0x11, 0x11}, // n
+	{0x00, 0x00, 0x0e, 0x11, 0x11, 0x11, 0x0e}, // o
+	{0x00, 0x00, 0x1e, 0x11, 0x1e, 0x10, 0x10}, // p
+	{0x00, 0x00, 0x0d, 0x13, 0x0f, 0x01, 0x01}, // q
+	{0x00, 0x00, 0x16, 0x19, 0x10, 0x10, 0x10}, // r
+	{0x00, 0x00, 0x0e, 0x10, 0x0e, 0x01, 0x1e}, // s
+	{0x08, 0x08, 0x1c, 0x08, 0x08, 0x09, 0x06}, // t
+	{0x00, 0x00, 0x11, 0x11, 0x11, 0x13, 0x0d}, // u
+	{0x00, 0x00, 0x11, 0x11, 0x11, 0x0a, 0x04}, // v
+	{0x00, 0x00, 0x11, 0x11, 0x15, 0x15, 0x0a}, // w
+	{0x00, 0x00, 0x11, 0x0a, 0x04, 0x0a, 0x11}, // x
+	{0x00, 0x00, 0x11, 0x11, 0x0f, 0x01, 0x0e}, // y
+	{0x00, 0x00, 0x1f, 0x02, 0x04, 0x08, 0x1f}, // z
+	{0x02, 0x04, 0x04, 0x08, 0x04, 0x04, 0x02}, // {
+	{0x04, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04}, // |
+	{0x08, 0x04, 0x04, 0x02, 0x04, 0x04, 0x08}, // }
+	{0x00, 0x00, 0x08, 0x15, 0x02, 0x00, 0x00}, // ~
+}
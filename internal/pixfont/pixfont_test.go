@@ -0,0 +1,104 @@
+package pixfont
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		scale int
+		want  int
+	}{
+		{"", 2, 0},
+		{"A", 1, 5},
+		{"05:30", 1, 29},
+		{"05:30", 2, 58},
+		{"Ramaḍān", 1, 41},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s, tt.scale); got != tt.want {
+			t.Errorf("Width(%q, %d) = %d, want %d", tt.s, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Fajr 05:30", "Fajr 05:30"},
+		{"Ramaḍān – Shawwāl", "Ramadan - Shawwal"},
+		{"Shaʿbān", "Sha'ban"},
+		{"Istanbul, Türkiye", "Istanbul, Turkiye"},
+		{"Café", "Cafe"},
+		{"مكة", "???"},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCanDraw(t *testing.T) {
+	if !CanDraw("Ramaḍān – Shawwāl") || !CanDraw("") {
+		t.Error("CanDraw() = false for transliterated Arabic")
+	}
+	if CanDraw("مسجد النور") {
+		t.Error("CanDraw() = true for Arabic script")
+	}
+}
+
+func TestDraw(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 12, 7))
+	Draw(img, 0, 0, "I-", 1, color.White, false)
+
+	var sb strings.Builder
+	for y := range 7 {
+		for x := range 12 {
+			if img.GrayAt(x, y).Y > 0 {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	want := `.###........
+..#.........
+..#.........
+..#...#####.
+..#.........
+..#.........
+.###........
+`
+	if got := sb.String(); got != want {
+		t.Errorf("Draw(\"I-\") =\n%swant\n%s", got, want)
+	}
+
+	// Scaled, each font pixel is a square; bold widens the strokes.
+	img = image.NewGray(image.Rect(0, 0, 20, 20))
+	Draw(img, 1, 1, "|", 2, color.White, true)
+	for _, p := range []image.Point{{5, 1}, {6, 2}, {7, 14}} {
+		if img.GrayAt(p.X, p.Y).Y == 0 {
+			t.Errorf("pixel %v of a bold scaled | is not set", p)
+		}
+	}
+	if img.GrayAt(8, 1).Y != 0 || img.GrayAt(5, 15).Y != 0 {
+		t.Error("a bold scaled | spills outside its strokes")
+	}
+}
+
+func TestGlyphsFitWidth(t *testing.T) {
+	for i, g := range glyphs {
+		for row, bits := range g {
+			if bits >= 1<<GlyphWidth {
+				t.Errorf("glyph %q row %d is wider than %d pixels", rune(' '+i), row, GlyphWidth)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package prayer
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status bars run the CLI every few seconds, thousands of times a day, often
+// on low-power devices. The built-in formats are therefore written by
+// appending to a byte slice rather than through fmt or text/template, and
+// FormatOutput and FormatStatus only build template data for templates.
+
+// AppendOutput appends p formatted in mode, as FormatOutput formats it, to
+// dst and returns the extended buffer. For the built-in modes it does not
+// allocate when dst has room, so a caller refreshing a status line can reuse
+// one buffer; custom templates are executed as FormatOutput executes them.
+func AppendOutput(dst []byte, p Prayer, now time.Time, mode string, timeFormat string) []byte {
+	return AppendOutputSince(dst, p, time.Time{}, now, mode, timeFormat)
+}
+
+// AppendOutputSince is AppendOutput with the window fields of custom
+// templates filled in from start, as in FormatOutputSince.
+func AppendOutputSince(dst []byte, p Prayer, start, now time.Time, mode string, timeFormat string) []byte {
+	if strings.Contains(mode, "{{") {
+		return append(dst, formatCustom(mode, newFormatData(p, start, now, timeFormat))...)
+	}
+
+	d := TimeRemaining(p, now)
+	switch mode {
+	case FormatTimeRemaining:
+		return appendRemaining(dst, d)
+	case FormatTimeRemainingSeconds:
+		return appendRemainingSeconds(dst, d)
+	case FormatNextPrayerTime:
+		return p.Time.AppendFormat(dst, timeFormat)
+	case FormatNameAndRemaining:
+		dst = append(dst, p.Name...)
+		dst = append(dst, ' ')
+		return appendRemaining(dst, d)
+	case FormatShortNameAndTime:
+		dst = append(dst, ShortName(p.Name)...)
+		dst = append(dst, ' ')
+		return p.Time.AppendFormat(dst, timeFormat)
+	case FormatShortNameAndRemain:
+		dst = append(dst, ShortName(p.Name)...)
+		dst = append(dst, ' ')
+		return appendRemaining(dst, d)
+	case FormatFull:
+		dst = append(dst, p.Name...)
+		dst = append(dst, ' ')
+		dst = p.Time.AppendFormat(dst, timeFormat)
+		dst = append(dst, " ("...)
+		dst = appendRemaining(dst, d)
+		return append(dst, ')')
+	default:
+		// Default to name-and-time.
+		dst = append(dst, p.Name...)
+		dst = append(dst, ' ')
+		return p.Time.AppendFormat(dst, timeFormat)
+	}
+}
+
+// AppendStatus appends current and next formatted in mode, as FormatStatus
+// formats them, to dst and returns the extended buffer. Like AppendOutput,
+// it does not allocate for the built-in modes when dst has room.
+func AppendStatus(dst []byte, current, next Prayer, now time.Time, mode string, timeFormat string) []byte {
+	if strings.Contains(mode, "{{") {
+		return append(dst, formatCustom(mode, StatusFormatData{
+			Current: newCurrentFormatData(current, next, now, timeFormat),
+			Next:    newFormatData(next, current.Time, now, timeFormat),
+		})...)
+	}
+
+	remaining := TimeRemaining(next, now)
+	switch mode {
+	case FormatNameAndRemaining, FormatShortNameAndRemain:
+		currentName, nextName := current.Name, next.Name
+		if mode == FormatShortNameAndRemain {
+			currentName, nextName = ShortName(current.Name), ShortName(next.Name)
+		}
+		dst = append(dst, currentName...)
+		dst = append(dst, ' ')
+		dst = appendRemaining(dst, now.Sub(current.Time))
+		dst = append(dst, " · "...)
+		dst = append(dst, nextName...)
+		dst = append(dst, ' ')
+		return appendRemaining(dst, remaining)
+	default:
+		dst = append(dst, current.Name...)
+		dst = append(dst, ' ')
+		dst = current.Time.AppendFormat(dst, timeFormat)
+		dst = append(dst, " · "...)
+		dst = append(dst, next.Name...)
+		dst = append(dst, ' ')
+		dst = next.Time.AppendFormat(dst, timeFormat)
+		dst = append(dst, " ("...)
+		dst = appendRemaining(dst, remaining)
+		return append(dst, ')')
+	}
+}
+
+// ShortName returns the abbreviation of the prayer called name, as in
+// ShortNames, or "" for a name it doesn't know.
+func ShortName(name string) string {
+	switch name {
+	case "Fajr":
+		return "F"
+	case "Sunrise":
+		return "S"
+	case "Dhuhr":
+		return "D"
+	case "Asr":
+		return "A"
+	case "Sunset":
+		return "St"
+	case "Maghrib":
+		return "M"
+	case "Isha":
+		return "I"
+	case "Imsak":
+		return "Im"
+	case "Midnight":
+		return "Mi"
+	case "Firstthird":
+		return "F3"
+	case "Lastthird":
+		return "L3"
+	}
+	return ""
+}
+
+// appendRemaining appends d as FormatRemaining formats it.
+func appendRemaining(dst []byte, d time.Duration) []byte {
+	if d < 0 {
+		return append(dst, "0m"...)
+	}
+	h := int64(d.Hours())
+	m := int64(d.Minutes()) % 60
+	if h > 0 {
+		dst = strconv.AppendInt(dst, h, 10)
+		dst = append(dst, "h "...)
+	}
+	dst = strconv.AppendInt(dst, m, 10)
+	return append(dst, 'm')
+}
+
+// appendRemainingSeconds appends d as FormatRemainingSeconds formats it.
+func appendRemainingSeconds(dst []byte, d time.Duration) []byte {
+	if d < 0 {
+		return append(dst, "0s"...)
+	}
+	h := int64(d.Hours())
+	m := int64(d.Minutes()) % 60
+	sec := int64(d.Seconds()) % 60
+	switch {
+	case h > 0:
+		dst = strconv.AppendInt(dst, h, 10)
+		dst = append(dst, "h "...)
+		dst = appendTwoDigits(dst, m)
+		dst = append(dst, "m "...)
+		dst = appendTwoDigits(dst, sec)
+	case m > 0:
+		dst = strconv.AppendInt(dst, m, 10)
+		dst = append(dst, "m "...)
+		dst = appendTwoDigits(dst, sec)
+	default:
+		dst = strconv.AppendInt(dst, sec, 10)
+	}
+	return append(dst, 's')
+}
+
+// appendTwoDigits appends n, which is under 100, zero-padded to two digits.
+func appendTwoDigits(dst []byte, n int64) []byte {
+	return append(dst, byte('0'+n/10), byte('0'+n%10))
+}
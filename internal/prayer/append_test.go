@@ -0,0 +1,97 @@
+package prayer
+
+import (
+	"testing"
+	"time"
+)
+
+// builtinModes are the modes AppendOutput writes without a template.
+var builtinModes = []string{
+	FormatTimeRemaining, FormatTimeRemainingSeconds, FormatNextPrayerTime,
+	FormatNameAndTime, FormatNameAndRemaining, FormatShortNameAndTime,
+	FormatShortNameAndRemain, FormatFull,
+}
+
+func TestAppendOutput_NoAllocs(t *testing.T) {
+	p, now := formatTestPrayer()
+	buf := make([]byte, 0, 64)
+
+	for _, mode := range builtinModes {
+		for _, layout := range []string{"15:04", "3:04 PM"} {
+			allocs := testing.AllocsPerRun(100, func() {
+				buf = AppendOutput(buf[:0], p, now, mode, layout)
+			})
+			if allocs != 0 {
+				t.Errorf("AppendOutput(%q, %q) allocates %v times, want 0", mode, layout, allocs)
+			}
+		}
+	}
+
+	current := Prayer{Name: "Dhuhr", Time: p.Time.Add(-3 * time.Hour)}
+	for _, mode := range []string{FormatFull, FormatNameAndRemaining, FormatShortNameAndRemain} {
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = AppendStatus(buf[:0], current, p, now, mode, "15:04")
+		})
+		if allocs != 0 {
+			t.Errorf("AppendStatus(%q) allocates %v times, want 0", mode, allocs)
+		}
+	}
+}
+
+func TestAppendOutput_Appends(t *testing.T) {
+	p, now := formatTestPrayer()
+
+	got := string(AppendOutput([]byte("🕌 "), p, now, FormatFull, "15:04"))
+	if want := "🕌 Asr 15:02 (2h 15m)"; got != want {
+		t.Errorf("AppendOutput = %q, want %q", got, want)
+	}
+	got = string(AppendOutput([]byte("> "), p, now, "{{.ShortName}}{{.Minutes}}", "15:04"))
+	if want := "> A15"; got != want {
+		t.Errorf("AppendOutput with a template = %q, want %q", got, want)
+	}
+}
+
+func TestShortName(t *testing.T) {
+	for name, short := range ShortNames {
+		if got := ShortName(name); got != short {
+			t.Errorf("ShortName(%q) = %q, ShortNames has %q", name, got, short)
+		}
+	}
+	if got := ShortName("Zuhr"); got != "" {
+		t.Errorf("ShortName(Zuhr) = %q, want \"\"", got)
+	}
+}
+
+func BenchmarkFormatOutput(b *testing.B) {
+	p, now := formatTestPrayer()
+	b.ReportAllocs()
+	for range b.N {
+		FormatOutput(p, now, FormatShortNameAndRemain, "15:04")
+	}
+}
+
+func BenchmarkAppendOutput(b *testing.B) {
+	p, now := formatTestPrayer()
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for range b.N {
+		buf = AppendOutput(buf[:0], p, now, FormatShortNameAndRemain, "15:04")
+	}
+}
+
+func BenchmarkFormatOutput_Template(b *testing.B) {
+	p, now := formatTestPrayer()
+	b.ReportAllocs()
+	for range b.N {
+		FormatOutput(p, now, "{{.ShortName}} {{.Remaining}}", "15:04")
+	}
+}
+
+func BenchmarkFormatStatus(b *testing.B) {
+	next, now := formatTestPrayer()
+	current := Prayer{Name: "Dhuhr", Time: next.Time.Add(-3 * time.Hour)}
+	b.ReportAllocs()
+	for range b.N {
+		FormatStatus(current, next, now, FormatFull, "15:04")
+	}
+}
@@ -17,9 +17,23 @@ const (
 	FormatShortNameAndTime   = "short-name-and-time"
 	FormatShortNameAndRemain = "short-name-and-remaining"
 	FormatFull               = "full"
+	// FormatTimeRemainingSeconds counts down to the second, e.g.
+	// "1h 04m 32s", for status lines refreshed every second.
+	FormatTimeRemainingSeconds = "time-remaining-seconds"
+)
+
+// Default progress bar drawn by .ProgressBar; templates can draw others with
+// .Bar.
+const (
+	DefaultBarWidth = 10
+	DefaultBarFull  = "█"
+	DefaultBarEmpty = "░"
 )
 
 // FormatData is the data passed to custom Go templates.
+//
+// The window fields describe the stretch from the previous prayer to this
+// one, e.g. Dhuhr to Asr. They are empty when the previous prayer is unknown.
 type FormatData struct {
 	Name      string // Full prayer name, e.g. "Asr"
 	ShortName string // Abbreviated name, e.g. "A"
@@ -27,56 +41,171 @@ type FormatData struct {
 	Remaining string // Time remaining, e.g. "2h 15m"
 	Hours     int    // Whole hours remaining
 	Minutes   int    // Remaining minutes after hours
+	Seconds   int    // Remaining seconds after minutes
+
+	Elapsed        string // Time since the previous prayer, e.g. "45m"
+	WindowDuration string // Previous prayer to this one, e.g. "3h 0m"
+	Progress       int    // Percent of the window elapsed, 0-100
+	ProgressBar    string // Progress as a bar, e.g. "██░░░░░░░░"
+
+	progress float64 // fraction of the window elapsed; -1 when unknown
+}
+
+// Bar draws the window's progress as width cells of full and empty, e.g.
+// {{.Bar 20 "#" "-"}}. It is empty when the previous prayer is unknown.
+func (d FormatData) Bar(width int, full, empty string) string {
+	if d.progress < 0 || width <= 0 {
+		return ""
+	}
+	filled := int(d.progress*float64(width) + 0.5)
+	return strings.Repeat(full, filled) + strings.Repeat(empty, width-filled)
 }
 
 // FormatOutput formats a prayer for display according to the chosen format mode.
 // timeFormat should be "15:04" for 24h or "3:04 PM" for 12h.
 //
 // If mode contains "{{", it is treated as a custom Go template string.
-// Available template fields: .Name, .ShortName, .Time, .Remaining, .Hours, .Minutes
+// Available template fields: .Name, .ShortName, .Time, .Remaining, .Hours,
+// .Minutes, .Seconds
 //
 // Example: "{{.Name}} in {{.Remaining}}" -> "Asr in 2h 15m"
 func FormatOutput(p Prayer, now time.Time, mode string, timeFormat string) string {
+	return FormatOutputSince(p, time.Time{}, now, mode, timeFormat)
+}
+
+// FormatOutputSince is FormatOutput with the window fields filled in: start
+// is when the window ending at p began, normally the previous prayer. A zero
+// start leaves them empty.
+//
+// Example: "{{.Name}} {{.ProgressBar}}" -> "Asr ███████░░░"
+func FormatOutputSince(p Prayer, start, now time.Time, mode string, timeFormat string) string {
+	var buf [64]byte
+	return string(AppendOutputSince(buf[:0], p, start, now, mode, timeFormat))
+}
+
+// newFormatData returns the template data for p at now, with the window
+// fields filled in from start unless it is zero.
+func newFormatData(p Prayer, start, now time.Time, timeFormat string) FormatData {
 	d := TimeRemaining(p, now)
+	data := FormatData{
+		Name:      p.Name,
+		ShortName: ShortName(p.Name),
+		Time:      p.Time.Format(timeFormat),
+		Remaining: FormatRemaining(d),
+		Hours:     int(d.Hours()),
+		Minutes:   int(d.Minutes()) % 60,
+		Seconds:   int(d.Seconds()) % 60,
+		progress:  -1,
+	}
+	if window := p.Time.Sub(start); !start.IsZero() && window > 0 {
+		elapsed := min(max(now.Sub(start), 0), window)
+		data.progress = float64(elapsed) / float64(window)
+		data.Elapsed = FormatRemaining(elapsed)
+		data.WindowDuration = FormatRemaining(window)
+		data.Progress = int(data.progress * 100)
+		data.ProgressBar = data.Bar(DefaultBarWidth, DefaultBarFull, DefaultBarEmpty)
+	}
+	return data
+}
+
+// Format modes of the current prayer window, besides FormatTimeRemaining,
+// FormatNameAndRemaining and FormatFull, which it shares with FormatOutput.
+const (
+	FormatName         = "name"
+	FormatNameAndStart = "name-and-start"
+)
+
+// CurrentFormatData is the data passed to custom Go templates for the
+// current prayer window.
+type CurrentFormatData struct {
+	Name      string // Current prayer, e.g. "Asr"
+	ShortName string // Abbreviated name, e.g. "A"
+	Start     string // When it began, e.g. "15:02"
+	Next      string // The prayer that ends the window, e.g. "Maghrib"
+	End       string // When the window ends, e.g. "18:10"
+	Elapsed   string // Time since it began, e.g. "35m"
+	Remaining string // Time until it ends, e.g. "2h 15m"
+	Hours     int    // Whole hours remaining
+	Minutes   int    // Remaining minutes after hours
+	Seconds   int    // Remaining seconds after minutes
+}
+
+// FormatCurrent formats the prayer window from current until next for
+// display according to mode, as FormatOutput does for the next prayer.
+//
+// If mode contains "{{", it is treated as a custom Go template string over
+// CurrentFormatData.
+//
+// Example: "{{.Name}} ends in {{.Remaining}}" -> "Asr ends in 2h 15m"
+func FormatCurrent(current, next Prayer, now time.Time, mode string, timeFormat string) string {
+	d := TimeRemaining(next, now)
 	remaining := FormatRemaining(d)
-	timeStr := p.Time.Format(timeFormat)
-	short := ShortNames[p.Name]
+	start := current.Time.Format(timeFormat)
 
-	// Custom template mode: any format string containing "{{" is a Go template.
 	if strings.Contains(mode, "{{") {
-		return formatCustom(mode, FormatData{
-			Name:      p.Name,
-			ShortName: short,
-			Time:      timeStr,
-			Remaining: remaining,
-			Hours:     int(d.Hours()),
-			Minutes:   int(d.Minutes()) % 60,
-		})
+		return formatCustom(mode, newCurrentFormatData(current, next, now, timeFormat))
 	}
 
 	switch mode {
+	case FormatName:
+		return current.Name
+	case FormatNameAndStart:
+		return fmt.Sprintf("%s %s", current.Name, start)
 	case FormatTimeRemaining:
 		return remaining
-	case FormatNextPrayerTime:
-		return timeStr
-	case FormatNameAndTime:
-		return fmt.Sprintf("%s %s", p.Name, timeStr)
+	case FormatTimeRemainingSeconds:
+		return FormatRemainingSeconds(d)
 	case FormatNameAndRemaining:
-		return fmt.Sprintf("%s %s", p.Name, remaining)
-	case FormatShortNameAndTime:
-		return fmt.Sprintf("%s %s", short, timeStr)
-	case FormatShortNameAndRemain:
-		return fmt.Sprintf("%s %s", short, remaining)
-	case FormatFull:
-		return fmt.Sprintf("%s %s (%s)", p.Name, timeStr, remaining)
+		return fmt.Sprintf("%s %s", current.Name, remaining)
 	default:
-		// Default to name-and-time.
-		return fmt.Sprintf("%s %s", p.Name, timeStr)
+		return fmt.Sprintf("%s since %s, %s in %s", current.Name, start, next.Name, remaining)
 	}
 }
 
-// formatCustom executes a user-provided Go template string against the FormatData.
-func formatCustom(tmpl string, data FormatData) string {
+// newCurrentFormatData returns the template data for the window from current
+// until next at now.
+func newCurrentFormatData(current, next Prayer, now time.Time, timeFormat string) CurrentFormatData {
+	d := TimeRemaining(next, now)
+	return CurrentFormatData{
+		Name:      current.Name,
+		ShortName: ShortName(current.Name),
+		Start:     current.Time.Format(timeFormat),
+		Next:      next.Name,
+		End:       next.Time.Format(timeFormat),
+		Elapsed:   FormatRemaining(now.Sub(current.Time)),
+		Remaining: FormatRemaining(d),
+		Hours:     int(d.Hours()),
+		Minutes:   int(d.Minutes()) % 60,
+		Seconds:   int(d.Seconds()) % 60,
+	}
+}
+
+// StatusFormatData is the data passed to custom Go templates for the current
+// and next prayer together: the fields of FormatCurrent's templates under
+// .Current and of FormatOutput's under .Next, with the window from the
+// current prayer to the next.
+type StatusFormatData struct {
+	Current CurrentFormatData
+	Next    FormatData
+}
+
+// FormatStatus formats the current prayer and the next one together for
+// display according to mode: FormatNameAndRemaining gives the time since
+// the current prayer and until the next, e.g. "Dhuhr 45m · Asr 2h 15m", and
+// FormatShortNameAndRemain the same with short names; anything else but a
+// template is FormatFull, e.g. "Dhuhr 12:30 · Asr 15:02 (2h 15m)".
+//
+// If mode contains "{{", it is treated as a custom Go template string over
+// StatusFormatData.
+//
+// Example: "{{.Current.Name}} → {{.Next.Name}} {{.Next.Remaining}}" -> "Dhuhr → Asr 2h 15m"
+func FormatStatus(current, next Prayer, now time.Time, mode string, timeFormat string) string {
+	var buf [96]byte
+	return string(AppendStatus(buf[:0], current, next, now, mode, timeFormat))
+}
+
+// formatCustom executes a user-provided Go template string against data.
+func formatCustom(tmpl string, data any) string {
 	t, err := template.New("custom").Parse(tmpl)
 	if err != nil {
 		return fmt.Sprintf("template-err: %v", err)
@@ -21,6 +21,7 @@ func TestFormatOutput_AllBuiltinModes(t *testing.T) {
 		want string
 	}{
 		{FormatTimeRemaining, "2h 15m"},
+		{FormatTimeRemainingSeconds, "2h 15m 00s"},
 		{FormatNextPrayerTime, "15:02"},
 		{FormatNameAndTime, "Asr 15:02"},
 		{FormatNameAndRemaining, "Asr 2h 15m"},
@@ -82,8 +83,8 @@ func TestFormatOutput_CustomTemplate(t *testing.T) {
 		},
 		{
 			"all fields",
-			"{{.Name}}|{{.ShortName}}|{{.Time}}|{{.Remaining}}|{{.Hours}}|{{.Minutes}}",
-			"Asr|A|15:02|2h 15m|2|15",
+			"{{.Name}}|{{.ShortName}}|{{.Time}}|{{.Remaining}}|{{.Hours}}|{{.Minutes}}|{{.Seconds}}",
+			"Asr|A|15:02|2h 15m|2|15|0",
 		},
 	}
 
@@ -97,6 +98,26 @@ func TestFormatOutput_CustomTemplate(t *testing.T) {
 	}
 }
 
+func TestFormatOutputSince_WindowFields(t *testing.T) {
+	p, now := formatTestPrayer()
+	dhuhr := time.Date(2026, 2, 28, 12, 2, 0, 0, time.UTC)
+
+	tmpl := "{{.Elapsed}}|{{.WindowDuration}}|{{.Progress}}|{{.ProgressBar}}|{{.Bar 4 \"#\" \"-\"}}"
+	if got, want := FormatOutputSince(p, dhuhr, now, tmpl, "15:04"), "45m|3h 0m|25|███░░░░░░░|#---"; got != want {
+		t.Errorf("window fields = %q, want %q", got, want)
+	}
+
+	// Past the end of the window the bar is full, not overflowing.
+	if got := FormatOutputSince(p, dhuhr, p.Time.Add(time.Minute), "{{.Progress}} {{.Bar 4 \"#\" \"-\"}}", "15:04"); got != "100 ####" {
+		t.Errorf("after the window = %q, want %q", got, "100 ####")
+	}
+
+	// Without a start the window fields are empty.
+	if got := FormatOutput(p, now, tmpl, "15:04"); got != "||0||" {
+		t.Errorf("no start = %q, want %q", got, "||0||")
+	}
+}
+
 func TestFormatOutput_InvalidTemplate(t *testing.T) {
 	p, now := formatTestPrayer()
 
@@ -136,3 +157,46 @@ func TestFormatOutput_ZeroRemaining(t *testing.T) {
 		t.Errorf("zero remaining = %q, want %q", got, "0m")
 	}
 }
+
+func TestFormatCurrent(t *testing.T) {
+	current := Prayer{Name: "Dhuhr", Time: time.Date(2026, 2, 28, 12, 30, 0, 0, time.UTC)}
+	next, now := formatTestPrayer()
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{FormatName, "Dhuhr"},
+		{FormatNameAndStart, "Dhuhr 12:30"},
+		{FormatTimeRemaining, "2h 15m"},
+		{FormatTimeRemainingSeconds, "2h 15m 00s"},
+		{FormatNameAndRemaining, "Dhuhr 2h 15m"},
+		{FormatFull, "Dhuhr since 12:30, Asr in 2h 15m"},
+		{"{{.ShortName}} {{.Elapsed}} / {{.Next}} {{.End}}", "D 17m / Asr 15:02"},
+	}
+	for _, tt := range tests {
+		if got := FormatCurrent(current, next, now, tt.mode, "15:04"); got != tt.want {
+			t.Errorf("FormatCurrent(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestFormatStatus(t *testing.T) {
+	current := Prayer{Name: "Dhuhr", Time: time.Date(2026, 2, 28, 12, 2, 0, 0, time.UTC)}
+	next, now := formatTestPrayer()
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{FormatFull, "Dhuhr 12:02 · Asr 15:02 (2h 15m)"},
+		{FormatNameAndRemaining, "Dhuhr 45m · Asr 2h 15m"},
+		{FormatShortNameAndRemain, "D 45m · A 2h 15m"},
+		{"{{.Current.ShortName}} {{.Current.Elapsed}} | {{.Next.ShortName}} {{.Next.Remaining}} {{.Next.Bar 4 \"#\" \"-\"}}", "D 45m | A 2h 15m #---"},
+	}
+	for _, tt := range tests {
+		if got := FormatStatus(current, next, now, tt.mode, "15:04"); got != tt.want {
+			t.Errorf("FormatStatus(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
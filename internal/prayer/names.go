@@ -0,0 +1,72 @@
+package prayer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Aliases are the other spellings accepted for each prayer: common
+// transliterations of the Arabic, and the names used in Turkish, Urdu,
+// Malay and Indonesian.
+var Aliases = map[string][]string{
+	"Fajr":       {"Fajar", "Fajir", "Fadjr", "Subh", "Sobh", "Subuh", "Sabah"},
+	"Sunrise":    {"Shuruq", "Shurooq", "Syuruk", "Terbit", "Güneş"},
+	"Dhuhr":      {"Zuhr", "Zohr", "Zuhur", "Zohar", "Duhr", "Dhuhur", "Dhohr", "Thuhr", "Thohr", "Dzuhur", "Öğle"},
+	"Asr":        {"Asar", "Aser", "Ashar", "İkindi"},
+	"Sunset":     {"Ghurub", "Ghuroob", "Terbenam"},
+	"Maghrib":    {"Maghreb", "Magrib", "Maghrip", "Mughrib", "Akşam"},
+	"Isha":       {"Ishaa", "Ishah", "Esha", "Eshaa", "Isya", "Yatsı"},
+	"Imsak":      {"Imsaak"},
+	"Midnight":   {"Nisf al-Layl"},
+	"Firstthird": {"First third"},
+	"Lastthird":  {"Last third"},
+}
+
+// lookupIndex maps the folded form (see foldName) of every prayer name and
+// alias to the prayer's name.
+var lookupIndex = func() map[string]string {
+	index := make(map[string]string)
+	for _, name := range AllPrayerNames {
+		index[foldName(name)] = name
+		for _, alias := range Aliases[name] {
+			index[foldName(alias)] = name
+		}
+	}
+	return index
+}()
+
+// Lookup returns the name of the prayer called name, which is a name from
+// AllPrayerNames or one of its Aliases, matched ignoring case, accents,
+// spaces, hyphens and apostrophes: "zuhr", "Maghreb" and "last-third" are
+// Dhuhr, Maghrib and Lastthird.
+func Lookup(name string) (string, bool) {
+	canonical, ok := lookupIndex[foldName(name)]
+	return canonical, ok
+}
+
+// foldName reduces a prayer name to the form Lookup compares: lower case,
+// without separators or marks, and with Turkish letters spelled in ASCII.
+func foldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_', '\'', '’', 'ʿ', '`':
+			return -1
+		case 'ı':
+			return 'i'
+		case 'ç':
+			return 'c'
+		case 'ğ':
+			return 'g'
+		case 'ö':
+			return 'o'
+		case 'ş':
+			return 's'
+		case 'ü':
+			return 'u'
+		}
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
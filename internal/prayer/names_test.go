@@ -0,0 +1,54 @@
+package prayer
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Dhuhr", "Dhuhr"},
+		{"fajr", "Fajr"},
+		{"ZUHR", "Dhuhr"},
+		{"Thohr", "Dhuhr"},
+		{"maghreb", "Maghrib"},
+		{"Ishaa", "Isha"},
+		{"last-third", "Lastthird"},
+		{"First third", "Firstthird"},
+		{"ogle", "Dhuhr"},
+		{"Öğle", "Dhuhr"},
+		{"ikindi", "Asr"},
+		{"İkindi", "Asr"},
+		{"yatsi", "Isha"},
+		{"  Asar ", "Asr"},
+	}
+	for _, tt := range tests {
+		if got, ok := Lookup(tt.name); !ok || got != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v; want %q", tt.name, got, ok, tt.want)
+		}
+	}
+
+	for _, name := range []string{"", "Prayer", "Tahajjud", "Zuhrr"} {
+		if got, ok := Lookup(name); ok {
+			t.Errorf("Lookup(%q) = %q, want no match", name, got)
+		}
+	}
+}
+
+func TestAliasesAreUnambiguous(t *testing.T) {
+	seen := make(map[string]string)
+	for _, name := range AllPrayerNames {
+		for _, spelling := range append([]string{name}, Aliases[name]...) {
+			key := foldName(spelling)
+			if other, ok := seen[key]; ok && other != name {
+				t.Errorf("%q is a spelling of both %s and %s", spelling, other, name)
+			}
+			seen[key] = name
+		}
+	}
+	for name := range Aliases {
+		if _, ok := ShortNames[name]; !ok {
+			t.Errorf("Aliases has %q, which isn't a prayer", name)
+		}
+	}
+}
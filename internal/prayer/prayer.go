@@ -26,6 +26,7 @@ var DefaultPrayerNames = []string{
 }
 
 // ShortNames maps full prayer names to single-character abbreviations.
+// ShortName looks one up without the map, for the status bar formats.
 var ShortNames = map[string]string{
 	"Fajr":       "F",
 	"Sunrise":    "S",
@@ -44,19 +45,7 @@ var ShortNames = map[string]string{
 // It filters to only include the specified prayer names.
 // The location is used to construct proper time.Time values in the correct timezone.
 func ParseTimings(timings api.Timings, date time.Time, loc *time.Location, selected []string) ([]Prayer, error) {
-	timingMap := map[string]string{
-		"Fajr":       timings.Fajr,
-		"Sunrise":    timings.Sunrise,
-		"Dhuhr":      timings.Dhuhr,
-		"Asr":        timings.Asr,
-		"Sunset":     timings.Sunset,
-		"Maghrib":    timings.Maghrib,
-		"Isha":       timings.Isha,
-		"Imsak":      timings.Imsak,
-		"Midnight":   timings.Midnight,
-		"Firstthird": timings.Firstthird,
-		"Lastthird":  timings.Lastthird,
-	}
+	timingMap := TimingsMap(timings)
 
 	var prayers []Prayer
 	for _, name := range selected {
@@ -76,6 +65,35 @@ func ParseTimings(timings api.Timings, date time.Time, loc *time.Location, selec
 	return prayers, nil
 }
 
+// ApplyTune shifts each prayer by its offset in minutes. It is for timings
+// that did not come from the API with the offsets already applied; prayers
+// without an offset are returned unchanged.
+func ApplyTune(prayers []Prayer, offsets map[string]int) []Prayer {
+	tuned := make([]Prayer, len(prayers))
+	for i, p := range prayers {
+		p.Time = p.Time.Add(time.Duration(offsets[p.Name]) * time.Minute)
+		tuned[i] = p
+	}
+	return tuned
+}
+
+// TimingsMap returns the raw API time strings keyed by prayer name.
+func TimingsMap(timings api.Timings) map[string]string {
+	return map[string]string{
+		"Fajr":       timings.Fajr,
+		"Sunrise":    timings.Sunrise,
+		"Dhuhr":      timings.Dhuhr,
+		"Asr":        timings.Asr,
+		"Sunset":     timings.Sunset,
+		"Maghrib":    timings.Maghrib,
+		"Isha":       timings.Isha,
+		"Imsak":      timings.Imsak,
+		"Midnight":   timings.Midnight,
+		"Firstthird": timings.Firstthird,
+		"Lastthird":  timings.Lastthird,
+	}
+}
+
 // NextPrayer finds the next upcoming prayer from the given slice, relative to now.
 // If all prayers for today have passed, it returns nil (caller should fetch tomorrow's Fajr).
 func NextPrayer(prayers []Prayer, now time.Time) *Prayer {
@@ -104,18 +122,24 @@ func TimeRemaining(prayer Prayer, now time.Time) time.Duration {
 	return prayer.Time.Sub(now)
 }
 
+// Imminent reports whether prayer is less than threshold away at now, for
+// displays that alert on an approaching prayer. A threshold of zero or less
+// never alerts.
+func Imminent(prayer Prayer, now time.Time, threshold time.Duration) bool {
+	return threshold > 0 && TimeRemaining(prayer, now) < threshold
+}
+
 // FormatRemaining formats a duration as "Xh Ym" or "Ym" if less than an hour.
 func FormatRemaining(d time.Duration) string {
-	if d < 0 {
-		return "0m"
-	}
-	h := int(d.Hours())
-	m := int(d.Minutes()) % 60
+	var buf [16]byte
+	return string(appendRemaining(buf[:0], d))
+}
 
-	if h > 0 {
-		return fmt.Sprintf("%dh %dm", h, m)
-	}
-	return fmt.Sprintf("%dm", m)
+// FormatRemainingSeconds formats a duration to the second, for countdowns
+// refreshed every second: "1h 04m 32s", "4m 32s" or "32s".
+func FormatRemainingSeconds(d time.Duration) string {
+	var buf [16]byte
+	return string(appendRemainingSeconds(buf[:0], d))
 }
 
 // parseTimeStr parses a time string like "15:02" or "15:02 (BST)" into a time.Time
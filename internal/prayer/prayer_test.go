@@ -132,6 +132,31 @@ func TestParseTimings_UnknownPrayer(t *testing.T) {
 	}
 }
 
+func TestApplyTune(t *testing.T) {
+	base := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	prayers := []Prayer{
+		{"Fajr", base.Add(5 * time.Hour)},
+		{"Asr", base.Add(15 * time.Hour)},
+		{"Isha", base.Add(19 * time.Hour)},
+	}
+
+	got := ApplyTune(prayers, map[string]int{"Fajr": 3, "Asr": -2})
+
+	want := []time.Time{
+		base.Add(5*time.Hour + 3*time.Minute),
+		base.Add(15*time.Hour - 2*time.Minute),
+		base.Add(19 * time.Hour),
+	}
+	for i, p := range got {
+		if !p.Time.Equal(want[i]) {
+			t.Errorf("ApplyTune: %s = %v, want %v", p.Name, p.Time, want[i])
+		}
+	}
+	if !prayers[0].Time.Equal(base.Add(5 * time.Hour)) {
+		t.Error("ApplyTune modified its input")
+	}
+}
+
 func TestParseTimings_TimezoneSuffix(t *testing.T) {
 	timings := sampleTimings()
 	timings.Fajr = "05:17 (BST)"
@@ -302,6 +327,25 @@ func TestTimeRemaining(t *testing.T) {
 	}
 }
 
+func TestImminent(t *testing.T) {
+	p := Prayer{Name: "Asr", Time: makeTime(t, 15, 2)}
+
+	tests := []struct {
+		now       time.Time
+		threshold time.Duration
+		want      bool
+	}{
+		{makeTime(t, 14, 50), 15 * time.Minute, true},
+		{makeTime(t, 14, 47), 15 * time.Minute, false},
+		{makeTime(t, 15, 1), 0, false},
+	}
+	for _, tt := range tests {
+		if got := Imminent(p, tt.now, tt.threshold); got != tt.want {
+			t.Errorf("Imminent(at %s, %v) = %v, want %v", tt.now.Format("15:04"), tt.threshold, got, tt.want)
+		}
+	}
+}
+
 func TestTimeRemaining_Negative(t *testing.T) {
 	p := Prayer{Name: "Fajr", Time: makeTime(t, 5, 0)}
 	now := makeTime(t, 10, 0)
@@ -341,6 +385,26 @@ func TestFormatRemaining(t *testing.T) {
 	}
 }
 
+func TestFormatRemainingSeconds(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{1*time.Hour + 4*time.Minute + 32*time.Second, "1h 04m 32s"},
+		{4*time.Minute + 5*time.Second, "4m 05s"},
+		{32*time.Second + 900*time.Millisecond, "32s"},
+		{2 * time.Hour, "2h 00m 00s"},
+		{0, "0s"},
+		{-time.Second, "0s"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatRemainingSeconds(tt.duration); got != tt.want {
+			t.Errorf("FormatRemainingSeconds(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // ShortNames
 // ---------------------------------------------------------------------------
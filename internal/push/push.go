@@ -0,0 +1,123 @@
+// Package push sends phone push notifications through self-hostable
+// services, ntfy (ntfy.sh or a private server) and Gotify, and through
+// Pushover.
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gotifyPriority is the Gotify message priority; 5 and above make a sound
+// on Android.
+const gotifyPriority = 5
+
+// pushoverURL is Pushover's message API; a variable so tests can point it
+// at a local server.
+var pushoverURL = "https://api.pushover.net/1/messages.json"
+
+// ParseURL checks that value, the setting named key, is an http or https
+// URL, and returns it without a trailing slash.
+func ParseURL(key, value string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid %s %q: must be an http or https URL", key, value)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// ParseNtfyTopic checks that value is a topic URL, such as
+// "https://ntfy.sh/my-prayers".
+func ParseNtfyTopic(value string) (string, error) {
+	topic, err := ParseURL("ntfy_url", value)
+	if err != nil {
+		return "", err
+	}
+	if u, _ := url.Parse(topic); strings.Trim(u.Path, "/") == "" {
+		return "", fmt.Errorf("invalid ntfy_url %q: must include the topic, e.g. https://ntfy.sh/my-prayers", value)
+	}
+	return topic, nil
+}
+
+// Ntfy publishes message to the topic at topicURL with title. token, if
+// set, is an access token for a protected topic.
+func Ntfy(client *http.Client, topicURL, token, title, message string) error {
+	req, err := http.NewRequest(http.MethodPost, topicURL, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", title)
+	req.Header.Set("Tags", "mosque")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return do(client, req)
+}
+
+// Gotify sends message with title to the Gotify server at serverURL, as
+// the application token belongs to.
+func Gotify(client *http.Client, serverURL, token, title, message string) error {
+	body, err := json.Marshal(map[string]any{"title": title, "message": message, "priority": gotifyPriority})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, serverURL+"/message", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", token)
+	return do(client, req)
+}
+
+// Pushover sends message with title to the devices of the Pushover user
+// or group key user, from the application token belongs to.
+func Pushover(client *http.Client, user, token, title, message string) error {
+	form := url.Values{"token": {token}, "user": {user}, "title": {title}, "message": {message}}
+	req, err := http.NewRequest(http.MethodPost, pushoverURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Pushover explains a rejected message, e.g. an invalid user key, in
+	// its errors list.
+	var result struct {
+		Status int      `json:"status"`
+		Errors []string `json:"errors"`
+	}
+	decodeErr := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result)
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 && result.Status == 1 {
+		return nil
+	}
+	if decodeErr == nil && len(result.Errors) > 0 {
+		return fmt.Errorf("pushover: %s", strings.Join(result.Errors, "; "))
+	}
+	return fmt.Errorf("pushover: %s", resp.Status)
+}
+
+// do sends req and fails on a non-2xx response.
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return nil
+}
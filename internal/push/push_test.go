@@ -0,0 +1,93 @@
+package push
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNtfy(t *testing.T) {
+	var got *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got, body = r, string(data)
+		if r.URL.Path == "/private" && r.Header.Get("Authorization") != "Bearer tk_abc" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	if err := Ntfy(server.Client(), server.URL+"/prayers", "", "Asr", "It's time for Asr"); err != nil {
+		t.Fatalf("Ntfy() error: %v", err)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/prayers" || got.Header.Get("Title") != "Asr" || body != "It's time for Asr" {
+		t.Errorf("request = %s %s %q %q, want the message posted to the topic", got.Method, got.URL.Path, got.Header.Get("Title"), body)
+	}
+
+	if err := Ntfy(server.Client(), server.URL+"/private", "tk_abc", "Asr", "hi"); err != nil {
+		t.Errorf("Ntfy() with a token error: %v", err)
+	}
+	if err := Ntfy(server.Client(), server.URL+"/private", "", "Asr", "hi"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Ntfy() without the token error = %v, want 403", err)
+	}
+}
+
+func TestGotify(t *testing.T) {
+	var path, key string
+	var msg map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, key = r.URL.Path, r.Header.Get("X-Gotify-Key")
+		json.NewDecoder(r.Body).Decode(&msg)
+	}))
+	defer server.Close()
+
+	if err := Gotify(server.Client(), server.URL+"/gotify", "AbC", "Asr", "It's time for Asr"); err != nil {
+		t.Fatalf("Gotify() error: %v", err)
+	}
+	if path != "/gotify/message" || key != "AbC" || msg["title"] != "Asr" || msg["message"] != "It's time for Asr" {
+		t.Errorf("request = %s key %q %v, want the message sent with the app token", path, key, msg)
+	}
+}
+
+func TestPushover(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		got = map[string]string{"token": r.PostFormValue("token"), "user": r.PostFormValue("user"), "title": r.PostFormValue("title"), "message": r.PostFormValue("message")}
+		if got["user"] != "u123" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"user":"invalid","errors":["user identifier is not a valid user, group, or subscribed user key"],"status":0}`))
+			return
+		}
+		w.Write([]byte(`{"status":1,"request":"abc"}`))
+	}))
+	defer server.Close()
+	defer func(orig string) { pushoverURL = orig }(pushoverURL)
+	pushoverURL = server.URL
+
+	if err := Pushover(server.Client(), "u123", "a456", "Asr", "It's time for Asr"); err != nil {
+		t.Fatalf("Pushover() error: %v", err)
+	}
+	if got["token"] != "a456" || got["user"] != "u123" || got["title"] != "Asr" || got["message"] != "It's time for Asr" {
+		t.Errorf("form = %v, want the message sent with the app token and user key", got)
+	}
+
+	if err := Pushover(server.Client(), "bad", "a456", "Asr", "hi"); err == nil || !strings.Contains(err.Error(), "not a valid user") {
+		t.Errorf("Pushover() with a bad user key error = %v, want Pushover's explanation", err)
+	}
+}
+
+func TestParseNtfyTopic(t *testing.T) {
+	if got, err := ParseNtfyTopic("https://ntfy.sh/my-prayers/"); err != nil || got != "https://ntfy.sh/my-prayers" {
+		t.Errorf("ParseNtfyTopic() = %q, %v", got, err)
+	}
+	for _, value := range []string{"", "ntfy.sh/prayers", "https://ntfy.sh", "https://ntfy.sh/"} {
+		if _, err := ParseNtfyTopic(value); err == nil {
+			t.Errorf("ParseNtfyTopic(%q) should error", value)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package qibla computes the direction of the Kaaba from any point on Earth.
+package qibla
+
+import "math"
+
+// Coordinates of the Kaaba in Mecca.
+const (
+	KaabaLatitude  = 21.422487
+	KaabaLongitude = 39.826206
+)
+
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0
+
+// Direction returns the initial great-circle bearing from (lat, lon) to the
+// Kaaba, in degrees clockwise from true north (0-360).
+func Direction(lat, lon float64) float64 {
+	phi1 := radians(lat)
+	phi2 := radians(KaabaLatitude)
+	dLambda := radians(KaabaLongitude - lon)
+
+	y := math.Sin(dLambda)
+	x := math.Cos(phi1)*math.Tan(phi2) - math.Sin(phi1)*math.Cos(dLambda)
+	bearing := degrees(math.Atan2(y, x))
+	return math.Mod(bearing+360, 360)
+}
+
+// Distance returns the great-circle distance from (lat, lon) to the Kaaba in kilometres.
+func Distance(lat, lon float64) float64 {
+	phi1 := radians(lat)
+	phi2 := radians(KaabaLatitude)
+	dPhi := phi2 - phi1
+	dLambda := radians(KaabaLongitude - lon)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// Compass returns the 16-point compass name for a bearing, e.g. "ESE".
+func Compass(bearing float64) string {
+	points := []string{
+		"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+		"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+	}
+	i := int(math.Mod(bearing+11.25, 360) / 22.5)
+	return points[i]
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
@@ -0,0 +1,49 @@
+package qibla
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDirection(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     float64
+	}{
+		{"London", 51.5074, -0.1278, 118.99},
+		{"New York", 40.7128, -74.0060, 58.48},
+		{"Jakarta", -6.2088, 106.8456, 295.15},
+		{"Riyadh", 24.7136, 46.6753, 243.80},
+	}
+	for _, tt := range tests {
+		got := Direction(tt.lat, tt.lon)
+		if math.Abs(got-tt.want) > 0.1 {
+			t.Errorf("%s: Direction() = %.2f, want %.2f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// London to Mecca is roughly 4,790 km.
+	if got := Distance(51.5074, -0.1278); math.Abs(got-4790) > 20 {
+		t.Errorf("Distance(London) = %.0f km, want ~4790", got)
+	}
+	if got := Distance(KaabaLatitude, KaabaLongitude); got > 0.001 {
+		t.Errorf("Distance(Kaaba) = %f, want 0", got)
+	}
+}
+
+func TestCompass(t *testing.T) {
+	tests := []struct {
+		bearing float64
+		want    string
+	}{
+		{0, "N"}, {359, "N"}, {11.3, "NNE"}, {90, "E"}, {118.99, "ESE"}, {244.6, "WSW"},
+	}
+	for _, tt := range tests {
+		if got := Compass(tt.bearing); got != tt.want {
+			t.Errorf("Compass(%v) = %q, want %q", tt.bearing, got, tt.want)
+		}
+	}
+}
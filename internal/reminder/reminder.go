@@ -0,0 +1,156 @@
+// Package reminder provides a small offline collection of Quran verses and
+// hadith about prayer, rotated daily beneath the schedule.
+package reminder
+
+import "time"
+
+// Reminder is a short verse or hadith in Arabic with translations.
+type Reminder struct {
+	Arabic       string
+	Translations map[string]string // keyed by language code, e.g. "en"
+	Source       string            // e.g. "Quran 2:238" or "Sahih al-Bukhari 527"
+}
+
+// DefaultLang is the translation used when the requested language is unavailable.
+const DefaultLang = "en"
+
+// collection is the bundled set of reminders, rotated one per day.
+var collection = []Reminder{
+	{
+		Arabic: "حَافِظُوا عَلَى الصَّلَوَاتِ وَالصَّلَاةِ الْوُسْطَىٰ وَقُومُوا لِلَّهِ قَانِتِينَ",
+		Translations: map[string]string{
+			"en": "Maintain with care the prayers and the middle prayer, and stand before Allah devoutly obedient.",
+			"fr": "Soyez assidus aux prières, et surtout à la prière médiane, et tenez-vous debout devant Allah avec dévotion.",
+			"id": "Peliharalah semua salat dan salat wustha, dan berdirilah karena Allah dengan khusyuk.",
+			"tr": "Namazlara ve orta namaza devam edin ve Allah'a gönülden boyun eğerek divan durun.",
+			"ur": "نمازوں کی حفاظت کرو، خصوصاً درمیانی نماز کی، اور اللہ کے حضور ادب سے کھڑے رہو۔",
+		},
+		Source: "Quran 2:238",
+	},
+	{
+		Arabic: "إِنَّ الصَّلَاةَ كَانَتْ عَلَى الْمُؤْمِنِينَ كِتَابًا مَّوْقُوتًا",
+		Translations: map[string]string{
+			"en": "Indeed, prayer has been decreed upon the believers at specified times.",
+			"fr": "La prière demeure, pour les croyants, une prescription à des temps déterminés.",
+			"id": "Sungguh, salat itu adalah kewajiban yang ditentukan waktunya atas orang-orang yang beriman.",
+			"tr": "Şüphesiz namaz, müminler üzerine vakitleri belirlenmiş bir farzdır.",
+			"ur": "بے شک نماز مومنوں پر مقررہ وقتوں میں فرض ہے۔",
+		},
+		Source: "Quran 4:103",
+	},
+	{
+		Arabic: "وَأَقِمِ الصَّلَاةَ ۖ إِنَّ الصَّلَاةَ تَنْهَىٰ عَنِ الْفَحْشَاءِ وَالْمُنكَرِ",
+		Translations: map[string]string{
+			"en": "Establish prayer. Indeed, prayer prohibits immorality and wrongdoing.",
+			"fr": "Accomplis la prière. En vérité, la prière préserve de la turpitude et du blâmable.",
+			"id": "Laksanakanlah salat. Sesungguhnya salat itu mencegah dari perbuatan keji dan mungkar.",
+			"tr": "Namazı dosdoğru kıl. Çünkü namaz, hayâsızlıktan ve kötülükten alıkoyar.",
+			"ur": "اور نماز قائم کرو، بے شک نماز بے حیائی اور برائی سے روکتی ہے۔",
+		},
+		Source: "Quran 29:45",
+	},
+	{
+		Arabic: "وَاسْتَعِينُوا بِالصَّبْرِ وَالصَّلَاةِ",
+		Translations: map[string]string{
+			"en": "And seek help through patience and prayer.",
+			"fr": "Et cherchez secours dans l'endurance et la prière.",
+			"id": "Dan mohonlah pertolongan dengan sabar dan salat.",
+			"tr": "Sabır ve namaz ile Allah'tan yardım isteyin.",
+			"ur": "اور صبر اور نماز سے مدد لو۔",
+		},
+		Source: "Quran 2:45",
+	},
+	{
+		Arabic: "وَأَقِمِ الصَّلَاةَ لِذِكْرِي",
+		Translations: map[string]string{
+			"en": "And establish prayer for My remembrance.",
+			"fr": "Et accomplis la prière pour te souvenir de Moi.",
+			"id": "Dan laksanakanlah salat untuk mengingat Aku.",
+			"tr": "Beni anmak için namaz kıl.",
+			"ur": "اور میری یاد کے لیے نماز قائم کرو۔",
+		},
+		Source: "Quran 20:14",
+	},
+	{
+		Arabic: "قَدْ أَفْلَحَ الْمُؤْمِنُونَ ۝ الَّذِينَ هُمْ فِي صَلَاتِهِمْ خَاشِعُونَ",
+		Translations: map[string]string{
+			"en": "Successful indeed are the believers, those who are humble in their prayer.",
+			"fr": "Bienheureux sont certes les croyants, ceux qui sont humbles dans leur prière.",
+			"id": "Sungguh beruntung orang-orang yang beriman, yaitu orang yang khusyuk dalam salatnya.",
+			"tr": "Müminler gerçekten kurtuluşa ermiştir; onlar ki namazlarında huşû içindedirler.",
+			"ur": "یقیناً ایمان والے کامیاب ہو گئے، جو اپنی نماز میں عاجزی کرتے ہیں۔",
+		},
+		Source: "Quran 23:1-2",
+	},
+	{
+		Arabic: "وَأَقِمِ الصَّلَاةَ طَرَفَيِ النَّهَارِ وَزُلَفًا مِّنَ اللَّيْلِ ۚ إِنَّ الْحَسَنَاتِ يُذْهِبْنَ السَّيِّئَاتِ",
+		Translations: map[string]string{
+			"en": "Establish prayer at the two ends of the day and in the early hours of the night. Indeed, good deeds wipe away bad deeds.",
+			"fr": "Accomplis la prière aux deux extrémités du jour et à certaines heures de la nuit. Les bonnes œuvres dissipent les mauvaises.",
+			"id": "Laksanakanlah salat pada kedua ujung siang dan pada bagian permulaan malam. Sesungguhnya perbuatan baik menghapus kesalahan.",
+			"tr": "Gündüzün iki ucunda ve gecenin ilk saatlerinde namaz kıl. Şüphesiz iyilikler kötülükleri giderir.",
+			"ur": "اور دن کے دونوں کناروں اور رات کے کچھ حصوں میں نماز قائم کرو، بے شک نیکیاں برائیوں کو مٹا دیتی ہیں۔",
+		},
+		Source: "Quran 11:114",
+	},
+	{
+		Arabic: "إِنَّ أَوَّلَ مَا يُحَاسَبُ بِهِ الْعَبْدُ يَوْمَ الْقِيَامَةِ مِنْ عَمَلِهِ صَلَاتُهُ",
+		Translations: map[string]string{
+			"en": "The first of his deeds for which a servant will be called to account on the Day of Resurrection is his prayer.",
+			"fr": "La première de ses œuvres dont le serviteur rendra compte le Jour de la Résurrection est sa prière.",
+			"id": "Amal hamba yang pertama kali dihisab pada hari kiamat adalah salatnya.",
+			"tr": "Kıyamet günü kulun amellerinden ilk hesaba çekileceği şey namazıdır.",
+			"ur": "قیامت کے دن بندے کے اعمال میں سب سے پہلے اس کی نماز کا حساب ہوگا۔",
+		},
+		Source: "Jami` at-Tirmidhi 413",
+	},
+	{
+		Arabic: "أَيُّ الْعَمَلِ أَحَبُّ إِلَى اللَّهِ؟ قَالَ: الصَّلَاةُ عَلَى وَقْتِهَا",
+		Translations: map[string]string{
+			"en": "Which deed is most beloved to Allah? He said: Prayer at its proper time.",
+			"fr": "Quelle œuvre Allah aime-t-Il le plus ? Il dit : la prière accomplie à son heure.",
+			"id": "Amal apakah yang paling dicintai Allah? Beliau menjawab: Salat tepat pada waktunya.",
+			"tr": "Allah'a en sevimli amel hangisidir? Buyurdu ki: Vaktinde kılınan namaz.",
+			"ur": "اللہ کو کون سا عمل سب سے زیادہ محبوب ہے؟ فرمایا: نماز کو اس کے وقت پر پڑھنا۔",
+		},
+		Source: "Sahih al-Bukhari 527",
+	},
+	{
+		Arabic: "فَذَلِكَ مِثْلُ الصَّلَوَاتِ الْخَمْسِ يَمْحُو اللَّهُ بِهِنَّ الْخَطَايَا",
+		Translations: map[string]string{
+			"en": "That is the likeness of the five prayers: through them Allah wipes away sins.",
+			"fr": "Tel est l'exemple des cinq prières : par elles, Allah efface les péchés.",
+			"id": "Demikianlah perumpamaan salat lima waktu, dengannya Allah menghapus kesalahan-kesalahan.",
+			"tr": "İşte beş vakit namazın misali budur; Allah onlarla günahları siler.",
+			"ur": "یہی پانچ نمازوں کی مثال ہے، اللہ ان کے ذریعے گناہوں کو مٹا دیتا ہے۔",
+		},
+		Source: "Sahih al-Bukhari 528",
+	},
+}
+
+// ForDate returns the reminder for the given day. The same day always yields
+// the same reminder, and consecutive days rotate through the collection.
+func ForDate(t time.Time) Reminder {
+	days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return collection[int(days%int64(len(collection)))]
+}
+
+// All returns a copy of the bundled collection.
+func All() []Reminder {
+	out := make([]Reminder, len(collection))
+	copy(out, collection)
+	return out
+}
+
+// Text returns the reminder in the given language, one of i18n.Langs. Arabic
+// ("ar") returns the original text; other languages fall back to DefaultLang
+// when untranslated.
+func (r Reminder) Text(lang string) string {
+	if lang == "ar" {
+		return r.Arabic
+	}
+	if t, ok := r.Translations[lang]; ok {
+		return t
+	}
+	return r.Translations[DefaultLang]
+}
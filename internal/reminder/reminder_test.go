@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCollection_Complete(t *testing.T) {
+	for i, r := range All() {
+		if r.Arabic == "" {
+			t.Errorf("reminder %d has no Arabic text", i)
+		}
+		if r.Source == "" {
+			t.Errorf("reminder %d has no source", i)
+		}
+		for _, lang := range []string{"en", "fr", "id", "tr", "ur"} {
+			if r.Translations[lang] == "" {
+				t.Errorf("reminder %d (%s) has no %s translation", i, r.Source, lang)
+			}
+		}
+	}
+}
+
+func TestForDate_StableWithinDay(t *testing.T) {
+	morning := time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	if ForDate(morning).Source != ForDate(night).Source {
+		t.Error("ForDate should return the same reminder for the whole day")
+	}
+}
+
+func TestForDate_Rotates(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if ForDate(day).Source == ForDate(day.AddDate(0, 0, 1)).Source {
+		t.Error("ForDate should rotate to a different reminder on consecutive days")
+	}
+	if ForDate(day).Source != ForDate(day.AddDate(0, 0, len(collection))).Source {
+		t.Error("ForDate should cycle through the whole collection")
+	}
+}
+
+func TestText_Languages(t *testing.T) {
+	r := All()[0]
+
+	if got := r.Text("ar"); got != r.Arabic {
+		t.Errorf("Text(ar) = %q, want the Arabic original", got)
+	}
+	if got := r.Text("en"); got != r.Translations["en"] {
+		t.Errorf("Text(en) = %q", got)
+	}
+	if got := r.Text("xx"); got != r.Translations[DefaultLang] {
+		t.Errorf("Text(xx) = %q, want %s fallback", got, DefaultLang)
+	}
+}
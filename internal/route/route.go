@@ -0,0 +1,54 @@
+// Package route does great-circle geometry for flight paths: distances and
+// positions part way between two points.
+package route
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0
+
+// Point is a position in degrees.
+type Point struct {
+	Lat, Lon float64
+}
+
+// Distance returns the great-circle distance from a to b in kilometres.
+func Distance(a, b Point) float64 {
+	return earthRadiusKm * angle(a, b)
+}
+
+// Intermediate returns the point a fraction f (0-1) of the way from a to b
+// along the great circle.
+func Intermediate(a, b Point, f float64) Point {
+	d := angle(a, b)
+	if d == 0 {
+		return a
+	}
+	phi1, lambda1 := radians(a.Lat), radians(a.Lon)
+	phi2, lambda2 := radians(b.Lat), radians(b.Lon)
+
+	ka := math.Sin((1-f)*d) / math.Sin(d)
+	kb := math.Sin(f*d) / math.Sin(d)
+	x := ka*math.Cos(phi1)*math.Cos(lambda1) + kb*math.Cos(phi2)*math.Cos(lambda2)
+	y := ka*math.Cos(phi1)*math.Sin(lambda1) + kb*math.Cos(phi2)*math.Sin(lambda2)
+	z := ka*math.Sin(phi1) + kb*math.Sin(phi2)
+
+	return Point{
+		Lat: degrees(math.Atan2(z, math.Hypot(x, y))),
+		Lon: degrees(math.Atan2(y, x)),
+	}
+}
+
+// angle returns the central angle between a and b in radians (haversine).
+func angle(a, b Point) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dPhi := phi2 - phi1
+	dLambda := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
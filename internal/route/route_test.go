@@ -0,0 +1,60 @@
+package route
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	london = Point{Lat: 51.4700, Lon: -0.4543}
+	dubai  = Point{Lat: 25.2532, Lon: 55.3657}
+)
+
+func TestDistance(t *testing.T) {
+	// Heathrow to Dubai International is roughly 5,500 km.
+	if got := Distance(london, dubai); math.Abs(got-5500) > 20 {
+		t.Errorf("Distance(LHR, DXB) = %.0f km, want ~5500", got)
+	}
+	if got := Distance(london, london); got != 0 {
+		t.Errorf("Distance(LHR, LHR) = %f, want 0", got)
+	}
+}
+
+func TestIntermediate(t *testing.T) {
+	if got := Intermediate(london, dubai, 0); math.Abs(got.Lat-london.Lat) > 1e-9 || math.Abs(got.Lon-london.Lon) > 1e-9 {
+		t.Errorf("Intermediate(0) = %+v, want %+v", got, london)
+	}
+	if got := Intermediate(london, dubai, 1); math.Abs(got.Lat-dubai.Lat) > 1e-9 || math.Abs(got.Lon-dubai.Lon) > 1e-9 {
+		t.Errorf("Intermediate(1) = %+v, want %+v", got, dubai)
+	}
+
+	// The midpoint is equidistant from both ends, on the great circle.
+	mid := Intermediate(london, dubai, 0.5)
+	total := Distance(london, dubai)
+	if a, b := Distance(london, mid), Distance(mid, dubai); math.Abs(a-total/2) > 0.1 || math.Abs(b-total/2) > 0.1 {
+		t.Errorf("midpoint distances = %.1f, %.1f, want %.1f each", a, b, total/2)
+	}
+
+	// Great circles bow towards the pole: the midpoint lies north of the
+	// average latitude.
+	if avg := (london.Lat + dubai.Lat) / 2; mid.Lat <= avg {
+		t.Errorf("midpoint latitude = %.2f, want north of %.2f", mid.Lat, avg)
+	}
+
+	if got := Intermediate(dubai, dubai, 0.5); got != dubai {
+		t.Errorf("Intermediate(same point) = %+v, want %+v", got, dubai)
+	}
+}
+
+func TestIntermediate_DateLine(t *testing.T) {
+	tokyo := Point{Lat: 35.5494, Lon: 139.7798}
+	sfo := Point{Lat: 37.6213, Lon: -122.3790}
+	mid := Intermediate(tokyo, sfo, 0.5)
+	// The short way crosses the Pacific, not Eurasia.
+	if math.Abs(mid.Lon) < 150 {
+		t.Errorf("midpoint longitude = %.2f, want near the date line", mid.Lon)
+	}
+	if mid.Lat < 45 {
+		t.Errorf("midpoint latitude = %.2f, want well north of the endpoints", mid.Lat)
+	}
+}
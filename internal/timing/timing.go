@@ -0,0 +1,230 @@
+// Package timing measures where a run of the CLI spends its time — loading
+// the config, reading and writing the cache, waiting on the network and
+// rendering — and keeps the breakdowns of recent runs, so the slow ones a
+// status bar sees now and then can be told apart from the usual ones.
+//
+// Recent runs are stored as a single JSON file under
+// ~/.local/state/prayer-times/ (respects $XDG_STATE_HOME).
+package timing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The phases a run's time is split into. Render is whatever the others
+// don't account for: mostly formatting and writing the output.
+const (
+	Config  = "config"
+	Cache   = "cache"
+	Network = "network"
+	Render  = "render"
+	Total   = "total"
+)
+
+// Phases are the phases of a Breakdown, in the order they're shown.
+var Phases = []string{Config, Cache, Network, Render, Total}
+
+// MaxRuns is how many runs of each command the stats file keeps.
+const MaxRuns = 100
+
+// Recorder adds up the time spent in each phase of a run. It is safe for
+// concurrent use, and a nil Recorder records nothing.
+type Recorder struct {
+	mu    sync.Mutex
+	start time.Time
+	spent map[string]time.Duration
+}
+
+// New returns a Recorder whose run starts now.
+func New() *Recorder {
+	return &Recorder{start: time.Now(), spent: make(map[string]time.Duration)}
+}
+
+// Add records d spent in phase.
+func (r *Recorder) Add(phase string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.spent[phase] += d
+	r.mu.Unlock()
+}
+
+// Start begins timing phase and returns the func that ends it.
+func (r *Recorder) Start(phase string) func() {
+	if r == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { r.Add(phase, time.Since(start)) }
+}
+
+// Breakdown is the milliseconds a run spent in each phase.
+type Breakdown map[string]float64
+
+// Breakdown returns the time spent in each phase so far. Render is the total
+// less the other phases; it is never negative, though concurrent requests
+// can make the network's share larger than the time they took together.
+func (r *Recorder) Breakdown() Breakdown {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := time.Since(r.start)
+	b := Breakdown{Total: ms(total)}
+	rest := total
+	for _, phase := range []string{Config, Cache, Network} {
+		b[phase] = ms(r.spent[phase])
+		rest -= r.spent[phase]
+	}
+	b[Render] = ms(max(rest, 0))
+	return b
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// String returns b as "config 1.2ms · cache 0.3ms · …".
+func (b Breakdown) String() string {
+	parts := make([]string, len(Phases))
+	for i, phase := range Phases {
+		parts[i] = fmt.Sprintf("%s %.1fms", phase, b[phase])
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Run is one recorded run of a command.
+type Run struct {
+	Command string    `json:"command"`
+	At      time.Time `json:"at"`
+	Ms      Breakdown `json:"ms"`
+}
+
+// Stats are the recorded runs, oldest first.
+type Stats struct {
+	Runs []Run `json:"runs"`
+}
+
+// Add records run, dropping the oldest runs of its command beyond MaxRuns.
+func (s *Stats) Add(run Run) {
+	s.Runs = append(s.Runs, run)
+	n := 0
+	for i := len(s.Runs) - 1; i >= 0; i-- {
+		if s.Runs[i].Command == run.Command {
+			n++
+			if n > MaxRuns {
+				s.Runs = append(s.Runs[:i], s.Runs[i+1:]...)
+			}
+		}
+	}
+}
+
+// P95 returns the 95th percentile of each phase over the recorded runs of
+// command, and how many runs there were.
+func (s *Stats) P95(command string) (Breakdown, int) {
+	var runs []Run
+	for _, r := range s.Runs {
+		if r.Command == command {
+			runs = append(runs, r)
+		}
+	}
+	if len(runs) == 0 {
+		return nil, 0
+	}
+	b := make(Breakdown, len(Phases))
+	values := make([]float64, len(runs))
+	for _, phase := range Phases {
+		for i, r := range runs {
+			values[i] = r.Ms[phase]
+		}
+		sort.Float64s(values)
+		// Nearest rank: the smallest value at least 95% of runs are within.
+		rank := (95*len(values) + 99) / 100
+		b[phase] = values[rank-1]
+	}
+	return b, len(runs)
+}
+
+// DefaultPath returns the default stats file location.
+// It respects $XDG_STATE_HOME if set, otherwise uses ~/.local/state/.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot determine home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "prayer-times", "timing.json"), nil
+}
+
+// Load reads the stats at path. A missing file yields empty stats.
+func Load(path string) (*Stats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Stats{}, nil
+		}
+		return nil, fmt.Errorf("failed to read timing stats: %w", err)
+	}
+
+	var s Stats
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("corrupt timing stats %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the stats to path, creating parent directories as needed.
+// Concurrent saves don't corrupt the file; the last one wins.
+func (s *Stats) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cannot create directory for timing stats: %w", err)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal timing stats: %w", err)
+	}
+	if err := writeFile(path, data); err != nil {
+		return fmt.Errorf("failed to write timing stats: %w", err)
+	}
+	return nil
+}
+
+// writeFile atomically writes data to path via a temp file and rename, so
+// runs saving at once leave one of their files whole rather than both
+// interleaved.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package timing
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	r := New()
+	r.Add(Network, 40*time.Millisecond)
+	r.Add(Network, 10*time.Millisecond)
+	r.Start(Cache)()
+	time.Sleep(60 * time.Millisecond)
+
+	b := r.Breakdown()
+	if b[Network] != 50 {
+		t.Errorf("network = %v, want 50", b[Network])
+	}
+	if b[Total] < 60 || b[Render] < 9 {
+		t.Errorf("Breakdown() = %v, want a total of 60ms or more and the rest as render", b)
+	}
+	if got := b.String(); !strings.HasPrefix(got, "config 0.0ms · cache ") || !strings.Contains(got, " · network 50.0ms · render ") {
+		t.Errorf("String() = %q", got)
+	}
+
+	var none *Recorder
+	none.Add(Network, time.Second)
+	none.Start(Cache)()
+}
+
+func TestStats(t *testing.T) {
+	var s Stats
+	for i := 1; i <= 20; i++ {
+		s.Add(Run{Command: "prayer-times next", Ms: Breakdown{Network: float64(i), Total: float64(i * 10)}})
+	}
+	s.Add(Run{Command: "prayer-times list", Ms: Breakdown{Total: 1000}})
+
+	p95, n := s.P95("prayer-times next")
+	if n != 20 || p95[Network] != 19 || p95[Total] != 190 {
+		t.Errorf("P95() = %v, %d; want network 19, total 190 over 20 runs", p95, n)
+	}
+	if _, n := s.P95("prayer-times bar"); n != 0 {
+		t.Errorf("P95() of an unrecorded command = %d runs, want 0", n)
+	}
+
+	for i := 0; i < MaxRuns; i++ {
+		s.Add(Run{Command: "prayer-times next", Ms: Breakdown{Total: 5}})
+	}
+	if _, n := s.P95("prayer-times next"); n != MaxRuns {
+		t.Errorf("runs kept = %d, want %d", n, MaxRuns)
+	}
+	if _, n := s.P95("prayer-times list"); n != 1 {
+		t.Errorf("other command's runs kept = %d, want 1", n)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "timing.json")
+	s, err := Load(path)
+	if err != nil || len(s.Runs) != 0 {
+		t.Fatalf("Load() of a missing file = %v, %v; want empty stats", s, err)
+	}
+	s.Add(Run{Command: "prayer-times", At: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Ms: Breakdown{Total: 12.5}})
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Runs) != 1 || got.Runs[0].Ms[Total] != 12.5 {
+		t.Errorf("Load() = %+v", got)
+	}
+}
+
+// TestSave_Concurrent checks that runs saving at once leave a readable
+// file and no temp files behind.
+func TestSave_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timing.json")
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var s Stats
+			for range 200 {
+				s.Add(Run{Command: "prayer-times " + strconv.Itoa(i), Ms: Breakdown{Total: float64(i)}})
+			}
+			if err := s.Save(path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := Load(path); err != nil {
+		t.Fatalf("Load() after concurrent saves: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files after saving, want only timing.json", len(entries))
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	if got, err := DefaultPath(); err != nil || got != "/tmp/state/prayer-times/timing.json" {
+		t.Errorf("DefaultPath() = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,113 @@
+// Package tzlookup maps coordinates to an IANA timezone without a network
+// round trip, using a coarse boundary index bundled into the binary.
+package tzlookup
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	// Bundle the zone database so resolved zones load on systems without one.
+	_ "time/tzdata"
+)
+
+//go:embed zones.txt
+var zonesData []byte
+
+// box is one rectangle of the boundary index.
+type box struct {
+	zone                           string
+	minLat, maxLat, minLon, maxLon float64
+}
+
+func (b box) contains(lat, lon float64) bool {
+	return lat >= b.minLat && lat <= b.maxLat && lon >= b.minLon && lon <= b.maxLon
+}
+
+var (
+	loadOnce sync.Once
+	boxes    []box
+)
+
+// index returns the parsed boundary index, parsing it on first use.
+func index() []box {
+	loadOnce.Do(func() {
+		var err error
+		boxes, err = parse(zonesData)
+		if err != nil {
+			panic("tzlookup: " + err.Error())
+		}
+	})
+	return boxes
+}
+
+// parse reads the "zone min-lat max-lat min-lon max-lon" lines of the index.
+func parse(data []byte) ([]box, error) {
+	var out []box
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("line %d: want 5 fields, got %d", n, len(fields))
+		}
+		var v [4]float64
+		for i, f := range fields[1:] {
+			x, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			v[i] = x
+		}
+		out = append(out, box{zone: fields[0], minLat: v[0], maxLat: v[1], minLon: v[2], maxLon: v[3]})
+	}
+	return out, sc.Err()
+}
+
+// Lookup returns the IANA timezone for (lat, lon). ok is false when the
+// point falls outside the bundled index; the returned zone is then the
+// fixed-offset "Etc/GMT±N" zone for the longitude, which is right at sea
+// but only approximate on land.
+func Lookup(lat, lon float64) (zone string, ok bool) {
+	for _, b := range index() {
+		if b.contains(lat, lon) {
+			return b.zone, true
+		}
+	}
+	return Nautical(lon), false
+}
+
+// Nautical returns the fixed-offset zone for lon, one hour per 15 degrees.
+// Etc zones use POSIX signs, so UTC+3 is "Etc/GMT-3".
+func Nautical(lon float64) string {
+	offset := int(math.Round(lon / 15))
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", offset)
+	case offset < 0:
+		return fmt.Sprintf("Etc/GMT+%d", -offset)
+	default:
+		return "Etc/GMT"
+	}
+}
+
+// Zones returns every zone named in the index, in first-seen order.
+func Zones() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, b := range index() {
+		if !seen[b.zone] {
+			seen[b.zone] = true
+			out = append(out, b.zone)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package tzlookup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     string
+	}{
+		{"Mecca", 21.4225, 39.8262, "Asia/Riyadh"},
+		{"Dubai", 25.2048, 55.2708, "Asia/Dubai"},
+		{"Doha", 25.2854, 51.5310, "Asia/Qatar"},
+		{"Cairo", 30.0444, 31.2357, "Africa/Cairo"},
+		{"Istanbul", 41.0082, 28.9784, "Europe/Istanbul"},
+		{"Tehran", 35.6892, 51.3890, "Asia/Tehran"},
+		{"Karachi", 24.8607, 67.0011, "Asia/Karachi"},
+		{"Delhi", 28.6139, 77.2090, "Asia/Kolkata"},
+		{"Kolkata", 22.5726, 88.3639, "Asia/Kolkata"},
+		{"Dhaka", 23.8103, 90.4125, "Asia/Dhaka"},
+		{"Kuala Lumpur", 3.1390, 101.6869, "Asia/Kuala_Lumpur"},
+		{"Singapore", 1.3521, 103.8198, "Asia/Singapore"},
+		{"Jakarta", -6.2088, 106.8456, "Asia/Jakarta"},
+		{"Makassar", -5.1477, 119.4327, "Asia/Makassar"},
+		{"Beijing", 39.9042, 116.4074, "Asia/Shanghai"},
+		{"Tokyo", 35.6762, 139.6503, "Asia/Tokyo"},
+		{"Moscow", 55.7558, 37.6173, "Europe/Moscow"},
+		{"London", 51.5074, -0.1278, "Europe/London"},
+		{"Paris", 48.8566, 2.3522, "Europe/Paris"},
+		{"Berlin", 52.5200, 13.4050, "Europe/Berlin"},
+		{"Casablanca", 33.5731, -7.5898, "Africa/Casablanca"},
+		{"Lagos", 6.5244, 3.3792, "Africa/Lagos"},
+		{"Nairobi", -1.2921, 36.8219, "Africa/Nairobi"},
+		{"Johannesburg", -26.2041, 28.0473, "Africa/Johannesburg"},
+		{"New York", 40.7128, -74.0060, "America/New_York"},
+		{"Chicago", 41.8781, -87.6298, "America/Chicago"},
+		{"Denver", 39.7392, -104.9903, "America/Denver"},
+		{"Phoenix", 33.4484, -112.0740, "America/Phoenix"},
+		{"Los Angeles", 34.0522, -118.2437, "America/Los_Angeles"},
+		{"Toronto", 43.6532, -79.3832, "America/Toronto"},
+		{"Mexico City", 19.4326, -99.1332, "America/Mexico_City"},
+		{"Sao Paulo", -23.5505, -46.6333, "America/Sao_Paulo"},
+		{"Buenos Aires", -34.6037, -58.3816, "America/Argentina/Buenos_Aires"},
+		{"Sydney", -33.8688, 151.2093, "Australia/Sydney"},
+		{"Perth", -31.9505, 115.8605, "Australia/Perth"},
+		{"Auckland", -36.8485, 174.7633, "Pacific/Auckland"},
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(tt.lat, tt.lon)
+		if !ok || got != tt.want {
+			t.Errorf("Lookup(%s) = %q, %v; want %q, true", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestLookup_Fallback(t *testing.T) {
+	// Mid-Pacific, far from any box.
+	got, ok := Lookup(0, -150)
+	if ok {
+		t.Errorf("Lookup(0, -150) ok = true, want false")
+	}
+	if got != "Etc/GMT+10" {
+		t.Errorf("Lookup(0, -150) = %q, want Etc/GMT+10", got)
+	}
+}
+
+func TestNautical(t *testing.T) {
+	tests := []struct {
+		lon  float64
+		want string
+	}{
+		{0, "Etc/GMT"},
+		{7.4, "Etc/GMT"},
+		{45, "Etc/GMT-3"},
+		{-75, "Etc/GMT+5"},
+		{180, "Etc/GMT-12"},
+	}
+	for _, tt := range tests {
+		if got := Nautical(tt.lon); got != tt.want {
+			t.Errorf("Nautical(%v) = %q, want %q", tt.lon, got, tt.want)
+		}
+	}
+}
+
+func TestZonesLoad(t *testing.T) {
+	for _, z := range Zones() {
+		if _, err := time.LoadLocation(z); err != nil {
+			t.Errorf("zone %q: %v", z, err)
+		}
+	}
+	for lon := -180.0; lon <= 180; lon += 15 {
+		if _, err := time.LoadLocation(Nautical(lon)); err != nil {
+			t.Errorf("Nautical(%v): %v", lon, err)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, in := range []string{
+		"Asia/Riyadh 1 2 3",
+		"Asia/Riyadh 1 2 3 x",
+	} {
+		if _, err := parse([]byte(in)); err == nil {
+			t.Errorf("parse(%q) expected error", in)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package webhook builds and sends the HTTP notifications posted at prayer
+// time and shortly before it.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/i18n"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+// Event kinds.
+const (
+	KindPrayer   = "prayer"   // the prayer time has begun
+	KindReminder = "reminder" // the prayer begins in MinutesBefore minutes
+)
+
+// Templates are the built-in body templates. "auto" picks slack or discord
+// from the webhook's host and json otherwise.
+var Templates = []string{"auto", "json", "slack", "discord"}
+
+// Event is one notification.
+type Event struct {
+	Kind          string    `json:"event"`
+	Prayer        string    `json:"prayer"`
+	Time          time.Time `json:"time"`
+	Location      string    `json:"location"`
+	Hijri         string    `json:"hijri,omitempty"`
+	MinutesBefore int       `json:"minutes_before,omitempty"`
+
+	// Lang and Layout localize the text made from the event: its language
+	// (see i18n.Langs; English when empty) and clock layout ("15:04" when
+	// empty). The JSON event stays in English.
+	Lang   string `json:"-"`
+	Layout string `json:"-"`
+}
+
+// Message is a one-line human-readable summary of e, as sent to chat
+// services, in e.Lang.
+func (e Event) Message() string {
+	name, clock, where := i18n.PrayerName(e.Lang, e.Prayer), e.clock(), i18n.Isolate(e.Lang, e.Location)
+	if e.Kind == KindReminder {
+		return i18n.Sprintf(e.Lang, "notify.reminder", name, i18n.Digits(e.Lang, strconv.Itoa(e.MinutesBefore)), clock, where)
+	}
+	return i18n.Sprintf(e.Lang, "notify.prayer", name, clock, where)
+}
+
+// clock is the prayer time in e.Layout and e.Lang.
+func (e Event) clock() string {
+	layout := e.Layout
+	if layout == "" {
+		layout = "15:04"
+	}
+	return i18n.Clock(e.Lang, e.Time, layout)
+}
+
+// ParseURLs splits a comma-separated list of webhook URLs and checks that
+// each is an absolute http or https URL.
+func ParseURLs(value string) ([]string, error) {
+	var urls []string
+	for _, raw := range strings.Split(value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q: must be an http or https URL", raw)
+		}
+		urls = append(urls, raw)
+	}
+	return urls, nil
+}
+
+// ParseTemplate checks tmpl: a name from Templates, or a Go text/template
+// for the request body over TemplateData, with a json function that quotes
+// a value as JSON.
+func ParseTemplate(tmpl string) error {
+	if isBuiltin(tmpl) {
+		return nil
+	}
+	if !strings.Contains(tmpl, "{{") {
+		return fmt.Errorf("invalid webhook template %q: must be one of %s, or a template such as {{json .Message}}", tmpl, strings.Join(Templates, ", "))
+	}
+	_, err := parse("webhook template", tmpl)
+	return err
+}
+
+// ParseMessageTemplate checks tmpl, the setting named key: empty for the
+// default, or a Go text/template for a chat message or notification title
+// over TemplateData, e.g. "🕌 {{.Name}} at {{.Clock}}".
+func ParseMessageTemplate(key, tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	_, err := parse(key, tmpl)
+	return err
+}
+
+// Text renders the chat message for e with template tmpl (see
+// ParseMessageTemplate), or returns e.Message() when tmpl is empty.
+func Text(tmpl string, e Event) (string, error) {
+	if tmpl == "" {
+		return e.Message(), nil
+	}
+	return render("message template", tmpl, e)
+}
+
+// Title renders the title of a notification for e with template tmpl, or
+// returns the prayer's name in e.Lang when tmpl is empty.
+func Title(tmpl string, e Event) (string, error) {
+	if tmpl == "" {
+		return i18n.PrayerName(e.Lang, e.Prayer), nil
+	}
+	return render("title template", tmpl, e)
+}
+
+func render(name, tmpl string, e Event) (string, error) {
+	t, err := parse(name, tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, newTemplateData(e)); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+func isBuiltin(tmpl string) bool {
+	if tmpl == "" {
+		return true
+	}
+	for _, t := range Templates {
+		if t == tmpl {
+			return true
+		}
+	}
+	return false
+}
+
+func parse(name, tmpl string) (*template.Template, error) {
+	t, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// Body renders the request body for e sent to rawURL with template tmpl.
+func Body(tmpl, rawURL string, e Event) ([]byte, error) {
+	if tmpl == "" || tmpl == "auto" {
+		tmpl = detect(rawURL)
+	}
+	switch tmpl {
+	case "json":
+		return json.Marshal(e)
+	case "slack":
+		return json.Marshal(map[string]string{"text": e.Message()})
+	case "discord":
+		return json.Marshal(map[string]string{"content": e.Message()})
+	}
+
+	t, err := parse("webhook template", tmpl)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, newTemplateData(e)); err != nil {
+		return nil, fmt.Errorf("webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// TemplateData is what body, message and title templates see: the fields
+// of the event (.Prayer, .Time, .Location, .Hijri, .Kind, .MinutesBefore),
+// its default .Message, and the fields of next's --format templates for its
+// prayer. .Time stays a time, for layouts such as {{.Time.Format "3:04 PM"}}.
+// .Message, .Name, .Clock and .Remaining are in the event's language; .Prayer
+// is always the API's English name.
+type TemplateData struct {
+	Event
+	Message   string // the default message, e.g. "It's time for Asr (15:45) in Mecca, SA"
+	Name      string // the prayer, e.g. "Asr" or "العصر"
+	ShortName string // its abbreviation, e.g. "A"
+	Clock     string // the prayer time, e.g. "15:45" or "3:45 PM"
+	Remaining string // from the event to the prayer: "10m" for a 10-minute reminder, "0m" at the prayer
+	Hours     int    // whole hours of Remaining
+	Minutes   int    // minutes of Remaining after hours
+	Reminder  bool   // the event is a reminder, not the prayer itself
+}
+
+// newTemplateData returns the template data for e.
+func newTemplateData(e Event) TemplateData {
+	var before time.Duration
+	if e.Kind == KindReminder {
+		before = time.Duration(e.MinutesBefore) * time.Minute
+	}
+	return TemplateData{
+		Event:     e,
+		Message:   e.Message(),
+		Name:      i18n.PrayerName(e.Lang, e.Prayer),
+		ShortName: prayer.ShortNames[e.Prayer],
+		Clock:     e.clock(),
+		Remaining: i18n.Duration(e.Lang, before),
+		Hours:     int(before.Hours()),
+		Minutes:   int(before.Minutes()) % 60,
+		Reminder:  e.Kind == KindReminder,
+	}
+}
+
+// detect picks the built-in template for a webhook URL's service.
+func detect(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "json"
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return "slack"
+	case (host == "discord.com" || host == "discordapp.com") && strings.HasPrefix(u.Path, "/api/webhooks/"):
+		return "discord"
+	default:
+		return "json"
+	}
+}
+
+// Post sends body to rawURL as JSON and fails on a non-2xx response.
+func Post(client *http.Client, rawURL string, body []byte) error {
+	resp, err := client.Post(rawURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", rawURL, resp.Status)
+	}
+	return nil
+}
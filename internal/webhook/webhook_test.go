@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent() Event {
+	return Event{
+		Kind:     KindPrayer,
+		Prayer:   "Asr",
+		Time:     time.Date(2026, 2, 10, 15, 45, 0, 0, time.FixedZone("+03", 3*3600)),
+		Location: "Mecca, SA",
+		Hijri:    "22 Shaʿbān 1447 AH",
+	}
+}
+
+func TestMessage(t *testing.T) {
+	e := testEvent()
+	if got, want := e.Message(), "It's time for Asr (15:45) in Mecca, SA"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	e.Kind, e.MinutesBefore = KindReminder, 10
+	if got, want := e.Message(), "Asr in 10 minutes (15:45) in Mecca, SA"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+
+	// In Arabic, with its digits and the place name isolated from the
+	// right-to-left sentence.
+	e.Lang, e.Layout = "ar", "3:04 PM"
+	if got, want := e.Message(), "صلاة العصر بعد ١٠ دقيقة (٣:٤٥ م) في \u2068Mecca, SA\u2069"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	e.Kind = KindPrayer
+	if got, err := Title("", e); err != nil || got != "العصر" {
+		t.Errorf("Title(\"\") = %q, %v; want the prayer's Arabic name", got, err)
+	}
+}
+
+func TestParseURLs(t *testing.T) {
+	urls, err := ParseURLs("https://example.com/hook, http://ha.local:8123/api/webhook/prayer,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 2 || urls[1] != "http://ha.local:8123/api/webhook/prayer" {
+		t.Errorf("ParseURLs() = %v", urls)
+	}
+	for _, bad := range []string{"example.com/hook", "ftp://example.com", "https://"} {
+		if _, err := ParseURLs(bad); err == nil {
+			t.Errorf("ParseURLs(%q) should error", bad)
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	for _, ok := range []string{"", "auto", "json", "slack", "discord", `{"msg": {{json .Message}}}`} {
+		if err := ParseTemplate(ok); err != nil {
+			t.Errorf("ParseTemplate(%q) error: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"teams", "{{.Prayer"} {
+		if err := ParseTemplate(bad); err == nil {
+			t.Errorf("ParseTemplate(%q) should error", bad)
+		}
+	}
+}
+
+func TestBody(t *testing.T) {
+	e := testEvent()
+	tests := []struct {
+		tmpl, url, want string
+	}{
+		{"auto", "https://hooks.slack.com/services/T0/B0/x", `{"text":"It's time for Asr (15:45) in Mecca, SA"}`},
+		{"auto", "https://discord.com/api/webhooks/1/x", `{"content":"It's time for Asr (15:45) in Mecca, SA"}`},
+		{"slack", "https://example.com", `{"text":"It's time for Asr (15:45) in Mecca, SA"}`},
+		{`{"state": {{json .Prayer}}, "at": "{{.Time.Format "15:04"}}"}`, "https://example.com", `{"state": "Asr", "at": "15:45"}`},
+	}
+	for _, tt := range tests {
+		got, err := Body(tt.tmpl, tt.url, e)
+		if err != nil {
+			t.Errorf("Body(%q) error: %v", tt.tmpl, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("Body(%q, %q) = %s, want %s", tt.tmpl, tt.url, got, tt.want)
+		}
+	}
+
+	// The json template is the event itself, e.g. for Home Assistant.
+	got, err := Body("auto", "http://ha.local:8123/api/webhook/prayer", e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["event"] != "prayer" || decoded["prayer"] != "Asr" || decoded["time"] != "2026-02-10T15:45:00+03:00" || decoded["hijri"] == nil {
+		t.Errorf("json body = %s", got)
+	}
+}
+
+func TestText(t *testing.T) {
+	e := testEvent()
+	if got, err := Text("", e); err != nil || got != e.Message() {
+		t.Errorf("Text(\"\") = %q, %v; want the default message", got, err)
+	}
+	if got, err := Text(`🕌 {{.Prayer}} at {{.Time.Format "15:04"}}`, e); err != nil || got != "🕌 Asr at 15:45" {
+		t.Errorf("Text() = %q, %v", got, err)
+	}
+	if err := ParseMessageTemplate("ntfy_template", "{{.Prayer"); err == nil || !strings.Contains(err.Error(), "ntfy_template") {
+		t.Errorf("ParseMessageTemplate() error = %v, want one naming the key", err)
+	}
+
+	// Templates get next's --format fields too, and can word each kind of
+	// event their own way.
+	tmpl := `{{if .Reminder}}{{.ShortName}} in {{.Remaining}}{{else}}{{.Name}} at {{.Clock}}, {{.Hijri}}{{end}}`
+	if got, err := Text(tmpl, e); err != nil || got != "Asr at 15:45, "+e.Hijri {
+		t.Errorf("Text() for the prayer = %q, %v", got, err)
+	}
+	e.Kind, e.MinutesBefore = KindReminder, 75
+	if got, err := Text(tmpl, e); err != nil || got != "A in 1h 15m" {
+		t.Errorf("Text() for the reminder = %q, %v", got, err)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	e := testEvent()
+	if got, err := Title("", e); err != nil || got != "Asr" {
+		t.Errorf("Title(\"\") = %q, %v; want the prayer's name", got, err)
+	}
+	if got, err := Title("🕌 {{.Name}} · {{.Location}}", e); err != nil || got != "🕌 Asr · Mecca, SA" {
+		t.Errorf("Title() = %q, %v", got, err)
+	}
+}
+
+func TestPost(t *testing.T) {
+	var gotBody, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody, gotType = string(data), r.Header.Get("Content-Type")
+		if r.URL.Path == "/fail" {
+			http.Error(w, "nope", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	if err := Post(srv.Client(), srv.URL+"/ok", []byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if gotBody != `{"a":1}` || gotType != "application/json" {
+		t.Errorf("server got %q (%s)", gotBody, gotType)
+	}
+	if err := Post(srv.Client(), srv.URL+"/fail", nil); err == nil {
+		t.Error("Post should fail on a 400 response")
+	}
+}
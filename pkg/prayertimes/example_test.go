@@ -0,0 +1,40 @@
+package prayertimes_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/pkg/prayertimes"
+)
+
+func ExampleNext() {
+	base := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	prayers := []prayertimes.Prayer{
+		{Name: "Fajr", Time: base.Add(5*time.Hour + 17*time.Minute)},
+		{Name: "Dhuhr", Time: base.Add(12*time.Hour + 13*time.Minute)},
+		{Name: "Asr", Time: base.Add(15*time.Hour + 2*time.Minute)},
+	}
+
+	now := base.Add(13 * time.Hour)
+	if next := prayertimes.Next(prayers, now); next != nil {
+		fmt.Println(prayertimes.Format(*next, now, prayertimes.FormatFull, "15:04"))
+	}
+	// Output: Asr 15:02 (2h 2m)
+}
+
+func ExampleClient_Day() {
+	var c prayertimes.Client
+	day, err := c.Day(time.Now(), prayertimes.Location{City: "London", Country: "UK"}, &prayertimes.Options{Method: 2, School: 0})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	prayers, err := day.Prayers("Fajr", "Maghrib")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, p := range prayers {
+		fmt.Println(p.Name, p.Time.Format("15:04"))
+	}
+}
@@ -0,0 +1,377 @@
+// Package prayertimes looks up Islamic prayer times from the Al Adhan API
+// and answers the usual questions about them: which prayer is next, which
+// one is current, and how long until the next one.
+//
+// It is the library behind the prayer-times CLI, exposed so other Go
+// programs can embed prayer-time lookups without shelling out:
+//
+//	var c prayertimes.Client
+//	day, err := c.Day(time.Now(), prayertimes.Location{City: "London", Country: "UK"}, nil)
+//	if err != nil {
+//		return err
+//	}
+//	prayers, err := day.Prayers()
+//	if err != nil {
+//		return err
+//	}
+//	if next := prayertimes.Next(prayers, time.Now()); next != nil {
+//		fmt.Println(prayertimes.Format(*next, time.Now(), prayertimes.FormatFull, "15:04"))
+//	}
+//
+// The Context variants of the Client methods can be cancelled or given a
+// deadline. Results are not cached; callers that poll should keep the Day
+// around.
+// ToHijri and FromHijri convert between the Gregorian and Hijri calendars
+// locally, without the API.
+package prayertimes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/smokyabdulrahman/prayer-times/internal/api"
+	"github.com/smokyabdulrahman/prayer-times/internal/hijri"
+	"github.com/smokyabdulrahman/prayer-times/internal/prayer"
+)
+
+// Display formats accepted by Format. Any format containing "{{" is treated
+// as a Go template with the fields .Name, .ShortName, .Time, .Remaining,
+// .Hours, .Minutes and .Seconds.
+const (
+	FormatTimeRemaining        = prayer.FormatTimeRemaining
+	FormatTimeRemainingSeconds = prayer.FormatTimeRemainingSeconds
+	FormatNextPrayerTime       = prayer.FormatNextPrayerTime
+	FormatNameAndTime          = prayer.FormatNameAndTime
+	FormatNameAndRemaining     = prayer.FormatNameAndRemaining
+	FormatShortNameAndTime     = prayer.FormatShortNameAndTime
+	FormatShortNameAndRemain   = prayer.FormatShortNameAndRemain
+	FormatFull                 = prayer.FormatFull
+)
+
+// AllPrayers lists every prayer and event the API returns.
+func AllPrayers() []string {
+	return append([]string(nil), prayer.AllPrayerNames...)
+}
+
+// DefaultPrayers lists the prayers returned by Day.Prayers when no names are given.
+func DefaultPrayers() []string {
+	return append([]string(nil), prayer.DefaultPrayerNames...)
+}
+
+// Prayer is a single prayer and the moment it begins.
+type Prayer struct {
+	Name string
+	Time time.Time
+}
+
+// Location identifies where to compute prayer times. Coordinates take
+// precedence when HasCoordinates is set, or either of them is nonzero;
+// otherwise City and Country are used. Set HasCoordinates (or use
+// Coordinates) for a location at latitude 0, longitude 0.
+type Location struct {
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+	City           string
+	Country        string
+}
+
+// Coordinates returns the Location at lat, lon, which may both be zero.
+func Coordinates(lat, lon float64) Location {
+	return Location{Latitude: lat, Longitude: lon, HasCoordinates: true}
+}
+
+func (l Location) useCoordinates() bool {
+	return l.HasCoordinates || l.Latitude != 0 || l.Longitude != 0
+}
+
+// Options tunes the calculation. A nil *Options lets the API pick its defaults.
+type Options struct {
+	// Method is the Al Adhan calculation method ID (e.g. 2 for ISNA,
+	// 4 for Umm Al-Qura). Negative values use the API default.
+	Method int
+	// School is 0 for Shafi (standard) or 1 for Hanafi Asr.
+	// Negative values use the API default.
+	School int
+	// FajrAngle, MaghribAngle and IshaAngle, when any is set, switch to the
+	// API's custom method with these angles in degrees. Zero leaves an
+	// angle at the custom method's default.
+	FajrAngle    float64
+	MaghribAngle float64
+	IshaAngle    float64
+	// Tune shifts prayers by whole minutes, keyed by prayer name, e.g.
+	// {"Fajr": 3, "Asr": -2}. Firstthird and Lastthird cannot be tuned.
+	Tune map[string]int
+	// LatitudeAdjustment picks how Fajr and Isha are placed at high
+	// latitudes: 1 middle of the night, 2 one-seventh of the night,
+	// 3 angle-based. Zero uses the API default.
+	LatitudeAdjustment int
+}
+
+func (o *Options) params() (method, school int) {
+	if o == nil {
+		return -1, -1
+	}
+	return o.Method, o.School
+}
+
+// Client fetches prayer times. The zero value is ready to use.
+type Client struct {
+	// BaseURL overrides the Al Adhan API endpoint, e.g. for a mirror or
+	// an httptest server. Empty means the public API.
+	BaseURL string
+}
+
+func (c *Client) api(opts *Options) *api.Client {
+	ac := api.NewClient()
+	if c != nil && c.BaseURL != "" {
+		ac.BaseURL = c.BaseURL
+	}
+	if opts != nil {
+		ac.MethodSettings = api.CustomMethodSettings(opts.FajrAngle, opts.MaghribAngle, opts.IshaAngle)
+		ac.Tune = api.TuneParam(opts.Tune)
+		ac.LatitudeAdjustment = opts.LatitudeAdjustment
+	}
+	return ac
+}
+
+// Day holds one day of prayer times for a location.
+type Day struct {
+	Date      time.Time // midnight at the start of the day, in Timezone
+	Timezone  string    // IANA timezone of the location
+	Hijri     string    // e.g. "10 Ramaḍān 1447 AH"; from ToHijri if the API gave none
+	Latitude  float64
+	Longitude float64
+	Method    string // calculation method name reported by the API
+
+	timings api.Timings
+	loc     *time.Location
+}
+
+// Prayers returns the named prayers for the day, in the order given.
+// With no names, DefaultPrayers is used.
+func (d *Day) Prayers(names ...string) ([]Prayer, error) {
+	if len(names) == 0 {
+		names = prayer.DefaultPrayerNames
+	}
+	parsed, err := prayer.ParseTimings(d.timings, d.Date, d.loc, names)
+	if err != nil {
+		return nil, err
+	}
+	prayers := make([]Prayer, len(parsed))
+	for i, p := range parsed {
+		prayers[i] = Prayer(p)
+	}
+	return prayers, nil
+}
+
+// Day fetches prayer times for the calendar date of date at loc. It is
+// DayContext with context.Background.
+func (c *Client) Day(date time.Time, loc Location, opts *Options) (*Day, error) {
+	return c.DayContext(context.Background(), date, loc, opts)
+}
+
+// DayContext fetches prayer times for the calendar date of date at loc,
+// giving up when ctx is done.
+func (c *Client) DayContext(ctx context.Context, date time.Time, loc Location, opts *Options) (*Day, error) {
+	method, school := opts.params()
+	ac := c.api(opts)
+
+	var (
+		resp *api.Response
+		err  error
+	)
+	switch {
+	case loc.useCoordinates():
+		resp, err = ac.FetchByCoordinates(ctx, date, loc.Latitude, loc.Longitude, method, school)
+	case loc.City != "" && loc.Country != "":
+		resp, err = ac.FetchByCity(ctx, date, loc.City, loc.Country, method, school)
+	default:
+		return nil, fmt.Errorf("location requires coordinates or both city and country")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newDay(date, resp.Data.Timings, resp.Data.Date, resp.Data.Meta)
+}
+
+// Month fetches prayer times for every day of month at loc. It is
+// MonthContext with context.Background.
+func (c *Client) Month(year int, month time.Month, loc Location, opts *Options) ([]*Day, error) {
+	return c.MonthContext(context.Background(), year, month, loc, opts)
+}
+
+// MonthContext fetches prayer times for every day of month at loc, giving up
+// when ctx is done.
+func (c *Client) MonthContext(ctx context.Context, year int, month time.Month, loc Location, opts *Options) ([]*Day, error) {
+	method, school := opts.params()
+	ac := c.api(opts)
+
+	var (
+		resp *api.CalendarResponse
+		err  error
+	)
+	switch {
+	case loc.useCoordinates():
+		resp, err = ac.FetchCalendarByCoordinates(ctx, year, int(month), loc.Latitude, loc.Longitude, method, school)
+	case loc.City != "" && loc.Country != "":
+		resp, err = ac.FetchCalendarByCity(ctx, year, int(month), loc.City, loc.Country, method, school)
+	default:
+		return nil, fmt.Errorf("location requires coordinates or both city and country")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]*Day, 0, len(resp.Data))
+	for i, d := range resp.Data {
+		date := time.Date(year, month, i+1, 0, 0, 0, 0, time.UTC)
+		day, err := newDay(date, d.Timings, d.Date, d.Meta)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// NextPrayer returns the next of the named prayers (DefaultPrayers if none)
+// after now at loc. After the day's last prayer it looks at tomorrow. It is
+// NextPrayerContext with context.Background.
+func (c *Client) NextPrayer(now time.Time, loc Location, opts *Options, names ...string) (*Prayer, error) {
+	return c.NextPrayerContext(context.Background(), now, loc, opts, names...)
+}
+
+// NextPrayerContext is NextPrayer, giving up when ctx is done.
+func (c *Client) NextPrayerContext(ctx context.Context, now time.Time, loc Location, opts *Options, names ...string) (*Prayer, error) {
+	for offset := 0; offset <= 1; offset++ {
+		day, err := c.DayContext(ctx, now.AddDate(0, 0, offset), loc, opts)
+		if err != nil {
+			return nil, err
+		}
+		prayers, err := day.Prayers(names...)
+		if err != nil {
+			return nil, err
+		}
+		if next := Next(prayers, now); next != nil {
+			return next, nil
+		}
+	}
+	return nil, fmt.Errorf("no upcoming prayer found")
+}
+
+// newDay builds a Day from an API payload. fallback supplies the calendar
+// date if the API's own Gregorian date is missing.
+func newDay(fallback time.Time, timings api.Timings, info api.DateInfo, meta api.Meta) (*Day, error) {
+	tz, err := time.LoadLocation(meta.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", meta.Timezone, err)
+	}
+
+	date := time.Date(fallback.Year(), fallback.Month(), fallback.Day(), 0, 0, 0, 0, tz)
+	if g, err := time.ParseInLocation("02-01-2006", info.Gregorian.Date, tz); err == nil {
+		date = g
+	}
+
+	h := info.Hijri.Format()
+	if h == "" {
+		h = ToHijri(date, 0).Format()
+	}
+
+	return &Day{
+		Date:      date,
+		Timezone:  meta.Timezone,
+		Hijri:     h,
+		Latitude:  meta.Latitude,
+		Longitude: meta.Longitude,
+		Method:    meta.Method.Name,
+		timings:   timings,
+		loc:       tz,
+	}, nil
+}
+
+// Next returns the first prayer after now, or nil if all have passed.
+// prayers must be in chronological order.
+func Next(prayers []Prayer, now time.Time) *Prayer {
+	p := prayer.NextPrayer(toInternal(prayers), now)
+	if p == nil {
+		return nil
+	}
+	out := Prayer(*p)
+	return &out
+}
+
+// Current returns the most recent prayer at or before now, or nil if none
+// has started yet. prayers must be in chronological order.
+func Current(prayers []Prayer, now time.Time) *Prayer {
+	p := prayer.CurrentPrayer(toInternal(prayers), now)
+	if p == nil {
+		return nil
+	}
+	out := Prayer(*p)
+	return &out
+}
+
+// FormatRemaining renders a duration as e.g. "2h 15m" or "45m".
+func FormatRemaining(d time.Duration) string {
+	return prayer.FormatRemaining(d)
+}
+
+// Format renders p relative to now in one of the Format* modes or a custom
+// template. timeLayout is a Go time layout such as "15:04" or "3:04 PM".
+func Format(p Prayer, now time.Time, format, timeLayout string) string {
+	return prayer.FormatOutput(prayer.Prayer(p), now, format, timeLayout)
+}
+
+// FormatSince is Format with the progress fields of custom templates
+// (.Elapsed, .WindowDuration, .Progress, .ProgressBar and .Bar) measured from
+// start, normally the previous prayer.
+func FormatSince(p Prayer, start, now time.Time, format, timeLayout string) string {
+	return prayer.FormatOutputSince(prayer.Prayer(p), start, now, format, timeLayout)
+}
+
+// HijriDate is a day of the Hijri calendar, as computed by ToHijri.
+type HijriDate struct {
+	Year  int
+	Month int // 1 (Muharram) to 12 (Dhul-Hijjah)
+	Day   int
+}
+
+// ToHijri converts t's calendar day to the Hijri calendar without calling the
+// API: Umm al-Qura, as the API uses, from 1423 to 1500 AH, and the tabular
+// Islamic calendar, a day or two off it, outside those years. adjustment,
+// from -2 to 2, moves the result by that many days, e.g. to follow local
+// moon sighting.
+func ToHijri(t time.Time, adjustment int) HijriDate {
+	return HijriDate(hijri.FromGregorian(t, adjustment))
+}
+
+// FromHijri converts h back to its Gregorian day, at midnight UTC; it is the
+// inverse of ToHijri with the same adjustment.
+func FromHijri(h HijriDate, adjustment int) (time.Time, error) {
+	return hijri.ToGregorian(hijri.Date(h), adjustment)
+}
+
+// MonthName returns the English name of h's month, e.g. "Ramaḍān".
+func (h HijriDate) MonthName() string {
+	return hijri.MonthName(h.Month)
+}
+
+// Format returns h as e.g. "10 Ramaḍān 1447 AH".
+func (h HijriDate) Format() string {
+	return hijri.Date(h).Format()
+}
+
+// String returns h as YYYY-MM-DD.
+func (h HijriDate) String() string {
+	return hijri.Date(h).String()
+}
+
+func toInternal(prayers []Prayer) []prayer.Prayer {
+	out := make([]prayer.Prayer, len(prayers))
+	for i, p := range prayers {
+		out[i] = prayer.Prayer(p)
+	}
+	return out
+}
@@ -0,0 +1,327 @@
+package prayertimes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleDay = `{
+	"timings": {
+		"Fajr": "05:17", "Sunrise": "06:48", "Dhuhr": "12:13", "Asr": "15:02",
+		"Sunset": "17:39", "Maghrib": "17:39", "Isha": "19:10", "Imsak": "05:07",
+		"Midnight": "00:14", "Firstthird": "22:02", "Lastthird": "02:25"
+	},
+	"date": {
+		"gregorian": {"date": "28-02-2026"},
+		"hijri": {"day": "11", "month": {"en": "Ramadan"}, "year": "1447"}
+	},
+	"meta": {
+		"latitude": 51.5074, "longitude": -0.1278, "timezone": "Europe/London",
+		"method": {"id": 2, "name": "ISNA"}
+	}
+}`
+
+// newTestClient returns a Client backed by a server that answers every
+// request with sampleDay, dated to the requested day, and records the last
+// request URL.
+func newTestClient(t *testing.T, lastURL *string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lastURL != nil {
+			*lastURL = r.URL.String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/calendar") {
+			w.Write([]byte(`{"code": 200, "status": "OK", "data": [` + sampleDay + `]}`))
+			return
+		}
+		day := sampleDay
+		if i := strings.LastIndex(r.URL.Path, "/"); i >= 0 && strings.Count(r.URL.Path[i:], "-") == 2 {
+			day = strings.Replace(day, "28-02-2026", r.URL.Path[i+1:], 1)
+		}
+		w.Write([]byte(`{"code": 200, "status": "OK", "data": ` + day + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	return &Client{BaseURL: srv.URL}
+}
+
+func TestDay_Coordinates(t *testing.T) {
+	var got string
+	c := newTestClient(t, &got)
+
+	day, err := c.Day(time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), Location{Latitude: 51.5, Longitude: -0.12}, &Options{Method: 2, School: -1})
+	if err != nil {
+		t.Fatalf("Day() error: %v", err)
+	}
+
+	if !strings.Contains(got, "/timings/28-02-2026") {
+		t.Errorf("request URL = %q, want /timings/28-02-2026", got)
+	}
+	if !strings.Contains(got, "method=2") || strings.Contains(got, "school=") {
+		t.Errorf("request URL = %q, want method=2 and no school", got)
+	}
+	if day.Timezone != "Europe/London" {
+		t.Errorf("Timezone = %q, want Europe/London", day.Timezone)
+	}
+	if day.Hijri != "11 Ramadan 1447 AH" {
+		t.Errorf("Hijri = %q, want %q", day.Hijri, "11 Ramadan 1447 AH")
+	}
+	if day.Method != "ISNA" {
+		t.Errorf("Method = %q, want ISNA", day.Method)
+	}
+	if got := day.Date.Format("2006-01-02 MST"); got != "2026-02-28 GMT" {
+		t.Errorf("Date = %q, want 2026-02-28 GMT", got)
+	}
+}
+
+func TestDay_City(t *testing.T) {
+	var got string
+	c := newTestClient(t, &got)
+
+	if _, err := c.Day(time.Now(), Location{City: "London", Country: "UK"}, nil); err != nil {
+		t.Fatalf("Day() error: %v", err)
+	}
+	if !strings.Contains(got, "/timingsByCity/") || !strings.Contains(got, "city=London") {
+		t.Errorf("request URL = %q, want timingsByCity with city=London", got)
+	}
+	if strings.Contains(got, "method=") {
+		t.Errorf("request URL = %q, nil options should not send method", got)
+	}
+}
+
+func TestDay_CustomAngles(t *testing.T) {
+	var got string
+	c := newTestClient(t, &got)
+
+	if _, err := c.Day(time.Now(), Location{Latitude: 51.5, Longitude: -0.12}, &Options{Method: 2, School: -1, FajrAngle: 18, IshaAngle: 17}); err != nil {
+		t.Fatalf("Day() error: %v", err)
+	}
+	if !strings.Contains(got, "method=99") || !strings.Contains(got, "methodSettings=18%2Cnull%2C17") {
+		t.Errorf("request URL = %q, want method=99 with methodSettings", got)
+	}
+}
+
+func TestDay_Tune(t *testing.T) {
+	var got string
+	c := newTestClient(t, &got)
+
+	if _, err := c.Day(time.Now(), Location{Latitude: 51.5, Longitude: -0.12}, &Options{Method: 2, School: -1, Tune: map[string]int{"Fajr": 3}}); err != nil {
+		t.Fatalf("Day() error: %v", err)
+	}
+	if !strings.Contains(got, "tune=0%2C3%2C0%2C0%2C0%2C0%2C0%2C0%2C0") {
+		t.Errorf("request URL = %q, want tune offsets", got)
+	}
+}
+
+// TestDay_ZeroCoordinates checks that a location at 0, 0 is looked up by its
+// coordinates when HasCoordinates says they are set.
+func TestDay_ZeroCoordinates(t *testing.T) {
+	var got string
+	c := newTestClient(t, &got)
+	if _, err := c.Day(time.Now(), Coordinates(0, 0), nil); err != nil {
+		t.Fatalf("Day() error: %v", err)
+	}
+	if !strings.Contains(got, "/timings/") || !strings.Contains(got, "latitude=0") || !strings.Contains(got, "longitude=0") {
+		t.Errorf("request URL = %q, want timings by coordinates 0, 0", got)
+	}
+
+	if _, err := c.Day(time.Now(), Location{}, nil); err == nil {
+		t.Error("expected error for a location with neither coordinates nor a city")
+	}
+}
+
+func TestDay_NoLocation(t *testing.T) {
+	var c Client
+	if _, err := c.Day(time.Now(), Location{City: "London"}, nil); err == nil {
+		t.Error("expected error for location without country")
+	}
+}
+
+func TestDay_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{"code": 400, "status": "Bad Request", "data": "bad"})
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL}
+	if _, err := c.Day(time.Now(), Location{Latitude: 1, Longitude: 1}, nil); err == nil {
+		t.Error("expected error for API failure")
+	}
+}
+
+func TestContext_Canceled(t *testing.T) {
+	c := newTestClient(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	loc := Location{City: "London", Country: "UK"}
+	date := time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC)
+
+	if _, err := c.DayContext(ctx, date, loc, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("DayContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := c.MonthContext(ctx, 2026, time.February, loc, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("MonthContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := c.NextPrayerContext(ctx, date, loc, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("NextPrayerContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDayPrayers(t *testing.T) {
+	c := newTestClient(t, nil)
+	day, err := c.Day(time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), Location{Latitude: 51.5, Longitude: -0.12}, nil)
+	if err != nil {
+		t.Fatalf("Day() error: %v", err)
+	}
+
+	prayers, err := day.Prayers()
+	if err != nil {
+		t.Fatalf("Prayers() error: %v", err)
+	}
+	if len(prayers) != len(DefaultPrayers()) {
+		t.Fatalf("Prayers() returned %d prayers, want %d", len(prayers), len(DefaultPrayers()))
+	}
+	if prayers[0].Name != "Fajr" || prayers[0].Time.Format("2006-01-02 15:04") != "2026-02-28 05:17" {
+		t.Errorf("first prayer = %s %v, want Fajr 2026-02-28 05:17", prayers[0].Name, prayers[0].Time)
+	}
+
+	prayers, err = day.Prayers("Isha", "Imsak")
+	if err != nil {
+		t.Fatalf("Prayers(Isha, Imsak) error: %v", err)
+	}
+	if len(prayers) != 2 || prayers[0].Name != "Isha" || prayers[1].Name != "Imsak" {
+		t.Errorf("Prayers(Isha, Imsak) = %v, want [Isha Imsak]", prayers)
+	}
+}
+
+func TestMonth(t *testing.T) {
+	var got string
+	c := newTestClient(t, &got)
+
+	days, err := c.Month(2026, time.February, Location{Latitude: 51.5, Longitude: -0.12}, nil)
+	if err != nil {
+		t.Fatalf("Month() error: %v", err)
+	}
+	if !strings.Contains(got, "/calendar/2026/2") {
+		t.Errorf("request URL = %q, want /calendar/2026/2", got)
+	}
+	if len(days) != 1 {
+		t.Fatalf("Month() returned %d days, want 1", len(days))
+	}
+}
+
+func TestClientNextPrayer(t *testing.T) {
+	c := newTestClient(t, nil)
+	london, _ := time.LoadLocation("Europe/London")
+
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2026, 2, 28, 13, 0, 0, 0, london), "Asr"},
+		{time.Date(2026, 2, 28, 21, 0, 0, 0, london), "Fajr"}, // tomorrow
+	}
+	for _, tt := range tests {
+		next, err := c.NextPrayer(tt.now, Location{Latitude: 51.5, Longitude: -0.12}, nil)
+		if err != nil {
+			t.Fatalf("NextPrayer(%v) error: %v", tt.now, err)
+		}
+		if next.Name != tt.want {
+			t.Errorf("NextPrayer(%v) = %q, want %q", tt.now, next.Name, tt.want)
+		}
+	}
+}
+
+func TestNextAndCurrent(t *testing.T) {
+	base := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	prayers := []Prayer{
+		{"Fajr", base.Add(5 * time.Hour)},
+		{"Dhuhr", base.Add(12 * time.Hour)},
+		{"Isha", base.Add(19 * time.Hour)},
+	}
+
+	tests := []struct {
+		now           time.Time
+		next, current string
+	}{
+		{base.Add(4 * time.Hour), "Fajr", ""},
+		{base.Add(12 * time.Hour), "Isha", "Dhuhr"},
+		{base.Add(20 * time.Hour), "", "Isha"},
+	}
+	for _, tt := range tests {
+		var next, cur string
+		if p := Next(prayers, tt.now); p != nil {
+			next = p.Name
+		}
+		if p := Current(prayers, tt.now); p != nil {
+			cur = p.Name
+		}
+		if next != tt.next {
+			t.Errorf("Next(%v) = %q, want %q", tt.now, next, tt.next)
+		}
+		if cur != tt.current {
+			t.Errorf("Current(%v) = %q, want %q", tt.now, cur, tt.current)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2026, 2, 28, 12, 45, 0, 0, time.UTC)
+	p := Prayer{Name: "Asr", Time: time.Date(2026, 2, 28, 15, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatFull, "Asr 15:00 (2h 15m)"},
+		{FormatShortNameAndTime, "A 15:00"},
+		{"{{.Name}} in {{.Remaining}}", "Asr in 2h 15m"},
+	}
+	for _, tt := range tests {
+		if got := Format(p, now, tt.format, "15:04"); got != tt.want {
+			t.Errorf("Format(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	dhuhr := time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC)
+	if got := FormatSince(p, dhuhr, now, "{{.Progress}}% {{.ProgressBar}}", "15:04"); got != "25% ███░░░░░░░" {
+		t.Errorf("FormatSince() = %q, want %q", got, "25% ███░░░░░░░")
+	}
+}
+
+func TestPrayerListsAreCopies(t *testing.T) {
+	all := AllPrayers()
+	all[0] = "changed"
+	if AllPrayers()[0] != "Fajr" {
+		t.Error("AllPrayers() exposes the package slice")
+	}
+}
+
+func TestToHijri(t *testing.T) {
+	g := time.Date(2026, 2, 18, 21, 0, 0, 0, time.UTC)
+	h := ToHijri(g, 0)
+	if h != (HijriDate{Year: 1447, Month: 9, Day: 1}) || h.MonthName() != "Ramaḍān" {
+		t.Errorf("ToHijri(2026-02-18) = %v %s, want 1447-09-01 Ramaḍān", h, h.MonthName())
+	}
+	if got := ToHijri(g, -1).Format(); got != "29 Shaʿbān 1447 AH" {
+		t.Errorf("ToHijri(2026-02-18, -1) = %q, want %q", got, "29 Shaʿbān 1447 AH")
+	}
+
+	back, err := FromHijri(h, 0)
+	if err != nil {
+		t.Fatalf("FromHijri() error: %v", err)
+	}
+	if back.Format("2006-01-02") != "2026-02-18" {
+		t.Errorf("FromHijri(%v) = %s, want 2026-02-18", h, back.Format("2006-01-02"))
+	}
+	if _, err := FromHijri(HijriDate{Year: 1447, Month: 8, Day: 30}, 0); err == nil {
+		t.Error("FromHijri(1447-08-30) should error: Shaʿbān has 29 days")
+	}
+}
@@ -42,7 +42,8 @@ fi
 # ---------------------------------------------------------------------------
 # Global flags (apply to all commands, placed before the subcommand).
 build_global_flags() {
-    local flags=()
+    # Warnings would end up in the status line; keep them out.
+    local flags=("--quiet")
 
     local city
     city="$(get_tmux_option "@prayer-times-city" "")"
@@ -95,8 +96,8 @@ build_global_flags() {
     echo "${flags[@]}"
 }
 
-# Subcommand-specific flags for `next`.
-build_next_flags() {
+# Subcommand-specific flags for `tmux`.
+build_tmux_flags() {
     local flags=()
 
     local format
@@ -111,19 +112,31 @@ build_next_flags() {
         flags+=("--prayers" "$prayers")
     fi
 
+    local icon
+    icon="$(get_tmux_option "@prayer-times-icon" "")"
+    if [ -n "$icon" ]; then
+        flags+=("--icon" "$icon")
+    fi
+
+    local urgent
+    urgent="$(get_tmux_option "@prayer-times-urgent" "")"
+    if [ -n "$urgent" ]; then
+        flags+=("--urgent" "$urgent")
+    fi
+
+    local urgent_style
+    urgent_style="$(get_tmux_option "@prayer-times-urgent-style" "")"
+    if [ -n "$urgent_style" ]; then
+        flags+=("--urgent-style" "$urgent_style")
+    fi
+
     echo "${flags[@]}"
 }
 
 # ---------------------------------------------------------------------------
-# Run the binary: prayer-times [global-flags] next [next-flags]
+# Run the binary: prayer-times [global-flags] tmux [tmux-flags]
 # ---------------------------------------------------------------------------
 # shellcheck disable=SC2046
-output=$("$BINARY" $(build_global_flags) next $(build_next_flags) 2>/dev/null) || output=""
-
-# Prepend icon if configured.
-icon="$(get_tmux_option "@prayer-times-icon" "")"
-if [ -n "$icon" ]; then
-    output="${icon} ${output}"
-fi
+output=$("$BINARY" $(build_global_flags) tmux $(build_tmux_flags) 2>/dev/null) || output=""
 
 echo -n "$output"